@@ -0,0 +1,375 @@
+// Package bgp manages the BGP instance of the router and its neighbors, and
+// reads the state of the sessions with the neighbors.
+package bgp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+const summaryCommand = "show ip bgp summary"
+
+type Client interface {
+	Get(context.Context) (*types.BGP, error)
+	Create(context.Context, *types.BGP) (*types.BGP, error)
+	Update(context.Context, *types.BGP, []jsonpatch.JsonPatchOperation) (*types.BGP, error)
+	Delete(context.Context) error
+
+	GetNeighbor(context.Context, string) (*types.BGPNeighbor, error)
+	ListNeighbors(context.Context) ([]*types.BGPNeighbor, error)
+	CreateNeighbor(context.Context, *types.BGPNeighbor) (*types.BGPNeighbor, error)
+	UpdateNeighbor(context.Context, *types.BGPNeighbor, []jsonpatch.JsonPatchOperation) (*types.BGPNeighbor, error)
+	DeleteNeighbor(context.Context, string) error
+
+	NeighborStatus(context.Context) (types.BGPNeighborStatuses, error)
+}
+
+type client struct {
+	apiClient     api.Client
+	commandClient api.CommandClient
+}
+
+// New returns a client that cannot read the state of the sessions, as that
+// requires running show commands.
+func New(httpClient *http.Client, host string) Client {
+	return NewWithAPIClient(api.New(httpClient, host), nil)
+}
+
+// NewWithAPIClient returns a client that reads the state of the sessions
+// through the command client, if set.
+func NewWithAPIClient(apiClient api.Client, commandClient api.CommandClient) Client {
+	return &client{
+		apiClient:     apiClient,
+		commandClient: commandClient,
+	}
+}
+
+// Get returns the BGP instance including its neighbors.
+func (c *client) Get(ctx context.Context) (*types.BGP, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toBGP(op)
+}
+
+// Create configures the BGP instance. Neighbors of the instance are created
+// in the same commit.
+func (c *client) Create(ctx context.Context, b *types.BGP) (*types.BGP, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+
+	if current, err := c.Get(ctx); err == nil {
+		return nil, fmt.Errorf("BGP is already configured with AS %d.", current.ASN)
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Protocols: bgpOf(b.ASN, b),
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx)
+}
+
+// Update applies the patches to the router ID and networks of the current
+// instance, setting the ones that changed and deleting the ones that were
+// removed in a single commit. The AS number cannot be patched and neighbors
+// are managed with the neighbor methods.
+func (c *client) Update(ctx context.Context, current *types.BGP, patches []jsonpatch.JsonPatchOperation) (*types.BGP, error) {
+	var b types.BGP
+	if err := utils.Patch(current.Properties(), &b, patches); err != nil {
+		return nil, err
+	}
+	b.ASN = current.ASN
+
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := new(api.Operation)
+
+	if !b.IsEmpty() {
+		in.Set = &api.Set{
+			Resources: api.Resources{
+				Protocols: bgpOf(b.ASN, &b),
+			},
+		}
+	}
+
+	if stale := staleBGP(current, &b); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Protocols: bgpOf(b.ASN, stale),
+			},
+		}
+	}
+
+	if in.Set != nil || in.Delete != nil {
+		if _, err := c.apiClient.Post(ctx, in); err != nil {
+			return nil, err
+		}
+	}
+	return c.Get(ctx)
+}
+
+// Delete removes the BGP instance along with its neighbors.
+func (c *client) Delete(ctx context.Context) error {
+	current, err := c.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Protocols: bgpOf(current.ASN, nil),
+			},
+		},
+	})
+	return err
+}
+
+func (c *client) GetNeighbor(ctx context.Context, address string) (*types.BGPNeighbor, error) {
+	b, err := c.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	n, ok := b.Neighbors[address]
+	if !ok || n == nil {
+		return nil, fmt.Errorf("The BGP neighbor %s does not exist.", address)
+	}
+	return n, nil
+}
+
+// ListNeighbors returns the neighbors ordered by address.
+func (c *client) ListNeighbors(ctx context.Context) ([]*types.BGPNeighbor, error) {
+	b, err := c.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	neighbors := []*types.BGPNeighbor{}
+	for _, n := range b.Neighbors {
+		if n != nil {
+			neighbors = append(neighbors, n)
+		}
+	}
+	sort.Slice(neighbors, func(i, j int) bool {
+		return neighbors[i].Address < neighbors[j].Address
+	})
+	return neighbors, nil
+}
+
+// CreateNeighbor adds the neighbor to the BGP instance, which must already be
+// configured.
+func (c *client) CreateNeighbor(ctx context.Context, n *types.BGPNeighbor) (*types.BGPNeighbor, error) {
+	if err := n.Validate(); err != nil {
+		return nil, err
+	}
+
+	b, err := c.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if existing, ok := b.Neighbors[n.Address]; ok && existing != nil {
+		return nil, fmt.Errorf("The BGP neighbor %s already exists.", n.Address)
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Protocols: neighborOf(b.ASN, n.Address, n),
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.GetNeighbor(ctx, n.Address)
+}
+
+// UpdateNeighbor applies the patches to the current neighbor, setting the
+// properties that changed and deleting the ones that were removed in a single
+// commit. The address cannot be patched.
+func (c *client) UpdateNeighbor(ctx context.Context, current *types.BGPNeighbor, patches []jsonpatch.JsonPatchOperation) (*types.BGPNeighbor, error) {
+	var n types.BGPNeighbor
+	if err := utils.Patch(current, &n, patches); err != nil {
+		return nil, err
+	}
+	n.Address = current.Address
+
+	if err := n.Validate(); err != nil {
+		return nil, err
+	}
+
+	b, err := c.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	in := &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Protocols: neighborOf(b.ASN, n.Address, &n),
+			},
+		},
+	}
+
+	if stale := staleNeighbor(current, &n); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Protocols: neighborOf(b.ASN, n.Address, stale),
+			},
+		}
+	}
+
+	if _, err := c.apiClient.Post(ctx, in); err != nil {
+		return nil, err
+	}
+	return c.GetNeighbor(ctx, n.Address)
+}
+
+func (c *client) DeleteNeighbor(ctx context.Context, address string) error {
+	b, err := c.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if n, ok := b.Neighbors[address]; !ok || n == nil {
+		return fmt.Errorf("The BGP neighbor %s does not exist.", address)
+	}
+
+	_, err = c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Protocols: neighborOf(b.ASN, address, nil),
+			},
+		},
+	})
+	return err
+}
+
+// NeighborStatus returns the state of the sessions with the neighbors ordered
+// by address. It reports the sessions of the running BGP instance rather than
+// the configured neighbors.
+func (c *client) NeighborStatus(ctx context.Context) (types.BGPNeighborStatuses, error) {
+	if c.commandClient == nil {
+		return nil, errors.New("The client cannot run show commands.")
+	}
+
+	out, err := c.commandClient.Run(ctx, summaryCommand)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses types.BGPNeighborStatuses
+	if err := statuses.UnmarshalText([]byte(out)); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// toBGP returns the BGP instance. EdgeOS allows a single one.
+func toBGP(op *api.Operation) (*types.BGP, error) {
+	if op == nil || op.Get == nil || op.Get.Protocols == nil {
+		return nil, errors.New("BGP is not configured.")
+	}
+	for _, b := range op.Get.Protocols.BGP {
+		if b != nil {
+			return b, nil
+		}
+	}
+	return nil, errors.New("BGP is not configured.")
+}
+
+// bgpOf returns protocols holding only the BGP instance.
+func bgpOf(asn int, b *types.BGP) *types.Protocols {
+	return &types.Protocols{
+		BGP: map[string]*types.BGP{
+			strconv.Itoa(asn): b,
+		},
+	}
+}
+
+// neighborOf returns protocols holding only the neighbor of the BGP instance.
+func neighborOf(asn int, address string, n *types.BGPNeighbor) *types.Protocols {
+	return bgpOf(asn, &types.BGP{
+		Neighbors: map[string]*types.BGPNeighbor{
+			address: n,
+		},
+	})
+}
+
+// staleBGP returns the router ID and networks that are set in current but not
+// in updated, encoded for deletion, or nil if there are none.
+func staleBGP(current, updated *types.BGP) *types.BGP {
+	stale := &types.BGP{
+		Networks: utils.StringSliceDiff(updated.Networks, current.Networks),
+	}
+	if current.RouterID != "" && updated.RouterID == "" {
+		stale.RouterID = current.RouterID
+	}
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	stale.SetOpMode(types.OpModeDelete)
+	return stale
+}
+
+// staleNeighbor returns the properties that are set in current but not in
+// updated, encoded for deletion, or nil if there are none.
+func staleNeighbor(current, updated *types.BGPNeighbor) *types.BGPNeighbor {
+	stale := &types.BGPNeighbor{
+		Description:  staleString(current.Description, updated.Description),
+		UpdateSource: staleString(current.UpdateSource, updated.UpdateSource),
+		Shutdown:     current.Shutdown && !updated.Shutdown,
+		RouteMap:     stalePolicy(current.RouteMap, updated.RouteMap),
+		PrefixList:   stalePolicy(current.PrefixList, updated.PrefixList),
+	}
+
+	// An empty neighbor encodes as null and would delete the neighbor.
+	if stale.IsEmpty() {
+		return nil
+	}
+	stale.SetOpMode(types.OpModeDelete)
+	return stale
+}
+
+func stalePolicy(current, updated *types.BGPPolicy) *types.BGPPolicy {
+	if current == nil {
+		return nil
+	}
+	if updated == nil {
+		updated = new(types.BGPPolicy)
+	}
+	stale := &types.BGPPolicy{
+		Import: staleString(current.Import, updated.Import),
+		Export: staleString(current.Export, updated.Export),
+	}
+	if stale.Import == "" && stale.Export == "" {
+		return nil
+	}
+	return stale
+}
+
+func staleString(current, updated string) string {
+	if current != "" && updated == "" {
+		return current
+	}
+	return ""
+}
@@ -0,0 +1,315 @@
+package bgp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	bgpConfig = `{"GET": {"protocols": {"bgp": {"65000": {
+	"parameters": {"router-id": "10.255.0.1"},
+	"network": {"10.1.0.0/24": null, "10.2.0.0/24": null},
+	"neighbor": {
+		"10.0.0.2": {
+			"remote-as": "65001",
+			"description": "transit",
+			"update-source": "10.0.0.1",
+			"route-map": {"import": "TRANSIT-IN", "export": "TRANSIT-OUT"},
+			"prefix-list": {"export": "OWN"}
+		},
+		"10.0.0.6": {"remote-as": "65002", "shutdown": null}
+	}
+}}}}, "success": true}`
+
+	emptyConfig = `{"GET": {}, "success": true}`
+
+	summaryOutput = `BGP router identifier 10.255.0.1, local AS number 65000
+BGP table version is 5
+2 BGP AS-PATH entries
+0 BGP community entries
+
+Neighbor        V    AS MsgRcvd MsgSent   TblVer  InQ OutQ Up/Down  State/PfxRcd
+10.0.0.6        4 65002       0       0        0    0    0 never    Idle (Admin)
+10.0.0.2        4 65001     120     118        5    0    0 01:55:03        3
+
+Total number of neighbors 2
+`
+)
+
+func TestOperations(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		config    string
+		committed string
+		do        func(Client) error
+		expected  []string
+		err       string
+	}{
+		{
+			name: "get",
+			do: func(c Client) error {
+				b, err := c.Get(context.Background())
+				if err != nil {
+					return err
+				}
+				require.Equal(t, 65000, b.ASN)
+				require.Equal(t, "10.255.0.1", b.RouterID)
+				require.Equal(t, []string{"10.1.0.0/24", "10.2.0.0/24"}, b.Networks)
+				require.Equal(t, &types.BGPNeighbor{
+					Address:      "10.0.0.2",
+					RemoteAS:     65001,
+					Description:  "transit",
+					UpdateSource: "10.0.0.1",
+					RouteMap:     &types.BGPPolicy{Import: "TRANSIT-IN", Export: "TRANSIT-OUT"},
+					PrefixList:   &types.BGPPolicy{Export: "OWN"},
+				}, b.Neighbors["10.0.0.2"])
+				require.True(t, b.Neighbors["10.0.0.6"].Shutdown)
+				return nil
+			},
+		},
+		{
+			name:   "get without BGP",
+			config: emptyConfig,
+			do: func(c Client) error {
+				_, err := c.Get(context.Background())
+				return err
+			},
+			err: "BGP is not configured.",
+		},
+		{
+			name:   "create",
+			config: emptyConfig,
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.BGP{
+					ASN:      65000,
+					RouterID: "10.255.0.1",
+					Networks: []string{"10.1.0.0/24"},
+					Neighbors: map[string]*types.BGPNeighbor{
+						"10.0.0.2": {RemoteAS: 65001, UpdateSource: "eth0"},
+					},
+				})
+				return err
+			},
+			committed: bgpConfig,
+			expected: []string{
+				`{"SET":{"protocols":{"bgp":{"65000":{"parameters":{"router-id":"10.255.0.1"},"network":{"10.1.0.0/24":null},` +
+					`"neighbor":{"10.0.0.2":{"remote-as":"65001","update-source":"eth0"}}}}}}}`,
+			},
+		},
+		{
+			name: "create a second instance",
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.BGP{ASN: 65100})
+				return err
+			},
+			err: "BGP is already configured with AS 65000.",
+		},
+		{
+			name:   "create an invalid instance",
+			config: emptyConfig,
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.BGP{
+					RouterID: "router",
+					Networks: []string{"10.1.0.1/24"},
+					Neighbors: map[string]*types.BGPNeighbor{
+						"peer": {UpdateSource: "eth 0"},
+					},
+				})
+				return err
+			},
+			err: `asn: 0 must be between 1 and 4294967295; parameters.router-id: "router" is not a valid IPv4 address; ` +
+				`network[0]: "10.1.0.1/24" has host bits set, use 10.1.0.0/24; neighbor[peer].address: "peer" is not a valid IP address; ` +
+				`neighbor[peer].remote-as: must be set; neighbor[peer].update-source: "eth 0" must not contain whitespace or quotes`,
+		},
+		{
+			name: "update",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background())
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/parameters"},
+					{Operation: "remove", Path: "/network/10.1.0.0~124"},
+					{Operation: "add", Path: "/network/10.3.0.0~124", Value: nil},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"protocols":{"bgp":{"65000":{"network":{"10.2.0.0/24":null,"10.3.0.0/24":null}}}}},` +
+					`"DELETE":{"protocols":{"bgp":{"65000":{"network":{"10.1.0.0/24":null},"parameters":{"router-id":null}}}}}}`,
+			},
+		},
+		{
+			name: "delete",
+			do: func(c Client) error {
+				return c.Delete(context.Background())
+			},
+			expected: []string{
+				`{"DELETE":{"protocols":{"bgp":{"65000":null}}}}`,
+			},
+		},
+	} {
+		config := bgpConfig
+		if test.config != "" {
+			config = test.config
+		}
+		apiClient := &apitest.Client{Config: config, Committed: test.committed}
+		err := test.do(NewWithAPIClient(apiClient, apiClient))
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
+
+func TestNeighborOperations(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		config    string
+		committed string
+		do        func(Client) error
+		expected  []string
+		err       string
+	}{
+		{
+			name: "list",
+			do: func(c Client) error {
+				neighbors, err := c.ListNeighbors(context.Background())
+				if err != nil {
+					return err
+				}
+				require.Len(t, neighbors, 2)
+				require.Equal(t, "10.0.0.2", neighbors[0].Address)
+				require.Equal(t, "10.0.0.6", neighbors[1].Address)
+				return nil
+			},
+		},
+		{
+			name: "get a missing neighbor",
+			do: func(c Client) error {
+				_, err := c.GetNeighbor(context.Background(), "10.0.0.10")
+				return err
+			},
+			err: "The BGP neighbor 10.0.0.10 does not exist.",
+		},
+		{
+			name: "create",
+			do: func(c Client) error {
+				_, err := c.CreateNeighbor(context.Background(), &types.BGPNeighbor{
+					Address:    "2001:db8::2",
+					RemoteAS:   65003,
+					PrefixList: &types.BGPPolicy{Import: "CUSTOMER"},
+				})
+				return err
+			},
+			committed: `{"GET": {"protocols": {"bgp": {"65000": {"neighbor": {"2001:db8::2": {"remote-as": "65003"}}}}}}, "success": true}`,
+			expected: []string{
+				`{"SET":{"protocols":{"bgp":{"65000":{"neighbor":{"2001:db8::2":{"remote-as":"65003","prefix-list":{"import":"CUSTOMER"}}}}}}}}`,
+			},
+		},
+		{
+			name: "create an existing neighbor",
+			do: func(c Client) error {
+				_, err := c.CreateNeighbor(context.Background(), &types.BGPNeighbor{Address: "10.0.0.6", RemoteAS: 65002})
+				return err
+			},
+			err: "The BGP neighbor 10.0.0.6 already exists.",
+		},
+		{
+			name:   "create without BGP",
+			config: emptyConfig,
+			do: func(c Client) error {
+				_, err := c.CreateNeighbor(context.Background(), &types.BGPNeighbor{Address: "10.0.0.2", RemoteAS: 65001})
+				return err
+			},
+			err: "BGP is not configured.",
+		},
+		{
+			name: "create an invalid neighbor",
+			do: func(c Client) error {
+				_, err := c.CreateNeighbor(context.Background(), &types.BGPNeighbor{
+					Address:  "10.0.0.10",
+					RemoteAS: -1,
+					RouteMap: &types.BGPPolicy{Export: "TO TRANSIT"},
+				})
+				return err
+			},
+			err: `remote-as: -1 must be between 1 and 4294967295; route-map.export: "TO TRANSIT" must not contain whitespace or quotes`,
+		},
+		{
+			name: "update",
+			do: func(c Client) error {
+				current, err := c.GetNeighbor(context.Background(), "10.0.0.2")
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateNeighbor(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/description"},
+					{Operation: "remove", Path: "/route-map/import"},
+					{Operation: "remove", Path: "/prefix-list"},
+					{Operation: "add", Path: "/shutdown", Value: nil},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"protocols":{"bgp":{"65000":{"neighbor":{"10.0.0.2":{"remote-as":"65001","shutdown":null,"update-source":"10.0.0.1","route-map":{"export":"TRANSIT-OUT"}}}}}}},` +
+					`"DELETE":{"protocols":{"bgp":{"65000":{"neighbor":{"10.0.0.2":{"description":null,"prefix-list":{"export":null},"route-map":{"import":null}}}}}}}}`,
+			},
+		},
+		{
+			name: "delete",
+			do: func(c Client) error {
+				return c.DeleteNeighbor(context.Background(), "10.0.0.6")
+			},
+			expected: []string{
+				`{"DELETE":{"protocols":{"bgp":{"65000":{"neighbor":{"10.0.0.6":null}}}}}}`,
+			},
+		},
+	} {
+		config := bgpConfig
+		if test.config != "" {
+			config = test.config
+		}
+		apiClient := &apitest.Client{Config: config, Committed: test.committed}
+		err := test.do(NewWithAPIClient(apiClient, apiClient))
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
+
+func TestNeighborStatus(t *testing.T) {
+	apiClient := &apitest.Client{Config: bgpConfig, Outputs: map[string]string{
+		summaryCommand: summaryOutput,
+	}}
+
+	statuses, err := NewWithAPIClient(apiClient, apiClient).NeighborStatus(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, types.BGPNeighborStatuses{
+		{Address: "10.0.0.2", RemoteAS: 65001, State: "Established", Uptime: "01:55:03", PrefixesReceived: 3, MessagesReceived: 120, MessagesSent: 118},
+		{Address: "10.0.0.6", RemoteAS: 65002, State: "Idle (Admin)", Uptime: "never"},
+	}, statuses)
+	require.True(t, statuses.Lookup("10.0.0.2").IsEstablished())
+	require.Nil(t, statuses.Lookup("10.0.0.10"))
+
+	_, err = NewWithAPIClient(apiClient, nil).NeighborStatus(context.Background())
+	require.EqualError(t, err, "The client cannot run show commands.")
+}
@@ -0,0 +1,626 @@
+// Package configboot parses and renders the curly brace format EdgeOS uses for
+// /config/config.boot.
+//
+// Sections the SDK models, such as firewall and interfaces, are decoded into
+// the same types the API clients use. Everything else is kept as a tree of
+// nodes. Rendering an unmodified configuration reproduces the original text,
+// including comments and the version footer. When a typed section changes, it
+// is rendered from the typed value and merged with the original tree so that
+// comments and nodes the types do not model are kept.
+package configboot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/frankgreco/edge-sdk-go/types"
+)
+
+// Config is a parsed config.boot file.
+type Config struct {
+	Firewall   *types.Firewall
+	Interfaces *types.Interfaces
+	// Nodes are the top level nodes that are not decoded into a typed
+	// section, such as service and system.
+	Nodes []*Node
+	// Footer are the comments after the last node. EdgeOS uses them for the
+	// config version string.
+	Footer []string
+
+	schema *schema
+	bases  map[string]*base
+}
+
+// base is what a typed section looked like when it was parsed.
+type base struct {
+	// node is the parsed node.
+	node *Node
+	// regen is the node rendered from the typed value, which differs from
+	// node by whatever the types do not model.
+	regen *Node
+	// snapshot is the JSON encoding of the typed value.
+	snapshot []byte
+	// index is the position of node among the parsed top level nodes and pos
+	// the number of Nodes that came before it.
+	index, pos int
+}
+
+// section is a top level node that is decoded into a typed value.
+type section struct {
+	name   string
+	get    func(*Config) (interface{}, bool)
+	set    func(*Config, interface{})
+	decode func([]byte) (interface{}, error)
+}
+
+var sections = []*section{
+	{
+		name: "firewall",
+		get: func(c *Config) (interface{}, bool) {
+			return c.Firewall, c.Firewall != nil
+		},
+		set: func(c *Config, v interface{}) {
+			c.Firewall = v.(*types.Firewall)
+		},
+		decode: func(data []byte) (interface{}, error) {
+			var fw types.Firewall
+			if err := json.Unmarshal(data, &fw); err != nil {
+				return nil, err
+			}
+			for name, rs := range fw.Rulesets {
+				rs.Name = name
+			}
+			if fw.Groups != nil {
+				for name, g := range fw.Groups.Address {
+					g.Name = name
+				}
+				for name, g := range fw.Groups.Port {
+					g.Name = name
+				}
+			}
+			return &fw, nil
+		},
+	},
+	{
+		name: "interfaces",
+		get: func(c *Config) (interface{}, bool) {
+			return c.Interfaces, c.Interfaces != nil
+		},
+		set: func(c *Config, v interface{}) {
+			c.Interfaces = v.(*types.Interfaces)
+		},
+		decode: func(data []byte) (interface{}, error) {
+			var ifaces types.Interfaces
+			if err := json.Unmarshal(data, &ifaces); err != nil {
+				return nil, err
+			}
+			identify(&ifaces)
+			return &ifaces, nil
+		},
+	},
+}
+
+// identify sets the fields the API clients set when they read interfaces:
+// the ids, the parents of VLANs and PPPoE sessions, the interfaces of
+// WireGuard peers and the interfaces of firewall attachments.
+func identify(ifaces *types.Interfaces) {
+	attach := func(a *types.FirewallAttachment, path types.InterfacePath) {
+		if a != nil {
+			a.Interface = path.String()
+			a.Path = path
+		}
+	}
+	pppoes := func(parent types.InterfacePath, sessions map[string]*types.PPPoE) {
+		for id, p := range sessions {
+			if p == nil {
+				continue
+			}
+			p.ID = id
+			p.Parent = parent
+			attach(p.Firewall, p.Path())
+		}
+	}
+	vifs := func(parent types.InterfacePath, vlans map[string]*types.VIF) {
+		for id, v := range vlans {
+			if v == nil {
+				continue
+			}
+			v.ID = id
+			v.Parent = parent
+			attach(v.Firewall, v.Path())
+			pppoes(v.Path(), v.PPPoE)
+		}
+	}
+
+	for id, e := range ifaces.Ethernet {
+		path := types.InterfacePath{"ethernet", id}
+		e.ID = id
+		attach(e.Firewall, path)
+		vifs(path, e.VIF)
+		pppoes(path, e.PPPoE)
+	}
+	for id, l := range ifaces.Loopback {
+		l.ID = id
+	}
+	for id, b := range ifaces.Bridge {
+		path := types.InterfacePath{"bridge", id}
+		b.ID = id
+		b.Members = ifaces.BridgeMembers(id)
+		attach(b.Firewall, path)
+		vifs(path, b.VIF)
+	}
+	for id, b := range ifaces.Bonding {
+		path := types.InterfacePath{"bonding", id}
+		b.ID = id
+		b.Members = ifaces.BondingMembers(id)
+		attach(b.Firewall, path)
+		vifs(path, b.VIF)
+	}
+	for id, p := range ifaces.PseudoEthernet {
+		path := types.InterfacePath{"pseudo-ethernet", id}
+		p.ID = id
+		attach(p.Firewall, path)
+		vifs(path, p.VIF)
+	}
+	for id, s := range ifaces.Switch {
+		path := types.InterfacePath{"switch", id}
+		s.ID = id
+		for _, p := range s.Ports {
+			p.Switch = id
+		}
+		attach(s.Firewall, path)
+		vifs(path, s.VIF)
+	}
+	for id, o := range ifaces.OpenVPN {
+		o.ID = id
+		attach(o.Firewall, types.InterfacePath{"openvpn", id})
+	}
+	for id, v := range ifaces.VTI {
+		v.ID = id
+		attach(v.Firewall, types.InterfacePath{"vti", id})
+	}
+	for id, w := range ifaces.WireGuard {
+		w.ID = id
+		for _, p := range w.Peers {
+			if p != nil {
+				p.Interface = id
+			}
+		}
+		attach(w.Firewall, types.InterfacePath{"wireguard", id})
+	}
+}
+
+func lookupSection(name string) *section {
+	for _, s := range sections {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// Parse parses config.boot text.
+func Parse(r io.Reader) (*Config, error) {
+	nodes, footer, err := ParseNodes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Config{
+		Nodes:  []*Node{},
+		Footer: footer,
+		schema: newSchema(),
+		bases:  map[string]*base{},
+	}
+	c.schema.learn(nil, nodes)
+
+	for i, n := range nodes {
+		sec := lookupSection(n.Name)
+		if sec == nil || n.IsLeaf() || n.Tag != "" || c.bases[n.Name] != nil {
+			c.Nodes = append(c.Nodes, n)
+			continue
+		}
+
+		data, err := json.Marshal(c.schema.toJSON([]string{n.Name}, n.Children))
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := sec.decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode %s: %s", n.Name, err.Error())
+		}
+		sec.set(c, v)
+
+		// Encoding a typed value can normalize it, so the base is computed
+		// from a copy the caller never sees.
+		tmp, err := sec.decode(data)
+		if err != nil {
+			return nil, err
+		}
+		regen, snapshot, err := c.schema.fromTyped(sec, tmp)
+		if err != nil {
+			return nil, err
+		}
+		c.bases[n.Name] = &base{
+			node:     n,
+			regen:    regen,
+			snapshot: snapshot,
+			index:    i,
+			pos:      len(c.Nodes),
+		}
+	}
+
+	return c, nil
+}
+
+// Render writes the configuration in the config.boot format.
+func (c *Config) Render(w io.Writer) error {
+	nodes, err := c.nodes()
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	writeNodes(&b, nodes, 0)
+	writeComments(&b, c.Footer, "")
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+func (c *Config) String() string {
+	var b strings.Builder
+	if err := c.Render(&b); err != nil {
+		return err.Error()
+	}
+	return b.String()
+}
+
+// nodes returns the top level nodes, rendering the typed sections that
+// changed since they were parsed. Parsed sections keep their position among
+// Nodes and new ones come after them.
+func (c *Config) nodes() ([]*Node, error) {
+	s := c.schema
+	if s == nil {
+		s = newSchema()
+	}
+
+	var parsed []*base
+	var added []*Node
+
+	for _, sec := range sections {
+		v, ok := sec.get(c)
+		if !ok {
+			continue
+		}
+
+		regen, snapshot, err := s.fromTyped(sec, v)
+		if err != nil {
+			return nil, err
+		}
+
+		b := c.bases[sec.name]
+		switch {
+		case b == nil:
+			added = append(added, regen)
+		case bytes.Equal(snapshot, b.snapshot):
+			parsed = append(parsed, &base{node: b.node, index: b.index, pos: b.pos})
+		default:
+			merge(b.node, b.regen, regen)
+			parsed = append(parsed, &base{node: regen, index: b.index, pos: b.pos})
+		}
+	}
+
+	sort.Slice(parsed, func(i, j int) bool {
+		return parsed[i].index < parsed[j].index
+	})
+
+	nodes := make([]*Node, 0, len(c.Nodes)+len(parsed)+len(added))
+	for i, n := range c.Nodes {
+		for len(parsed) > 0 && parsed[0].pos <= i {
+			nodes = append(nodes, parsed[0].node)
+			parsed = parsed[1:]
+		}
+		nodes = append(nodes, n)
+	}
+	for _, b := range parsed {
+		nodes = append(nodes, b.node)
+	}
+
+	return append(nodes, added...), nil
+}
+
+// toJSON converts nodes into the JSON shape of the API, where valueless
+// leaves are null and tag nodes are objects keyed by their value.
+func (s *schema) toJSON(path []string, nodes []*Node) map[string]interface{} {
+	obj := map[string]interface{}{}
+
+	for _, n := range nodes {
+		switch {
+		case !n.IsLeaf() && n.Tag != "":
+			tags, ok := obj[n.Name].(map[string]interface{})
+			if !ok {
+				tags = map[string]interface{}{}
+				obj[n.Name] = tags
+			}
+			children := s.toJSON(append(append(append([]string{}, path...), n.Name), wildcard), n.Children)
+			if existing, ok := tags[n.Tag].(map[string]interface{}); ok {
+				for k, v := range children {
+					existing[k] = v
+				}
+				continue
+			}
+			tags[n.Tag] = children
+		case !n.IsLeaf():
+			children := s.toJSON(append(append([]string{}, path...), n.Name), n.Children)
+			if existing, ok := obj[n.Name].(map[string]interface{}); ok {
+				for k, v := range children {
+					existing[k] = v
+				}
+				continue
+			}
+			obj[n.Name] = children
+		case n.Value == nil:
+			obj[n.Name] = nil
+		case s.isMulti(path, n.Name):
+			vals, _ := obj[n.Name].([]interface{})
+			obj[n.Name] = append(vals, *n.Value)
+		default:
+			obj[n.Name] = *n.Value
+		}
+	}
+
+	return obj
+}
+
+// fromTyped renders a typed section as a node and returns the JSON encoding
+// it was rendered from.
+func (s *schema) fromTyped(sec *section, v interface{}) (*Node, []byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	a, err := decodeJSON(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The types encode absent pointers as null, which is also how a valueless
+	// leaf is encoded. Decoding without the nulls and encoding again tells
+	// them apart: a null that comes back was put there by the encoder.
+	stripped, err := json.Marshal(stripNulls(a))
+	if err != nil {
+		return nil, nil, err
+	}
+	tmp, err := sec.decode(stripped)
+	if err != nil {
+		return nil, nil, err
+	}
+	again, err := json.Marshal(tmp)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := decodeJSON(again)
+	if err != nil {
+		return nil, nil, err
+	}
+	prune(a, b)
+
+	obj, _ := a.(map[string]interface{})
+
+	return &Node{
+		Name:     sec.name,
+		Children: s.fromJSON([]string{sec.name}, obj),
+	}, data, nil
+}
+
+func (s *schema) fromJSON(path []string, obj map[string]interface{}) []*Node {
+	nodes := []*Node{}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		p := append(append([]string{}, path...), k)
+
+		switch v := obj[k].(type) {
+		case nil:
+			nodes = append(nodes, &Node{Name: k})
+		case string:
+			val := v
+			nodes = append(nodes, &Node{Name: k, Value: &val})
+		case json.Number:
+			val := v.String()
+			nodes = append(nodes, &Node{Name: k, Value: &val})
+		case bool:
+			val := strconv.FormatBool(v)
+			nodes = append(nodes, &Node{Name: k, Value: &val})
+		case []interface{}:
+			for _, item := range v {
+				if val, ok := item.(string); ok {
+					nodes = append(nodes, &Node{Name: k, Value: &val})
+				}
+			}
+		case map[string]interface{}:
+			if !s.isTag(path, k) {
+				nodes = append(nodes, &Node{Name: k, Children: s.fromJSON(p, v)})
+				continue
+			}
+
+			tags := make([]string, 0, len(v))
+			for tag := range v {
+				tags = append(tags, tag)
+			}
+			sort.Slice(tags, func(i, j int) bool {
+				return lessTag(tags[i], tags[j])
+			})
+
+			for _, tag := range tags {
+				children, _ := v[tag].(map[string]interface{})
+				nodes = append(nodes, &Node{
+					Name:     k,
+					Tag:      tag,
+					Children: s.fromJSON(append(p, wildcard), children),
+				})
+			}
+		}
+	}
+
+	return nodes
+}
+
+// lessTag orders tag values the way EdgeOS does, numerically when both are
+// numbers, e.g. rule 9 before rule 10.
+func lessTag(a, b string) bool {
+	i, errA := strconv.Atoi(a)
+	j, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		return i < j
+	}
+	return a < b
+}
+
+func decodeJSON(data []byte) (interface{}, error) {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func stripNulls(v interface{}) interface{} {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	tmp := map[string]interface{}{}
+	for k, child := range obj {
+		if child != nil {
+			tmp[k] = stripNulls(child)
+		}
+	}
+	return tmp
+}
+
+// prune removes the nulls of a that b also has, along with empty values that
+// config.boot has no way to express.
+func prune(a, b interface{}) {
+	obj, ok := a.(map[string]interface{})
+	if !ok {
+		return
+	}
+	other, _ := b.(map[string]interface{})
+
+	for k, v := range obj {
+		switch tmp := v.(type) {
+		case nil:
+			if o, ok := other[k]; ok && o == nil {
+				delete(obj, k)
+			}
+		case string:
+			if tmp == "" {
+				delete(obj, k)
+			}
+		case []interface{}:
+			if len(tmp) == 0 {
+				delete(obj, k)
+			}
+		case map[string]interface{}:
+			prune(tmp, other[k])
+		}
+	}
+}
+
+// merge copies the comments and quoting of the original node onto the
+// rendered one, along with the descendants of the original that the types do
+// not model. Those are the ones the original has but its own rendering, regen,
+// does not. Children keep the order of the original, and children it does not
+// have follow the rendered sibling they come after.
+func merge(original, regen, rendered *Node) {
+	rendered.Comments = original.Comments
+	rendered.Trailing = original.Trailing
+
+	if sameToken(original, rendered) {
+		rendered.Quoted = original.Quoted
+	}
+
+	if original.IsLeaf() || rendered.IsLeaf() {
+		return
+	}
+
+	var regenChildren []*Node
+	if regen != nil {
+		regenChildren = regen.Children
+	}
+
+	// matched holds the rendered children of each original child and added
+	// the rendered children without one, keyed by the last matched rendered
+	// child before them.
+	matched := map[*Node][]*Node{}
+	added := map[*Node][]*Node{}
+	var last *Node
+
+	for _, n := range rendered.Children {
+		m := find(original.Children, n, true)
+		if m == nil {
+			m = find(original.Children, n, false)
+		}
+		if m == nil {
+			added[last] = append(added[last], n)
+			continue
+		}
+		merge(m, find(regenChildren, m, true), n)
+		matched[m] = append(matched[m], n)
+		last = n
+	}
+
+	children := append([]*Node{}, added[nil]...)
+	for _, n := range original.Children {
+		if find(regenChildren, n, true) == nil && find(rendered.Children, n, true) == nil {
+			children = append(children, n)
+		}
+		for _, m := range matched[n] {
+			children = append(children, m)
+			children = append(children, added[m]...)
+		}
+	}
+	rendered.Children = children
+}
+
+// find returns the node of nodes that is the same as n. When exact is false,
+// leaves only need to share a name, which is how a changed value is matched
+// with the original.
+func find(nodes []*Node, n *Node, exact bool) *Node {
+	for _, m := range nodes {
+		if m.Name != n.Name || m.Tag != n.Tag || m.IsLeaf() != n.IsLeaf() {
+			continue
+		}
+		if exact && !sameToken(m, n) {
+			continue
+		}
+		return m
+	}
+	return nil
+}
+
+func sameToken(a, b *Node) bool {
+	if a.Tag != b.Tag {
+		return false
+	}
+	if a.Value == nil || b.Value == nil {
+		return a.Value == nil && b.Value == nil
+	}
+	return *a.Value == *b.Value
+}
@@ -0,0 +1,526 @@
+package configboot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const configBoot = `firewall {
+    all-ping enable
+    broadcast-ping disable
+    group {
+        address-group servers {
+            address 192.168.1.10
+            address 192.168.1.11
+            description "web servers"
+        }
+        port-group web {
+            port 80
+            port 443
+        }
+    }
+    /* traffic from the internet */
+    name WAN_IN {
+        default-action drop
+        description "WAN to internal"
+        enable-default-log
+        rule 10 {
+            action accept
+            description "allow established"
+            log disable
+            state {
+                established enable
+                invalid disable
+                new disable
+                related enable
+            }
+        }
+        rule 20 {
+            action accept
+            destination {
+                group {
+                    address-group servers
+                    port-group web
+                }
+            }
+            log enable
+            protocol tcp
+        }
+    }
+}
+interfaces {
+    bridge br0 {
+        address 10.0.0.1/24
+        aging 300
+        description Bridge
+        hello-time 2
+        max-age 20
+        priority 0
+        promiscuous disable
+        stp false
+    }
+    ethernet eth0 {
+        address dhcp
+        description Internet
+        dhcp-options {
+            default-route update
+            name-server no-update
+        }
+        duplex auto
+        firewall {
+            in {
+                name WAN_IN
+            }
+        }
+        poe {
+            output off
+        }
+        speed auto
+    }
+    ethernet eth1 {
+        address 192.168.1.1/24
+        description "Local"
+        duplex auto
+        speed auto
+    }
+    ethernet eth2 {
+        bridge-group {
+            bridge br0
+        }
+        duplex auto
+        speed auto
+    }
+    loopback lo {
+    }
+}
+service {
+    gui {
+        http-port 80
+        https-port 443
+    }
+    ssh {
+        port 22
+    }
+}
+system {
+    host-name ubnt
+    login {
+        user ubnt {
+            authentication {
+                encrypted-password $5$abc$def
+            }
+            level admin
+        }
+    }
+}
+/* Warning: Do not remove the following line. */
+/* === vyatta-config-version: "config-management@1:conntrack@1:firewall@5:system@5" === */
+/* Release version: v2.0.9-hotfix.4.5521907.220630.0658 */
+`
+
+func TestParse(t *testing.T) {
+	c, err := Parse(strings.NewReader(configBoot))
+	require.NoError(t, err)
+
+	require.NotNil(t, c.Firewall)
+	rs := c.Firewall.Rulesets["WAN_IN"]
+	require.NotNil(t, rs)
+	assert.Equal(t, "WAN_IN", rs.Name)
+	assert.Equal(t, "drop", rs.DefaultAction)
+	assert.Equal(t, "WAN to internal", *rs.Description)
+	assert.True(t, *rs.DefaultLogging)
+	require.Len(t, rs.Rules, 2)
+
+	rs.SortRules()
+	assert.Equal(t, 10, rs.Rules[0].Priority)
+	assert.True(t, *rs.Rules[0].State.Established)
+	assert.Equal(t, "tcp", rs.Rules[1].Protocol)
+	assert.Equal(t, "servers", *rs.Rules[1].Destination.AddressGroup)
+	assert.Equal(t, "web", *rs.Rules[1].Destination.PortGroup)
+
+	assert.Equal(t, []string{"192.168.1.10", "192.168.1.11"}, c.Firewall.Groups.Address["servers"].Cidrs)
+	assert.Equal(t, []int{80, 443}, c.Firewall.Groups.Port["web"].Ports)
+
+	require.NotNil(t, c.Interfaces)
+	eth0 := c.Interfaces.Ethernet["eth0"]
+	require.NotNil(t, eth0)
+	assert.Equal(t, []string{"dhcp"}, eth0.Addresses)
+	assert.Equal(t, "update", eth0.DHCPOptions.DefaultRoute)
+	assert.Equal(t, "WAN_IN", *eth0.Firewall.In)
+	assert.Equal(t, "ethernet eth0", eth0.Firewall.Interface)
+	assert.Equal(t, "Local", c.Interfaces.Ethernet["eth1"].Description)
+
+	br0 := c.Interfaces.Bridge["br0"]
+	require.NotNil(t, br0)
+	assert.Equal(t, "br0", br0.ID)
+	assert.Equal(t, 300, br0.Aging)
+	assert.False(t, *br0.STP)
+	assert.Equal(t, []string{"eth2"}, c.Interfaces.BridgeMembers("br0"))
+	assert.Contains(t, c.Interfaces.Loopback, "lo")
+
+	require.Len(t, c.Nodes, 2)
+	assert.Equal(t, "service", c.Nodes[0].Name)
+	assert.Equal(t, []string{"22"}, c.Nodes[0].Child("ssh").Values("port"))
+	assert.Equal(t, "admin", *c.Nodes[1].Child("login").Child("user", "ubnt").Child("level").Value)
+
+	require.Len(t, c.Footer, 3)
+	assert.Equal(t, " Release version: v2.0.9-hotfix.4.5521907.220630.0658 ", c.Footer[2])
+}
+
+func TestRenderUnchanged(t *testing.T) {
+	c, err := Parse(strings.NewReader(configBoot))
+	require.NoError(t, err)
+	assert.Equal(t, configBoot, c.String())
+}
+
+func TestRenderChanged(t *testing.T) {
+	c, err := Parse(strings.NewReader(configBoot))
+	require.NoError(t, err)
+
+	description := "internet to internal"
+	rs := c.Firewall.Rulesets["WAN_IN"]
+	rs.Description = &description
+	rs.DefaultLogging = nil
+	rs.Rules = rs.Rules[:0]
+	rs.Rules = append(rs.Rules, &types.Rule{
+		Priority: 5,
+		Action:   "drop",
+		State: &types.State{
+			Invalid: boolptr(true),
+		},
+	})
+	delete(c.Firewall.Groups.Port, "web")
+
+	c.Interfaces.Ethernet["eth0"].Description = "Internet (PPPoE)"
+	c.Interfaces.Ethernet["eth1"].Addresses = append(c.Interfaces.Ethernet["eth1"].Addresses, "192.168.2.1/24")
+	c.Interfaces.Bridge["br0"].Aging = 600
+
+	expected := `firewall {
+    all-ping enable
+    broadcast-ping disable
+    group {
+        address-group servers {
+            address 192.168.1.10
+            address 192.168.1.11
+            description "web servers"
+        }
+    }
+    /* traffic from the internet */
+    name WAN_IN {
+        default-action drop
+        description "internet to internal"
+        rule 5 {
+            action drop
+            log disable
+            state {
+                established disable
+                invalid enable
+                new disable
+                related disable
+            }
+        }
+    }
+}
+interfaces {
+    bridge br0 {
+        address 10.0.0.1/24
+        aging 600
+        description Bridge
+        hello-time 2
+        max-age 20
+        priority 0
+        promiscuous disable
+        stp false
+    }
+    ethernet eth0 {
+        address dhcp
+        description "Internet (PPPoE)"
+        dhcp-options {
+            default-route update
+            name-server no-update
+        }
+        duplex auto
+        firewall {
+            in {
+                name WAN_IN
+            }
+        }
+        poe {
+            output off
+        }
+        speed auto
+    }
+    ethernet eth1 {
+        address 192.168.1.1/24
+        address 192.168.2.1/24
+        description "Local"
+        duplex auto
+        speed auto
+    }
+    ethernet eth2 {
+        bridge-group {
+            bridge br0
+        }
+        duplex auto
+        speed auto
+    }
+    loopback lo {
+    }
+}
+service {
+    gui {
+        http-port 80
+        https-port 443
+    }
+    ssh {
+        port 22
+    }
+}
+system {
+    host-name ubnt
+    login {
+        user ubnt {
+            authentication {
+                encrypted-password $5$abc$def
+            }
+            level admin
+        }
+    }
+}
+/* Warning: Do not remove the following line. */
+/* === vyatta-config-version: "config-management@1:conntrack@1:firewall@5:system@5" === */
+/* Release version: v2.0.9-hotfix.4.5521907.220630.0658 */
+`
+	assert.Equal(t, expected, c.String())
+}
+
+func TestRenderNew(t *testing.T) {
+	in := "WAN_IN"
+	c := &Config{
+		Interfaces: &types.Interfaces{
+			Ethernet: map[string]*types.Ethernet{
+				"eth0": {
+					Addresses: []string{"dhcp"},
+					Firewall: &types.FirewallAttachment{
+						In: &in,
+					},
+				},
+			},
+		},
+	}
+
+	expected := `interfaces {
+    ethernet eth0 {
+        address dhcp
+        firewall {
+            in {
+                name WAN_IN
+            }
+        }
+    }
+}
+`
+	assert.Equal(t, expected, c.String())
+}
+
+func TestParseIdentities(t *testing.T) {
+	c, err := Parse(strings.NewReader(`interfaces {
+    bonding bond0 {
+        vif 30 {
+            firewall {
+                local {
+                    name LAN_LOCAL
+                }
+            }
+        }
+    }
+    ethernet eth0 {
+        pppoe 0 {
+            firewall {
+                in {
+                    name WAN_IN
+                }
+            }
+            user-id user
+        }
+        vif 20 {
+            pppoe 1 {
+                user-id user
+            }
+        }
+    }
+    wireguard wg0 {
+        firewall {
+            local {
+                name WG_LOCAL
+            }
+        }
+        peer abc= {
+            allowed-ips 10.0.0.2/32
+        }
+    }
+}
+`))
+	require.NoError(t, err)
+
+	pppoe := c.Interfaces.Ethernet["eth0"].PPPoE["0"]
+	assert.Equal(t, "0", pppoe.ID)
+	assert.Equal(t, types.InterfacePath{"ethernet", "eth0"}, pppoe.Parent)
+	assert.Equal(t, "ethernet eth0 pppoe 0", pppoe.Firewall.Interface)
+	assert.Equal(t, pppoe.Path(), pppoe.Firewall.Path)
+
+	vif := c.Interfaces.Ethernet["eth0"].VIF["20"]
+	assert.Equal(t, "20", vif.ID)
+	assert.Equal(t, types.InterfacePath{"ethernet", "eth0"}, vif.Parent)
+	assert.Equal(t, "1", vif.PPPoE["1"].ID)
+	assert.Equal(t, types.InterfacePath{"ethernet", "eth0", "vif", "20"}, vif.PPPoE["1"].Parent)
+
+	vif = c.Interfaces.Bonding["bond0"].VIF["30"]
+	assert.Equal(t, "30", vif.ID)
+	assert.Equal(t, types.InterfacePath{"bonding", "bond0"}, vif.Parent)
+	assert.Equal(t, "bonding bond0 vif 30", vif.Firewall.Interface)
+
+	wg := c.Interfaces.WireGuard["wg0"]
+	assert.Equal(t, "wg0", wg.ID)
+	assert.Equal(t, "wg0", wg.Peers["abc="].Interface)
+	assert.Equal(t, "abc=", wg.Peers["abc="].PublicKey)
+	assert.Equal(t, "wireguard wg0", wg.Firewall.Interface)
+
+	assert.Len(t, c.Interfaces.Attachments(), 3)
+}
+
+func TestRenderKeepsOrder(t *testing.T) {
+	in := `system {
+    host-name ubnt
+}
+interfaces {
+    loopback lo {
+    }
+    ethernet eth0 {
+        speed auto
+        description Internet
+        duplex auto
+    }
+}
+firewall {
+    name WAN_IN {
+        rule 20 {
+            action accept
+            log disable
+        }
+        default-action drop
+        rule 10 {
+            action accept
+            log disable
+        }
+    }
+}
+service {
+    ssh {
+        port 22
+    }
+}
+`
+	c, err := Parse(strings.NewReader(in))
+	require.NoError(t, err)
+	assert.Equal(t, in, c.String())
+
+	c.Interfaces.Ethernet["eth0"].Description = "WAN"
+	rs := c.Firewall.Rulesets["WAN_IN"]
+	rs.Rules = append(rs.Rules, &types.Rule{
+		Priority: 15,
+		Action:   "drop",
+	})
+
+	expected := `system {
+    host-name ubnt
+}
+interfaces {
+    loopback lo {
+    }
+    ethernet eth0 {
+        speed auto
+        description WAN
+        duplex auto
+    }
+}
+firewall {
+    name WAN_IN {
+        rule 20 {
+            action accept
+            log disable
+        }
+        default-action drop
+        rule 10 {
+            action accept
+            log disable
+        }
+        rule 15 {
+            action drop
+            log disable
+        }
+    }
+}
+service {
+    ssh {
+        port 22
+    }
+}
+`
+	assert.Equal(t, expected, c.String())
+}
+
+func TestParseNodes(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "quoted value with escapes",
+			input: "system {\n    login {\n        banner {\n            pre-login \"say \\\"hi\\\"\"\n        }\n    }\n}\n",
+		},
+		{
+			name:  "missing closing brace",
+			input: "system {\n    host-name ubnt\n",
+			err:   "line 3: missing closing brace",
+		},
+		{
+			name:  "unexpected closing brace",
+			input: "system {\n}\n}\n",
+			err:   "line 3: unexpected closing brace",
+		},
+		{
+			name:  "unterminated comment",
+			input: "/* comment\nsystem {\n}\n",
+			err:   "line 1: unterminated comment",
+		},
+		{
+			name:  "too many values",
+			input: "system {\n    host-name a b\n}\n",
+			err:   `line 2: unexpected "b" after host-name`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			nodes, footer, err := ParseNodes(strings.NewReader(test.input))
+			if test.err != "" {
+				require.Error(t, err)
+				assert.Equal(t, test.err, err.Error())
+				return
+			}
+			require.NoError(t, err)
+
+			c := &Config{Nodes: nodes, Footer: footer}
+			assert.Equal(t, test.input, c.String())
+		})
+	}
+}
+
+func boolptr(b bool) *bool {
+	return &b
+}
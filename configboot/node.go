@@ -0,0 +1,313 @@
+package configboot
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const indentation = "    "
+
+// Node is a node of the configuration tree. A node is a leaf when Children is
+// nil and a container otherwise, so "loopback lo { }" is an empty container
+// rather than a leaf.
+type Node struct {
+	// Comments are the comments directly above the node, without the /* and
+	// */ delimiters.
+	Comments []string
+	Name     string
+	// Tag is the value of a tag node, e.g. WAN_IN for "name WAN_IN { ... }".
+	Tag string
+	// Value is the value of a leaf. It is nil for valueless leaves such as
+	// "enable-default-log".
+	Value *string
+	// Quoted is whether the tag or value was written in double quotes even
+	// though it did not need to be.
+	Quoted   bool
+	Children []*Node
+	// Trailing are the comments after the last child of a container.
+	Trailing []string
+}
+
+// IsLeaf reports whether the node holds a value rather than other nodes.
+func (n *Node) IsLeaf() bool {
+	return n.Children == nil
+}
+
+// Child returns the first child with the given name and, for tag nodes, the
+// given tag.
+func (n *Node) Child(name string, tag ...string) *Node {
+	for _, child := range n.Children {
+		if child.Name != name {
+			continue
+		}
+		if len(tag) > 0 && child.Tag != tag[0] {
+			continue
+		}
+		return child
+	}
+	return nil
+}
+
+// Values returns the values of every leaf child with the given name.
+func (n *Node) Values(name string) []string {
+	var vals []string
+	for _, child := range n.Children {
+		if child.Name == name && child.Value != nil {
+			vals = append(vals, *child.Value)
+		}
+	}
+	return vals
+}
+
+func (n *Node) String() string {
+	var b strings.Builder
+	writeNodes(&b, []*Node{n}, 0)
+	return b.String()
+}
+
+func writeNodes(w *strings.Builder, nodes []*Node, depth int) {
+	indent := strings.Repeat(indentation, depth)
+
+	for _, n := range nodes {
+		writeComments(w, n.Comments, indent)
+
+		w.WriteString(indent)
+		w.WriteString(n.Name)
+		if n.Tag != "" {
+			w.WriteString(" ")
+			w.WriteString(quote(n.Tag, n.Quoted))
+		}
+		if n.Value != nil {
+			w.WriteString(" ")
+			w.WriteString(quote(*n.Value, n.Quoted))
+		}
+
+		if n.IsLeaf() {
+			w.WriteString("\n")
+			continue
+		}
+
+		w.WriteString(" {\n")
+		writeNodes(w, n.Children, depth+1)
+		writeComments(w, n.Trailing, indent+indentation)
+		w.WriteString(indent)
+		w.WriteString("}\n")
+	}
+}
+
+func writeComments(w *strings.Builder, comments []string, indent string) {
+	for _, c := range comments {
+		w.WriteString(indent)
+		w.WriteString("/*")
+		w.WriteString(c)
+		w.WriteString("*/\n")
+	}
+}
+
+// quote quotes values the way EdgeOS does, which is only when they contain
+// whitespace or characters that are part of the syntax.
+func quote(val string, force bool) string {
+	if !force && val != "" && !strings.ContainsAny(val, " \t\n\"'{};#\\") && !strings.HasPrefix(val, "/*") {
+		return val
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(val) + `"`
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNewline
+	tokenWord
+	tokenString
+	tokenOpen
+	tokenClose
+	tokenComment
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	line int
+}
+
+type tokenizer struct {
+	src    string
+	pos    int
+	line   int
+	peeked *token
+}
+
+func (t *tokenizer) peek() (token, error) {
+	if t.peeked == nil {
+		tok, err := t.scan()
+		if err != nil {
+			return tok, err
+		}
+		t.peeked = &tok
+	}
+	return *t.peeked, nil
+}
+
+func (t *tokenizer) next() (token, error) {
+	tok, err := t.peek()
+	t.peeked = nil
+	return tok, err
+}
+
+func (t *tokenizer) scan() (token, error) {
+	for t.pos < len(t.src) && (t.src[t.pos] == ' ' || t.src[t.pos] == '\t' || t.src[t.pos] == '\r') {
+		t.pos++
+	}
+
+	if t.pos >= len(t.src) {
+		return token{kind: tokenEOF, line: t.line}, nil
+	}
+
+	start, line := t.pos, t.line
+
+	switch c := t.src[t.pos]; {
+	case c == '\n':
+		t.pos++
+		t.line++
+		return token{kind: tokenNewline, line: line}, nil
+	case c == '{':
+		t.pos++
+		return token{kind: tokenOpen, text: "{", line: line}, nil
+	case c == '}':
+		t.pos++
+		return token{kind: tokenClose, text: "}", line: line}, nil
+	case strings.HasPrefix(t.src[t.pos:], "/*"):
+		end := strings.Index(t.src[t.pos+2:], "*/")
+		if end < 0 {
+			return token{}, fmt.Errorf("line %d: unterminated comment", line)
+		}
+		text := t.src[t.pos+2 : t.pos+2+end]
+		t.pos += end + 4
+		t.line += strings.Count(text, "\n")
+		return token{kind: tokenComment, text: text, line: line}, nil
+	case c == '"':
+		var b strings.Builder
+		for t.pos++; t.pos < len(t.src); t.pos++ {
+			switch t.src[t.pos] {
+			case '\\':
+				if t.pos+1 < len(t.src) {
+					t.pos++
+				}
+			case '"':
+				t.pos++
+				return token{kind: tokenString, text: b.String(), line: line}, nil
+			case '\n':
+				t.line++
+			}
+			b.WriteByte(t.src[t.pos])
+		}
+		return token{}, fmt.Errorf("line %d: unterminated string", line)
+	default:
+		for t.pos < len(t.src) && !strings.ContainsRune(" \t\r\n{}\"", rune(t.src[t.pos])) {
+			t.pos++
+		}
+		return token{kind: tokenWord, text: t.src[start:t.pos], line: line}, nil
+	}
+}
+
+// ParseNodes parses configuration text into a tree of nodes. Comments that
+// follow the last top level node, such as the version footer, are returned
+// separately.
+func ParseNodes(r io.Reader) (nodes []*Node, footer []string, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t := &tokenizer{src: string(data), line: 1}
+
+	nodes, footer, err = parseNodes(t, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nodes, footer, nil
+}
+
+// parseNodes parses nodes until the end of input or, when nested, the closing
+// brace of the enclosing container.
+func parseNodes(t *tokenizer, nested bool) ([]*Node, []string, error) {
+	nodes := []*Node{}
+	var comments []string
+
+	for {
+		tok, err := t.next()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch tok.kind {
+		case tokenNewline:
+			continue
+		case tokenComment:
+			comments = append(comments, tok.text)
+			continue
+		case tokenEOF:
+			if nested {
+				return nil, nil, fmt.Errorf("line %d: missing closing brace", tok.line)
+			}
+			return nodes, comments, nil
+		case tokenClose:
+			if !nested {
+				return nil, nil, fmt.Errorf("line %d: unexpected closing brace", tok.line)
+			}
+			return nodes, comments, nil
+		case tokenWord:
+		default:
+			return nil, nil, fmt.Errorf("line %d: expected a node name but found %q", tok.line, tok.text)
+		}
+
+		n, err := parseNode(t, tok.text)
+		if err != nil {
+			return nil, nil, err
+		}
+		n.Comments = comments
+		comments = nil
+		nodes = append(nodes, n)
+	}
+}
+
+func parseNode(t *tokenizer, name string) (*Node, error) {
+	n := &Node{Name: name}
+
+	tok, err := t.peek()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok.kind == tokenWord || tok.kind == tokenString {
+		t.next()
+		val := tok.text
+		n.Value = &val
+		n.Quoted = tok.kind == tokenString && quote(val, false) == val
+
+		if tok, err = t.peek(); err != nil {
+			return nil, err
+		}
+	}
+
+	switch tok.kind {
+	case tokenOpen:
+		t.next()
+		if n.Value != nil {
+			n.Tag, n.Value = *n.Value, nil
+		}
+		children, trailing, err := parseNodes(t, true)
+		if err != nil {
+			return nil, err
+		}
+		n.Children = children
+		n.Trailing = trailing
+	case tokenNewline, tokenEOF, tokenClose, tokenComment:
+	default:
+		return nil, fmt.Errorf("line %d: unexpected %q after %s", tok.line, tok.text, name)
+	}
+
+	return n, nil
+}
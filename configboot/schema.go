@@ -0,0 +1,123 @@
+package configboot
+
+import "strings"
+
+// wildcard stands in for the value of a tag node in a schema path.
+const wildcard = "*"
+
+// tagNodes are the paths of the tag nodes of the typed sections. The children
+// of a tag node are keyed by value in JSON, e.g. {"name": {"WAN_IN": {...}}},
+// which cannot be told apart from a container without knowing the schema.
+var tagNodes = []string{
+	"firewall group address-group",
+	"firewall group network-group",
+	"firewall group port-group",
+	"firewall ipv6-name",
+	"firewall ipv6-name * rule",
+	"firewall name",
+	"firewall name * rule",
+	"interfaces bonding",
+	"interfaces bonding * vif",
+	"interfaces bridge",
+	"interfaces bridge * vif",
+	"interfaces ethernet",
+	"interfaces ethernet * pppoe",
+	"interfaces ethernet * vif",
+	"interfaces ethernet * vif * pppoe",
+	"interfaces loopback",
+	"interfaces openvpn",
+	"interfaces openvpn * local-address",
+	"interfaces pseudo-ethernet",
+	"interfaces pseudo-ethernet * vif",
+	"interfaces switch",
+	"interfaces switch * switch-port interface",
+	"interfaces switch * vif",
+	"interfaces vti",
+	"interfaces wireguard",
+	"interfaces wireguard * peer",
+}
+
+// multiNodes are the paths of the leaves of the typed sections that may hold
+// more than one value and are therefore arrays in JSON even when only one
+// value is present.
+var multiNodes = []string{
+	"firewall group address-group * address",
+	"firewall group network-group * network",
+	"firewall group port-group * port",
+	"interfaces bonding * address",
+	"interfaces bonding * vif * address",
+	"interfaces bridge * address",
+	"interfaces bridge * vif * address",
+	"interfaces ethernet * address",
+	"interfaces ethernet * vif * address",
+	"interfaces loopback * address",
+	"interfaces openvpn * openvpn-option",
+	"interfaces openvpn * remote-host",
+	"interfaces pseudo-ethernet * address",
+	"interfaces pseudo-ethernet * vif * address",
+	"interfaces switch * address",
+	"interfaces switch * switch-port interface * vlan vid",
+	"interfaces switch * vif * address",
+	"interfaces vti * address",
+	"interfaces wireguard * address",
+	"interfaces wireguard * peer * allowed-ips",
+}
+
+type schema struct {
+	tags  map[string]bool
+	multi map[string]bool
+}
+
+func newSchema() *schema {
+	s := &schema{
+		tags:  map[string]bool{},
+		multi: map[string]bool{},
+	}
+	for _, p := range tagNodes {
+		s.tags[p] = true
+	}
+	for _, p := range multiNodes {
+		s.multi[p] = true
+	}
+	return s
+}
+
+// learn adds the tag nodes and repeated leaves found in a parsed tree so that
+// nodes outside of the built in tables survive the trip through JSON.
+func (s *schema) learn(path []string, nodes []*Node) {
+	counts := map[string]int{}
+
+	for _, n := range nodes {
+		p := join(path, n.Name)
+
+		if n.IsLeaf() {
+			if counts[n.Name]++; counts[n.Name] > 1 {
+				s.multi[p] = true
+			}
+			continue
+		}
+
+		if n.Tag != "" {
+			s.tags[p] = true
+			s.learn(append(split(p), wildcard), n.Children)
+			continue
+		}
+		s.learn(split(p), n.Children)
+	}
+}
+
+func (s *schema) isTag(path []string, name string) bool {
+	return s.tags[join(path, name)]
+}
+
+func (s *schema) isMulti(path []string, name string) bool {
+	return s.multi[join(path, name)]
+}
+
+func join(path []string, name string) string {
+	return strings.Join(append(append([]string{}, path...), name), " ")
+}
+
+func split(p string) []string {
+	return strings.Split(p, " ")
+}
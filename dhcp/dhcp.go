@@ -0,0 +1,473 @@
+// Package dhcp manages the shared networks, subnets and static mappings of the
+// DHCP server and reads its leases.
+package dhcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+type Client interface {
+	GetSharedNetwork(context.Context, string) (*types.SharedNetwork, error)
+	CreateSharedNetwork(context.Context, *types.SharedNetwork) (*types.SharedNetwork, error)
+	UpdateSharedNetwork(context.Context, *types.SharedNetwork, []jsonpatch.JsonPatchOperation) (*types.SharedNetwork, error)
+	DeleteSharedNetwork(context.Context, string) error
+
+	GetSubnet(context.Context, string, string) (*types.DHCPSubnet, error)
+	CreateSubnet(context.Context, string, *types.DHCPSubnet) (*types.DHCPSubnet, error)
+	UpdateSubnet(context.Context, *types.DHCPSubnet, []jsonpatch.JsonPatchOperation) (*types.DHCPSubnet, error)
+	DeleteSubnet(context.Context, string, string) error
+
+	GetStaticMapping(context.Context, string, string, string) (*types.StaticMapping, error)
+	ListStaticMappings(context.Context, string, string) ([]*types.StaticMapping, error)
+	CreateStaticMapping(context.Context, *types.StaticMapping) (*types.StaticMapping, error)
+	UpdateStaticMapping(context.Context, *types.StaticMapping, []jsonpatch.JsonPatchOperation) (*types.StaticMapping, error)
+	DeleteStaticMapping(context.Context, string, string, string) error
+
+	Leases(context.Context) ([]*types.DHCPLease, error)
+	Stats(context.Context) ([]*types.DHCPPoolStats, error)
+	ReserveLease(context.Context, *types.DHCPLease, string) (*types.StaticMapping, error)
+}
+
+type client struct {
+	apiClient  api.Client
+	dataClient api.DataClient
+}
+
+func New(httpClient *http.Client, host string) Client {
+	return NewWithAPIClient(api.New(httpClient, host))
+}
+
+// NewWithAPIClient is used by clients that manage DHCP config on behalf of
+// their own resources. Leases and stats are read through the API client if it
+// is also a api.DataClient.
+func NewWithAPIClient(apiClient api.Client) Client {
+	dataClient, _ := apiClient.(api.DataClient)
+	return &client{
+		apiClient:  apiClient,
+		dataClient: dataClient,
+	}
+}
+
+func (c *client) GetSharedNetwork(ctx context.Context, name string) (*types.SharedNetwork, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toSharedNetwork(name, op)
+}
+
+// CreateSharedNetwork creates the shared network along with its subnets and
+// their static mappings.
+func (c *client) CreateSharedNetwork(ctx context.Context, n *types.SharedNetwork) (*types.SharedNetwork, error) {
+	if err := n.Validate(); err != nil {
+		return nil, err
+	}
+
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := toSharedNetwork(n.Name, op); err == nil {
+		return nil, fmt.Errorf("The DHCP shared network %s already exists.", n.Name)
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Service: sharedNetworkOf(n.Name, n),
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.GetSharedNetwork(ctx, n.Name)
+}
+
+// UpdateSharedNetwork applies the patches to the properties of the current
+// shared network, setting the ones that changed and deleting the ones that
+// were removed in a single commit. Subnets are changed with the subnet
+// methods.
+func (c *client) UpdateSharedNetwork(ctx context.Context, current *types.SharedNetwork, patches []jsonpatch.JsonPatchOperation) (*types.SharedNetwork, error) {
+	var n types.SharedNetwork
+	if err := utils.Patch(current.Properties(), &n, patches); err != nil {
+		return nil, err
+	}
+	n.Name = current.Name
+
+	if err := n.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := new(api.Operation)
+
+	if !n.IsEmpty() {
+		in.Set = &api.Set{
+			Resources: api.Resources{
+				Service: sharedNetworkOf(n.Name, n.Properties()),
+			},
+		}
+	}
+
+	if stale := staleSharedNetwork(current, &n); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Service: sharedNetworkOf(n.Name, stale),
+			},
+		}
+	}
+
+	if in.Set != nil || in.Delete != nil {
+		if _, err := c.apiClient.Post(ctx, in); err != nil {
+			return nil, err
+		}
+	}
+	return c.GetSharedNetwork(ctx, n.Name)
+}
+
+// DeleteSharedNetwork removes the shared network along with its subnets and
+// their static mappings.
+func (c *client) DeleteSharedNetwork(ctx context.Context, name string) error {
+	if _, err := c.GetSharedNetwork(ctx, name); err != nil {
+		return err
+	}
+
+	_, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Service: sharedNetworkOf(name, nil),
+			},
+		},
+	})
+	return err
+}
+
+func (c *client) GetSubnet(ctx context.Context, network, subnet string) (*types.DHCPSubnet, error) {
+	n, err := c.GetSharedNetwork(ctx, network)
+	if err != nil {
+		return nil, err
+	}
+	return toSubnet(n, subnet)
+}
+
+// CreateSubnet adds the subnet along with its static mappings to the shared
+// network.
+func (c *client) CreateSubnet(ctx context.Context, network string, s *types.DHCPSubnet) (*types.DHCPSubnet, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	n, err := c.GetSharedNetwork(ctx, network)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := n.Subnets[s.Subnet]; ok {
+		return nil, fmt.Errorf("The subnet %s of DHCP shared network %s already exists.", s.Subnet, network)
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Service: subnetOf(network, s.Subnet, s),
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.GetSubnet(ctx, network, s.Subnet)
+}
+
+// UpdateSubnet applies the patches to the properties of the current subnet,
+// setting the ones that changed and deleting the ones that were removed in a
+// single commit. Static mappings are changed with the static mapping methods.
+func (c *client) UpdateSubnet(ctx context.Context, current *types.DHCPSubnet, patches []jsonpatch.JsonPatchOperation) (*types.DHCPSubnet, error) {
+	var s types.DHCPSubnet
+	if err := utils.Patch(current.Properties(), &s, patches); err != nil {
+		return nil, err
+	}
+	s.Subnet = current.Subnet
+	s.SharedNetwork = current.SharedNetwork
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := new(api.Operation)
+
+	if !s.IsEmpty() {
+		in.Set = &api.Set{
+			Resources: api.Resources{
+				Service: subnetOf(s.SharedNetwork, s.Subnet, s.Properties()),
+			},
+		}
+	}
+
+	if stale := staleSubnet(current, &s); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Service: subnetOf(s.SharedNetwork, s.Subnet, stale),
+			},
+		}
+	}
+
+	if in.Set != nil || in.Delete != nil {
+		if _, err := c.apiClient.Post(ctx, in); err != nil {
+			return nil, err
+		}
+	}
+	return c.GetSubnet(ctx, s.SharedNetwork, s.Subnet)
+}
+
+// DeleteSubnet removes the subnet along with its static mappings.
+func (c *client) DeleteSubnet(ctx context.Context, network, subnet string) error {
+	if _, err := c.GetSubnet(ctx, network, subnet); err != nil {
+		return err
+	}
+
+	_, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Service: subnetOf(network, subnet, nil),
+			},
+		},
+	})
+	return err
+}
+
+func (c *client) GetStaticMapping(ctx context.Context, network, subnet, name string) (*types.StaticMapping, error) {
+	s, err := c.GetSubnet(ctx, network, subnet)
+	if err != nil {
+		return nil, err
+	}
+	return toStaticMapping(s, name)
+}
+
+// ListStaticMappings returns the static mappings of the subnet ordered by
+// name.
+func (c *client) ListStaticMappings(ctx context.Context, network, subnet string) ([]*types.StaticMapping, error) {
+	s, err := c.GetSubnet(ctx, network, subnet)
+	if err != nil {
+		return nil, err
+	}
+	return s.SortedStaticMappings(), nil
+}
+
+// CreateStaticMapping adds the static mapping to its subnet. The address and
+// MAC address must not already be mapped by another static mapping of the
+// subnet.
+func (c *client) CreateStaticMapping(ctx context.Context, m *types.StaticMapping) (*types.StaticMapping, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	s, err := c.GetSubnet(ctx, m.SharedNetwork, m.Subnet)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.StaticMappings[m.Name]; ok {
+		return nil, fmt.Errorf("The static mapping %s of subnet %s already exists.", m.Name, m.Subnet)
+	}
+	if err := ensureNotMapped(s, m); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Service: staticMappingOf(m.SharedNetwork, m.Subnet, m.Name, m),
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.GetStaticMapping(ctx, m.SharedNetwork, m.Subnet, m.Name)
+}
+
+// UpdateStaticMapping applies the patches to the current static mapping. Both
+// the address and the MAC address are always set, so nothing is deleted.
+func (c *client) UpdateStaticMapping(ctx context.Context, current *types.StaticMapping, patches []jsonpatch.JsonPatchOperation) (*types.StaticMapping, error) {
+	var m types.StaticMapping
+	if err := utils.Patch(current, &m, patches); err != nil {
+		return nil, err
+	}
+	m.Name = current.Name
+	m.SharedNetwork = current.SharedNetwork
+	m.Subnet = current.Subnet
+
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	s, err := c.GetSubnet(ctx, m.SharedNetwork, m.Subnet)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureNotMapped(s, &m); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Service: staticMappingOf(m.SharedNetwork, m.Subnet, m.Name, &m),
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.GetStaticMapping(ctx, m.SharedNetwork, m.Subnet, m.Name)
+}
+
+func (c *client) DeleteStaticMapping(ctx context.Context, network, subnet, name string) error {
+	if _, err := c.GetStaticMapping(ctx, network, subnet, name); err != nil {
+		return err
+	}
+
+	_, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Service: staticMappingOf(network, subnet, name, nil),
+			},
+		},
+	})
+	return err
+}
+
+// ensureNotMapped returns an error if another static mapping of the subnet
+// already maps the address or MAC address of m.
+func ensureNotMapped(s *types.DHCPSubnet, m *types.StaticMapping) error {
+	for _, other := range s.SortedStaticMappings() {
+		if other.Name == m.Name {
+			continue
+		}
+		if other.IPAddress == m.IPAddress {
+			return fmt.Errorf("The address %s is already mapped by static mapping %s.", m.IPAddress, other.Name)
+		}
+		if strings.EqualFold(other.MACAddress, m.MACAddress) {
+			return fmt.Errorf("The MAC address %s is already mapped by static mapping %s.", m.MACAddress, other.Name)
+		}
+	}
+	return nil
+}
+
+// sharedNetworkOf returns a service holding only the shared network.
+func sharedNetworkOf(name string, n *types.SharedNetwork) *types.Service {
+	return &types.Service{
+		DHCPServer: &types.DHCPServer{
+			SharedNetworks: map[string]*types.SharedNetwork{
+				name: n,
+			},
+		},
+	}
+}
+
+// subnetOf returns a service holding only the subnet of the shared network.
+func subnetOf(network, subnet string, s *types.DHCPSubnet) *types.Service {
+	return sharedNetworkOf(network, &types.SharedNetwork{
+		Subnets: map[string]*types.DHCPSubnet{
+			subnet: s,
+		},
+	})
+}
+
+// staticMappingOf returns a service holding only the static mapping of the
+// subnet.
+func staticMappingOf(network, subnet, name string, m *types.StaticMapping) *types.Service {
+	return subnetOf(network, subnet, &types.DHCPSubnet{
+		StaticMappings: map[string]*types.StaticMapping{
+			name: m,
+		},
+	})
+}
+
+func toSharedNetwork(name string, op *api.Operation) (*types.SharedNetwork, error) {
+	if op == nil || op.Get == nil || op.Get.Service == nil || op.Get.Service.DHCPServer == nil || op.Get.Service.DHCPServer.SharedNetworks == nil {
+		return nil, errors.New("No DHCP shared networks exist.")
+	}
+
+	n, ok := op.Get.Service.DHCPServer.SharedNetworks[name]
+	if !ok || n == nil {
+		return nil, fmt.Errorf("The DHCP shared network %s does not exist.", name)
+	}
+	return n, nil
+}
+
+func toSubnet(n *types.SharedNetwork, subnet string) (*types.DHCPSubnet, error) {
+	s, ok := n.Subnets[subnet]
+	if !ok || s == nil {
+		return nil, fmt.Errorf("The subnet %s of DHCP shared network %s does not exist.", subnet, n.Name)
+	}
+	return s, nil
+}
+
+func toStaticMapping(s *types.DHCPSubnet, name string) (*types.StaticMapping, error) {
+	m, ok := s.StaticMappings[name]
+	if !ok || m == nil {
+		return nil, fmt.Errorf("The static mapping %s of subnet %s does not exist.", name, s.Subnet)
+	}
+	return m, nil
+}
+
+// staleSharedNetwork returns the properties that are set in current but not
+// in updated, encoded for deletion, or nil if there are none.
+func staleSharedNetwork(current, updated *types.SharedNetwork) *types.SharedNetwork {
+	stale := new(types.SharedNetwork)
+	stale.SetOpMode(types.OpModeDelete)
+
+	stale.Authoritative = current.Authoritative && !updated.Authoritative
+	if current.Description != "" && updated.Description == "" {
+		stale.Description = current.Description
+	}
+	stale.Disable = current.Disable && !updated.Disable
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	return stale
+}
+
+// staleSubnet returns the properties that are set in current but not in
+// updated, encoded for deletion, or nil if there are none. Ranges are stale
+// when no updated range starts at the same address.
+func staleSubnet(current, updated *types.DHCPSubnet) *types.DHCPSubnet {
+	stale := new(types.DHCPSubnet)
+	stale.SetOpMode(types.OpModeDelete)
+
+	if current.DefaultRouter != "" && updated.DefaultRouter == "" {
+		stale.DefaultRouter = current.DefaultRouter
+	}
+	stale.DNSServers = utils.StringSliceDiff(updated.DNSServers, current.DNSServers)
+	if len(stale.DNSServers) == 0 {
+		stale.DNSServers = nil
+	}
+	if current.DomainName != "" && updated.DomainName == "" {
+		stale.DomainName = current.DomainName
+	}
+	if current.Lease != 0 && updated.Lease == 0 {
+		stale.Lease = current.Lease
+	}
+
+	starts := map[string]bool{}
+	for _, r := range updated.Ranges {
+		starts[r.Start] = true
+	}
+	for _, r := range current.Ranges {
+		if !starts[r.Start] {
+			stale.Ranges = append(stale.Ranges, r)
+		}
+	}
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	return stale
+}
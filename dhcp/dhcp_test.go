@@ -0,0 +1,285 @@
+package dhcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	dhcpConfig = `{"GET": {"service": {"dhcp-server": {"disabled": "false", "shared-network-name": {
+	"LAN": {"authoritative": "enable", "description": "main lan", "subnet": {
+		"192.168.1.0/24": {"default-router": "192.168.1.1", "dns-server": ["192.168.1.1", "1.1.1.1"], "lease": "86400", "domain-name": "home.lan",
+			"start": {"192.168.1.100": {"stop": "192.168.1.199"}, "192.168.1.50": {"stop": "192.168.1.59"}},
+			"static-mapping": {
+				"printer": {"ip-address": "192.168.1.20", "mac-address": "00:11:22:33:44:55"},
+				"nas": {"ip-address": "192.168.1.10", "mac-address": "00:11:22:33:44:66"}
+			}}
+	}}
+}}}}, "success": true}`
+
+	createdMappingConfig = `{"GET": {"service": {"dhcp-server": {"shared-network-name": {
+	"LAN": {"subnet": {"192.168.1.0/24": {"static-mapping": {"camera": {"ip-address": "192.168.1.30", "mac-address": "00:11:22:33:44:77"}}}}}
+}}}}, "success": true}`
+
+	createdNetworkConfig = `{"GET": {"service": {"dhcp-server": {"shared-network-name": {
+	"GUEST": {"subnet": {"10.0.50.0/24": {}}}
+}}}}, "success": true}`
+)
+
+func TestDHCPOperations(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		committed string
+		do        func(Client) error
+		expected  []string
+		err       string
+	}{
+		{
+			name: "get shared network",
+			do: func(c Client) error {
+				n, err := c.GetSharedNetwork(context.Background(), "LAN")
+				if err != nil {
+					return err
+				}
+				require.True(t, n.Authoritative)
+				s := n.Subnets["192.168.1.0/24"]
+				require.Equal(t, "LAN", s.SharedNetwork)
+				require.Equal(t, 86400, s.Lease)
+				require.Equal(t, []*types.DHCPRange{
+					{Start: "192.168.1.50", Stop: "192.168.1.59"},
+					{Start: "192.168.1.100", Stop: "192.168.1.199"},
+				}, s.Ranges)
+				return nil
+			},
+		},
+		{
+			name: "get a missing shared network",
+			do: func(c Client) error {
+				_, err := c.GetSharedNetwork(context.Background(), "IOT")
+				return err
+			},
+			err: "The DHCP shared network IOT does not exist.",
+		},
+		{
+			name:      "create shared network",
+			committed: createdNetworkConfig,
+			do: func(c Client) error {
+				_, err := c.CreateSharedNetwork(context.Background(), &types.SharedNetwork{
+					Name:          "GUEST",
+					Authoritative: true,
+					Subnets: map[string]*types.DHCPSubnet{
+						"10.0.50.0/24": {
+							DefaultRouter: "10.0.50.1",
+							DNSServers:    []string{"10.0.50.1"},
+							Ranges:        []*types.DHCPRange{{Start: "10.0.50.100", Stop: "10.0.50.200"}},
+						},
+					},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"service":{"dhcp-server":{"shared-network-name":{"GUEST":{"authoritative":"enable",` +
+					`"subnet":{"10.0.50.0/24":{"start":{"10.0.50.100":{"stop":"10.0.50.200"}},"default-router":"10.0.50.1","dns-server":["10.0.50.1"]}}}}}}}}`,
+			},
+		},
+		{
+			name: "create an invalid shared network",
+			do: func(c Client) error {
+				_, err := c.CreateSharedNetwork(context.Background(), &types.SharedNetwork{
+					Name: "GUEST",
+					Subnets: map[string]*types.DHCPSubnet{
+						"10.0.50.1/24": {
+							DefaultRouter: "10.0.60.1",
+							Lease:         60,
+							Ranges: []*types.DHCPRange{
+								{Start: "10.0.50.100", Stop: "10.0.50.200"},
+								{Start: "10.0.50.150", Stop: "10.0.50.250"},
+							},
+						},
+					},
+				})
+				return err
+			},
+			err: `subnet[10.0.50.1/24].subnet: "10.0.50.1/24" must be an IPv4 network in CIDR notation, e.g. 192.168.1.0/24; ` +
+				`subnet[10.0.50.1/24].lease: 60 must be at least 120 seconds; ` +
+				`subnet[10.0.50.1/24].start[1]: range 10.0.50.150-10.0.50.250 overlaps range 10.0.50.100-10.0.50.200`,
+		},
+		{
+			name: "update shared network",
+			do: func(c Client) error {
+				current, err := c.GetSharedNetwork(context.Background(), "LAN")
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateSharedNetwork(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/authoritative"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"service":{"dhcp-server":{"shared-network-name":{"LAN":{"description":"main lan"}}}}},` +
+					`"DELETE":{"service":{"dhcp-server":{"shared-network-name":{"LAN":{"authoritative":null}}}}}}`,
+			},
+		},
+		{
+			name: "delete shared network",
+			do: func(c Client) error {
+				return c.DeleteSharedNetwork(context.Background(), "LAN")
+			},
+			expected: []string{
+				`{"DELETE":{"service":{"dhcp-server":{"shared-network-name":{"LAN":null}}}}}`,
+			},
+		},
+		{
+			name: "update subnet",
+			do: func(c Client) error {
+				current, err := c.GetSubnet(context.Background(), "LAN", "192.168.1.0/24")
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateSubnet(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/dns-server/1"},
+					{Operation: "remove", Path: "/start/192.168.1.50"},
+					{Operation: "replace", Path: "/start/192.168.1.100/stop", Value: "192.168.1.249"},
+					{Operation: "remove", Path: "/domain-name"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"service":{"dhcp-server":{"shared-network-name":{"LAN":{"subnet":{"192.168.1.0/24":{"lease":"86400","start":{"192.168.1.100":{"stop":"192.168.1.249"}},"default-router":"192.168.1.1","dns-server":["192.168.1.1"]}}}}}}},` +
+					`"DELETE":{"service":{"dhcp-server":{"shared-network-name":{"LAN":{"subnet":{"192.168.1.0/24":{"dns-server":["1.1.1.1"],"domain-name":null,"start":{"192.168.1.50":null}}}}}}}}}`,
+			},
+		},
+		{
+			name: "delete subnet",
+			do: func(c Client) error {
+				return c.DeleteSubnet(context.Background(), "LAN", "192.168.1.0/24")
+			},
+			expected: []string{
+				`{"DELETE":{"service":{"dhcp-server":{"shared-network-name":{"LAN":{"subnet":{"192.168.1.0/24":null}}}}}}}`,
+			},
+		},
+		{
+			name: "list static mappings",
+			do: func(c Client) error {
+				mappings, err := c.ListStaticMappings(context.Background(), "LAN", "192.168.1.0/24")
+				if err != nil {
+					return err
+				}
+				require.Len(t, mappings, 2)
+				require.Equal(t, "nas", mappings[0].Name)
+				require.Equal(t, "192.168.1.0/24", mappings[0].Subnet)
+				return nil
+			},
+		},
+		{
+			name:      "create static mapping",
+			committed: createdMappingConfig,
+			do: func(c Client) error {
+				_, err := c.CreateStaticMapping(context.Background(), &types.StaticMapping{
+					Name:          "camera",
+					SharedNetwork: "LAN",
+					Subnet:        "192.168.1.0/24",
+					IPAddress:     "192.168.1.30",
+					MACAddress:    "00:11:22:33:44:77",
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"service":{"dhcp-server":{"shared-network-name":{"LAN":{"subnet":{"192.168.1.0/24":{"static-mapping":{"camera":{"ip-address":"192.168.1.30","mac-address":"00:11:22:33:44:77"}}}}}}}}}}`,
+			},
+		},
+		{
+			name: "create a static mapping for a mapped mac address",
+			do: func(c Client) error {
+				_, err := c.CreateStaticMapping(context.Background(), &types.StaticMapping{
+					Name:          "printer2",
+					SharedNetwork: "LAN",
+					Subnet:        "192.168.1.0/24",
+					IPAddress:     "192.168.1.31",
+					MACAddress:    "00:11:22:33:44:55",
+				})
+				return err
+			},
+			err: "The MAC address 00:11:22:33:44:55 is already mapped by static mapping printer.",
+		},
+		{
+			name: "create a static mapping outside of the subnet",
+			do: func(c Client) error {
+				_, err := c.CreateStaticMapping(context.Background(), &types.StaticMapping{
+					Name:          "camera",
+					SharedNetwork: "LAN",
+					Subnet:        "192.168.1.0/24",
+					IPAddress:     "192.168.2.30",
+					MACAddress:    "00:11:22:33:44:77",
+				})
+				return err
+			},
+			err: "ip-address: 192.168.2.30 is not within 192.168.1.0/24",
+		},
+		{
+			name: "update static mapping",
+			do: func(c Client) error {
+				current, err := c.GetStaticMapping(context.Background(), "LAN", "192.168.1.0/24", "printer")
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateStaticMapping(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "replace", Path: "/ip-address", Value: "192.168.1.21"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"service":{"dhcp-server":{"shared-network-name":{"LAN":{"subnet":{"192.168.1.0/24":{"static-mapping":{"printer":{"ip-address":"192.168.1.21","mac-address":"00:11:22:33:44:55"}}}}}}}}}}`,
+			},
+		},
+		{
+			name: "update a static mapping to a mapped address",
+			do: func(c Client) error {
+				current, err := c.GetStaticMapping(context.Background(), "LAN", "192.168.1.0/24", "printer")
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateStaticMapping(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "replace", Path: "/ip-address", Value: "192.168.1.10"},
+				})
+				return err
+			},
+			err: "The address 192.168.1.10 is already mapped by static mapping nas.",
+		},
+		{
+			name: "delete static mapping",
+			do: func(c Client) error {
+				return c.DeleteStaticMapping(context.Background(), "LAN", "192.168.1.0/24", "nas")
+			},
+			expected: []string{
+				`{"DELETE":{"service":{"dhcp-server":{"shared-network-name":{"LAN":{"subnet":{"192.168.1.0/24":{"static-mapping":{"nas":null}}}}}}}}}`,
+			},
+		},
+		{
+			name: "delete a missing static mapping",
+			do: func(c Client) error {
+				return c.DeleteStaticMapping(context.Background(), "LAN", "192.168.1.0/24", "tv")
+			},
+			err: "The static mapping tv of subnet 192.168.1.0/24 does not exist.",
+		},
+	} {
+		apiClient := &apitest.Client{Config: dhcpConfig, Committed: test.committed}
+		err := test.do(NewWithAPIClient(apiClient))
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
@@ -0,0 +1,70 @@
+package dhcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/frankgreco/edge-sdk-go/types"
+)
+
+const (
+	leasesData = "dhcp_leases"
+	statsData  = "dhcp_stats"
+)
+
+// Leases returns the active leases of every shared network, ordered by
+// address.
+func (c *client) Leases(ctx context.Context) ([]*types.DHCPLease, error) {
+	if c.dataClient == nil {
+		return nil, errors.New("The API client cannot read operational data.")
+	}
+
+	var leases types.DHCPLeases
+	if err := c.dataClient.Data(ctx, leasesData, &leases); err != nil {
+		return nil, err
+	}
+	return leases, nil
+}
+
+// Stats returns the utilisation of the pool of every shared network, ordered
+// by name.
+func (c *client) Stats(ctx context.Context) ([]*types.DHCPPoolStats, error) {
+	if c.dataClient == nil {
+		return nil, errors.New("The API client cannot read operational data.")
+	}
+
+	var stats types.DHCPStats
+	if err := c.dataClient.Data(ctx, statsData, &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// ReserveLease turns the lease into a static mapping of the subnet of its
+// shared network that contains its address. The mapping is named after the
+// hostname of the lease unless a name is given.
+func (c *client) ReserveLease(ctx context.Context, lease *types.DHCPLease, name string) (*types.StaticMapping, error) {
+	if name == "" {
+		name = lease.Hostname
+	}
+	if name == "" {
+		return nil, fmt.Errorf("The lease of %s has no hostname, a name is required.", lease.IPAddress)
+	}
+
+	n, err := c.GetSharedNetwork(ctx, lease.Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	ip := net.ParseIP(lease.IPAddress)
+	for cidr := range n.Subnets {
+		if _, network, err := net.ParseCIDR(cidr); err == nil && ip != nil && network.Contains(ip) {
+			m := lease.StaticMapping(name)
+			m.Subnet = cidr
+			return c.CreateStaticMapping(ctx, m)
+		}
+	}
+	return nil, fmt.Errorf("The address %s is not within any subnet of DHCP shared network %s.", lease.IPAddress, lease.Pool)
+}
@@ -0,0 +1,128 @@
+package dhcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+var leaseData = map[string]string{
+	"dhcp_leases": `{"dhcp-server-leases": {
+	"LAN": {
+		"192.168.1.150": {"expiration": "2022/06/21 08:30:00", "pool": "LAN", "mac": "00:11:22:33:44:88", "client-hostname": "laptop"},
+		"192.168.1.101": {"expiration": "2022/06/21 09:00:00", "pool": "LAN", "mac": "00:11:22:33:44:99", "client-hostname": ""}
+	},
+	"GUEST": {
+		"10.0.50.100": {"expiration": "2022/06/20 23:00:00", "pool": "GUEST", "mac": "00:11:22:33:44:aa", "client-hostname": "phone"}
+	}
+}}`,
+	"dhcp_stats": `{"dhcp-server-stats": {
+	"LAN": {"pool_size": "100", "leased": "25", "available": "75"},
+	"GUEST": {"pool_size": "0", "leased": "0", "available": "0"}
+}}`,
+}
+
+func TestLeases(t *testing.T) {
+	c := NewWithAPIClient(&apitest.Client{Config: dhcpConfig, DataSources: leaseData})
+
+	leases, err := c.Leases(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []*types.DHCPLease{
+		{IPAddress: "10.0.50.100", MACAddress: "00:11:22:33:44:aa", Hostname: "phone", Expiration: time.Date(2022, 6, 20, 23, 0, 0, 0, time.UTC), Pool: "GUEST"},
+		{IPAddress: "192.168.1.101", MACAddress: "00:11:22:33:44:99", Expiration: time.Date(2022, 6, 21, 9, 0, 0, 0, time.UTC), Pool: "LAN"},
+		{IPAddress: "192.168.1.150", MACAddress: "00:11:22:33:44:88", Hostname: "laptop", Expiration: time.Date(2022, 6, 21, 8, 30, 0, 0, time.UTC), Pool: "LAN"},
+	}, leases)
+
+	stats, err := c.Stats(context.Background())
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	require.Equal(t, "GUEST", stats[0].SharedNetwork)
+	require.Zero(t, stats[0].Utilization())
+	require.Equal(t, 0.25, stats[1].Utilization())
+}
+
+func TestLeaseExpiration(t *testing.T) {
+	c := NewWithAPIClient(&apitest.Client{Config: dhcpConfig, DataSources: map[string]string{
+		"dhcp_leases": `{"dhcp-server-leases": {"LAN": {
+			"192.168.1.150": {"expiration": "never", "pool": "LAN", "mac": "00:11:22:33:44:88"},
+			"192.168.1.151": {"expiration": "2022/06/21 08:30:00", "pool": "LAN", "mac": "00:11:22:33:44:99"}
+		}}}`,
+	}})
+
+	leases, err := c.Leases(context.Background())
+	require.NoError(t, err)
+	require.Len(t, leases, 2)
+	require.True(t, leases[0].Expiration.IsZero())
+	require.True(t, leases[0].ExpirationIn(time.UTC).IsZero())
+
+	loc := time.FixedZone("PDT", -7*60*60)
+	require.Equal(t, time.Date(2022, 6, 21, 8, 30, 0, 0, loc), leases[1].ExpirationIn(loc))
+	require.Equal(t, time.Date(2022, 6, 21, 15, 30, 0, 0, time.UTC), leases[1].ExpirationIn(loc).UTC())
+
+	c = NewWithAPIClient(&apitest.Client{Config: dhcpConfig, DataSources: map[string]string{
+		"dhcp_leases": `{"dhcp-server-leases": {"LAN": {
+			"192.168.1.150": {"expiration": "tomorrow", "pool": "LAN", "mac": "00:11:22:33:44:88"}
+		}}}`,
+	}})
+
+	_, err = c.Leases(context.Background())
+	require.EqualError(t, err, "malformed lease expiration: tomorrow")
+}
+
+func TestReserveLease(t *testing.T) {
+	laptop := &types.DHCPLease{IPAddress: "192.168.1.150", MACAddress: "00:11:22:33:44:88", Hostname: "laptop", Pool: "LAN"}
+
+	for _, test := range []struct {
+		name     string
+		lease    *types.DHCPLease
+		as       string
+		expected []string
+		err      string
+	}{
+		{
+			name:  "reserve",
+			lease: laptop,
+			expected: []string{
+				`{"SET":{"service":{"dhcp-server":{"shared-network-name":{"LAN":{"subnet":{"192.168.1.0/24":{"static-mapping":{"laptop":{"ip-address":"192.168.1.150","mac-address":"00:11:22:33:44:88"}}}}}}}}}}`,
+			},
+		},
+		{
+			name:  "reserve without a hostname",
+			lease: &types.DHCPLease{IPAddress: "192.168.1.101", MACAddress: "00:11:22:33:44:99", Pool: "LAN"},
+			err:   "The lease of 192.168.1.101 has no hostname, a name is required.",
+		},
+		{
+			name:  "reserve outside of every subnet",
+			lease: &types.DHCPLease{IPAddress: "192.168.2.10", MACAddress: "00:11:22:33:44:99", Pool: "LAN"},
+			as:    "tv",
+			err:   "The address 192.168.2.10 is not within any subnet of DHCP shared network LAN.",
+		},
+		{
+			name:  "reserve a mapped mac address",
+			lease: &types.DHCPLease{IPAddress: "192.168.1.160", MACAddress: "00:11:22:33:44:55", Pool: "LAN"},
+			as:    "printer-wifi",
+			err:   "The MAC address 00:11:22:33:44:55 is already mapped by static mapping printer.",
+		},
+	} {
+		apiClient := &apitest.Client{Config: dhcpConfig, Committed: createdLeaseMappingConfig}
+		_, err := NewWithAPIClient(apiClient).ReserveLease(context.Background(), test.lease, test.as)
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
+
+const createdLeaseMappingConfig = `{"GET": {"service": {"dhcp-server": {"shared-network-name": {
+	"LAN": {"subnet": {"192.168.1.0/24": {"static-mapping": {"laptop": {"ip-address": "192.168.1.150", "mac-address": "00:11:22:33:44:88"}}}}}
+}}}}, "success": true}`
@@ -0,0 +1,258 @@
+// Package dns manages the DNS forwarder of the router and the static host
+// mappings it serves.
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+type Client interface {
+	GetForwarding(context.Context) (*types.DNSForwarding, error)
+	UpdateForwarding(context.Context, *types.DNSForwarding, []jsonpatch.JsonPatchOperation) (*types.DNSForwarding, error)
+
+	GetHostMapping(context.Context, string) (*types.HostMapping, error)
+	ListHostMappings(context.Context) ([]*types.HostMapping, error)
+	CreateHostMapping(context.Context, *types.HostMapping) (*types.HostMapping, error)
+	UpdateHostMapping(context.Context, *types.HostMapping, []jsonpatch.JsonPatchOperation) (*types.HostMapping, error)
+	DeleteHostMapping(context.Context, string) error
+}
+
+type client struct {
+	apiClient api.Client
+}
+
+func New(httpClient *http.Client, host string) Client {
+	return &client{
+		apiClient: api.New(httpClient, host),
+	}
+}
+
+// GetForwarding returns the DNS forwarder. A router that does not forward DNS
+// returns an empty forwarder.
+func (c *client) GetForwarding(ctx context.Context) (*types.DNSForwarding, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if op == nil || op.Get == nil || op.Get.Service == nil || op.Get.Service.DNS == nil || op.Get.Service.DNS.Forwarding == nil {
+		return new(types.DNSForwarding), nil
+	}
+	return op.Get.Service.DNS.Forwarding, nil
+}
+
+// UpdateForwarding applies the patches to the current forwarder, setting the
+// properties that changed and deleting the ones that were removed in a single
+// commit. Patching an empty forwarder enables DNS forwarding.
+func (c *client) UpdateForwarding(ctx context.Context, current *types.DNSForwarding, patches []jsonpatch.JsonPatchOperation) (*types.DNSForwarding, error) {
+	var f types.DNSForwarding
+	if err := utils.Patch(current, &f, patches); err != nil {
+		return nil, err
+	}
+
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := new(api.Operation)
+
+	if !f.IsEmpty() {
+		in.Set = &api.Set{
+			Resources: api.Resources{
+				Service: forwardingOf(&f),
+			},
+		}
+	}
+
+	if stale := staleForwarding(current, &f); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Service: forwardingOf(stale),
+			},
+		}
+	}
+
+	if in.Set != nil || in.Delete != nil {
+		if _, err := c.apiClient.Post(ctx, in); err != nil {
+			return nil, err
+		}
+	}
+	return c.GetForwarding(ctx)
+}
+
+func (c *client) GetHostMapping(ctx context.Context, hostName string) (*types.HostMapping, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toHostMapping(hostName, op)
+}
+
+// ListHostMappings returns every static host mapping ordered by host name.
+func (c *client) ListHostMappings(ctx context.Context) ([]*types.HostMapping, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := []*types.HostMapping{}
+	if op == nil || op.Get == nil || op.Get.System == nil || op.Get.System.StaticHostMapping == nil {
+		return hosts, nil
+	}
+	for _, h := range op.Get.System.StaticHostMapping.Hosts {
+		if h != nil {
+			hosts = append(hosts, h)
+		}
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		return hosts[i].HostName < hosts[j].HostName
+	})
+	return hosts, nil
+}
+
+func (c *client) CreateHostMapping(ctx context.Context, h *types.HostMapping) (*types.HostMapping, error) {
+	if err := h.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.GetHostMapping(ctx, h.HostName); err == nil {
+		return nil, fmt.Errorf("The static host mapping %s already exists.", h.HostName)
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				System: hostMappingOf(h.HostName, h),
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.GetHostMapping(ctx, h.HostName)
+}
+
+// UpdateHostMapping applies the patches to the current host, setting the
+// address and aliases that changed and deleting the aliases that were removed
+// in a single commit.
+func (c *client) UpdateHostMapping(ctx context.Context, current *types.HostMapping, patches []jsonpatch.JsonPatchOperation) (*types.HostMapping, error) {
+	var h types.HostMapping
+	if err := utils.Patch(current, &h, patches); err != nil {
+		return nil, err
+	}
+	h.HostName = current.HostName
+
+	if err := h.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				System: hostMappingOf(h.HostName, &h),
+			},
+		},
+	}
+
+	if stale := utils.StringSliceDiff(h.Aliases, current.Aliases); len(stale) > 0 {
+		aliases := &types.HostMapping{Aliases: stale}
+		aliases.SetOpMode(types.OpModeDelete)
+
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				System: hostMappingOf(h.HostName, aliases),
+			},
+		}
+	}
+
+	if _, err := c.apiClient.Post(ctx, in); err != nil {
+		return nil, err
+	}
+	return c.GetHostMapping(ctx, h.HostName)
+}
+
+func (c *client) DeleteHostMapping(ctx context.Context, hostName string) error {
+	if _, err := c.GetHostMapping(ctx, hostName); err != nil {
+		return err
+	}
+
+	_, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				System: hostMappingOf(hostName, nil),
+			},
+		},
+	})
+	return err
+}
+
+// forwardingOf returns a service holding only the forwarder.
+func forwardingOf(f *types.DNSForwarding) *types.Service {
+	return &types.Service{
+		DNS: &types.DNS{
+			Forwarding: f,
+		},
+	}
+}
+
+// hostMappingOf returns a system holding only the host.
+func hostMappingOf(hostName string, h *types.HostMapping) *types.System {
+	return &types.System{
+		StaticHostMapping: &types.StaticHostMapping{
+			Hosts: map[string]*types.HostMapping{
+				hostName: h,
+			},
+		},
+	}
+}
+
+func toHostMapping(hostName string, op *api.Operation) (*types.HostMapping, error) {
+	if op == nil || op.Get == nil || op.Get.System == nil || op.Get.System.StaticHostMapping == nil {
+		return nil, errors.New("No static host mappings exist.")
+	}
+
+	h, ok := op.Get.System.StaticHostMapping.Hosts[hostName]
+	if !ok || h == nil {
+		return nil, fmt.Errorf("The static host mapping %s does not exist.", hostName)
+	}
+	return h, nil
+}
+
+// staleForwarding returns the properties that are set in current but not in
+// updated, encoded for deletion, or nil if there are none.
+func staleForwarding(current, updated *types.DNSForwarding) *types.DNSForwarding {
+	stale := new(types.DNSForwarding)
+	stale.SetOpMode(types.OpModeDelete)
+
+	if current.CacheSize != 0 && updated.CacheSize == 0 {
+		stale.CacheSize = current.CacheSize
+	}
+	stale.System = current.System && !updated.System
+
+	for _, list := range []struct {
+		current, updated []string
+		stale            *[]string
+	}{
+		{current.ListenOn, updated.ListenOn, &stale.ListenOn},
+		{current.NameServers, updated.NameServers, &stale.NameServers},
+		{current.Options, updated.Options, &stale.Options},
+		{current.DHCP, updated.DHCP, &stale.DHCP},
+	} {
+		if diff := utils.StringSliceDiff(list.updated, list.current); len(diff) > 0 {
+			*list.stale = diff
+		}
+	}
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	return stale
+}
@@ -0,0 +1,192 @@
+package dns
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	dnsConfig = `{"GET": {
+	"service": {"dns": {"forwarding": {"cache-size": "150", "listen-on": ["eth1", "switch0"], "name-server": ["1.1.1.1", "9.9.9.9"], "options": ["address=/nas.lan/192.168.1.10"], "system": null}}},
+	"system": {"static-host-mapping": {"host-name": {
+		"nas.lan": {"inet": "192.168.1.10", "alias": ["nas", "files"]},
+		"printer.lan": {"inet": "192.168.1.20"}
+	}}}
+}, "success": true}`
+
+	createdHostConfig = `{"GET": {"system": {"static-host-mapping": {"host-name": {
+	"camera.lan": {"inet": "192.168.1.30"}
+}}}}, "success": true}`
+)
+
+func TestDNSOperations(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		config    string
+		committed string
+		do        func(Client) error
+		expected  []string
+		err       string
+	}{
+		{
+			name: "get forwarding",
+			do: func(c Client) error {
+				f, err := c.GetForwarding(context.Background())
+				if err != nil {
+					return err
+				}
+				require.Equal(t, 150, f.CacheSize)
+				require.True(t, f.System)
+				return nil
+			},
+		},
+		{
+			name: "update forwarding",
+			do: func(c Client) error {
+				current, err := c.GetForwarding(context.Background())
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateForwarding(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "replace", Path: "/cache-size", Value: "1000"},
+					{Operation: "remove", Path: "/name-server/1"},
+					{Operation: "remove", Path: "/system"},
+					{Operation: "add", Path: "/options/-", Value: "strict-order"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"service":{"dns":{"forwarding":{"cache-size":"1000","listen-on":["eth1","switch0"],"name-server":["1.1.1.1"],"options":["address=/nas.lan/192.168.1.10","strict-order"]}}}},` +
+					`"DELETE":{"service":{"dns":{"forwarding":{"name-server":["9.9.9.9"],"system":null}}}}}`,
+			},
+		},
+		{
+			name:   "enable forwarding",
+			config: `{"GET": {}, "success": true}`,
+			do: func(c Client) error {
+				current, err := c.GetForwarding(context.Background())
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateForwarding(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "add", Path: "/listen-on", Value: []string{"eth1"}},
+					{Operation: "add", Path: "/system", Value: nil},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"service":{"dns":{"forwarding":{"system":null,"listen-on":["eth1"]}}}}}`,
+			},
+		},
+		{
+			name: "update forwarding with an invalid name server",
+			do: func(c Client) error {
+				current, err := c.GetForwarding(context.Background())
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateForwarding(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "add", Path: "/name-server/-", Value: "dns.google"},
+				})
+				return err
+			},
+			err: `name-server[2]: "dns.google" is not a valid IP address`,
+		},
+		{
+			name: "list host mappings",
+			do: func(c Client) error {
+				hosts, err := c.ListHostMappings(context.Background())
+				if err != nil {
+					return err
+				}
+				require.Len(t, hosts, 2)
+				require.Equal(t, "nas.lan", hosts[0].HostName)
+				require.Equal(t, []string{"nas", "files"}, hosts[0].Aliases)
+				return nil
+			},
+		},
+		{
+			name:      "create host mapping",
+			committed: createdHostConfig,
+			do: func(c Client) error {
+				_, err := c.CreateHostMapping(context.Background(), &types.HostMapping{HostName: "camera.lan", Inet: "192.168.1.30"})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"system":{"static-host-mapping":{"host-name":{"camera.lan":{"inet":"192.168.1.30"}}}}}}`,
+			},
+		},
+		{
+			name: "create an existing host mapping",
+			do: func(c Client) error {
+				_, err := c.CreateHostMapping(context.Background(), &types.HostMapping{HostName: "nas.lan", Inet: "192.168.1.11"})
+				return err
+			},
+			err: "The static host mapping nas.lan already exists.",
+		},
+		{
+			name: "create an invalid host mapping",
+			do: func(c Client) error {
+				_, err := c.CreateHostMapping(context.Background(), &types.HostMapping{HostName: "-camera.lan", Aliases: []string{"cam", "CAM"}})
+				return err
+			},
+			err: `host-name: "-camera.lan" is not a valid host name; inet: must not be empty; alias[1]: "CAM" is listed more than once`,
+		},
+		{
+			name: "update host mapping",
+			do: func(c Client) error {
+				current, err := c.GetHostMapping(context.Background(), "nas.lan")
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateHostMapping(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "replace", Path: "/inet", Value: "192.168.1.11"},
+					{Operation: "remove", Path: "/alias/1"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"system":{"static-host-mapping":{"host-name":{"nas.lan":{"inet":"192.168.1.11","alias":["nas"]}}}}},` +
+					`"DELETE":{"system":{"static-host-mapping":{"host-name":{"nas.lan":{"alias":["files"]}}}}}}`,
+			},
+		},
+		{
+			name: "delete host mapping",
+			do: func(c Client) error {
+				return c.DeleteHostMapping(context.Background(), "printer.lan")
+			},
+			expected: []string{
+				`{"DELETE":{"system":{"static-host-mapping":{"host-name":{"printer.lan":null}}}}}`,
+			},
+		},
+		{
+			name: "delete a missing host mapping",
+			do: func(c Client) error {
+				return c.DeleteHostMapping(context.Background(), "tv.lan")
+			},
+			err: "The static host mapping tv.lan does not exist.",
+		},
+	} {
+		config := test.config
+		if config == "" {
+			config = dnsConfig
+		}
+		apiClient := &apitest.Client{Config: config, Committed: test.committed}
+		err := test.do(&client{apiClient: apiClient})
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
@@ -7,16 +7,88 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/frankgreco/edge-sdk-go/bgp"
+	"github.com/frankgreco/edge-sdk-go/dhcp"
+	"github.com/frankgreco/edge-sdk-go/dns"
 	"github.com/frankgreco/edge-sdk-go/firewall"
 	"github.com/frankgreco/edge-sdk-go/interfaces"
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/nat"
+	"github.com/frankgreco/edge-sdk-go/ospf"
+	"github.com/frankgreco/edge-sdk-go/portforward"
+	"github.com/frankgreco/edge-sdk-go/routes"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
 )
 
 type Client struct {
-	Firewall   firewall.Client
-	Interfaces *interfaces.Client
+	Firewall    firewall.Client
+	Interfaces  *interfaces.Client
+	DHCP        dhcp.Client
+	DNS         dns.Client
+	NAT         nat.Client
+	PortForward portforward.Client
+	Routes      routes.Client
+	BGP         bgp.Client
+	OSPF        ospf.Client
+}
+
+// LoginOption configures the clients returned by Login.
+type LoginOption func(*loginOptions)
+
+type loginOptions struct {
+	hostKeyCallback ssh.HostKeyCallback
+	knownHosts      string
 }
 
-func Login(host string, insecure bool, username, password string) (*Client, error) {
+// WithSSH runs show commands over SSH with the same credentials, verifying the
+// host key of the router with hostKeyCallback. The state of routing protocols,
+// such as the status of BGP and OSPF neighbors, is only available from show
+// commands.
+func WithSSH(hostKeyCallback ssh.HostKeyCallback) LoginOption {
+	return func(o *loginOptions) {
+		o.hostKeyCallback = hostKeyCallback
+		o.knownHosts = ""
+	}
+}
+
+// WithSSHKnownHosts is like WithSSH, verifying the host key of the router
+// against the known_hosts file at path.
+func WithSSHKnownHosts(path string) LoginOption {
+	return func(o *loginOptions) {
+		o.hostKeyCallback = nil
+		o.knownHosts = path
+	}
+}
+
+// Login authenticates against the web interface of the router at host.
+// insecure only skips the verification of its TLS certificate. Show commands
+// are not run, and the status of routing protocol neighbors is unavailable,
+// unless WithSSH or WithSSHKnownHosts is given.
+func Login(host string, insecure bool, username, password string, opts ...LoginOption) (*Client, error) {
+	o := new(loginOptions)
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	// The SSH client is set up before logging in so that a bad known_hosts
+	// file is reported without contacting the router.
+	var (
+		commandClient api.CommandClient
+		err           error
+	)
+	if o.knownHosts != "" {
+		if o.hostKeyCallback, err = knownhosts.New(o.knownHosts); err != nil {
+			return nil, err
+		}
+	}
+	if o.hostKeyCallback != nil {
+		if commandClient, err = api.NewSSH(host, username, password, o.hostKeyCallback); err != nil {
+			return nil, err
+		}
+	}
+
 	jar, err := cookiejar.New(nil)
 	if err != nil {
 		return nil, err
@@ -47,7 +119,14 @@ func Login(host string, insecure bool, username, password string) (*Client, erro
 	defer resp.Body.Close()
 
 	return &Client{
-		Firewall:   firewall.New(httpClient, host),
-		Interfaces: interfaces.New(httpClient, host),
+		Firewall:    firewall.New(httpClient, host),
+		Interfaces:  interfaces.New(httpClient, host),
+		DHCP:        dhcp.New(httpClient, host),
+		DNS:         dns.New(httpClient, host),
+		NAT:         nat.New(httpClient, host),
+		PortForward: portforward.New(httpClient, host),
+		Routes:      routes.New(httpClient, host),
+		BGP:         bgp.NewWithAPIClient(api.New(httpClient, host), commandClient),
+		OSPF:        ospf.NewWithAPIClient(api.New(httpClient, host), commandClient),
 	}, nil
 }
@@ -0,0 +1,261 @@
+package firewall
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/frankgreco/edge-sdk-go/types"
+)
+
+// FindingKind classifies a problem found by Analyze.
+type FindingKind string
+
+const (
+	// FindingDuplicate is a rule that matches exactly the same traffic as an
+	// earlier rule and takes the same action.
+	FindingDuplicate FindingKind = "duplicate"
+	// FindingShadowed is a rule that can never match because earlier rules
+	// together match all of its traffic first.
+	FindingShadowed FindingKind = "shadowed"
+	// FindingConflict is a rule that overlaps an earlier rule with the opposite
+	// action, so the earlier rule decides the overlapping traffic.
+	FindingConflict FindingKind = "conflict"
+	// FindingRedundant is a rule that can be removed because the traffic it
+	// matches is handled the same way by the default action.
+	FindingRedundant FindingKind = "redundant"
+)
+
+// maxRegions bounds the number of regions tracked per rule so that pathological
+// rulesets cannot make the analysis explode. Rules that exceed it are skipped.
+const maxRegions = 4096
+
+// Finding is a problem with a rule found by Analyze.
+type Finding struct {
+	Kind FindingKind
+	// Priority is the rule the finding is about.
+	Priority int
+	// Related are the priorities of the earlier rules involved, if any.
+	Related []int
+	// Explanation describes the finding in terms of the priorities involved.
+	Explanation string
+}
+
+func (f *Finding) String() string {
+	return fmt.Sprintf("%s: %s", f.Kind, f.Explanation)
+}
+
+// Analyze reports duplicate, shadowed, conflicting and redundant rules in the
+// ruleset. Address and port groups are resolved from groups, which may be nil if
+// no rule uses a group. Findings are ordered by priority.
+func Analyze(rs *types.Ruleset, groups *types.Groups) ([]*Finding, error) {
+	rules, err := compileRuleset(rs, groups)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := rs.DefaultAction
+	if fallback == "" {
+		fallback = defaultAction
+	}
+	defaultLog := rs.DefaultLogging != nil && *rs.DefaultLogging
+
+	findings := []*Finding{}
+
+	// live are the earlier rules that decide at least some traffic. Duplicate
+	// and shadowed rules never do and are left out so they are not blamed.
+	var live []*compiledRule
+
+	for i, r := range rules {
+		if finding := duplicateOf(r, live); finding != nil {
+			findings = append(findings, finding)
+			continue
+		}
+
+		regions, covering, ok := remainder(r, live)
+		if !ok {
+			live = append(live, r)
+			continue
+		}
+
+		if len(regions) == 0 {
+			findings = append(findings, shadowed(r, covering))
+			continue
+		}
+
+		if finding := conflictWith(r, covering); finding != nil {
+			findings = append(findings, finding)
+		}
+
+		if finding := redundantWithDefault(r, regions, rules[i+1:], fallback, defaultLog); finding != nil {
+			findings = append(findings, finding)
+		}
+
+		live = append(live, r)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Priority < findings[j].Priority
+	})
+	return findings, nil
+}
+
+func duplicateOf(r *compiledRule, earlier []*compiledRule) *Finding {
+	for _, e := range earlier {
+		if e.rule.Action == r.rule.Action && e.criteria.equal(r.criteria) {
+			return &Finding{
+				Kind:        FindingDuplicate,
+				Priority:    r.rule.Priority,
+				Related:     []int{e.rule.Priority},
+				Explanation: fmt.Sprintf("rule %d matches the same traffic as rule %d and also %s it", r.rule.Priority, e.rule.Priority, verb(r.rule.Action)),
+			}
+		}
+	}
+	return nil
+}
+
+// remainder returns the regions of r that no earlier rule matches, along with
+// the earlier rules that decide some of the traffic r matches. If the analysis
+// exceeds maxRegions, ok is false.
+func remainder(r *compiledRule, earlier []*compiledRule) (regions []*criteria, overlapping []*compiledRule, ok bool) {
+	regions = []*criteria{r.criteria}
+	for _, e := range earlier {
+		decides := false
+		for _, region := range regions {
+			if region.intersects(e.criteria) {
+				decides = true
+				break
+			}
+		}
+		if !decides {
+			continue
+		}
+		overlapping = append(overlapping, e)
+
+		var next []*criteria
+		for _, region := range regions {
+			next = append(next, region.subtract(e.criteria)...)
+		}
+		if len(next) > maxRegions {
+			return nil, nil, false
+		}
+		regions = next
+		if len(regions) == 0 {
+			break
+		}
+	}
+	return regions, overlapping, true
+}
+
+func shadowed(r *compiledRule, covering []*compiledRule) *Finding {
+	// Prefer naming a single earlier rule that covers r on its own.
+	for _, e := range covering {
+		if r.criteria.subsetOf(e.criteria) {
+			return &Finding{
+				Kind:        FindingShadowed,
+				Priority:    r.rule.Priority,
+				Related:     []int{e.rule.Priority},
+				Explanation: fmt.Sprintf("rule %d can never match because rule %d matches all of its traffic first and %s it", r.rule.Priority, e.rule.Priority, verb(e.rule.Action)),
+			}
+		}
+	}
+
+	related := priorities(covering)
+	return &Finding{
+		Kind:        FindingShadowed,
+		Priority:    r.rule.Priority,
+		Related:     related,
+		Explanation: fmt.Sprintf("rule %d can never match because %s together match all of its traffic first", r.rule.Priority, ruleNoun(related)),
+	}
+}
+
+// conflictWith reports r if any of the earlier rules that decide some of its
+// traffic take the opposite action.
+func conflictWith(r *compiledRule, overlapping []*compiledRule) *Finding {
+	var conflicting []*compiledRule
+	for _, e := range overlapping {
+		if isAccept(e.rule.Action) != isAccept(r.rule.Action) {
+			conflicting = append(conflicting, e)
+		}
+	}
+	if len(conflicting) == 0 {
+		return nil
+	}
+
+	related := priorities(conflicting)
+	return &Finding{
+		Kind:        FindingConflict,
+		Priority:    r.rule.Priority,
+		Related:     related,
+		Explanation: fmt.Sprintf("rule %d %s traffic that is partly matched first by %s, which %s it instead", r.rule.Priority, verb(r.rule.Action), ruleNoun(related), verbs(conflicting)),
+	}
+}
+
+// redundantWithDefault reports r if every packet it decides would otherwise
+// fall through to a default action that treats it the same way.
+func redundantWithDefault(r *compiledRule, regions []*criteria, later []*compiledRule, fallback string, defaultLog bool) *Finding {
+	if r.rule.Action != fallback || (r.rule.Log != nil && *r.rule.Log) != defaultLog {
+		return nil
+	}
+
+	for _, l := range later {
+		if l.rule.Action == fallback && (l.rule.Log != nil && *l.rule.Log) == defaultLog {
+			continue
+		}
+		for _, region := range regions {
+			if region.intersects(l.criteria) {
+				return nil
+			}
+		}
+	}
+
+	return &Finding{
+		Kind:        FindingRedundant,
+		Priority:    r.rule.Priority,
+		Explanation: fmt.Sprintf("rule %d %s traffic that the default action would %s anyway", r.rule.Priority, verb(r.rule.Action), fallback),
+	}
+}
+
+func isAccept(action string) bool {
+	return action == "accept"
+}
+
+// verb returns the action in the third person, e.g. "drops".
+func verb(action string) string {
+	if action == "" {
+		return "matches"
+	}
+	return action + "s"
+}
+
+func verbs(rules []*compiledRule) string {
+	seen := map[string]bool{}
+	actions := []string{}
+	for _, r := range rules {
+		if !seen[r.rule.Action] {
+			seen[r.rule.Action] = true
+			actions = append(actions, verb(r.rule.Action))
+		}
+	}
+	return strings.Join(actions, " or ")
+}
+
+func priorities(rules []*compiledRule) []int {
+	vals := make([]int, len(rules))
+	for i, r := range rules {
+		vals[i] = r.rule.Priority
+	}
+	return vals
+}
+
+// ruleNoun returns e.g. "rule 10" or "rules 10, 20 and 30".
+func ruleNoun(vals []int) string {
+	strs := make([]string, len(vals))
+	for i, v := range vals {
+		strs[i] = fmt.Sprintf("%d", v)
+	}
+	if len(strs) == 1 {
+		return "rule " + strs[0]
+	}
+	return "rules " + strings.Join(strs[:len(strs)-1], ", ") + " and " + strs[len(strs)-1]
+}
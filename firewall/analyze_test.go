@@ -0,0 +1,118 @@
+package firewall
+
+import (
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	groups := &types.Groups{
+		Address: map[string]*types.AddressGroup{
+			"lan": {Cidrs: []string{"192.168.1.0/24"}},
+		},
+	}
+
+	rs := &types.Ruleset{
+		Name:          "WAN_IN",
+		DefaultAction: "drop",
+		Rules: []*types.Rule{
+			{
+				Priority: 10,
+				Action:   "accept",
+				Protocol: "tcp",
+				Source:   &types.Source{AddressGroup: strptr("lan")},
+			},
+			{
+				Priority: 20,
+				Action:   "accept",
+				Protocol: "tcp",
+				Source:   &types.Source{Address: strptr("192.168.1.0/24")},
+			},
+			{
+				Priority:    30,
+				Action:      "drop",
+				Protocol:    "tcp",
+				Source:      &types.Source{Address: strptr("192.168.1.5")},
+				Destination: &types.Destination{Port: &types.PortRange{From: 22, To: 22}},
+			},
+			{
+				Priority: 40,
+				Action:   "accept",
+				Protocol: "udp",
+				Source:   &types.Source{Address: strptr("192.168.1.0/25")},
+			},
+			{
+				Priority: 50,
+				Action:   "accept",
+				Protocol: "udp",
+				Source:   &types.Source{Address: strptr("192.168.1.128/25")},
+			},
+			{
+				Priority: 60,
+				Action:   "accept",
+				Protocol: "tcp_udp",
+				Source:   &types.Source{Address: strptr("192.168.1.0/24")},
+			},
+			{
+				Priority: 70,
+				Action:   "drop",
+				Protocol: "all",
+				Source:   &types.Source{Address: strptr("10.0.0.0/8")},
+			},
+			{
+				Priority: 80,
+				Action:   "drop",
+				Protocol: "all",
+				Source:   &types.Source{Address: strptr("0.0.0.0/0")},
+			},
+		},
+	}
+
+	findings, err := Analyze(rs, groups)
+	require.NoError(t, err)
+
+	actual := []Finding{}
+	for _, f := range findings {
+		actual = append(actual, *f)
+	}
+
+	require.Equal(t, []Finding{
+		{
+			Kind:        FindingDuplicate,
+			Priority:    20,
+			Related:     []int{10},
+			Explanation: "rule 20 matches the same traffic as rule 10 and also accepts it",
+		},
+		{
+			Kind:        FindingShadowed,
+			Priority:    30,
+			Related:     []int{10},
+			Explanation: "rule 30 can never match because rule 10 matches all of its traffic first and accepts it",
+		},
+		{
+			Kind:        FindingShadowed,
+			Priority:    60,
+			Related:     []int{10, 40, 50},
+			Explanation: "rule 60 can never match because rules 10, 40 and 50 together match all of its traffic first",
+		},
+		{
+			Kind:        FindingRedundant,
+			Priority:    70,
+			Explanation: "rule 70 drops traffic that the default action would drop anyway",
+		},
+		{
+			Kind:        FindingConflict,
+			Priority:    80,
+			Related:     []int{10, 40, 50},
+			Explanation: "rule 80 drops traffic that is partly matched first by rules 10, 40 and 50, which accepts it instead",
+		},
+		{
+			Kind:        FindingRedundant,
+			Priority:    80,
+			Explanation: "rule 80 drops traffic that the default action would drop anyway",
+		},
+	}, actual)
+}
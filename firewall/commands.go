@@ -0,0 +1,993 @@
+package firewall
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/frankgreco/edge-sdk-go/types"
+)
+
+// Command is an EdgeOS configuration command such as
+// "set firewall name WAN_IN rule 10 action accept". Path does not include the
+// leading set or delete.
+type Command struct {
+	Delete bool
+	Path   []string
+}
+
+func (c Command) String() string {
+	tokens := make([]string, 0, len(c.Path)+1)
+	if c.Delete {
+		tokens = append(tokens, "delete")
+	} else {
+		tokens = append(tokens, "set")
+	}
+	for _, token := range c.Path {
+		tokens = append(tokens, quote(token))
+	}
+	return strings.Join(tokens, " ")
+}
+
+// shellSpecial are the characters that make a token need quoting, as the
+// shell would otherwise split, expand or interpret it, such as the ! used for
+// negation.
+const shellSpecial = " \t\n'\"\\#;&|<>(){}[]!$`*?~"
+
+// quote quotes tokens that contain whitespace, quotes or characters the shell
+// interprets. Tokens are wrapped in single quotes, within which the shell
+// expands nothing. An embedded single quote ends the quoted text, is escaped
+// with a backslash and starts a new quoted text. tokenize is its inverse.
+func quote(token string) string {
+	if token != "" && !strings.ContainsAny(token, shellSpecial) {
+		return token
+	}
+	return "'" + strings.ReplaceAll(token, "'", `'\''`) + "'"
+}
+
+// line is a rendered set command along with what its last token is.
+type line struct {
+	path []string
+	// valued is whether the last token of path is a value rather than a node.
+	valued bool
+	// multi is whether the node holds more than one value, in which case a set
+	// adds a value rather than replacing it.
+	multi bool
+}
+
+func node(path []string, tokens ...string) line {
+	return line{path: extend(path, tokens...)}
+}
+
+func leaf(path []string, name, val string) line {
+	return line{path: extend(path, name, val), valued: true}
+}
+
+func multiLeaf(path []string, name, val string) line {
+	return line{path: extend(path, name, val), valued: true, multi: true}
+}
+
+// Commands renders the firewall as set commands in the order EdgeOS shows them
+// with "show configuration commands".
+func Commands(fw *types.Firewall) []Command {
+	lines := render(fw)
+	cmds := make([]Command, len(lines))
+	for i, l := range lines {
+		cmds[i] = Command{Path: l.path}
+	}
+	return cmds
+}
+
+func render(fw *types.Firewall) []line {
+	lines := []line{}
+	if fw == nil {
+		return lines
+	}
+
+	if g := fw.Groups; g != nil {
+		names := make([]string, 0, len(g.Address))
+		for name := range g.Address {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			lines = append(lines, addressGroupLines(name, g.Address[name])...)
+		}
+
+		names = make([]string, 0, len(g.Port))
+		for name := range g.Port {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			lines = append(lines, portGroupLines(name, g.Port[name])...)
+		}
+	}
+
+	names := make([]string, 0, len(fw.Rulesets))
+	for name := range fw.Rulesets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		lines = append(lines, rulesetLines(name, fw.Rulesets[name])...)
+	}
+
+	return lines
+}
+
+func addressGroupLines(name string, g *types.AddressGroup) []line {
+	path := []string{"firewall", "group", "address-group", name}
+	lines := []line{}
+
+	if g != nil {
+		for _, cidr := range g.Cidrs {
+			lines = append(lines, multiLeaf(path, "address", cidr))
+		}
+		if g.Description != nil && *g.Description != "" {
+			lines = append(lines, leaf(path, "description", *g.Description))
+		}
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, node(path))
+	}
+	return lines
+}
+
+func portGroupLines(name string, g *types.PortGroup) []line {
+	path := []string{"firewall", "group", "port-group", name}
+	lines := []line{}
+
+	if g != nil {
+		if g.Description != nil && *g.Description != "" {
+			lines = append(lines, leaf(path, "description", *g.Description))
+		}
+		for _, port := range g.Ports {
+			lines = append(lines, multiLeaf(path, "port", strconv.Itoa(port)))
+		}
+		for _, r := range g.Ranges {
+			if r != nil {
+				lines = append(lines, multiLeaf(path, "port", portString(r)))
+			}
+		}
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, node(path))
+	}
+	return lines
+}
+
+func rulesetLines(name string, rs *types.Ruleset) []line {
+	path := []string{"firewall", "name", name}
+	lines := []line{}
+
+	if rs != nil {
+		if rs.DefaultAction != "" {
+			lines = append(lines, leaf(path, "default-action", rs.DefaultAction))
+		}
+		if rs.Description != nil && *rs.Description != "" {
+			lines = append(lines, leaf(path, "description", *rs.Description))
+		}
+		if rs.DefaultLogging != nil && *rs.DefaultLogging {
+			lines = append(lines, node(path, "enable-default-log"))
+		}
+
+		rules := make([]*types.Rule, 0, len(rs.Rules))
+		for _, rule := range rs.Rules {
+			if rule != nil {
+				rules = append(rules, rule)
+			}
+		}
+		sort.SliceStable(rules, func(i, j int) bool {
+			return rules[i].Priority < rules[j].Priority
+		})
+		for _, rule := range rules {
+			lines = append(lines, ruleLines(extend(path, "rule", strconv.Itoa(rule.Priority)), rule)...)
+		}
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, node(path))
+	}
+	return lines
+}
+
+func ruleLines(path []string, r *types.Rule) []line {
+	lines := []line{}
+
+	if r.Action != "" {
+		lines = append(lines, leaf(path, "action", r.Action))
+	}
+	if r.Description != nil && *r.Description != "" {
+		lines = append(lines, leaf(path, "description", *r.Description))
+	}
+	if d := r.Destination; d != nil {
+		lines = append(lines, endpointLines(extend(path, "destination"), d.Address, d.AddressGroup, d.PortGroup, nil, d.Port)...)
+	}
+	if r.Log != nil {
+		lines = append(lines, leaf(path, "log", toEnableDisable(*r.Log)))
+	}
+	if isSpecificProtocol(r.Protocol) {
+		lines = append(lines, leaf(path, "protocol", r.Protocol))
+	}
+	if s := r.Source; s != nil {
+		lines = append(lines, endpointLines(extend(path, "source"), s.Address, s.AddressGroup, s.PortGroup, s.MAC, s.Port)...)
+	}
+	if st := r.State; st != nil {
+		for _, state := range []struct {
+			name    string
+			enabled *bool
+		}{
+			{"established", st.Established},
+			{"invalid", st.Invalid},
+			{"new", st.New},
+			{"related", st.Related},
+		} {
+			lines = append(lines, leaf(extend(path, "state"), state.name, toEnableDisable(state.enabled != nil && *state.enabled)))
+		}
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, node(path))
+	}
+	return lines
+}
+
+func endpointLines(path []string, address, addressGroup, portGroup, mac *string, port *types.PortRange) []line {
+	lines := []line{}
+	if address != nil && *address != "" {
+		lines = append(lines, leaf(path, "address", *address))
+	}
+	if addressGroup != nil && *addressGroup != "" {
+		lines = append(lines, leaf(extend(path, "group"), "address-group", *addressGroup))
+	}
+	if portGroup != nil && *portGroup != "" {
+		lines = append(lines, leaf(extend(path, "group"), "port-group", *portGroup))
+	}
+	if mac != nil && *mac != "" {
+		lines = append(lines, leaf(path, "mac-address", *mac))
+	}
+	if port != nil {
+		lines = append(lines, leaf(path, "port", portString(port)))
+	}
+	return lines
+}
+
+func toEnableDisable(b bool) string {
+	if b {
+		return "enable"
+	}
+	return "disable"
+}
+
+func portString(r *types.PortRange) string {
+	if r.From == r.To {
+		return strconv.Itoa(r.From)
+	}
+	return fmt.Sprintf("%d-%d", r.From, r.To)
+}
+
+func extend(path []string, tokens ...string) []string {
+	extended := make([]string, 0, len(path)+len(tokens))
+	extended = append(extended, path...)
+	return append(extended, tokens...)
+}
+
+// DiffCommands returns the set and delete commands that turn current into
+// desired. Deletes come first and remove the highest node that no longer
+// exists, e.g. a whole rule rather than each of its values.
+func DiffCommands(current, desired *types.Firewall) []Command {
+	have := render(current)
+	want := render(desired)
+
+	wanted := map[string]bool{}
+	prefixes := map[string]bool{}
+	for _, l := range want {
+		wanted[pathKey(l.path)] = true
+		for i := 1; i <= len(l.path); i++ {
+			prefixes[pathKey(l.path[:i])] = true
+		}
+	}
+
+	had := map[string]bool{}
+	deleted := map[string]bool{}
+	cmds := []Command{}
+
+	for _, l := range have {
+		had[pathKey(l.path)] = true
+		if wanted[pathKey(l.path)] {
+			continue
+		}
+
+		i := 1
+		for i < len(l.path) && prefixes[pathKey(l.path[:i])] {
+			i++
+		}
+
+		// A single value that is replaced by a set does not need a delete.
+		if i == len(l.path) && l.valued && !l.multi {
+			continue
+		}
+
+		path := l.path[:i]
+		if alreadyDeleted(deleted, path) {
+			continue
+		}
+		deleted[pathKey(path)] = true
+		cmds = append(cmds, Command{
+			Delete: true,
+			Path:   path,
+		})
+	}
+
+	for _, l := range want {
+		if !had[pathKey(l.path)] {
+			cmds = append(cmds, Command{Path: l.path})
+		}
+	}
+
+	return cmds
+}
+
+func alreadyDeleted(deleted map[string]bool, path []string) bool {
+	for i := 1; i <= len(path); i++ {
+		if deleted[pathKey(path[:i])] {
+			return true
+		}
+	}
+	return false
+}
+
+func pathKey(path []string) string {
+	return strings.Join(path, "\x00")
+}
+
+// WriteCommands writes one command per line.
+func WriteCommands(w io.Writer, cmds []Command) error {
+	for _, cmd := range cmds {
+		if _, err := fmt.Fprintln(w, cmd.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseCommands reads a script of set and delete commands, one per line.
+// Blank lines, comments starting with # and the configure, commit, save and
+// exit commands are ignored.
+func ParseCommands(r io.Reader) ([]Command, error) {
+	cmds := []Command{}
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		tokens, err := tokenize(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err.Error())
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+
+		switch tokens[0] {
+		case "set", "delete":
+			if len(tokens) == 1 {
+				return nil, fmt.Errorf("line %d: %s requires a path", line, tokens[0])
+			}
+			cmds = append(cmds, Command{
+				Delete: tokens[0] == "delete",
+				Path:   tokens[1:],
+			})
+		case "configure", "commit", "save", "exit":
+		default:
+			return nil, fmt.Errorf("line %d: unknown command %q", line, tokens[0])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return cmds, nil
+}
+
+// tokenize splits a line into tokens the way the shell does: text within single
+// quotes is taken literally, a backslash escapes the next character and text
+// within double quotes is taken literally except for backslash escapes of $,
+// `, " and \\. Everything from a # at the start of a token is a comment.
+func tokenize(line string) ([]string, error) {
+	tokens := []string{}
+
+	var current strings.Builder
+	inToken := false
+	escaped := false
+	var quote rune
+
+	for _, c := range line {
+		switch {
+		case escaped:
+			// Within double quotes a backslash only escapes the characters
+			// that are special there.
+			if quote == '"' && !strings.ContainsRune("$`\"\\", c) {
+				current.WriteRune('\\')
+			}
+			current.WriteRune(c)
+			escaped = false
+		case quote == '\'':
+			if c == quote {
+				quote = 0
+				continue
+			}
+			current.WriteRune(c)
+		case c == '\\':
+			escaped = true
+			inToken = true
+		case quote == '"':
+			if c == quote {
+				quote = 0
+				continue
+			}
+			current.WriteRune(c)
+		case c == '\'' || c == '"':
+			quote = c
+			inToken = true
+		case c == '#' && !inToken:
+			return tokens, nil
+		case c == ' ' || c == '\t':
+			if inToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				inToken = false
+			}
+		default:
+			current.WriteRune(c)
+			inToken = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if escaped {
+		return nil, errors.New("unterminated escape")
+	}
+	if inToken {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
+
+// ImportCommands parses a script of commands and applies them to an empty
+// firewall. Commands outside of what types.Firewall models, such as a rule port
+// list that is not a single range, are returned as skipped rather than causing
+// an error. A rule is imported without the matches of its skipped commands, so
+// the skipped commands should be reviewed before the firewall is used.
+func ImportCommands(r io.Reader) (fw *types.Firewall, skipped []Command, err error) {
+	cmds, err := ParseCommands(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fw = new(types.Firewall)
+	skipped, err = ApplyCommands(fw, cmds)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fw, skipped, nil
+}
+
+// ApplyCommands applies the commands to the firewall in order. Commands outside
+// of what types.Firewall models are returned as skipped.
+func ApplyCommands(fw *types.Firewall, cmds []Command) (skipped []Command, err error) {
+	for _, cmd := range cmds {
+		ok, err := applyCommand(fw, cmd)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", cmd.String(), err.Error())
+		}
+		if !ok {
+			skipped = append(skipped, cmd)
+		}
+	}
+
+	for _, rs := range fw.Rulesets {
+		if rs != nil {
+			rs.SortRules()
+		}
+	}
+	return skipped, nil
+}
+
+// applyCommand applies a single command and reports whether it is modelled.
+func applyCommand(fw *types.Firewall, cmd Command) (bool, error) {
+	path := cmd.Path
+	if len(path) < 3 || path[0] != "firewall" {
+		return false, nil
+	}
+
+	switch {
+	case path[1] == "name":
+		return applyRuleset(fw, path[2], path[3:], cmd.Delete)
+	case path[1] == "group" && path[2] == "address-group" && len(path) > 3:
+		return applyAddressGroup(fw, path[3], path[4:], cmd.Delete)
+	case path[1] == "group" && path[2] == "port-group" && len(path) > 3:
+		return applyPortGroup(fw, path[3], path[4:], cmd.Delete)
+	}
+	return false, nil
+}
+
+func applyAddressGroup(fw *types.Firewall, name string, path []string, del bool) (bool, error) {
+	if fw.Groups == nil {
+		fw.Groups = new(types.Groups)
+	}
+	if fw.Groups.Address == nil {
+		fw.Groups.Address = map[string]*types.AddressGroup{}
+	}
+
+	if len(path) == 0 {
+		if del {
+			delete(fw.Groups.Address, name)
+		} else if fw.Groups.Address[name] == nil {
+			fw.Groups.Address[name] = &types.AddressGroup{Name: name}
+		}
+		return true, nil
+	}
+
+	g := fw.Groups.Address[name]
+	if g == nil {
+		if del {
+			return true, nil
+		}
+		g = &types.AddressGroup{Name: name}
+		fw.Groups.Address[name] = g
+	}
+
+	switch path[0] {
+	case "description":
+		return applyString(&g.Description, path[1:], del)
+	case "address":
+		if del {
+			if len(path) == 1 {
+				g.Cidrs = nil
+			} else {
+				g.Cidrs = removeString(g.Cidrs, path[1])
+			}
+			return true, nil
+		}
+		val, err := value(path[1:])
+		if err != nil {
+			return true, err
+		}
+		if !containsString(g.Cidrs, val) {
+			g.Cidrs = append(g.Cidrs, val)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func applyPortGroup(fw *types.Firewall, name string, path []string, del bool) (bool, error) {
+	if fw.Groups == nil {
+		fw.Groups = new(types.Groups)
+	}
+	if fw.Groups.Port == nil {
+		fw.Groups.Port = map[string]*types.PortGroup{}
+	}
+
+	if len(path) == 0 {
+		if del {
+			delete(fw.Groups.Port, name)
+		} else if fw.Groups.Port[name] == nil {
+			fw.Groups.Port[name] = &types.PortGroup{Name: name}
+		}
+		return true, nil
+	}
+
+	g := fw.Groups.Port[name]
+	if g == nil {
+		if del {
+			return true, nil
+		}
+		g = &types.PortGroup{Name: name}
+		fw.Groups.Port[name] = g
+	}
+
+	switch path[0] {
+	case "description":
+		return applyString(&g.Description, path[1:], del)
+	case "port":
+		if del && len(path) == 1 {
+			g.Ports, g.Ranges = nil, nil
+			return true, nil
+		}
+		val, err := value(path[1:])
+		if err != nil {
+			return true, err
+		}
+		r, err := parsePortRange(val)
+		if err != nil {
+			return true, err
+		}
+
+		if r.From == r.To {
+			ports := []int{}
+			for _, port := range g.Ports {
+				if port != r.From {
+					ports = append(ports, port)
+				}
+			}
+			if !del {
+				ports = append(ports, r.From)
+			}
+			g.Ports = ports
+			return true, nil
+		}
+
+		ranges := []*types.PortRange{}
+		for _, existing := range g.Ranges {
+			if existing != nil && *existing != *r {
+				ranges = append(ranges, existing)
+			}
+		}
+		if !del {
+			ranges = append(ranges, r)
+		}
+		g.Ranges = ranges
+		return true, nil
+	}
+	return false, nil
+}
+
+func applyRuleset(fw *types.Firewall, name string, path []string, del bool) (bool, error) {
+	if fw.Rulesets == nil {
+		fw.Rulesets = map[string]*types.Ruleset{}
+	}
+
+	if len(path) == 0 {
+		if del {
+			delete(fw.Rulesets, name)
+		} else if fw.Rulesets[name] == nil {
+			fw.Rulesets[name] = &types.Ruleset{Name: name}
+		}
+		return true, nil
+	}
+
+	rs := fw.Rulesets[name]
+	if rs == nil {
+		if del {
+			return true, nil
+		}
+		rs = &types.Ruleset{Name: name}
+		fw.Rulesets[name] = rs
+	}
+
+	switch path[0] {
+	case "description":
+		return applyString(&rs.Description, path[1:], del)
+	case "default-action":
+		if del {
+			rs.DefaultAction = ""
+			return true, nil
+		}
+		val, err := value(path[1:])
+		rs.DefaultAction = val
+		return true, err
+	case "enable-default-log":
+		if del {
+			rs.DefaultLogging = nil
+		} else {
+			t := true
+			rs.DefaultLogging = &t
+		}
+		return true, nil
+	case "rule":
+		if len(path) < 2 {
+			if del {
+				rs.Rules = nil
+				return true, nil
+			}
+			return true, errors.New("a rule priority is required")
+		}
+		priority, err := strconv.Atoi(path[1])
+		if err != nil {
+			return true, fmt.Errorf("malformed rule priority %q", path[1])
+		}
+		return applyRule(rs, priority, path[2:], del)
+	}
+	return false, nil
+}
+
+func applyRule(rs *types.Ruleset, priority int, path []string, del bool) (bool, error) {
+	var rule *types.Rule
+	for i, r := range rs.Rules {
+		if r == nil || r.Priority != priority {
+			continue
+		}
+		if del && len(path) == 0 {
+			rs.Rules = append(rs.Rules[:i], rs.Rules[i+1:]...)
+			return true, nil
+		}
+		rule = r
+		break
+	}
+
+	if rule == nil {
+		if del {
+			return true, nil
+		}
+		rule = &types.Rule{Priority: priority}
+		rs.Rules = append(rs.Rules, rule)
+	}
+
+	if len(path) == 0 {
+		return true, nil
+	}
+
+	switch path[0] {
+	case "action":
+		if del {
+			rule.Action = ""
+			return true, nil
+		}
+		val, err := value(path[1:])
+		rule.Action = val
+		return true, err
+	case "description":
+		return applyString(&rule.Description, path[1:], del)
+	case "protocol":
+		if del {
+			rule.Protocol = ""
+			return true, nil
+		}
+		val, err := value(path[1:])
+		rule.Protocol = val
+		return true, err
+	case "log":
+		if del {
+			rule.Log = nil
+			return true, nil
+		}
+		val, err := value(path[1:])
+		if err != nil {
+			return true, err
+		}
+		b, err := enableDisable(val)
+		rule.Log = &b
+		return true, err
+	case "state":
+		return applyState(rule, path[1:], del)
+	case "source":
+		if rule.Source == nil {
+			if del {
+				return true, nil
+			}
+			rule.Source = new(types.Source)
+		}
+		if len(path) == 1 {
+			if del {
+				rule.Source = nil
+			}
+			return true, nil
+		}
+		if path[1] == "mac-address" {
+			return applyString(&rule.Source.MAC, path[2:], del)
+		}
+		return applyEndpoint(&rule.Source.Address, &rule.Source.AddressGroup, &rule.Source.PortGroup, &rule.Source.Port, path[1:], del)
+	case "destination":
+		if rule.Destination == nil {
+			if del {
+				return true, nil
+			}
+			rule.Destination = new(types.Destination)
+		}
+		if len(path) == 1 {
+			if del {
+				rule.Destination = nil
+			}
+			return true, nil
+		}
+		return applyEndpoint(&rule.Destination.Address, &rule.Destination.AddressGroup, &rule.Destination.PortGroup, &rule.Destination.Port, path[1:], del)
+	}
+	return false, nil
+}
+
+func applyState(rule *types.Rule, path []string, del bool) (bool, error) {
+	if len(path) == 0 {
+		if del {
+			rule.State = nil
+		} else if rule.State == nil {
+			rule.State = new(types.State)
+		}
+		return true, nil
+	}
+
+	if rule.State == nil {
+		if del {
+			return true, nil
+		}
+		rule.State = new(types.State)
+	}
+
+	var field **bool
+	switch path[0] {
+	case "established":
+		field = &rule.State.Established
+	case "invalid":
+		field = &rule.State.Invalid
+	case "new":
+		field = &rule.State.New
+	case "related":
+		field = &rule.State.Related
+	default:
+		return false, nil
+	}
+
+	if del {
+		*field = nil
+		return true, nil
+	}
+
+	val, err := value(path[1:])
+	if err != nil {
+		return true, err
+	}
+	b, err := enableDisable(val)
+	if err != nil {
+		return true, err
+	}
+
+	// Like the JSON codec, disabled states are left unset.
+	*field = nil
+	if b {
+		*field = &b
+	}
+	return true, nil
+}
+
+func applyEndpoint(address, addressGroup, portGroup **string, port **types.PortRange, path []string, del bool) (bool, error) {
+	switch path[0] {
+	case "address":
+		return applyString(address, path[1:], del)
+	case "port":
+		if del {
+			*port = nil
+			return true, nil
+		}
+		val, err := value(path[1:])
+		if err != nil {
+			return true, err
+		}
+		ranges, err := parsePortList(val)
+		if err != nil {
+			return true, err
+		}
+		// A rule matches a single port or range, so a list of ports that
+		// does not merge into one range is left to the caller.
+		if len(ranges) != 1 {
+			return false, nil
+		}
+		*port = ranges[0]
+		return true, nil
+	case "group":
+		if len(path) == 1 {
+			if del {
+				*addressGroup, *portGroup = nil, nil
+			}
+			return true, nil
+		}
+		switch path[1] {
+		case "address-group":
+			return applyString(addressGroup, path[2:], del)
+		case "port-group":
+			return applyString(portGroup, path[2:], del)
+		}
+	}
+	return false, nil
+}
+
+func applyString(field **string, path []string, del bool) (bool, error) {
+	if del {
+		*field = nil
+		return true, nil
+	}
+	val, err := value(path)
+	if err != nil {
+		return true, err
+	}
+	*field = &val
+	return true, nil
+}
+
+func value(path []string) (string, error) {
+	if len(path) != 1 {
+		return "", errors.New("exactly one value is required")
+	}
+	return path[0], nil
+}
+
+func enableDisable(val string) (bool, error) {
+	switch val {
+	case "enable":
+		return true, nil
+	case "disable":
+		return false, nil
+	}
+	return false, fmt.Errorf("%q must be enable or disable", val)
+}
+
+// parsePortList parses a comma separated list of ports and port ranges, such
+// as 80,443,8000-8080, merging overlapping and adjacent ranges.
+func parsePortList(val string) ([]*types.PortRange, error) {
+	var ranges []*types.PortRange
+	for _, elem := range strings.Split(val, ",") {
+		r, err := parsePortRange(elem)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].From < ranges[j].From
+	})
+
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := merged[len(merged)-1]
+		if r.From > last.To+1 {
+			merged = append(merged, r)
+			continue
+		}
+		if r.To > last.To {
+			last.To = r.To
+		}
+	}
+	return merged, nil
+}
+
+func parsePortRange(val string) (*types.PortRange, error) {
+	fromTo := strings.Split(val, "-")
+	if len(fromTo) > 2 {
+		return nil, fmt.Errorf("malformed port %q", val)
+	}
+
+	from, err := strconv.Atoi(fromTo[0])
+	if err != nil {
+		return nil, fmt.Errorf("malformed port %q", val)
+	}
+	to := from
+	if len(fromTo) == 2 {
+		if to, err = strconv.Atoi(fromTo[1]); err != nil {
+			return nil, fmt.Errorf("malformed port %q", val)
+		}
+	}
+
+	return &types.PortRange{
+		From: from,
+		To:   to,
+	}, nil
+}
+
+func containsString(vals []string, val string) bool {
+	for _, elem := range vals {
+		if elem == val {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(vals []string, val string) []string {
+	kept := []string{}
+	for _, elem := range vals {
+		if elem != val {
+			kept = append(kept, elem)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,164 @@
+package firewall
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+const commandScript = `set firewall group address-group servers address 10.0.0.1
+set firewall group address-group servers address 10.0.0.2-10.0.0.9
+set firewall group address-group servers description 'web servers'
+set firewall group port-group web port 80
+set firewall group port-group web port 8000-8080
+set firewall name WAN_IN default-action drop
+set firewall name WAN_IN description 'WAN to '\''internal'\'''
+set firewall name WAN_IN enable-default-log
+set firewall name WAN_IN rule 10 action accept
+set firewall name WAN_IN rule 10 log disable
+set firewall name WAN_IN rule 10 state established enable
+set firewall name WAN_IN rule 10 state invalid disable
+set firewall name WAN_IN rule 10 state new disable
+set firewall name WAN_IN rule 10 state related enable
+set firewall name WAN_IN rule 20 action accept
+set firewall name WAN_IN rule 20 description 'web traffic'
+set firewall name WAN_IN rule 20 destination group address-group servers
+set firewall name WAN_IN rule 20 destination group port-group web
+set firewall name WAN_IN rule 20 protocol tcp
+set firewall name WAN_IN rule 20 source address '!192.168.0.0/16'
+set firewall name WAN_IN rule 20 source mac-address 00:11:22:33:44:55
+set firewall name WAN_IN rule 20 source port 1024-65535
+`
+
+func TestCommandsRoundTrip(t *testing.T) {
+	fw, skipped, err := ImportCommands(strings.NewReader("configure\n# pasted from the forum\n" + commandScript + "set firewall all-ping enable\ncommit\n"))
+	require.NoError(t, err)
+	require.Equal(t, []Command{{Path: []string{"firewall", "all-ping", "enable"}}}, skipped)
+
+	rs := fw.Rulesets["WAN_IN"]
+	require.Equal(t, "WAN to 'internal'", *rs.Description)
+	require.True(t, *rs.DefaultLogging)
+	require.Len(t, rs.Rules, 2)
+	require.Equal(t, &types.State{Established: boolptr(true), Related: boolptr(true)}, rs.Rules[0].State)
+	require.Equal(t, &types.PortRange{From: 1024, To: 65535}, rs.Rules[1].Source.Port)
+	require.Equal(t, []int{80}, fw.Groups.Port["web"].Ports)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCommands(&buf, Commands(fw)))
+	require.Equal(t, commandScript, buf.String())
+}
+
+func TestImportPortLists(t *testing.T) {
+	fw, skipped, err := ImportCommands(strings.NewReader(`set firewall name WAN_IN rule 30 action accept
+set firewall name WAN_IN rule 30 protocol tcp
+set firewall name WAN_IN rule 30 destination port 80,443
+set firewall name WAN_IN rule 40 action accept
+set firewall name WAN_IN rule 40 protocol tcp
+set firewall name WAN_IN rule 40 destination port 8081,8000-8080,8080
+`))
+	require.NoError(t, err)
+	require.Equal(t, []Command{{Path: []string{"firewall", "name", "WAN_IN", "rule", "30", "destination", "port", "80,443"}}}, skipped)
+
+	rs := fw.Rulesets["WAN_IN"]
+	require.Len(t, rs.Rules, 2)
+	require.Nil(t, rs.Rules[0].Destination.Port)
+	require.Equal(t, &types.PortRange{From: 8000, To: 8081}, rs.Rules[1].Destination.Port)
+
+	_, _, err = ImportCommands(strings.NewReader("set firewall name WAN_IN rule 30 destination port 80,https"))
+	require.EqualError(t, err, `set firewall name WAN_IN rule 30 destination port 80,https: malformed port "https"`)
+}
+
+func TestQuoteRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		token    string
+		rendered string
+	}{
+		{token: "accept", rendered: "accept"},
+		{token: "", rendered: "''"},
+		{token: "web traffic", rendered: "'web traffic'"},
+		{token: "it's", rendered: `'it'\''s'`},
+		{token: `it's "x"`, rendered: `'it'\''s "x"'`},
+		{token: "$HOME", rendered: "'$HOME'"},
+		{token: "!192.168.0.0/16", rendered: "'!192.168.0.0/16'"},
+		{token: `C:\temp`, rendered: `'C:\temp'`},
+		{token: "`id`", rendered: "'`id`'"},
+		{token: "#1", rendered: "'#1'"},
+	} {
+		cmd := Command{Path: []string{"firewall", "name", "WAN_IN", "description", test.token}}
+		rendered := cmd.String()
+		require.Equal(t, "set firewall name WAN_IN description "+test.rendered, rendered, test.token)
+
+		tokens, err := tokenize(rendered)
+		require.NoError(t, err, test.token)
+		require.Equal(t, append([]string{"set"}, cmd.Path...), tokens, test.token)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	for _, test := range []struct {
+		line     string
+		expected []string
+	}{
+		{line: `set a "it's \"x\""`, expected: []string{"set", "a", `it's "x"`}},
+		{line: `set a "\$HOME \d"`, expected: []string{"set", "a", `$HOME \d`}},
+		{line: `set a it\'s\ x`, expected: []string{"set", "a", "it's x"}},
+		{line: `set a 'x'"y"z # comment`, expected: []string{"set", "a", "xyz"}},
+	} {
+		tokens, err := tokenize(test.line)
+		require.NoError(t, err, test.line)
+		require.Equal(t, test.expected, tokens, test.line)
+	}
+
+	_, err := tokenize(`set a b\`)
+	require.Error(t, err)
+}
+
+func TestDiffCommands(t *testing.T) {
+	current, _, err := ImportCommands(strings.NewReader(commandScript))
+	require.NoError(t, err)
+
+	desired, _, err := ImportCommands(strings.NewReader(commandScript + `
+delete firewall group address-group servers address 10.0.0.1
+set firewall group address-group servers address 10.0.0.10
+delete firewall name WAN_IN enable-default-log
+delete firewall name WAN_IN rule 10
+set firewall name WAN_IN rule 20 description 'all web traffic'
+delete firewall name WAN_IN rule 20 source
+set firewall name WAN_IN rule 30 action drop
+`))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCommands(&buf, DiffCommands(current, desired)))
+	require.Equal(t, `delete firewall group address-group servers address 10.0.0.1
+delete firewall name WAN_IN enable-default-log
+delete firewall name WAN_IN rule 10
+delete firewall name WAN_IN rule 20 source
+set firewall group address-group servers address 10.0.0.10
+set firewall name WAN_IN rule 20 description 'all web traffic'
+set firewall name WAN_IN rule 30 action drop
+`, buf.String())
+
+	require.Empty(t, DiffCommands(current, current))
+}
+
+func TestParseCommandsErrors(t *testing.T) {
+	for _, script := range []string{
+		"set firewall name WAN_IN description 'unterminated",
+		"show firewall",
+		"set",
+	} {
+		_, err := ParseCommands(strings.NewReader(script))
+		require.Error(t, err, script)
+	}
+
+	_, _, err := ImportCommands(strings.NewReader("set firewall name WAN_IN rule ten action accept"))
+	require.Error(t, err)
+
+	_, _, err = ImportCommands(strings.NewReader("set firewall name WAN_IN rule 10 log maybe"))
+	require.Error(t, err)
+}
@@ -0,0 +1,206 @@
+package firewall
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/frankgreco/edge-sdk-go/types"
+)
+
+// ConnectionState is the connection tracking state of a packet.
+type ConnectionState string
+
+const (
+	StateNew         ConnectionState = "new"
+	StateEstablished ConnectionState = "established"
+	StateRelated     ConnectionState = "related"
+	StateInvalid     ConnectionState = "invalid"
+)
+
+// defaultAction is what EdgeOS does with traffic that no rule matches if the
+// ruleset does not have a default action.
+const defaultAction = "drop"
+
+// Packet is a synthetic IPv4 packet that can be evaluated against a ruleset.
+// Ports are ignored unless the protocol is tcp or udp. If State is empty the
+// packet is treated as a new connection.
+type Packet struct {
+	Protocol        string
+	Source          net.IP
+	Destination     net.IP
+	SourcePort      int
+	DestinationPort int
+	SourceMAC       net.HardwareAddr
+	State           ConnectionState
+}
+
+// Verdict is the outcome of evaluating a packet against a ruleset.
+type Verdict struct {
+	// Rule is the first rule that matched the packet or nil if the packet fell
+	// through to the default action.
+	Rule *types.Rule
+	// Priority is the priority of Rule or zero if no rule matched.
+	Priority int
+	// Action is what happens to the packet.
+	Action string
+	// DefaultAction is the action of the ruleset for packets no rule matches.
+	DefaultAction string
+	// Log is whether the packet is logged by the rule or the default action.
+	Log bool
+}
+
+// Matched reports whether a rule matched the packet.
+func (v *Verdict) Matched() bool {
+	return v.Rule != nil
+}
+
+func (v *Verdict) String() string {
+	if v.Rule == nil {
+		return fmt.Sprintf("%s by default action", v.Action)
+	}
+	return fmt.Sprintf("%s by rule %d", v.Action, v.Priority)
+}
+
+// Evaluate returns what the ruleset does with the packet. Rules are evaluated in
+// priority order and the first rule that matches decides. Address and port
+// groups are resolved from groups, which may be nil if no rule uses a group.
+func Evaluate(rs *types.Ruleset, groups *types.Groups, p *Packet) (*Verdict, error) {
+	values, err := p.values()
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := compileRuleset(rs, groups)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := rs.DefaultAction
+	if fallback == "" {
+		fallback = defaultAction
+	}
+
+	for _, r := range rules {
+		if r.criteria.containsPacket(values) {
+			return &Verdict{
+				Rule:          r.rule,
+				Priority:      r.rule.Priority,
+				Action:        r.rule.Action,
+				DefaultAction: fallback,
+				Log:           r.rule.Log != nil && *r.rule.Log,
+			}, nil
+		}
+	}
+
+	return &Verdict{
+		Action:        fallback,
+		DefaultAction: fallback,
+		Log:           rs.DefaultLogging != nil && *rs.DefaultLogging,
+	}, nil
+}
+
+type compiledRule struct {
+	rule     *types.Rule
+	criteria *criteria
+}
+
+// compileRuleset compiles every rule of the ruleset in priority order without
+// modifying the ruleset.
+func compileRuleset(rs *types.Ruleset, groups *types.Groups) ([]*compiledRule, error) {
+	rules := make([]*types.Rule, 0, len(rs.Rules))
+	for _, rule := range rs.Rules {
+		if rule != nil {
+			rules = append(rules, rule)
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Priority < rules[j].Priority
+	})
+
+	compiled := make([]*compiledRule, len(rules))
+	for i, rule := range rules {
+		c, err := compileRule(rule, groups)
+		if err != nil {
+			return nil, err
+		}
+		compiled[i] = &compiledRule{
+			rule:     rule,
+			criteria: c,
+		}
+	}
+	return compiled, nil
+}
+
+func (c *criteria) containsPacket(values [numDimensions]uint64) bool {
+	for i := range c {
+		if !c[i].contains(values[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// values returns the value of the packet for each dimension.
+func (p *Packet) values() (values [numDimensions]uint64, err error) {
+	protocol := strings.ToLower(p.Protocol)
+	number, ok := protocolNumbers[protocol]
+	if !ok {
+		protocols, err := compileProtocol(protocol)
+		if err != nil || protocols.any || len(protocols.spans) != 1 || protocols.spans[0].from != protocols.spans[0].to {
+			return values, fmt.Errorf("The packet protocol %q is not a single known protocol.", p.Protocol)
+		}
+		number = protocols.spans[0].from
+	}
+	values[dimProtocol] = number
+
+	for _, ip := range []struct {
+		dim  int
+		ip   net.IP
+		name string
+	}{
+		{dimSourceAddress, p.Source, "source"},
+		{dimDestinationAddress, p.Destination, "destination"},
+	} {
+		if ip.ip == nil {
+			return values, fmt.Errorf("The packet %s address is required.", ip.name)
+		}
+		if ip.ip.To4() == nil {
+			return values, fmt.Errorf("The packet %s address %s is not an IPv4 address.", ip.name, ip.ip)
+		}
+		if values[ip.dim], err = ipv4(ip.ip.String()); err != nil {
+			return values, err
+		}
+	}
+
+	if number == protocolNumbers["tcp"] || number == protocolNumbers["udp"] {
+		for _, port := range []int{p.SourcePort, p.DestinationPort} {
+			if port < 0 || port > maxPort {
+				return values, fmt.Errorf("The packet port %d is out of range.", port)
+			}
+		}
+		values[dimSourcePort] = uint64(p.SourcePort)
+		values[dimDestinationPort] = uint64(p.DestinationPort)
+	}
+
+	if p.SourceMAC != nil {
+		values[dimSourceMAC] = macValue(p.SourceMAC)
+	} else {
+		// A packet without a MAC address must not match a rule with one.
+		values[dimSourceMAC] = maxMAC + 1
+	}
+
+	state := p.State
+	if state == "" {
+		state = StateNew
+	}
+	v, ok := stateValues[state]
+	if !ok {
+		return values, errors.New("The packet connection state must be one of new, established, related or invalid.")
+	}
+	values[dimState] = v
+
+	return values, nil
+}
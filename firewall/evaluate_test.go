@@ -0,0 +1,198 @@
+package firewall
+
+import (
+	"net"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	groups := &types.Groups{
+		Address: map[string]*types.AddressGroup{
+			"admins": {Cidrs: []string{"192.168.1.10", "192.168.1.20-192.168.1.29"}},
+		},
+		Port: map[string]*types.PortGroup{
+			"web": (&types.PortGroup{Ports: []int{80, 443}}).WithRanges(8000, 8080),
+		},
+	}
+
+	rs := &types.Ruleset{
+		Name:           "WAN_IN",
+		DefaultAction:  "drop",
+		DefaultLogging: boolptr(true),
+		Rules: []*types.Rule{
+			{
+				Priority: 40,
+				Action:   "accept",
+				Protocol: "tcp",
+				Destination: &types.Destination{
+					PortGroup: strptr("web"),
+				},
+			},
+			{
+				Priority: 10,
+				Action:   "accept",
+				Protocol: "all",
+				State: &types.State{
+					Established: boolptr(true),
+					Related:     boolptr(true),
+				},
+			},
+			{
+				Priority: 20,
+				Action:   "drop",
+				Protocol: "all",
+				State: &types.State{
+					Invalid: boolptr(true),
+				},
+				Log: boolptr(true),
+			},
+			{
+				Priority: 30,
+				Action:   "accept",
+				Protocol: "tcp",
+				Source: &types.Source{
+					AddressGroup: strptr("admins"),
+				},
+				Destination: &types.Destination{
+					Address: strptr("10.0.0.0/24"),
+					Port:    &types.PortRange{From: 22, To: 22},
+				},
+			},
+			{
+				Priority: 50,
+				Action:   "reject",
+				Protocol: "!icmp",
+				Source: &types.Source{
+					MAC: strptr("00:11:22:33:44:55"),
+				},
+			},
+			{
+				Priority: 60,
+				Action:   "accept",
+				Protocol: "udp",
+				Source: &types.Source{
+					MAC: strptr("!00:11:22:33:44:55"),
+				},
+				Destination: &types.Destination{
+					Port: &types.PortRange{From: 53, To: 53},
+				},
+			},
+		},
+	}
+
+	mac, err := net.ParseMAC("00:11:22:33:44:55")
+	require.NoError(t, err)
+	other, err := net.ParseMAC("66:77:88:99:aa:bb")
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		name     string
+		packet   *Packet
+		priority int
+		action   string
+		log      bool
+	}{
+		{
+			name:     "established",
+			packet:   &Packet{Protocol: "udp", Source: net.ParseIP("1.1.1.1"), Destination: net.ParseIP("10.0.0.1"), State: StateEstablished},
+			priority: 10,
+			action:   "accept",
+		},
+		{
+			name:     "invalid",
+			packet:   &Packet{Protocol: "tcp", Source: net.ParseIP("1.1.1.1"), Destination: net.ParseIP("10.0.0.1"), DestinationPort: 22, State: StateInvalid},
+			priority: 20,
+			action:   "drop",
+			log:      true,
+		},
+		{
+			name:     "ssh from an admin range",
+			packet:   &Packet{Protocol: "tcp", Source: net.ParseIP("192.168.1.25"), Destination: net.ParseIP("10.0.0.5"), SourcePort: 40000, DestinationPort: 22},
+			priority: 30,
+			action:   "accept",
+		},
+		{
+			name:   "ssh from elsewhere",
+			packet: &Packet{Protocol: "tcp", Source: net.ParseIP("192.168.1.30"), Destination: net.ParseIP("10.0.0.5"), DestinationPort: 22},
+			action: "drop",
+			log:    true,
+		},
+		{
+			name:     "web port range",
+			packet:   &Packet{Protocol: "tcp", Source: net.ParseIP("8.8.8.8"), Destination: net.ParseIP("10.0.0.5"), DestinationPort: 8042},
+			priority: 40,
+			action:   "accept",
+		},
+		{
+			name:   "web port over udp",
+			packet: &Packet{Protocol: "udp", Source: net.ParseIP("8.8.8.8"), Destination: net.ParseIP("10.0.0.5"), DestinationPort: 443},
+			action: "drop",
+			log:    true,
+		},
+		{
+			name:     "negated protocol with mac",
+			packet:   &Packet{Protocol: "gre", Source: net.ParseIP("8.8.8.8"), Destination: net.ParseIP("10.0.0.5"), SourceMAC: mac},
+			priority: 50,
+			action:   "reject",
+		},
+		{
+			name:   "negated protocol excludes icmp",
+			packet: &Packet{Protocol: "icmp", Source: net.ParseIP("8.8.8.8"), Destination: net.ParseIP("10.0.0.5"), SourceMAC: mac},
+			action: "drop",
+			log:    true,
+		},
+		{
+			name:     "negated mac",
+			packet:   &Packet{Protocol: "udp", Source: net.ParseIP("8.8.8.8"), Destination: net.ParseIP("10.0.0.5"), DestinationPort: 53, SourceMAC: other},
+			priority: 60,
+			action:   "accept",
+		},
+		{
+			name:   "negated mac without a mac",
+			packet: &Packet{Protocol: "udp", Source: net.ParseIP("8.8.8.8"), Destination: net.ParseIP("10.0.0.5"), DestinationPort: 53},
+			action: "drop",
+			log:    true,
+		},
+	} {
+		verdict, err := Evaluate(rs, groups, test.packet)
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.priority, verdict.Priority, test.name)
+		require.Equal(t, test.priority != 0, verdict.Matched(), test.name)
+		require.Equal(t, test.action, verdict.Action, test.name)
+		require.Equal(t, "drop", verdict.DefaultAction, test.name)
+		require.Equal(t, test.log, verdict.Log, test.name)
+	}
+
+	require.Equal(t, 40, rs.Rules[0].Priority, "the ruleset must not be reordered")
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	rs := &types.Ruleset{
+		Rules: []*types.Rule{
+			{
+				Priority: 10,
+				Action:   "accept",
+				Source: &types.Source{
+					AddressGroup: strptr("missing"),
+				},
+			},
+		},
+	}
+
+	_, err := Evaluate(rs, nil, &Packet{Protocol: "tcp", Source: net.ParseIP("1.1.1.1"), Destination: net.ParseIP("2.2.2.2")})
+	require.EqualError(t, err, "rule 10: source address group missing does not exist")
+
+	_, err = Evaluate(&types.Ruleset{}, nil, &Packet{Protocol: "tcp", Source: net.ParseIP("fe80::1"), Destination: net.ParseIP("2.2.2.2")})
+	require.Error(t, err)
+
+	_, err = Evaluate(&types.Ruleset{}, nil, &Packet{Protocol: "all", Source: net.ParseIP("1.1.1.1"), Destination: net.ParseIP("2.2.2.2")})
+	require.Error(t, err)
+}
+
+func strptr(s string) *string {
+	return &s
+}
@@ -0,0 +1,332 @@
+package firewall
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/frankgreco/edge-sdk-go/types"
+)
+
+// Export is a firewall translated to the syntax of another firewall.
+type Export struct {
+	// Sets creates the address and port groups. It is empty when the target
+	// declares sets as part of the ruleset.
+	Sets string
+	// Ruleset is the translated rulesets.
+	Ruleset string
+	// Issues are the constructs that could not be translated.
+	Issues []*ExportIssue
+}
+
+// ExportIssue is a construct that could not be translated, along with what was
+// done about it.
+type ExportIssue struct {
+	// Ruleset is empty for issues with groups.
+	Ruleset string
+	// Group is empty for issues with rulesets.
+	Group string
+	// Priority is zero for issues with the ruleset itself.
+	Priority int
+	Message  string
+}
+
+func (i *ExportIssue) String() string {
+	switch {
+	case i.Group != "":
+		return fmt.Sprintf("group %s: %s", i.Group, i.Message)
+	case i.Priority != 0:
+		return fmt.Sprintf("ruleset %s rule %d: %s", i.Ruleset, i.Priority, i.Message)
+	default:
+		return fmt.Sprintf("ruleset %s: %s", i.Ruleset, i.Message)
+	}
+}
+
+// exportRule is a rule reduced to what the exporters translate.
+type exportRule struct {
+	ruleset  string
+	priority int
+	// protocols are the protocols the rule matches, which is more than one
+	// for tcp_udp and none for any protocol.
+	protocols      []string
+	negateProtocol bool
+	source         exportEndpoint
+	destination    exportEndpoint
+	mac            string
+	negateMAC      bool
+	states         []string
+	log            bool
+	action         string
+	comment        string
+}
+
+type exportEndpoint struct {
+	address       string
+	negateAddress bool
+	addressGroup  string
+	negateGroup   bool
+	port          *types.PortRange
+	portGroup     string
+}
+
+func (e *exportEndpoint) hasPort() bool {
+	return e.port != nil || e.portGroup != ""
+}
+
+// isRange reports whether the address is a range such as 10.0.0.1-10.0.0.9.
+func (e *exportEndpoint) isRange() bool {
+	return strings.Contains(e.address, "-")
+}
+
+// exporter holds the state shared by the iptables and nftables exporters.
+type exporter struct {
+	fw     *types.Firewall
+	issues []*ExportIssue
+	// skippedPortGroups are port groups that share a name with an address
+	// group. Sets share a namespace, so they cannot be created.
+	skippedPortGroups map[string]bool
+}
+
+func newExporter(fw *types.Firewall) *exporter {
+	e := &exporter{
+		fw:                fw,
+		skippedPortGroups: map[string]bool{},
+	}
+	if fw == nil {
+		e.fw = &types.Firewall{}
+	}
+
+	if g := e.fw.Groups; g != nil {
+		names := make([]string, 0, len(g.Port))
+		for name := range g.Port {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if _, ok := g.Address[name]; ok {
+				e.skippedPortGroups[name] = true
+				e.groupIssue(name, "port group has the same name as an address group and was skipped")
+			}
+		}
+	}
+	return e
+}
+
+func (e *exporter) ruleIssue(ruleset string, priority int, format string, args ...interface{}) {
+	e.issues = append(e.issues, &ExportIssue{
+		Ruleset:  ruleset,
+		Priority: priority,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (e *exporter) groupIssue(group string, format string, args ...interface{}) {
+	e.issues = append(e.issues, &ExportIssue{
+		Group:   group,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (e *exporter) rulesets() []*types.Ruleset {
+	names := make([]string, 0, len(e.fw.Rulesets))
+	for name := range e.fw.Rulesets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rulesets := make([]*types.Ruleset, 0, len(names))
+	for _, name := range names {
+		rs := e.fw.Rulesets[name]
+		if rs == nil {
+			continue
+		}
+		tmp := *rs
+		tmp.Name = name
+		tmp.Rules = append([]*types.Rule{}, rs.Rules...)
+		tmp.SortRules()
+		rulesets = append(rulesets, &tmp)
+	}
+	return rulesets
+}
+
+func (e *exporter) addressGroups() []*types.AddressGroup {
+	if e.fw.Groups == nil {
+		return nil
+	}
+	names := make([]string, 0, len(e.fw.Groups.Address))
+	for name := range e.fw.Groups.Address {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var groups []*types.AddressGroup
+	for _, name := range names {
+		if g := e.fw.Groups.Address[name]; g != nil {
+			tmp := *g
+			tmp.Name = name
+			groups = append(groups, &tmp)
+		}
+	}
+	return groups
+}
+
+func (e *exporter) portGroups() []*types.PortGroup {
+	if e.fw.Groups == nil {
+		return nil
+	}
+	names := make([]string, 0, len(e.fw.Groups.Port))
+	for name := range e.fw.Groups.Port {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var groups []*types.PortGroup
+	for _, name := range names {
+		if g := e.fw.Groups.Port[name]; g != nil && !e.skippedPortGroups[name] {
+			tmp := *g
+			tmp.Name = name
+			groups = append(groups, &tmp)
+		}
+	}
+	return groups
+}
+
+// rule reduces a rule to what the exporters translate. Rules that cannot be
+// translated by any exporter are reported and nil is returned.
+func (e *exporter) rule(rs *types.Ruleset, r *types.Rule) *exportRule {
+	out := &exportRule{
+		ruleset:  rs.Name,
+		priority: r.Priority,
+		log:      r.Log != nil && *r.Log,
+		action:   r.Action,
+	}
+
+	switch r.Action {
+	case "accept", "drop", "reject":
+	default:
+		e.ruleIssue(rs.Name, r.Priority, "action %q has no equivalent; rule skipped", r.Action)
+		return nil
+	}
+
+	if r.Description != nil {
+		out.comment = *r.Description
+	}
+
+	protocol := strings.ToLower(r.Protocol)
+	if strings.HasPrefix(protocol, "!") {
+		out.negateProtocol = true
+		protocol = strings.TrimPrefix(protocol, "!")
+	}
+	switch protocol {
+	case "", "*", "all":
+	case "tcp_udp":
+		out.protocols = []string{"tcp", "udp"}
+	default:
+		out.protocols = []string{protocol}
+	}
+
+	if r.Source != nil {
+		out.source = exportEndpoint{
+			address:      stringValue(r.Source.Address),
+			addressGroup: stringValue(r.Source.AddressGroup),
+			port:         r.Source.Port,
+			portGroup:    stringValue(r.Source.PortGroup),
+		}
+		out.mac = stringValue(r.Source.MAC)
+	}
+	if r.Destination != nil {
+		out.destination = exportEndpoint{
+			address:      stringValue(r.Destination.Address),
+			addressGroup: stringValue(r.Destination.AddressGroup),
+			port:         r.Destination.Port,
+			portGroup:    stringValue(r.Destination.PortGroup),
+		}
+	}
+
+	out.negateMAC, out.mac = negated(out.mac)
+	for _, ep := range []*exportEndpoint{&out.source, &out.destination} {
+		ep.negateAddress, ep.address = negated(ep.address)
+		ep.negateGroup, ep.addressGroup = negated(ep.addressGroup)
+
+		if ep.addressGroup != "" && (e.fw.Groups == nil || e.fw.Groups.Address[ep.addressGroup] == nil) {
+			e.ruleIssue(rs.Name, r.Priority, "address group %s does not exist; rule skipped", ep.addressGroup)
+			return nil
+		}
+		if ep.portGroup != "" && (e.fw.Groups == nil || e.fw.Groups.Port[ep.portGroup] == nil) {
+			e.ruleIssue(rs.Name, r.Priority, "port group %s does not exist; rule skipped", ep.portGroup)
+			return nil
+		}
+		if e.skippedPortGroups[ep.portGroup] {
+			e.ruleIssue(rs.Name, r.Priority, "port group %s was skipped; rule skipped", ep.portGroup)
+			return nil
+		}
+	}
+
+	if out.source.hasPort() || out.destination.hasPort() {
+		for _, p := range out.protocols {
+			if p != "tcp" && p != "udp" {
+				out.protocols = nil
+				break
+			}
+		}
+		if len(out.protocols) == 0 || out.negateProtocol {
+			e.ruleIssue(rs.Name, r.Priority, "ports require protocol tcp, udp or tcp_udp; rule skipped")
+			return nil
+		}
+	}
+
+	if st := r.State; st != nil {
+		for _, state := range []struct {
+			name    string
+			enabled *bool
+		}{
+			{"established", st.Established},
+			{"invalid", st.Invalid},
+			{"new", st.New},
+			{"related", st.Related},
+		} {
+			if state.enabled != nil && *state.enabled {
+				out.states = append(out.states, state.name)
+			}
+		}
+	}
+
+	return out
+}
+
+// logPrefix is the prefix EdgeOS gives to the log messages of a rule, e.g.
+// [WAN_IN-10-A] for a rule that accepts.
+func logPrefix(ruleset, rule, action string) string {
+	return fmt.Sprintf("[%s-%s-%s]", ruleset, rule, strings.ToUpper(action[:1]))
+}
+
+func defaultActionOf(rs *types.Ruleset) string {
+	if rs.DefaultAction == "" {
+		return defaultAction
+	}
+	return rs.DefaultAction
+}
+
+func negated(val string) (bool, string) {
+	if strings.HasPrefix(val, "!") {
+		return true, strings.TrimPrefix(val, "!")
+	}
+	return false, val
+}
+
+// isIPv6 reports whether an address, network or range is IPv6.
+func isIPv6(val string) bool {
+	val = strings.SplitN(val, "-", 2)[0]
+	val = strings.SplitN(val, "/", 2)[0]
+	ip := net.ParseIP(val)
+	return ip != nil && ip.To4() == nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,251 @@
+package firewall
+
+import (
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func exportFirewall() *types.Firewall {
+	return &types.Firewall{
+		Groups: &types.Groups{
+			Address: map[string]*types.AddressGroup{
+				"servers": {
+					Cidrs: []string{"192.168.1.10", "192.168.1.0/28", "2001:db8::1"},
+				},
+			},
+			Port: map[string]*types.PortGroup{
+				"web": (&types.PortGroup{}).WithPorts([]int{80, 443}).WithRanges(8000, 8080),
+			},
+		},
+		Rulesets: map[string]*types.Ruleset{
+			"WAN_IN": {
+				DefaultAction:  "drop",
+				DefaultLogging: boolptr(true),
+				Rules: []*types.Rule{
+					{
+						Priority: 20,
+						Action:   "accept",
+						Protocol: "tcp_udp",
+						Destination: &types.Destination{
+							AddressGroup: strptr("servers"),
+							PortGroup:    strptr("web"),
+						},
+						Log: boolptr(true),
+					},
+					{
+						Priority:    10,
+						Action:      "accept",
+						Description: strptr("allow established"),
+						State: &types.State{
+							Established: boolptr(true),
+							Related:     boolptr(true),
+						},
+					},
+					{
+						Priority: 30,
+						Action:   "reject",
+						Protocol: "tcp",
+						Source: &types.Source{
+							Address: strptr("!10.0.0.1-10.0.0.9"),
+							Port:    &types.PortRange{From: 1024, To: 65535},
+							MAC:     strptr("00:11:22:33:44:55"),
+						},
+						Destination: &types.Destination{
+							Address: strptr("192.168.1.1"),
+							Port:    &types.PortRange{From: 22, To: 22},
+						},
+					},
+					{
+						Priority: 40,
+						Action:   "drop",
+						Protocol: "icmp",
+						Destination: &types.Destination{
+							Port: &types.PortRange{From: 22, To: 22},
+						},
+					},
+					{
+						Priority: 50,
+						Action:   "accept",
+						Source: &types.Source{
+							Address: strptr("2001:db8::/32"),
+						},
+					},
+					{
+						Priority: 60,
+						Action:   "accept",
+						Source: &types.Source{
+							AddressGroup: strptr("missing"),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestExportIPTables(t *testing.T) {
+	export := ExportIPTables(exportFirewall())
+
+	assert.Equal(t, `create servers hash:net family inet
+add servers 192.168.1.10
+add servers 192.168.1.0/28
+create web bitmap:port range 0-65535
+add web 80
+add web 443
+add web 8000-8080
+`, export.Sets)
+
+	assert.Equal(t, `*filter
+:WAN_IN - [0:0]
+-A WAN_IN -m conntrack --ctstate ESTABLISHED,RELATED -m comment --comment "allow established" -j ACCEPT
+-A WAN_IN -p tcp -m set --match-set servers dst -m set --match-set web dst -j LOG --log-prefix [WAN_IN-20-A]
+-A WAN_IN -p tcp -m set --match-set servers dst -m set --match-set web dst -j ACCEPT
+-A WAN_IN -p udp -m set --match-set servers dst -m set --match-set web dst -j LOG --log-prefix [WAN_IN-20-A]
+-A WAN_IN -p udp -m set --match-set servers dst -m set --match-set web dst -j ACCEPT
+-A WAN_IN -p tcp -m iprange ! --src-range 10.0.0.1-10.0.0.9 -d 192.168.1.1 --sport 1024:65535 --dport 22 -m mac --mac-source 00:11:22:33:44:55 -j REJECT
+-A WAN_IN -j LOG --log-prefix [WAN_IN-default-D]
+-A WAN_IN -j DROP
+COMMIT
+`, export.Ruleset)
+
+	var issues []string
+	for _, issue := range export.Issues {
+		issues = append(issues, issue.String())
+	}
+	assert.Equal(t, []string{
+		"group servers: IPv6 address 2001:db8::1 cannot be added to an IPv4 set; address skipped",
+		"ruleset WAN_IN rule 40: ports require protocol tcp, udp or tcp_udp; rule skipped",
+		"ruleset WAN_IN rule 50: IPv6 address 2001:db8::/32 requires ip6tables; rule skipped",
+		"ruleset WAN_IN rule 60: address group missing does not exist; rule skipped",
+	}, issues)
+}
+
+func TestExportNftables(t *testing.T) {
+	export := ExportNftables(exportFirewall())
+
+	assert.Empty(t, export.Sets)
+	assert.Equal(t, `table inet edgeos {
+	set servers {
+		type ipv4_addr
+		flags interval
+		auto-merge
+		elements = { 192.168.1.10, 192.168.1.0/28 }
+	}
+
+	set web {
+		type inet_service
+		flags interval
+		auto-merge
+		elements = { 80, 443, 8000-8080 }
+	}
+
+	chain WAN_IN {
+		ct state established,related accept comment "allow established"
+		meta l4proto { tcp, udp } ip daddr @servers th dport @web log prefix "[WAN_IN-20-A]" accept
+		ip saddr != 10.0.0.1-10.0.0.9 ip daddr 192.168.1.1 tcp sport 1024-65535 tcp dport 22 ether saddr 00:11:22:33:44:55 reject
+		ip6 saddr 2001:db8::/32 accept
+		log prefix "[WAN_IN-default-D]" drop
+	}
+}
+`, export.Ruleset)
+
+	var issues []string
+	for _, issue := range export.Issues {
+		issues = append(issues, issue.String())
+	}
+	assert.Equal(t, []string{
+		"group servers: sets cannot mix IPv4 and IPv6 addresses; IPv6 addresses 2001:db8::1 skipped",
+		"ruleset WAN_IN rule 40: ports require protocol tcp, udp or tcp_udp; rule skipped",
+		"ruleset WAN_IN rule 60: address group missing does not exist; rule skipped",
+	}, issues)
+}
+
+func TestExportNftablesOverlappingSets(t *testing.T) {
+	fw := &types.Firewall{
+		Groups: &types.Groups{
+			Address: map[string]*types.AddressGroup{
+				"lan": {Cidrs: []string{"10.0.0.0/24", "10.0.0.128/25", "10.0.0.5-10.0.0.20", "10.0.0.7"}},
+			},
+			Port: map[string]*types.PortGroup{
+				"alt": (&types.PortGroup{}).WithPorts([]int{8080}).WithRanges(8000, 8080, 8070, 8090),
+			},
+		},
+	}
+
+	export := ExportNftables(fw)
+	assert.Empty(t, export.Issues)
+	assert.Equal(t, `table inet edgeos {
+	set lan {
+		type ipv4_addr
+		flags interval
+		auto-merge
+		elements = { 10.0.0.0/24, 10.0.0.128/25, 10.0.0.5-10.0.0.20, 10.0.0.7 }
+	}
+
+	set alt {
+		type inet_service
+		flags interval
+		auto-merge
+		elements = { 8080, 8000-8080, 8070-8090 }
+	}
+}
+`, export.Ruleset)
+}
+
+func TestExportIssues(t *testing.T) {
+	fw := &types.Firewall{
+		Groups: &types.Groups{
+			Address: map[string]*types.AddressGroup{
+				"shared": {Cidrs: []string{"10.0.0.0/8"}},
+			},
+			Port: map[string]*types.PortGroup{
+				"shared": (&types.PortGroup{}).WithPorts([]int{22}),
+			},
+		},
+		Rulesets: map[string]*types.Ruleset{
+			"A_VERY_LONG_RULESET_NAME": {
+				DefaultAction: "accept",
+				Rules: []*types.Rule{
+					{
+						Priority: 10,
+						Action:   "drop",
+						Protocol: "!tcp_udp",
+						Log:      boolptr(true),
+					},
+					{
+						Priority: 20,
+						Action:   "accept",
+						Protocol: "tcp",
+						Destination: &types.Destination{
+							PortGroup: strptr("shared"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	export := ExportIPTables(fw)
+
+	var issues []string
+	for _, issue := range export.Issues {
+		issues = append(issues, issue.String())
+	}
+	assert.Equal(t, []string{
+		"group shared: port group has the same name as an address group and was skipped",
+		"ruleset A_VERY_LONG_RULESET_NAME rule 10: !tcp_udp cannot be expressed in a single rule; rule skipped",
+		"ruleset A_VERY_LONG_RULESET_NAME rule 20: port group shared was skipped; rule skipped",
+	}, issues)
+
+	assert.Equal(t, `*filter
+:A_VERY_LONG_RULESET_NAME - [0:0]
+-A A_VERY_LONG_RULESET_NAME -j ACCEPT
+COMMIT
+`, export.Ruleset)
+
+	export = ExportNftables(fw)
+	assert.Contains(t, export.Ruleset, "\t\tmeta l4proto != { tcp, udp } log prefix \"[A_VERY_LONG_RULESET_NAME-10-D]\" drop\n")
+}
@@ -18,17 +18,28 @@ type Client interface {
 	GetRuleset(context.Context, string) (*types.Ruleset, error)
 	CreateRuleset(context.Context, *types.Ruleset) (*types.Ruleset, error)
 	UpdateRuleset(context.Context, *types.Ruleset, []jsonpatch.JsonPatchOperation) (*types.Ruleset, error)
-	DeleteRuleset(context.Context, string) error
+	DeleteRuleset(context.Context, string, ...DeleteOption) error
+
+	GetRule(context.Context, string, int) (*types.Rule, error)
+	CreateRule(context.Context, string, *types.Rule) (*types.Rule, error)
+	UpdateRule(context.Context, string, *types.Rule, []jsonpatch.JsonPatchOperation) (*types.Rule, error)
+	DeleteRule(context.Context, string, int) error
+	MoveRule(context.Context, string, int, int) (*types.Rule, error)
+	SyncRules(context.Context, *types.Ruleset, *types.Ruleset) (*types.Ruleset, error)
 
 	CreateAddressGroup(context.Context, *types.AddressGroup) (*types.AddressGroup, error)
 	GetAddressGroup(context.Context, string) (*types.AddressGroup, error)
 	UpdateAddressGroup(context.Context, *types.AddressGroup, []jsonpatch.JsonPatchOperation) (*types.AddressGroup, error)
-	DeleteAddressGroup(context.Context, string) error
+	DeleteAddressGroup(context.Context, string, ...DeleteOption) error
 
 	CreatePortGroup(context.Context, *types.PortGroup) (*types.PortGroup, error)
 	GetPortGroup(context.Context, string) (*types.PortGroup, error)
 	UpdatePortGroup(context.Context, *types.PortGroup, []jsonpatch.JsonPatchOperation) (*types.PortGroup, error)
-	DeletePortGroup(context.Context, string) error
+	DeletePortGroup(context.Context, string, ...DeleteOption) error
+
+	References(context.Context) (*types.ReferenceIndex, error)
+	RulesetAttachments(context.Context, string) ([]types.Reference, error)
+	IPv6RulesetAttachments(context.Context, string) ([]types.Reference, error)
 }
 
 type client struct {
@@ -50,6 +61,10 @@ func (c *client) GetRuleset(ctx context.Context, name string) (*types.Ruleset, e
 }
 
 func (c *client) CreateRuleset(ctx context.Context, p *types.Ruleset) (*types.Ruleset, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
 	p.SetCodecMode(types.CodecModeRemote)
 	_, err := c.apiClient.Post(ctx, &api.Operation{
 		Set: &api.Set{
@@ -68,8 +83,8 @@ func (c *client) CreateRuleset(ctx context.Context, p *types.Ruleset) (*types.Ru
 	return c.GetRuleset(ctx, p.Name)
 }
 
-func (c *client) DeleteRuleset(ctx context.Context, name string) error {
-	_, err := c.apiClient.Post(ctx, &api.Operation{
+func (c *client) DeleteRuleset(ctx context.Context, name string, opts ...DeleteOption) error {
+	in := &api.Operation{
 		Delete: &api.Delete{
 			Resources: api.Resources{
 				Firewall: &types.Firewall{
@@ -79,7 +94,15 @@ func (c *client) DeleteRuleset(ctx context.Context, name string) error {
 				},
 			},
 		},
-	})
+	}
+
+	if err := c.resolveReferences(ctx, in, "ruleset", name, func(idx *types.ReferenceIndex) []types.Reference {
+		return idx.Ruleset(name)
+	}, opts); err != nil {
+		return err
+	}
+
+	_, err := c.apiClient.Post(ctx, in)
 	return err
 }
 
@@ -112,6 +135,11 @@ func (c *client) UpdateRuleset(ctx context.Context, current *types.Ruleset, patc
 		if err := json.Unmarshal(modifiedData, &rs); err != nil {
 			return nil, err
 		}
+		rs.Name = current.Name
+	}
+
+	if err := rs.Validate(); err != nil {
+		return nil, err
 	}
 
 	var discard []*types.Rule
@@ -189,6 +217,10 @@ func (c *client) UpdateRuleset(ctx context.Context, current *types.Ruleset, patc
 }
 
 func (c *client) CreateAddressGroup(ctx context.Context, g *types.AddressGroup) (*types.AddressGroup, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
 	_, err := c.apiClient.Post(ctx, &api.Operation{
 		Set: &api.Set{
 			Resources: api.Resources{
@@ -221,6 +253,11 @@ func (c *client) UpdateAddressGroup(ctx context.Context, current *types.AddressG
 	if err := utils.Patch(current, &group, patches); err != nil {
 		return nil, err
 	}
+	group.Name = current.Name
+
+	if err := group.Validate(); err != nil {
+		return nil, err
+	}
 
 	in := &api.Operation{
 		Set: &api.Set{
@@ -277,8 +314,8 @@ func (c *client) UpdateAddressGroup(ctx context.Context, current *types.AddressG
 	return c.GetAddressGroup(ctx, current.Name)
 }
 
-func (c *client) DeleteAddressGroup(ctx context.Context, name string) error {
-	_, err := c.apiClient.Post(ctx, &api.Operation{
+func (c *client) DeleteAddressGroup(ctx context.Context, name string, opts ...DeleteOption) error {
+	in := &api.Operation{
 		Delete: &api.Delete{
 			Resources: api.Resources{
 				Firewall: &types.Firewall{
@@ -290,11 +327,23 @@ func (c *client) DeleteAddressGroup(ctx context.Context, name string) error {
 				},
 			},
 		},
-	})
+	}
+
+	if err := c.resolveReferences(ctx, in, "address group", name, func(idx *types.ReferenceIndex) []types.Reference {
+		return idx.AddressGroup(name)
+	}, opts); err != nil {
+		return err
+	}
+
+	_, err := c.apiClient.Post(ctx, in)
 	return err
 }
 
 func (c *client) CreatePortGroup(ctx context.Context, g *types.PortGroup) (*types.PortGroup, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
 	_, err := c.apiClient.Post(ctx, &api.Operation{
 		Set: &api.Set{
 			Resources: api.Resources{
@@ -331,6 +380,11 @@ func (c *client) UpdatePortGroup(ctx context.Context, current *types.PortGroup,
 	if err := utils.Patch(current, &group, patches); err != nil {
 		return nil, err
 	}
+	group.Name = current.Name
+
+	if err := group.Validate(); err != nil {
+		return nil, err
+	}
 
 	in := &api.Operation{
 		Set: &api.Set{
@@ -387,8 +441,8 @@ func (c *client) UpdatePortGroup(ctx context.Context, current *types.PortGroup,
 	return c.GetPortGroup(ctx, current.Name)
 }
 
-func (c *client) DeletePortGroup(ctx context.Context, name string) error {
-	_, err := c.apiClient.Post(ctx, &api.Operation{
+func (c *client) DeletePortGroup(ctx context.Context, name string, opts ...DeleteOption) error {
+	in := &api.Operation{
 		Delete: &api.Delete{
 			Resources: api.Resources{
 				Firewall: &types.Firewall{
@@ -400,7 +454,15 @@ func (c *client) DeletePortGroup(ctx context.Context, name string) error {
 				},
 			},
 		},
-	})
+	}
+
+	if err := c.resolveReferences(ctx, in, "port group", name, func(idx *types.ReferenceIndex) []types.Reference {
+		return idx.PortGroup(name)
+	}, opts); err != nil {
+		return err
+	}
+
+	_, err := c.apiClient.Post(ctx, in)
 	return err
 }
 
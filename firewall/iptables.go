@@ -0,0 +1,198 @@
+package firewall
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/frankgreco/edge-sdk-go/types"
+)
+
+const (
+	// maxLogPrefix is the longest log prefix the iptables LOG target accepts.
+	maxLogPrefix = 29
+	// maxComment is the longest comment the iptables comment match accepts.
+	maxComment = 256
+)
+
+// ExportIPTables translates the firewall to iptables-save text for the filter
+// table and its groups to ipset save text. Each ruleset becomes a chain of the
+// same name ending in its default action. The chains are not referenced from
+// INPUT, FORWARD or OUTPUT, which is up to the caller, the same way a ruleset
+// does nothing until it is attached to an interface. Only IPv4 is translated,
+// as IPv6 needs ip6tables.
+func ExportIPTables(fw *types.Firewall) *Export {
+	e := newExporter(fw)
+
+	var sets strings.Builder
+	for _, g := range e.addressGroups() {
+		fmt.Fprintf(&sets, "create %s hash:net family inet\n", g.Name)
+		for _, cidr := range g.Cidrs {
+			if isIPv6(cidr) {
+				e.groupIssue(g.Name, "IPv6 address %s cannot be added to an IPv4 set; address skipped", cidr)
+				continue
+			}
+			fmt.Fprintf(&sets, "add %s %s\n", g.Name, cidr)
+		}
+	}
+	for _, g := range e.portGroups() {
+		fmt.Fprintf(&sets, "create %s bitmap:port range 0-65535\n", g.Name)
+		for _, port := range g.Ports {
+			fmt.Fprintf(&sets, "add %s %d\n", g.Name, port)
+		}
+		for _, r := range g.Ranges {
+			if r != nil {
+				fmt.Fprintf(&sets, "add %s %d-%d\n", g.Name, r.From, r.To)
+			}
+		}
+	}
+
+	rulesets := e.rulesets()
+
+	var rules strings.Builder
+	rules.WriteString("*filter\n")
+	for _, rs := range rulesets {
+		fmt.Fprintf(&rules, ":%s - [0:0]\n", rs.Name)
+	}
+	for _, rs := range rulesets {
+		for _, r := range rs.Rules {
+			if out := e.rule(rs, r); out != nil {
+				for _, l := range e.iptablesRule(out) {
+					rules.WriteString(l)
+					rules.WriteString("\n")
+				}
+			}
+		}
+
+		action := defaultActionOf(rs)
+		if rs.DefaultLogging != nil && *rs.DefaultLogging {
+			fmt.Fprintf(&rules, "-A %s -j LOG --log-prefix %s\n", rs.Name, iptablesQuote(e.iptablesLogPrefix(rs.Name, 0, logPrefix(rs.Name, "default", action))))
+		}
+		fmt.Fprintf(&rules, "-A %s -j %s\n", rs.Name, strings.ToUpper(action))
+	}
+	rules.WriteString("COMMIT\n")
+
+	return &Export{
+		Sets:    sets.String(),
+		Ruleset: rules.String(),
+		Issues:  e.issues,
+	}
+}
+
+// iptablesRule translates a rule to the lines that append it to its chain.
+// A rule becomes one line per protocol, each preceded by a LOG line if the
+// rule logs.
+func (e *exporter) iptablesRule(r *exportRule) []string {
+	for _, address := range []string{r.source.address, r.destination.address} {
+		if isIPv6(address) {
+			e.ruleIssue(r.ruleset, r.priority, "IPv6 address %s requires ip6tables; rule skipped", address)
+			return nil
+		}
+	}
+	if r.negateProtocol && len(r.protocols) > 1 {
+		e.ruleIssue(r.ruleset, r.priority, "!tcp_udp cannot be expressed in a single rule; rule skipped")
+		return nil
+	}
+
+	protocols := r.protocols
+	if len(protocols) == 0 {
+		protocols = []string{""}
+	}
+
+	comment := r.comment
+	if len(comment) > maxComment {
+		e.ruleIssue(r.ruleset, r.priority, "description is longer than %d characters; comment truncated", maxComment)
+		comment = comment[:maxComment]
+	}
+
+	var logPrefixOption string
+	if r.log {
+		logPrefixOption = iptablesQuote(e.iptablesLogPrefix(r.ruleset, r.priority, logPrefix(r.ruleset, fmt.Sprint(r.priority), r.action)))
+	}
+
+	var lines []string
+	for _, protocol := range protocols {
+		matches := []string{"-A", r.ruleset}
+
+		if protocol != "" {
+			matches = append(matches, not(r.negateProtocol)+"-p", protocol)
+		}
+		matches = append(matches, iptablesAddress(&r.source, "-s", "--src-range")...)
+		matches = append(matches, iptablesAddress(&r.destination, "-d", "--dst-range")...)
+		if p := r.source.port; p != nil {
+			matches = append(matches, "--sport", iptablesPort(p))
+		}
+		if p := r.destination.port; p != nil {
+			matches = append(matches, "--dport", iptablesPort(p))
+		}
+		if r.source.addressGroup != "" {
+			matches = append(matches, "-m", "set", not(r.source.negateGroup)+"--match-set", r.source.addressGroup, "src")
+		}
+		if r.destination.addressGroup != "" {
+			matches = append(matches, "-m", "set", not(r.destination.negateGroup)+"--match-set", r.destination.addressGroup, "dst")
+		}
+		if r.source.portGroup != "" {
+			matches = append(matches, "-m", "set", "--match-set", r.source.portGroup, "src")
+		}
+		if r.destination.portGroup != "" {
+			matches = append(matches, "-m", "set", "--match-set", r.destination.portGroup, "dst")
+		}
+		if r.mac != "" {
+			matches = append(matches, "-m", "mac", not(r.negateMAC)+"--mac-source", r.mac)
+		}
+		if len(r.states) > 0 {
+			matches = append(matches, "-m", "conntrack", "--ctstate", strings.ToUpper(strings.Join(r.states, ",")))
+		}
+		if comment != "" {
+			matches = append(matches, "-m", "comment", "--comment", iptablesQuote(comment))
+		}
+
+		prefix := strings.Join(matches, " ")
+		if r.log {
+			lines = append(lines, fmt.Sprintf("%s -j LOG --log-prefix %s", prefix, logPrefixOption))
+		}
+		lines = append(lines, fmt.Sprintf("%s -j %s", prefix, strings.ToUpper(r.action)))
+	}
+
+	return lines
+}
+
+func (e *exporter) iptablesLogPrefix(ruleset string, priority int, prefix string) string {
+	if len(prefix) <= maxLogPrefix {
+		return prefix
+	}
+	e.ruleIssue(ruleset, priority, "log prefix %s is longer than %d characters; prefix truncated", prefix, maxLogPrefix)
+	return prefix[:maxLogPrefix]
+}
+
+func iptablesAddress(ep *exportEndpoint, flag, rangeFlag string) []string {
+	switch {
+	case ep.address == "":
+		return nil
+	case ep.isRange():
+		return []string{"-m", "iprange", not(ep.negateAddress) + rangeFlag, ep.address}
+	default:
+		return []string{not(ep.negateAddress) + flag, ep.address}
+	}
+}
+
+func iptablesPort(p *types.PortRange) string {
+	if p.From == p.To {
+		return fmt.Sprint(p.From)
+	}
+	return fmt.Sprintf("%d:%d", p.From, p.To)
+}
+
+// not returns the prefix that negates the following option.
+func not(negate bool) string {
+	if negate {
+		return "! "
+	}
+	return ""
+}
+
+func iptablesQuote(val string) string {
+	if val != "" && !strings.ContainsAny(val, " \t\"'") {
+		return val
+	}
+	return `"` + strings.ReplaceAll(val, `"`, `\"`) + `"`
+}
@@ -0,0 +1,501 @@
+package firewall
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/frankgreco/edge-sdk-go/types"
+)
+
+const (
+	maxProtocol = 255
+	maxIPv4     = 1<<32 - 1
+	maxPort     = 65535
+	maxMAC      = 1<<48 - 1
+)
+
+// protocolNumbers maps the protocol names EdgeOS accepts to their IANA numbers.
+var protocolNumbers = map[string]uint64{
+	"icmp":      1,
+	"igmp":      2,
+	"ipip":      4,
+	"tcp":       6,
+	"udp":       17,
+	"gre":       47,
+	"esp":       50,
+	"ah":        51,
+	"ipv6-icmp": 58,
+	"ospf":      89,
+	"pim":       103,
+	"vrrp":      112,
+	"l2tp":      115,
+	"sctp":      132,
+}
+
+// span is an inclusive range of values.
+type span struct {
+	from, to uint64
+}
+
+// dimension is the set of values a rule matches for one property of a packet,
+// such as its destination address or protocol.
+type dimension struct {
+	any   bool
+	spans []span
+}
+
+func anyValue() dimension {
+	return dimension{any: true}
+}
+
+// newDimension returns the union of the spans.
+func newDimension(spans ...span) dimension {
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].from < spans[j].from
+	})
+
+	merged := []span{}
+	for _, s := range spans {
+		if n := len(merged); n > 0 && s.from <= merged[n-1].to+1 {
+			if s.to > merged[n-1].to {
+				merged[n-1].to = s.to
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return dimension{spans: merged}
+}
+
+func (d dimension) contains(v uint64) bool {
+	if d.any {
+		return true
+	}
+	for _, s := range d.spans {
+		if v >= s.from && v <= s.to {
+			return true
+		}
+	}
+	return false
+}
+
+// complement returns every value up to max that is not in d.
+func (d dimension) complement(max uint64) dimension {
+	if d.any {
+		return dimension{}
+	}
+
+	var spans []span
+	next := uint64(0)
+	for _, s := range d.spans {
+		if s.from > next {
+			spans = append(spans, span{next, s.from - 1})
+		}
+		next = s.to + 1
+	}
+	if len(d.spans) == 0 || d.spans[len(d.spans)-1].to < max {
+		spans = append(spans, span{next, max})
+	}
+	return newDimension(spans...)
+}
+
+// subsetOf reports whether every value in d is also in o.
+func (d dimension) subsetOf(o dimension) bool {
+	if o.any {
+		return true
+	}
+	if d.any {
+		return false
+	}
+	for _, s := range d.spans {
+		covered := false
+		for _, t := range o.spans {
+			if s.from >= t.from && s.to <= t.to {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// intersects reports whether d and o have any value in common.
+func (d dimension) intersects(o dimension) bool {
+	if d.any || o.any {
+		return d.any && o.any || len(d.spans) > 0 || len(o.spans) > 0
+	}
+	for _, s := range d.spans {
+		for _, t := range o.spans {
+			if s.from <= t.to && t.from <= s.to {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// intersect returns the values that are in both d and o.
+func (d dimension) intersect(o dimension) dimension {
+	if d.any {
+		return o
+	}
+	if o.any {
+		return d
+	}
+	var spans []span
+	for _, s := range d.spans {
+		for _, t := range o.spans {
+			from, to := s.from, s.to
+			if t.from > from {
+				from = t.from
+			}
+			if t.to < to {
+				to = t.to
+			}
+			if from <= to {
+				spans = append(spans, span{from, to})
+			}
+		}
+	}
+	return newDimension(spans...)
+}
+
+func (d dimension) empty() bool {
+	return !d.any && len(d.spans) == 0
+}
+
+// The properties of a packet that a rule can match on.
+const (
+	dimProtocol = iota
+	dimSourceAddress
+	dimDestinationAddress
+	dimSourcePort
+	dimDestinationPort
+	dimSourceMAC
+	dimState
+	numDimensions
+)
+
+// dimensionMax is the largest value of each dimension. The source MAC address
+// has one extra value for packets without a MAC address.
+var dimensionMax = [numDimensions]uint64{
+	dimProtocol:           maxProtocol,
+	dimSourceAddress:      maxIPv4,
+	dimDestinationAddress: maxIPv4,
+	dimSourcePort:         maxPort,
+	dimDestinationPort:    maxPort,
+	dimSourceMAC:          maxMAC + 1,
+	dimState:              3,
+}
+
+// connection states are matched as values of the state dimension.
+var stateValues = map[ConnectionState]uint64{
+	StateNew:         0,
+	StateEstablished: 1,
+	StateRelated:     2,
+	StateInvalid:     3,
+}
+
+// criteria is the set of packets a rule matches, expressed as one dimension per
+// packet property. A packet matches if every dimension contains its value.
+type criteria [numDimensions]dimension
+
+func (c *criteria) subsetOf(o *criteria) bool {
+	for i := range c {
+		if !c[i].subsetOf(o[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *criteria) intersects(o *criteria) bool {
+	for i := range c {
+		if !c[i].intersects(o[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *criteria) equal(o *criteria) bool {
+	return c.subsetOf(o) && o.subsetOf(c)
+}
+
+// subtract returns disjoint criteria that together match every packet that c
+// matches but o does not.
+func (c *criteria) subtract(o *criteria) []*criteria {
+	if !c.intersects(o) {
+		return []*criteria{c}
+	}
+
+	var remainder []*criteria
+	prefix := *c
+	for i := range c {
+		if diff := c[i].intersect(o[i].complement(dimensionMax[i])); !diff.empty() {
+			box := prefix
+			box[i] = diff
+			remainder = append(remainder, &box)
+		}
+		prefix[i] = c[i].intersect(o[i])
+	}
+	return remainder
+}
+
+// compileRule returns the criteria of the rule, resolving group references
+// from groups.
+func compileRule(r *types.Rule, groups *types.Groups) (*criteria, error) {
+	c := new(criteria)
+	for i := range c {
+		c[i] = anyValue()
+	}
+
+	var err error
+	if c[dimProtocol], err = compileProtocol(r.Protocol); err != nil {
+		return nil, fmt.Errorf("rule %d: %s", r.Priority, err.Error())
+	}
+
+	if s := r.Source; s != nil {
+		if c[dimSourceAddress], err = compileAddress(s.Address, s.AddressGroup, groups); err != nil {
+			return nil, fmt.Errorf("rule %d: source %s", r.Priority, err.Error())
+		}
+		if c[dimSourcePort], err = compilePort(s.Port, s.PortGroup, groups); err != nil {
+			return nil, fmt.Errorf("rule %d: source %s", r.Priority, err.Error())
+		}
+		if c[dimSourceMAC], err = compileMAC(s.MAC); err != nil {
+			return nil, fmt.Errorf("rule %d: source %s", r.Priority, err.Error())
+		}
+	}
+
+	if d := r.Destination; d != nil {
+		if c[dimDestinationAddress], err = compileAddress(d.Address, d.AddressGroup, groups); err != nil {
+			return nil, fmt.Errorf("rule %d: destination %s", r.Priority, err.Error())
+		}
+		if c[dimDestinationPort], err = compilePort(d.Port, d.PortGroup, groups); err != nil {
+			return nil, fmt.Errorf("rule %d: destination %s", r.Priority, err.Error())
+		}
+	}
+
+	// Ports can only be matched for protocols that have them.
+	if !c[dimSourcePort].any || !c[dimDestinationPort].any {
+		c[dimProtocol] = c[dimProtocol].intersect(newDimension(span{6, 6}, span{17, 17}))
+	}
+
+	if st := r.State; st != nil {
+		var spans []span
+		for _, state := range []struct {
+			enabled *bool
+			value   uint64
+		}{
+			{st.New, stateValues[StateNew]},
+			{st.Established, stateValues[StateEstablished]},
+			{st.Related, stateValues[StateRelated]},
+			{st.Invalid, stateValues[StateInvalid]},
+		} {
+			if state.enabled != nil && *state.enabled {
+				spans = append(spans, span{state.value, state.value})
+			}
+		}
+		if len(spans) > 0 {
+			c[dimState] = newDimension(spans...)
+		}
+	}
+
+	return c, nil
+}
+
+func compileProtocol(protocol string) (dimension, error) {
+	negate := strings.HasPrefix(protocol, "!")
+	protocol = strings.ToLower(strings.TrimPrefix(protocol, "!"))
+
+	var d dimension
+	switch protocol {
+	case "", "*", "all":
+		d = anyValue()
+	case "tcp_udp":
+		d = newDimension(span{6, 6}, span{17, 17})
+	default:
+		n, ok := protocolNumbers[protocol]
+		if !ok {
+			i, err := strconv.ParseUint(protocol, 10, 8)
+			if err != nil {
+				return dimension{}, fmt.Errorf("unknown protocol %q", protocol)
+			}
+			n = i
+		}
+		d = newDimension(span{n, n})
+	}
+
+	if negate {
+		return d.complement(maxProtocol), nil
+	}
+	return d, nil
+}
+
+func compileAddress(address, addressGroup *string, groups *types.Groups) (dimension, error) {
+	d := anyValue()
+
+	if address != nil && *address != "" {
+		a, err := compileAddressValues([]string{*address})
+		if err != nil {
+			return dimension{}, err
+		}
+		d = d.intersect(a)
+	}
+
+	if addressGroup != nil && *addressGroup != "" {
+		negate := strings.HasPrefix(*addressGroup, "!")
+		name := strings.TrimPrefix(*addressGroup, "!")
+
+		var group *types.AddressGroup
+		if groups != nil {
+			group = groups.Address[name]
+		}
+		if group == nil {
+			return dimension{}, fmt.Errorf("address group %s does not exist", name)
+		}
+
+		g, err := compileAddressValues(group.Cidrs)
+		if err != nil {
+			return dimension{}, fmt.Errorf("address group %s: %s", name, err.Error())
+		}
+		if negate {
+			g = g.complement(maxIPv4)
+		}
+		d = d.intersect(g)
+	}
+
+	return d, nil
+}
+
+// compileAddressValues returns the union of IPv4 addresses, CIDRs and ranges,
+// each of which may be negated with a leading "!".
+func compileAddressValues(vals []string) (dimension, error) {
+	var spans []span
+	for _, val := range vals {
+		negate := strings.HasPrefix(val, "!")
+		s, err := parseAddress(strings.TrimPrefix(val, "!"))
+		if err != nil {
+			return dimension{}, err
+		}
+		if negate {
+			spans = append(spans, newDimension(s).complement(maxIPv4).spans...)
+			continue
+		}
+		spans = append(spans, s)
+	}
+	return newDimension(spans...), nil
+}
+
+func parseAddress(val string) (span, error) {
+	if _, n, err := net.ParseCIDR(val); err == nil {
+		ip := n.IP.To4()
+		if ip == nil {
+			return span{}, fmt.Errorf("%q is not an IPv4 CIDR", val)
+		}
+		ones, _ := n.Mask.Size()
+		from := uint64(binary.BigEndian.Uint32(ip))
+		return span{from, from + (uint64(1) << uint(32-ones)) - 1}, nil
+	}
+
+	if fromTo := strings.Split(val, "-"); len(fromTo) == 2 {
+		from, err := ipv4(fromTo[0])
+		if err != nil {
+			return span{}, err
+		}
+		to, err := ipv4(fromTo[1])
+		if err != nil {
+			return span{}, err
+		}
+		return span{from, to}, nil
+	}
+
+	ip, err := ipv4(val)
+	if err != nil {
+		return span{}, err
+	}
+	return span{ip, ip}, nil
+}
+
+func ipv4(val string) (uint64, error) {
+	ip := net.ParseIP(val).To4()
+	if ip == nil {
+		return 0, fmt.Errorf("%q is not an IPv4 address", val)
+	}
+	return uint64(binary.BigEndian.Uint32(ip)), nil
+}
+
+func compilePort(port *types.PortRange, portGroup *string, groups *types.Groups) (dimension, error) {
+	d := anyValue()
+
+	if port != nil {
+		d = newDimension(span{uint64(port.From), uint64(port.To)})
+	}
+
+	if portGroup != nil && *portGroup != "" {
+		negate := strings.HasPrefix(*portGroup, "!")
+		name := strings.TrimPrefix(*portGroup, "!")
+
+		var group *types.PortGroup
+		if groups != nil {
+			group = groups.Port[name]
+		}
+		if group == nil {
+			return dimension{}, fmt.Errorf("port group %s does not exist", name)
+		}
+
+		var spans []span
+		for _, p := range group.Ports {
+			spans = append(spans, span{uint64(p), uint64(p)})
+		}
+		for _, r := range group.Ranges {
+			if r != nil {
+				spans = append(spans, span{uint64(r.From), uint64(r.To)})
+			}
+		}
+
+		g := newDimension(spans...)
+		if negate {
+			g = g.complement(maxPort)
+		}
+		d = d.intersect(g)
+	}
+
+	return d, nil
+}
+
+func compileMAC(mac *string) (dimension, error) {
+	if mac == nil || *mac == "" {
+		return anyValue(), nil
+	}
+
+	negate := strings.HasPrefix(*mac, "!")
+	hw, err := net.ParseMAC(strings.TrimPrefix(*mac, "!"))
+	if err != nil {
+		return dimension{}, err
+	}
+
+	v := macValue(hw)
+	d := newDimension(span{v, v})
+	if negate {
+		// Like iptables, a negated MAC address only matches packets that
+		// have one, so the value of packets without one is left out.
+		d = d.complement(maxMAC)
+	}
+	return d, nil
+}
+
+func macValue(hw net.HardwareAddr) uint64 {
+	var v uint64
+	for _, b := range hw {
+		v = v<<8 | uint64(b)
+	}
+	return v
+}
@@ -0,0 +1,199 @@
+package firewall
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/frankgreco/edge-sdk-go/types"
+)
+
+// nftablesTable is the inet table the nftables exporter declares.
+const nftablesTable = "edgeos"
+
+// ExportNftables translates the firewall to an nft ruleset in an inet table
+// named edgeos. Groups become named sets and each ruleset becomes a regular
+// chain of the same name ending in its default action. The chains have no
+// hook, so traffic only reaches them through a jump added by the caller, the
+// same way a ruleset does nothing until it is attached to an interface.
+func ExportNftables(fw *types.Firewall) *Export {
+	e := newExporter(fw)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "table inet %s {\n", nftablesTable)
+
+	// families are the address families of the address sets, ip or ip6.
+	families := map[string]string{}
+
+	blocks := 0
+	block := func() {
+		if blocks > 0 {
+			b.WriteString("\n")
+		}
+		blocks++
+	}
+
+	for _, g := range e.addressGroups() {
+		var v4, v6 []string
+		for _, cidr := range g.Cidrs {
+			if isIPv6(cidr) {
+				v6 = append(v6, cidr)
+			} else {
+				v4 = append(v4, cidr)
+			}
+		}
+
+		family, addressType, elements := "ip", "ipv4_addr", v4
+		if len(v4) == 0 && len(v6) > 0 {
+			family, addressType, elements = "ip6", "ipv6_addr", v6
+		} else if len(v6) > 0 {
+			e.groupIssue(g.Name, "sets cannot mix IPv4 and IPv6 addresses; IPv6 addresses %s skipped", strings.Join(v6, ", "))
+		}
+		families[g.Name] = family
+
+		block()
+		writeNftablesSet(&b, g.Name, addressType, elements)
+	}
+	for _, g := range e.portGroups() {
+		var elements []string
+		for _, port := range g.Ports {
+			elements = append(elements, fmt.Sprint(port))
+		}
+		for _, r := range g.Ranges {
+			if r != nil {
+				elements = append(elements, fmt.Sprintf("%d-%d", r.From, r.To))
+			}
+		}
+
+		block()
+		writeNftablesSet(&b, g.Name, "inet_service", elements)
+	}
+
+	for _, rs := range e.rulesets() {
+		block()
+		fmt.Fprintf(&b, "\tchain %s {\n", rs.Name)
+		for _, r := range rs.Rules {
+			if out := e.rule(rs, r); out != nil {
+				fmt.Fprintf(&b, "\t\t%s\n", nftablesRule(out, families))
+			}
+		}
+
+		action := defaultActionOf(rs)
+		if rs.DefaultLogging != nil && *rs.DefaultLogging {
+			fmt.Fprintf(&b, "\t\tlog prefix %s %s\n", nftablesQuote(logPrefix(rs.Name, "default", action)), action)
+		} else {
+			fmt.Fprintf(&b, "\t\t%s\n", action)
+		}
+		b.WriteString("\t}\n")
+	}
+
+	b.WriteString("}\n")
+
+	return &Export{
+		Ruleset: b.String(),
+		Issues:  e.issues,
+	}
+}
+
+// writeNftablesSet writes an interval set. Group members often overlap, e.g. an
+// address within a subnet of the same group, which nft rejects for interval
+// sets unless the set merges them with auto-merge.
+func writeNftablesSet(b *strings.Builder, name, elementType string, elements []string) {
+	fmt.Fprintf(b, "\tset %s {\n", name)
+	fmt.Fprintf(b, "\t\ttype %s\n", elementType)
+	b.WriteString("\t\tflags interval\n")
+	b.WriteString("\t\tauto-merge\n")
+	if len(elements) > 0 {
+		fmt.Fprintf(b, "\t\telements = { %s }\n", strings.Join(elements, ", "))
+	}
+	b.WriteString("\t}\n")
+}
+
+// nftablesRule translates a rule to a single nft rule.
+func nftablesRule(r *exportRule, families map[string]string) string {
+	var stmts []string
+
+	hasPort := r.source.hasPort() || r.destination.hasPort()
+
+	// With a single protocol, the port matches imply it.
+	portProtocol := "th"
+	if len(r.protocols) == 1 && hasPort {
+		portProtocol = r.protocols[0]
+	} else if len(r.protocols) > 0 {
+		stmts = append(stmts, "meta l4proto", nftablesOperator(r.negateProtocol)+nftablesSet(r.protocols))
+	}
+
+	for _, ep := range []struct {
+		*exportEndpoint
+		dir string
+	}{
+		{&r.source, "saddr"},
+		{&r.destination, "daddr"},
+	} {
+		if ep.address != "" {
+			family := "ip"
+			if isIPv6(ep.address) {
+				family = "ip6"
+			}
+			stmts = append(stmts, family, ep.dir, nftablesOperator(ep.negateAddress)+ep.address)
+		}
+		if ep.addressGroup != "" {
+			stmts = append(stmts, families[ep.addressGroup], ep.dir, nftablesOperator(ep.negateGroup)+"@"+ep.addressGroup)
+		}
+	}
+
+	for _, ep := range []struct {
+		*exportEndpoint
+		dir string
+	}{
+		{&r.source, "sport"},
+		{&r.destination, "dport"},
+	} {
+		if ep.port != nil {
+			stmts = append(stmts, portProtocol, ep.dir, nftablesPort(ep.port))
+		}
+		if ep.portGroup != "" {
+			stmts = append(stmts, portProtocol, ep.dir, "@"+ep.portGroup)
+		}
+	}
+
+	if r.mac != "" {
+		stmts = append(stmts, "ether saddr", nftablesOperator(r.negateMAC)+r.mac)
+	}
+	if len(r.states) > 0 {
+		stmts = append(stmts, "ct state", strings.Join(r.states, ","))
+	}
+	if r.log {
+		stmts = append(stmts, "log prefix", nftablesQuote(logPrefix(r.ruleset, fmt.Sprint(r.priority), r.action)))
+	}
+	stmts = append(stmts, r.action)
+	if r.comment != "" {
+		stmts = append(stmts, "comment", nftablesQuote(r.comment))
+	}
+
+	return strings.Join(stmts, " ")
+}
+
+func nftablesOperator(negate bool) string {
+	if negate {
+		return "!= "
+	}
+	return ""
+}
+
+func nftablesSet(vals []string) string {
+	if len(vals) == 1 {
+		return vals[0]
+	}
+	return "{ " + strings.Join(vals, ", ") + " }"
+}
+
+func nftablesPort(p *types.PortRange) string {
+	if p.From == p.To {
+		return fmt.Sprint(p.From)
+	}
+	return fmt.Sprintf("%d-%d", p.From, p.To)
+}
+
+func nftablesQuote(val string) string {
+	return `"` + strings.ReplaceAll(val, `"`, `\"`) + `"`
+}
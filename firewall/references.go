@@ -0,0 +1,212 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/types"
+)
+
+// DeleteOption configures how a group or ruleset that is still referenced is
+// deleted. Without any option the delete is sent as is and EdgeOS rejects the
+// commit if the object is still in use.
+type DeleteOption func(*deleteOptions)
+
+type deleteOptions struct {
+	safe    bool
+	cascade bool
+}
+
+// WithSafeDelete refuses to delete a group or ruleset that is still referenced
+// and returns an *InUseError listing the referrers instead.
+func WithSafeDelete() DeleteOption {
+	return func(o *deleteOptions) {
+		o.safe = true
+	}
+}
+
+// WithCascade removes every reference within the same commit. Rulesets are
+// detached from every interface they are attached to. Removing a group from a
+// firewall or NAT rule, or removing the rule itself, would change the traffic
+// the rule matches, e.g. a drop rule on a group would drop everything, so
+// such references are refused with an *InUseError listing them instead.
+func WithCascade() DeleteOption {
+	return func(o *deleteOptions) {
+		o.cascade = true
+	}
+}
+
+// InUseError is returned when deleting a group or ruleset that is still
+// referenced using WithSafeDelete, or using WithCascade when it is still
+// referenced by a firewall or NAT rule.
+type InUseError struct {
+	Kind       string
+	Name       string
+	References []types.Reference
+}
+
+func (e *InUseError) Error() string {
+	refs := make([]string, len(e.References))
+	for i, ref := range e.References {
+		refs[i] = ref.String()
+	}
+	return fmt.Sprintf("The %s %s is still referenced by: %s.", e.Kind, e.Name, strings.Join(refs, ", "))
+}
+
+// References returns the reference index of the current configuration.
+func (c *client) References(ctx context.Context) (*types.ReferenceIndex, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return indexOf(op), nil
+}
+
+func indexOf(op *api.Operation) *types.ReferenceIndex {
+	if op == nil || op.Get == nil {
+		return types.NewReferenceIndex(nil, nil, nil)
+	}
+	var nat *types.NAT
+	if op.Get.Service != nil {
+		nat = op.Get.Service.NAT
+	}
+	return types.NewReferenceIndex(op.Get.Firewall, op.Get.Interfaces, nat)
+}
+
+// RulesetAttachments returns every interface and direction the ruleset is
+// attached to, including VLANs, PPPoE sessions, bridges and tunnels, ordered
+// by interface path.
+func (c *client) RulesetAttachments(ctx context.Context, name string) ([]types.Reference, error) {
+	idx, err := c.References(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append([]types.Reference{}, idx.Ruleset(name)...), nil
+}
+
+// IPv6RulesetAttachments is RulesetAttachments for a ruleset defined under
+// firewall ipv6-name.
+func (c *client) IPv6RulesetAttachments(ctx context.Context, name string) ([]types.Reference, error) {
+	idx, err := c.References(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append([]types.Reference{}, idx.IPv6Ruleset(name)...), nil
+}
+
+// resolveReferences applies the delete options to the delete operation of the
+// object identified by kind and name.
+func (c *client) resolveReferences(ctx context.Context, in *api.Operation, kind, name string, lookup func(*types.ReferenceIndex) []types.Reference, opts []DeleteOption) error {
+	o := new(deleteOptions)
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if !o.safe && !o.cascade {
+		return nil
+	}
+
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	refs := lookup(indexOf(op))
+	if len(refs) == 0 {
+		return nil
+	}
+
+	if !o.cascade {
+		return &InUseError{
+			Kind:       kind,
+			Name:       name,
+			References: refs,
+		}
+	}
+
+	if widened := widenedBy(refs); len(widened) > 0 {
+		return &InUseError{
+			Kind:       kind,
+			Name:       name,
+			References: widened,
+		}
+	}
+
+	return cascade(in.Delete, refs)
+}
+
+// widenedBy returns the references that cannot be removed without widening a
+// rule. Removing a match condition makes a rule match more traffic, and
+// removing a rule lets through the traffic it used to drop, so every
+// reference from a firewall or NAT rule widens the ruleset.
+func widenedBy(refs []types.Reference) []types.Reference {
+	var widened []types.Reference
+	for _, ref := range refs {
+		switch ref.Kind {
+		case types.ReferenceKindRule, types.ReferenceKindNATRule:
+			widened = append(widened, ref)
+		}
+	}
+	return widened
+}
+
+// cascade adds the removal of every reference to del, detaching rulesets from
+// their interfaces.
+func cascade(del *api.Delete, refs []types.Reference) error {
+	for _, ref := range refs {
+		switch ref.Kind {
+		case types.ReferenceKindAttachment:
+			path, err := types.ParseInterfacePath(ref.Interface)
+			if err != nil {
+				return fmt.Errorf("Cannot detach a ruleset from interface %s: %s", ref.Interface, err.Error())
+			}
+
+			if del.Interfaces == nil {
+				del.Interfaces = new(types.Interfaces)
+			}
+
+			a, _ := del.Interfaces.Attachment(path)
+			if a == nil {
+				a = new(types.FirewallAttachment)
+				a.SetOpMode(types.OpModeDelete)
+				if err := del.Interfaces.SetAttachment(path, a); err != nil {
+					return err
+				}
+			}
+			if err := detach(a, ref.Direction, ref.IPv6, ref.Ruleset); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("Cannot remove the reference %s.", ref)
+		}
+	}
+	return nil
+}
+
+// detach marks the ruleset attached in the direction for deletion.
+func detach(a *types.FirewallAttachment, direction string, ipv6 bool, ruleset string) error {
+	var field **string
+	switch direction {
+	case "in":
+		field = &a.In
+		if ipv6 {
+			field = &a.IPv6In
+		}
+	case "out":
+		field = &a.Out
+		if ipv6 {
+			field = &a.IPv6Out
+		}
+	case "local":
+		field = &a.Local
+		if ipv6 {
+			field = &a.IPv6Local
+		}
+	default:
+		return fmt.Errorf("Unknown firewall direction %s.", direction)
+	}
+	*field = &ruleset
+	return nil
+}
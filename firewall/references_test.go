@@ -0,0 +1,207 @@
+package firewall
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+const referencedConfig = `{"GET": {
+	"firewall": {
+		"group": {"address-group": {"servers": {"address": ["10.0.0.1"]}, "unused": {"address": ["10.0.0.2"]}, "blocked": {"address": ["198.51.100.0/24"]}}},
+		"name": {"WAN_IN": {"default-action": "drop", "rule": {
+			"10": {"action": "accept", "protocol": "tcp", "destination": {"port": "22", "group": {"address-group": "servers"}}},
+			"20": {"action": "accept", "source": {"group": {"address-group": "servers"}}, "destination": {"group": {"address-group": "servers"}}},
+			"30": {"action": "drop", "source": {"group": {"address-group": "blocked"}}, "destination": {"group": {"address-group": "blocked"}}},
+			"40": {"action": "reject", "protocol": "tcp", "source": {"group": {"address-group": "blocked"}}, "destination": {"port": "23"}}
+		}}}
+	},
+	"interfaces": {"ethernet": {
+		"eth0": {"firewall": {"in": {"name": "WAN_IN"}, "local": {"name": "WAN_IN"}}},
+		"eth1": {"firewall": {"out": {"name": "LAN_OUT"}}}
+	}},
+	"service": {"nat": {"rule": {
+		"5000": {"type": "source", "outbound-interface": "eth0", "source": {"group": {"address-group": "servers"}}, "outside-address": {"address": "203.0.113.1"}}
+	}}}
+}, "success": true}`
+
+func TestDeleteWithReferences(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		do       func(Client) error
+		expected []string
+		refs     []types.Reference
+	}{
+		{
+			name: "unsafe delete ignores references",
+			do: func(c Client) error {
+				return c.DeleteAddressGroup(context.Background(), "servers")
+			},
+			expected: []string{
+				`{"DELETE":{"firewall":{"group":{"address-group":{"servers":null}}}}}`,
+			},
+		},
+		{
+			name: "safe delete of an unused group",
+			do: func(c Client) error {
+				return c.DeleteAddressGroup(context.Background(), "unused", WithSafeDelete())
+			},
+			expected: []string{
+				`{"DELETE":{"firewall":{"group":{"address-group":{"unused":null}}}}}`,
+			},
+		},
+		{
+			name: "safe delete of a referenced group",
+			do: func(c Client) error {
+				return c.DeleteAddressGroup(context.Background(), "servers", WithSafeDelete())
+			},
+			refs: []types.Reference{
+				{Kind: types.ReferenceKindNATRule, Priority: 5000, Field: "source group address-group"},
+				{Kind: types.ReferenceKindRule, Ruleset: "WAN_IN", Priority: 10, Field: "destination group address-group"},
+				{Kind: types.ReferenceKindRule, Ruleset: "WAN_IN", Priority: 20, Field: "destination group address-group"},
+				{Kind: types.ReferenceKindRule, Ruleset: "WAN_IN", Priority: 20, Field: "source group address-group"},
+			},
+		},
+		{
+			name: "cascading delete of a group referenced by drop and reject rules",
+			do: func(c Client) error {
+				return c.DeleteAddressGroup(context.Background(), "blocked", WithCascade())
+			},
+			refs: []types.Reference{
+				{Kind: types.ReferenceKindRule, Ruleset: "WAN_IN", Priority: 30, Field: "destination group address-group"},
+				{Kind: types.ReferenceKindRule, Ruleset: "WAN_IN", Priority: 30, Field: "source group address-group"},
+				{Kind: types.ReferenceKindRule, Ruleset: "WAN_IN", Priority: 40, Field: "source group address-group"},
+			},
+		},
+		{
+			name: "cascading delete of a group referenced by accept and NAT rules",
+			do: func(c Client) error {
+				return c.DeleteAddressGroup(context.Background(), "servers", WithCascade())
+			},
+			refs: []types.Reference{
+				{Kind: types.ReferenceKindNATRule, Priority: 5000, Field: "source group address-group"},
+				{Kind: types.ReferenceKindRule, Ruleset: "WAN_IN", Priority: 10, Field: "destination group address-group"},
+				{Kind: types.ReferenceKindRule, Ruleset: "WAN_IN", Priority: 20, Field: "destination group address-group"},
+				{Kind: types.ReferenceKindRule, Ruleset: "WAN_IN", Priority: 20, Field: "source group address-group"},
+			},
+		},
+		{
+			name: "safe delete of an attached ruleset",
+			do: func(c Client) error {
+				return c.DeleteRuleset(context.Background(), "WAN_IN", WithSafeDelete())
+			},
+			refs: []types.Reference{
+				{Kind: types.ReferenceKindAttachment, Ruleset: "WAN_IN", Interface: "ethernet eth0", Direction: "in"},
+				{Kind: types.ReferenceKindAttachment, Ruleset: "WAN_IN", Interface: "ethernet eth0", Direction: "local"},
+			},
+		},
+		{
+			name: "cascading delete of an attached ruleset",
+			do: func(c Client) error {
+				return c.DeleteRuleset(context.Background(), "WAN_IN", WithCascade())
+			},
+			expected: []string{
+				`{"DELETE":{"firewall":{"name":{"WAN_IN":null}},"interfaces":{"ethernet":{"eth0":{"firewall":{"in":{"name":"WAN_IN"},"local":{"name":"WAN_IN"}}}}}}}`,
+			},
+		},
+	} {
+		apiClient := &apitest.Client{Config: referencedConfig}
+		err := test.do(&client{apiClient: apiClient})
+
+		if len(test.refs) > 0 {
+			require.IsType(t, &InUseError{}, err, test.name)
+			require.Equal(t, test.refs, err.(*InUseError).References, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
+
+// TestCascadeDeleteKeepsRuleMatches ensures that a cascading delete never
+// leaves a rule in its ruleset with one of its matches removed, which would
+// widen the rule.
+func TestCascadeDeleteKeepsRuleMatches(t *testing.T) {
+	for _, do := range []func(Client) error{
+		func(c Client) error { return c.DeleteAddressGroup(context.Background(), "servers", WithCascade()) },
+		func(c Client) error { return c.DeleteAddressGroup(context.Background(), "unused", WithCascade()) },
+		func(c Client) error { return c.DeleteAddressGroup(context.Background(), "blocked", WithCascade()) },
+		func(c Client) error { return c.DeleteRuleset(context.Background(), "WAN_IN", WithCascade()) },
+	} {
+		apiClient := &apitest.Client{Config: referencedConfig}
+		if err := do(&client{apiClient: apiClient}); err != nil {
+			require.IsType(t, &InUseError{}, err)
+		}
+
+		for _, posted := range apiClient.Posted {
+			var op struct {
+				Delete struct {
+					Firewall struct {
+						Name map[string]*struct {
+							Rule map[string]json.RawMessage `json:"rule"`
+						} `json:"name"`
+					} `json:"firewall"`
+				} `json:"DELETE"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(posted), &op))
+			for name, rs := range op.Delete.Firewall.Name {
+				if rs == nil {
+					continue
+				}
+				for priority, rule := range rs.Rule {
+					require.Equal(t, "null", string(rule), "rule %s of %s lost a match", priority, name)
+				}
+			}
+		}
+	}
+}
+
+const attachedConfig = `{"GET": {
+	"interfaces": {
+		"ethernet": {
+			"eth0": {"firewall": {"local": {"name": "WAN_LOCAL"}}, "pppoe": {"0": {"firewall": {"in": {"name": "WAN_IN", "ipv6-name": "WAN6_IN"}, "local": {"name": "WAN_LOCAL"}}}}},
+			"eth1": {"vif": {"10": {"firewall": {"in": {"name": "LAN_IN"}}}, "20": {"firewall": {"out": {"name": "WAN_IN"}}}}}
+		},
+		"bridge": {"br0": {"firewall": {"in": {"name": "WAN_IN"}}}},
+		"openvpn": {"vtun0": {"firewall": {"local": {"ipv6-name": "WAN6_IN"}}}},
+		"wireguard": {"wg0": {"firewall": {"in": {"name": "WAN_IN"}}}}
+	}
+}, "success": true}`
+
+func TestRulesetAttachments(t *testing.T) {
+	c := &client{apiClient: &apitest.Client{Config: attachedConfig}}
+
+	refs, err := c.RulesetAttachments(context.Background(), "WAN_IN")
+	require.NoError(t, err)
+	require.Equal(t, []types.Reference{
+		{Kind: types.ReferenceKindAttachment, Ruleset: "WAN_IN", Interface: "bridge br0", Direction: "in"},
+		{Kind: types.ReferenceKindAttachment, Ruleset: "WAN_IN", Interface: "ethernet eth0 pppoe 0", Direction: "in"},
+		{Kind: types.ReferenceKindAttachment, Ruleset: "WAN_IN", Interface: "ethernet eth1 vif 20", Direction: "out"},
+		{Kind: types.ReferenceKindAttachment, Ruleset: "WAN_IN", Interface: "wireguard wg0", Direction: "in"},
+	}, refs)
+
+	refs, err = c.IPv6RulesetAttachments(context.Background(), "WAN6_IN")
+	require.NoError(t, err)
+	require.Equal(t, []types.Reference{
+		{Kind: types.ReferenceKindAttachment, Ruleset: "WAN6_IN", Interface: "ethernet eth0 pppoe 0", Direction: "in", IPv6: true},
+		{Kind: types.ReferenceKindAttachment, Ruleset: "WAN6_IN", Interface: "openvpn vtun0", Direction: "local", IPv6: true},
+	}, refs)
+	require.Equal(t, "interfaces openvpn vtun0 firewall local ipv6-name", refs[1].String())
+
+	refs, err = c.RulesetAttachments(context.Background(), "UNUSED")
+	require.NoError(t, err)
+	require.Empty(t, refs)
+
+	apiClient := &apitest.Client{Config: attachedConfig}
+	require.NoError(t, (&client{apiClient: apiClient}).DeleteRuleset(context.Background(), "WAN_LOCAL", WithCascade()))
+	require.Equal(t, []string{
+		`{"DELETE":{"firewall":{"name":{"WAN_LOCAL":null}},"interfaces":{"ethernet":{"eth0":{"firewall":{"local":{"name":"WAN_LOCAL"}},"pppoe":{"0":{"firewall":{"local":{"name":"WAN_LOCAL"}}}}}}}}}`,
+	}, apiClient.Posted)
+}
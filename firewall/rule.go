@@ -0,0 +1,358 @@
+package firewall
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+func (c *client) GetRule(ctx context.Context, ruleset string, priority int) (*types.Rule, error) {
+	rs, err := c.GetRuleset(ctx, ruleset)
+	if err != nil {
+		return nil, err
+	}
+	return toRule(priority, rs)
+}
+
+func (c *client) CreateRule(ctx context.Context, ruleset string, r *types.Rule) (*types.Rule, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.apiClient.Post(ctx, setRule(ruleset, r)); err != nil {
+		return nil, err
+	}
+	return c.GetRule(ctx, ruleset, r.Priority)
+}
+
+func (c *client) UpdateRule(ctx context.Context, ruleset string, current *types.Rule, patches []jsonpatch.JsonPatchOperation) (*types.Rule, error) {
+	if len(patches) == 0 {
+		return current, nil
+	}
+
+	// Patch a copy so that the codec mode of the caller's rule is left alone.
+	tmp := *current
+	tmp.SetCodecMode(types.CodecModeLocal)
+
+	var rule types.Rule
+	if err := utils.Patch(&tmp, &rule, patches); err != nil {
+		return nil, err
+	}
+
+	if rule.Priority != current.Priority {
+		return nil, fmt.Errorf("The priority of rule %d cannot be patched, use MoveRule instead.", current.Priority)
+	}
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := setRule(ruleset, &rule)
+	if stale := staleRule(current, &rule); stale != nil {
+		in.Delete = deleteRules(ruleset, stale)
+	}
+
+	if _, err := c.apiClient.Post(ctx, in); err != nil {
+		return nil, err
+	}
+	return c.GetRule(ctx, ruleset, current.Priority)
+}
+
+func (c *client) DeleteRule(ctx context.Context, ruleset string, priority int) error {
+	_, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: deleteRules(ruleset, &types.Rule{
+			Priority: priority,
+		}),
+	})
+	return err
+}
+
+func (c *client) MoveRule(ctx context.Context, ruleset string, from, to int) (*types.Rule, error) {
+	rs, err := c.GetRuleset(ctx, ruleset)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := toRule(from, rs)
+	if err != nil {
+		return nil, err
+	}
+
+	if from == to {
+		return rule, nil
+	}
+
+	if _, err := toRule(to, rs); err == nil {
+		return nil, fmt.Errorf("The rule %d already exists in ruleset %s.", to, ruleset)
+	}
+
+	moved := *rule
+	moved.Priority = to
+
+	if err := moved.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := setRule(ruleset, &moved)
+	in.Delete = deleteRules(ruleset, &types.Rule{
+		Priority: from,
+	})
+
+	if _, err := c.apiClient.Post(ctx, in); err != nil {
+		return nil, err
+	}
+	return c.GetRule(ctx, ruleset, to)
+}
+
+// SyncRules changes the rules of the current ruleset into the rules of the
+// desired ruleset, typically the result of InsertRuleBefore, InsertRuleAfter or
+// Renumber on a Clone of current. Only rules that differ are set and only
+// stale rules and nodes are deleted, all within a single commit. Neither
+// ruleset is changed, so current still describes the router if the commit
+// fails.
+func (c *client) SyncRules(ctx context.Context, current, desired *types.Ruleset) (*types.Ruleset, error) {
+	in, err := syncRules(current, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	if in != nil {
+		if _, err := c.apiClient.Post(ctx, in); err != nil {
+			return nil, err
+		}
+	}
+	return c.GetRuleset(ctx, current.Name)
+}
+
+// syncRules returns the operation that turns the rules of current into the
+// rules of desired. If the rules are already in sync, nil is returned.
+func syncRules(current, desired *types.Ruleset) (*api.Operation, error) {
+	if err := desired.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing := map[int]*types.Rule{}
+	for _, rule := range current.Rules {
+		existing[rule.Priority] = rule
+	}
+
+	var set []*types.Rule
+	var stale []*types.Rule
+	wanted := map[int]bool{}
+
+	for _, rule := range desired.Rules {
+		if wanted[rule.Priority] {
+			return nil, fmt.Errorf("The rule %d is defined more than once in ruleset %s.", rule.Priority, current.Name)
+		}
+		wanted[rule.Priority] = true
+
+		old, ok := existing[rule.Priority]
+		if !ok {
+			set = append(set, rule)
+			continue
+		}
+
+		equal, err := rulesEqual(old, rule)
+		if err != nil {
+			return nil, err
+		}
+		if equal {
+			continue
+		}
+
+		set = append(set, rule)
+		if s := staleRule(old, rule); s != nil {
+			stale = append(stale, s)
+		}
+	}
+
+	var discard []int
+	for _, rule := range current.Rules {
+		if !wanted[rule.Priority] {
+			discard = append(discard, rule.Priority)
+		}
+	}
+
+	if len(set) == 0 && len(discard) == 0 {
+		return nil, nil
+	}
+
+	in := new(api.Operation)
+
+	if len(set) > 0 {
+		in.Set = setRule(current.Name, set...).Set
+	}
+
+	for _, priority := range discard {
+		stale = append(stale, &types.Rule{
+			Priority: priority,
+		})
+	}
+	if len(stale) > 0 {
+		in.Delete = deleteRules(current.Name, stale...)
+	}
+
+	return in, nil
+}
+
+// rulesEqual compares two rules by their encoding in the remote codec mode.
+func rulesEqual(a, b *types.Rule) (bool, error) {
+	encode := func(r *types.Rule) (string, error) {
+		tmp := *r
+		tmp.SetCodecMode(types.CodecModeRemote)
+		tmp.SetOpMode(types.OpModeUnknown)
+		data, err := json.Marshal(&tmp)
+		return string(data), err
+	}
+
+	x, err := encode(a)
+	if err != nil {
+		return false, err
+	}
+	y, err := encode(b)
+	if err != nil {
+		return false, err
+	}
+	return x == y, nil
+}
+
+func setRule(ruleset string, rules ...*types.Rule) *api.Operation {
+	rs := &types.Ruleset{
+		Rules: rules,
+	}
+	rs.SetCodecMode(types.CodecModeRemote)
+
+	return &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Firewall: &types.Firewall{
+					Rulesets: map[string]*types.Ruleset{
+						ruleset: rs,
+					},
+				},
+			},
+		},
+	}
+}
+
+// deleteRules deletes the nodes of each rule. A rule with only a priority is
+// deleted entirely.
+func deleteRules(ruleset string, rules ...*types.Rule) *api.Delete {
+	for _, rule := range rules {
+		rule.SetOpMode(types.OpModeDelete)
+	}
+
+	return &api.Delete{
+		Resources: api.Resources{
+			Firewall: &types.Firewall{
+				Rulesets: map[string]*types.Ruleset{
+					ruleset: {
+						Rules: rules,
+					},
+				},
+			},
+		},
+	}
+}
+
+// staleRule returns a rule containing the nodes that are set in current but no
+// longer set in updated. Nodes that are merely changed are overwritten by the
+// SET operation and are not included. If nothing is stale, nil is returned.
+func staleRule(current, updated *types.Rule) *types.Rule {
+	stale := &types.Rule{
+		Priority: current.Priority,
+	}
+	isStale := false
+
+	if current.Description != nil && *current.Description != "" && (updated.Description == nil || *updated.Description == "") {
+		stale.Description = current.Description
+		isStale = true
+	}
+
+	if isSpecificProtocol(current.Protocol) && !isSpecificProtocol(updated.Protocol) {
+		stale.Protocol = current.Protocol
+		isStale = true
+	}
+
+	if current.State != nil && updated.State == nil {
+		stale.State = current.State
+		isStale = true
+	}
+
+	if current.Source != nil {
+		var updatedSource types.Source
+		if updated.Source != nil {
+			updatedSource = *updated.Source
+		}
+
+		s := types.Source{
+			Address:      staleString(current.Source.Address, updatedSource.Address),
+			AddressGroup: staleString(current.Source.AddressGroup, updatedSource.AddressGroup),
+			PortGroup:    staleString(current.Source.PortGroup, updatedSource.PortGroup),
+			MAC:          staleString(current.Source.MAC, updatedSource.MAC),
+		}
+		if current.Source.Port != nil && updatedSource.Port == nil {
+			s.Port = current.Source.Port
+		}
+
+		if s != (types.Source{}) {
+			stale.Source = &s
+			isStale = true
+		}
+	}
+
+	if current.Destination != nil {
+		var updatedDestination types.Destination
+		if updated.Destination != nil {
+			updatedDestination = *updated.Destination
+		}
+
+		d := types.Destination{
+			Address:      staleString(current.Destination.Address, updatedDestination.Address),
+			AddressGroup: staleString(current.Destination.AddressGroup, updatedDestination.AddressGroup),
+			PortGroup:    staleString(current.Destination.PortGroup, updatedDestination.PortGroup),
+		}
+		if current.Destination.Port != nil && updatedDestination.Port == nil {
+			d.Port = current.Destination.Port
+		}
+
+		if d != (types.Destination{}) {
+			stale.Destination = &d
+			isStale = true
+		}
+	}
+
+	if !isStale {
+		return nil
+	}
+	return stale
+}
+
+func staleString(current, updated *string) *string {
+	if current == nil || *current == "" {
+		return nil
+	}
+	if updated == nil || *updated == "" {
+		return current
+	}
+	return nil
+}
+
+func isSpecificProtocol(protocol string) bool {
+	return protocol != "" && protocol != "*"
+}
+
+func toRule(priority int, rs *types.Ruleset) (*types.Rule, error) {
+	for _, rule := range rs.Rules {
+		if rule != nil && rule.Priority == priority {
+			return rule, nil
+		}
+	}
+	return nil, fmt.Errorf("The rule %d does not exist in ruleset %s.", priority, rs.Name)
+}
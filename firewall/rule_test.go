@@ -0,0 +1,156 @@
+package firewall
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	rulesConfig = `{"GET": {"firewall": {"name": {"WAN_IN": {"default-action": "drop", "rule": {
+		"10": {"action": "accept", "description": "established", "state": {"established": "enable", "related": "enable"}},
+		"20": {"action": "accept", "protocol": "tcp", "description": "ssh", "source": {"address": "10.0.0.0/8"}, "destination": {"port": "22", "group": {"address-group": "servers"}}}
+	}}}}}, "success": true}`
+
+	movedRulesConfig = `{"GET": {"firewall": {"name": {"WAN_IN": {"default-action": "drop", "rule": {
+		"10": {"action": "accept", "description": "established", "state": {"established": "enable", "related": "enable"}},
+		"30": {"action": "accept", "protocol": "tcp", "description": "ssh", "source": {"address": "10.0.0.0/8"}, "destination": {"port": "22", "group": {"address-group": "servers"}}}
+	}}}}}, "success": true}`
+)
+
+func TestRuleOperations(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		committed string
+		do        func(Client) error
+		expected  []string
+	}{
+		{
+			name: "delete rule",
+			do: func(c Client) error {
+				return c.DeleteRule(context.Background(), "WAN_IN", 20)
+			},
+			expected: []string{
+				`{"DELETE":{"firewall":{"name":{"WAN_IN":{"rule":{"20":null}}}}}}`,
+			},
+		},
+		{
+			name:      "move rule",
+			committed: movedRulesConfig,
+			do: func(c Client) error {
+				_, err := c.MoveRule(context.Background(), "WAN_IN", 20, 30)
+				return err
+			},
+			expected: []string{
+				`{"SET":{"firewall":{"name":{"WAN_IN":{"rule":{"30":{"log":"disable","description":"ssh","action":"accept","protocol":"tcp","source":{"address":"10.0.0.0/8"},"destination":{"port":"22","group":{"address-group":"servers"}},"state":null}}}}}},"DELETE":{"firewall":{"name":{"WAN_IN":{"rule":{"20":null}}}}}}`,
+			},
+		},
+		{
+			name: "update rule removes only stale nodes",
+			do: func(c Client) error {
+				rule, err := c.GetRule(context.Background(), "WAN_IN", 20)
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateRule(context.Background(), "WAN_IN", rule, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/source"},
+					{Operation: "remove", Path: "/destination/group"},
+					{Operation: "replace", Path: "/destination/port", Value: "2222"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"firewall":{"name":{"WAN_IN":{"rule":{"20":{"log":"disable","description":"ssh","action":"accept","protocol":"tcp","source":null,"destination":{"port":"2222"},"state":null}}}}}},"DELETE":{"firewall":{"name":{"WAN_IN":{"rule":{"20":{"destination":{"group":{"address-group":null}},"source":{"address":null}}}}}}}}`,
+			},
+		},
+		{
+			name: "update rule without stale nodes",
+			do: func(c Client) error {
+				rule, err := c.GetRule(context.Background(), "WAN_IN", 10)
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateRule(context.Background(), "WAN_IN", rule, []jsonpatch.JsonPatchOperation{
+					{Operation: "replace", Path: "/action", Value: "drop"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"firewall":{"name":{"WAN_IN":{"rule":{"10":{"log":"disable","description":"established","action":"drop","protocol":"","source":null,"destination":null,"state":{"established":"enable","invalid":"disable","new":"disable","related":"enable"}}}}}}}}`,
+			},
+		},
+	} {
+		apiClient := &apitest.Client{Config: rulesConfig, Committed: test.committed}
+		require.NoError(t, test.do(&client{apiClient: apiClient}), test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
+
+func TestMoveRuleToExistingPriority(t *testing.T) {
+	apiClient := &apitest.Client{Config: rulesConfig}
+	_, err := (&client{apiClient: apiClient}).MoveRule(context.Background(), "WAN_IN", 20, 10)
+	require.Error(t, err)
+	require.Empty(t, apiClient.Posted)
+}
+
+func TestUpdateRuleLeavesCurrentUnchanged(t *testing.T) {
+	c := &client{apiClient: &apitest.Client{Config: rulesConfig}}
+
+	rule, err := c.GetRule(context.Background(), "WAN_IN", 20)
+	require.NoError(t, err)
+	before, err := json.Marshal(rule)
+	require.NoError(t, err)
+
+	_, err = c.UpdateRule(context.Background(), "WAN_IN", rule, []jsonpatch.JsonPatchOperation{
+		{Operation: "replace", Path: "/action", Value: "drop"},
+	})
+	require.NoError(t, err)
+
+	after, err := json.Marshal(rule)
+	require.NoError(t, err)
+	require.Equal(t, string(before), string(after))
+}
+
+func TestSyncRules(t *testing.T) {
+	apiClient := &apitest.Client{Config: rulesConfig}
+	c := &client{apiClient: apiClient}
+
+	current, err := c.GetRuleset(context.Background(), "WAN_IN")
+	require.NoError(t, err)
+
+	desired := current.Clone()
+
+	ssh, err := toRule(20, desired)
+	require.NoError(t, err)
+	ssh.Source = nil
+
+	require.NoError(t, desired.InsertRuleBefore(types.RuleRef{Description: "established"}, &types.Rule{
+		Action:   "drop",
+		Protocol: "all",
+		State: &types.State{
+			Invalid: boolptr(true),
+		},
+	}))
+	require.NoError(t, desired.Renumber(100))
+
+	in, err := syncRules(current, desired)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	require.Equal(t, `{"SET":{"firewall":{"name":{"WAN_IN":{"rule":{"100":{"log":"disable","action":"drop","protocol":"all","source":null,"destination":null,"state":{"established":"disable","invalid":"enable","new":"disable","related":"disable"}},"200":{"log":"disable","description":"established","action":"accept","protocol":"","source":null,"destination":null,"state":{"established":"enable","invalid":"disable","new":"disable","related":"enable"}},"300":{"log":"disable","description":"ssh","action":"accept","protocol":"tcp","source":null,"destination":{"port":"22","group":{"address-group":"servers"}},"state":null}}}}}},"DELETE":{"firewall":{"name":{"WAN_IN":{"rule":{"10":null,"20":null}}}}}}`, string(data))
+
+	in, err = syncRules(current, current)
+	require.NoError(t, err)
+	require.Nil(t, in)
+}
+
+func boolptr(b bool) *bool {
+	return &b
+}
@@ -1,6 +1,6 @@
 module github.com/frankgreco/edge-sdk-go
 
-go 1.17
+go 1.20
 
 require (
 	github.com/evanphx/json-patch v0.5.2
@@ -8,7 +8,10 @@ require (
 	github.com/stretchr/testify v1.7.2
 )
 
-require github.com/hashicorp/terraform-plugin-framework v0.13.0
+require (
+	github.com/hashicorp/terraform-plugin-framework v0.13.0
+	golang.org/x/crypto v0.33.0
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
@@ -18,7 +21,8 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/vmihailenco/msgpack/v4 v4.3.12 // indirect
 	github.com/vmihailenco/tagparser v0.1.1 // indirect
-	golang.org/x/net v0.0.0-20211112202133-69e39bad7dc2 // indirect
+	golang.org/x/net v0.21.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
 	google.golang.org/appengine v1.6.5 // indirect
 	google.golang.org/protobuf v1.28.1 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
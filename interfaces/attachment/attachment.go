@@ -0,0 +1,229 @@
+// Package attachment manages the firewall rulesets attached to any kind of
+// interface, addressed by its path below the interfaces node.
+package attachment
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+type Client interface {
+	Get(context.Context, types.InterfacePath) (*types.FirewallAttachment, error)
+	List(context.Context) ([]*types.FirewallAttachment, error)
+	Attach(context.Context, types.InterfacePath, *types.FirewallAttachment) (*types.FirewallAttachment, error)
+	Update(context.Context, *types.FirewallAttachment, []jsonpatch.JsonPatchOperation) (*types.FirewallAttachment, error)
+	Detach(context.Context, types.InterfacePath) error
+}
+
+type client struct {
+	apiClient api.Client
+}
+
+func New(httpClient *http.Client, host string) Client {
+	return NewWithAPIClient(api.New(httpClient, host))
+}
+
+// NewWithAPIClient is used by the interface clients that manage attachments on
+// behalf of their own interface type.
+func NewWithAPIClient(apiClient api.Client) Client {
+	return &client{
+		apiClient: apiClient,
+	}
+}
+
+// Get returns the rulesets attached to the interface. An interface without
+// any attached ruleset returns an empty attachment.
+func (c *client) Get(ctx context.Context, path types.InterfacePath) (*types.FirewallAttachment, error) {
+	if err := path.Validate(); err != nil {
+		return nil, err
+	}
+
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ifaces := new(types.Interfaces)
+	if op != nil && op.Get != nil && op.Get.Interfaces != nil {
+		ifaces = op.Get.Interfaces
+	}
+
+	a, err := ifaces.Attachment(path)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		a = new(types.FirewallAttachment)
+	}
+	a.Path = path
+	a.Interface = path.String()
+	return a, nil
+}
+
+// List returns the attachments of every interface, ordered by path.
+func (c *client) List(ctx context.Context) ([]*types.FirewallAttachment, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if op == nil || op.Get == nil || op.Get.Interfaces == nil {
+		return []*types.FirewallAttachment{}, nil
+	}
+
+	attachments := []*types.FirewallAttachment{}
+	for _, a := range op.Get.Interfaces.Attachments() {
+		if a.IsEmpty() {
+			continue
+		}
+		a.Interface = a.Path.String()
+		attachments = append(attachments, a)
+	}
+	return attachments, nil
+}
+
+// Attach attaches the rulesets named in each direction of the attachment.
+// Directions the attachment leaves empty are not changed.
+func (c *client) Attach(ctx context.Context, path types.InterfacePath, a *types.FirewallAttachment) (*types.FirewallAttachment, error) {
+	tmp := *a
+	tmp.Path = path
+	if err := tmp.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Setting an attachment on an interface that does not exist would create
+	// the interface.
+	if _, err := c.Get(ctx, path); err != nil {
+		return nil, err
+	}
+
+	set := new(types.Interfaces)
+	if err := set.SetAttachment(path, &tmp); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Interfaces: set,
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, path)
+}
+
+// Update applies the patches to the current attachment, attaching the
+// rulesets that changed and detaching the ones that were removed in a single
+// commit.
+func (c *client) Update(ctx context.Context, current *types.FirewallAttachment, patches []jsonpatch.JsonPatchOperation) (*types.FirewallAttachment, error) {
+	path := current.Path
+	if err := path.Validate(); err != nil {
+		return nil, err
+	}
+
+	var a types.FirewallAttachment
+	if err := utils.Patch(current, &a, patches); err != nil {
+		return nil, err
+	}
+	a.Path = path
+	a.Interface = current.Interface
+
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := new(api.Operation)
+
+	if !a.IsEmpty() {
+		set := new(types.Interfaces)
+		if err := set.SetAttachment(path, &a); err != nil {
+			return nil, err
+		}
+		in.Set = &api.Set{
+			Resources: api.Resources{
+				Interfaces: set,
+			},
+		}
+	}
+
+	if stale := staleAttachment(current, &a); stale != nil {
+		del := new(types.Interfaces)
+		if err := del.SetAttachment(path, stale); err != nil {
+			return nil, err
+		}
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Interfaces: del,
+			},
+		}
+	}
+
+	if in.Set != nil || in.Delete != nil {
+		if _, err := c.apiClient.Post(ctx, in); err != nil {
+			return nil, err
+		}
+	}
+	return c.Get(ctx, path)
+}
+
+// Detach removes every ruleset attached to the interface.
+func (c *client) Detach(ctx context.Context, path types.InterfacePath) error {
+	a := new(types.FirewallAttachment)
+	a.SetOpMode(types.OpModeDelete)
+
+	del := new(types.Interfaces)
+	if err := del.SetAttachment(path, a); err != nil {
+		return err
+	}
+
+	_, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Interfaces: del,
+			},
+		},
+	})
+	return err
+}
+
+// staleAttachment returns the rulesets that are attached in current but not
+// in updated, or nil if there are none.
+func staleAttachment(current, updated *types.FirewallAttachment) *types.FirewallAttachment {
+	stale := new(types.FirewallAttachment)
+	stale.SetOpMode(types.OpModeDelete)
+
+	found := false
+	for _, dir := range []struct {
+		current, updated *string
+		stale            **string
+	}{
+		{current.In, updated.In, &stale.In},
+		{current.Out, updated.Out, &stale.Out},
+		{current.Local, updated.Local, &stale.Local},
+		{current.IPv6In, updated.IPv6In, &stale.IPv6In},
+		{current.IPv6Out, updated.IPv6Out, &stale.IPv6Out},
+		{current.IPv6Local, updated.IPv6Local, &stale.IPv6Local},
+	} {
+		if dir.current == nil || *dir.current == "" {
+			continue
+		}
+		if dir.updated != nil && *dir.updated != "" {
+			continue
+		}
+		name := *dir.current
+		*dir.stale = &name
+		found = true
+	}
+
+	if !found {
+		return nil
+	}
+	return stale
+}
@@ -0,0 +1,117 @@
+package attachment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const attachmentsConfig = `{"GET": {"interfaces": {
+	"ethernet": {
+		"eth0": {"pppoe": {"0": {"firewall": {"in": {"name": "WAN_IN", "ipv6-name": "WAN6_IN"}, "local": {"name": "WAN_LOCAL"}}}}},
+		"eth1": {"vif": {"20": {"address": ["10.0.20.1/24"]}}}
+	},
+	"wireguard": {"wg0": {"firewall": {"in": {"name": "WG_IN"}}}}
+}}, "success": true}`
+
+func TestAttachmentOperations(t *testing.T) {
+	wan := types.InterfacePath{"ethernet", "eth0", "pppoe", "0"}
+	vlan := types.InterfacePath{"ethernet", "eth1", "vif", "20"}
+
+	for _, test := range []struct {
+		name     string
+		do       func(Client) error
+		expected []string
+		err      string
+	}{
+		{
+			name: "get",
+			do: func(c Client) error {
+				a, err := c.Get(context.Background(), wan)
+				if err != nil {
+					return err
+				}
+				require.Equal(t, "WAN6_IN", *a.IPv6In)
+				require.Equal(t, "ethernet eth0 pppoe 0", a.Interface)
+				return nil
+			},
+		},
+		{
+			name: "list",
+			do: func(c Client) error {
+				attachments, err := c.List(context.Background())
+				if err != nil {
+					return err
+				}
+				require.Len(t, attachments, 2)
+				require.Equal(t, "ethernet eth0 pppoe 0", attachments[0].Interface)
+				require.Equal(t, "wireguard wg0", attachments[1].Interface)
+				return nil
+			},
+		},
+		{
+			name: "attach to a vlan",
+			do: func(c Client) error {
+				in := "LAN_IN"
+				_, err := c.Attach(context.Background(), vlan, &types.FirewallAttachment{IPv6In: &in})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"ethernet":{"eth1":{"vif":{"20":{"firewall":{"in":{"ipv6-name":"LAN_IN"}}}}}}}}}`,
+			},
+		},
+		{
+			name: "attach to a missing interface",
+			do: func(c Client) error {
+				in := "LAN_IN"
+				_, err := c.Attach(context.Background(), types.InterfacePath{"ethernet", "eth1", "vif", "30"}, &types.FirewallAttachment{In: &in})
+				return err
+			},
+			err: "The interface ethernet eth1 vif 30 does not exist.",
+		},
+		{
+			name: "update",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background(), wan)
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "replace", Path: "/in/name", Value: "WAN_IN_V2"},
+					{Operation: "remove", Path: "/in/ipv6-name"},
+					{Operation: "remove", Path: "/local"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"ethernet":{"eth0":{"pppoe":{"0":{"firewall":{"in":{"name":"WAN_IN_V2"}}}}}}}},"DELETE":{"interfaces":{"ethernet":{"eth0":{"pppoe":{"0":{"firewall":{"in":{"ipv6-name":"WAN6_IN"},"local":{"name":"WAN_LOCAL"}}}}}}}}}`,
+			},
+		},
+		{
+			name: "detach",
+			do: func(c Client) error {
+				return c.Detach(context.Background(), types.InterfacePath{"wireguard", "wg0"})
+			},
+			expected: []string{
+				`{"DELETE":{"interfaces":{"wireguard":{"wg0":{"firewall":null}}}}}`,
+			},
+		},
+	} {
+		apiClient := &apitest.Client{Config: attachmentsConfig}
+		err := test.do(NewWithAPIClient(apiClient))
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
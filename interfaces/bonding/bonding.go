@@ -0,0 +1,321 @@
+// Package bonding manages link aggregation interfaces and their member
+// ethernet interfaces.
+package bonding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+// Client manages the properties and members of bonds. Their firewall
+// attachments are managed by the attachment client and their VLANs by the vif
+// client.
+type Client interface {
+	Get(context.Context, string) (*types.Bonding, error)
+	Create(context.Context, *types.Bonding) (*types.Bonding, error)
+	Update(context.Context, *types.Bonding, []jsonpatch.JsonPatchOperation) (*types.Bonding, error)
+	Delete(context.Context, string) error
+	AddMember(context.Context, string, string) (*types.Bonding, error)
+	RemoveMember(context.Context, string, string) (*types.Bonding, error)
+}
+
+type client struct {
+	apiClient api.Client
+}
+
+func New(httpClient *http.Client, host string) Client {
+	return &client{
+		apiClient: api.New(httpClient, host),
+	}
+}
+
+func (c *client) Get(ctx context.Context, id string) (*types.Bonding, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toBonding(id, op)
+}
+
+// Create creates the bond and makes each of its members part of it in a
+// single commit. Members must exist and must not belong to a bridge or
+// another bond.
+func (c *client) Create(ctx context.Context, bonding *types.Bonding) (*types.Bonding, error) {
+	if err := bonding.Validate(); err != nil {
+		return nil, err
+	}
+
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ifaces := interfacesOf(op)
+
+	if _, ok := ifaces.Bonding[bonding.ID]; ok {
+		return nil, fmt.Errorf("The bonding interface %s already exists.", bonding.ID)
+	}
+	for _, member := range bonding.Members {
+		if err := ifaces.EnsureNotMember(member); err != nil {
+			return nil, err
+		}
+	}
+
+	set := &types.Interfaces{
+		Bonding: map[string]*types.Bonding{
+			bonding.ID: bonding.Properties(),
+		},
+	}
+	setMembers(set, bonding.ID, bonding.Members)
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Interfaces: set,
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, bonding.ID)
+}
+
+// Update applies the patches to the properties of the current bond, setting
+// the ones that changed and deleting the ones that were removed in a single
+// commit. Members are changed with AddMember and RemoveMember.
+func (c *client) Update(ctx context.Context, current *types.Bonding, patches []jsonpatch.JsonPatchOperation) (*types.Bonding, error) {
+	var bonding types.Bonding
+	if err := utils.Patch(current.Properties(), &bonding, patches); err != nil {
+		return nil, err
+	}
+	bonding.ID = current.ID
+	bonding.Members = current.Members
+
+	if err := bonding.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := new(api.Operation)
+
+	if !bonding.IsEmpty() {
+		in.Set = &api.Set{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					Bonding: map[string]*types.Bonding{
+						bonding.ID: bonding.Properties(),
+					},
+				},
+			},
+		}
+	}
+
+	if stale := staleBonding(current, &bonding); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					Bonding: map[string]*types.Bonding{
+						bonding.ID: stale,
+					},
+				},
+			},
+		}
+	}
+
+	if in.Set != nil || in.Delete != nil {
+		if _, err := c.apiClient.Post(ctx, in); err != nil {
+			return nil, err
+		}
+	}
+	return c.Get(ctx, bonding.ID)
+}
+
+// Delete removes the bond and releases its members in a single commit. A bond
+// that still has VLANs returns a *types.SubInterfacesError instead.
+func (c *client) Delete(ctx context.Context, id string) error {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return err
+	}
+	current, err := toBonding(id, op)
+	if err != nil {
+		return err
+	}
+	if err := op.Get.Interfaces.EnsureNoSubInterfaces(path(id)); err != nil {
+		return err
+	}
+
+	del := &types.Interfaces{
+		Bonding: map[string]*types.Bonding{
+			id: nil,
+		},
+	}
+	deleteMembers(del, id, current.Members)
+
+	_, err = c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Interfaces: del,
+			},
+		},
+	})
+	return err
+}
+
+// AddMember makes the ethernet interface a member of the bond.
+func (c *client) AddMember(ctx context.Context, id, member string) (*types.Bonding, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := toBonding(id, op); err != nil {
+		return nil, err
+	}
+	if err := op.Get.Interfaces.EnsureNotMember(member); err != nil {
+		return nil, err
+	}
+
+	set := new(types.Interfaces)
+	setMembers(set, id, []string{member})
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Interfaces: set,
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, id)
+}
+
+// RemoveMember removes the ethernet interface from the bond.
+func (c *client) RemoveMember(ctx context.Context, id, member string) (*types.Bonding, error) {
+	current, err := c.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !contains(current.Members, member) {
+		return nil, fmt.Errorf("The ethernet interface %s is not a member of bond %s.", member, id)
+	}
+
+	del := new(types.Interfaces)
+	deleteMembers(del, id, []string{member})
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Interfaces: del,
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, id)
+}
+
+func path(id string) types.InterfacePath {
+	return types.InterfacePath{"bonding", id}
+}
+
+func interfacesOf(op *api.Operation) *types.Interfaces {
+	if op == nil || op.Get == nil || op.Get.Interfaces == nil {
+		return new(types.Interfaces)
+	}
+	return op.Get.Interfaces
+}
+
+func toBonding(id string, op *api.Operation) (*types.Bonding, error) {
+	if op == nil || op.Get == nil || op.Get.Interfaces == nil || op.Get.Interfaces.Bonding == nil {
+		return nil, errors.New("No bonding interfaces exist.")
+	}
+
+	bonding, ok := op.Get.Interfaces.Bonding[id]
+	if !ok || bonding == nil {
+		return nil, fmt.Errorf("The bonding interface %s does not exist.", id)
+	}
+
+	bonding.ID = id
+	bonding.Members = op.Get.Interfaces.BondingMembers(id)
+	if bonding.Firewall != nil {
+		bonding.Firewall.Interface = path(id).String()
+		bonding.Firewall.Path = path(id)
+	}
+
+	return bonding, nil
+}
+
+// setMembers adds the bond-group of each member to the interfaces.
+func setMembers(ifaces *types.Interfaces, id string, members []string) {
+	for _, member := range members {
+		if ifaces.Ethernet == nil {
+			ifaces.Ethernet = map[string]*types.Ethernet{}
+		}
+		ifaces.Ethernet[member] = &types.Ethernet{
+			BondGroup: id,
+		}
+	}
+}
+
+// deleteMembers adds the deletion of the bond-group of each member to the
+// interfaces.
+func deleteMembers(ifaces *types.Interfaces, id string, members []string) {
+	for _, member := range members {
+		if ifaces.Ethernet == nil {
+			ifaces.Ethernet = map[string]*types.Ethernet{}
+		}
+		e := &types.Ethernet{BondGroup: id}
+		e.SetOpMode(types.OpModeDelete)
+		ifaces.Ethernet[member] = e
+	}
+}
+
+// staleBonding returns the properties that are set in current but not in
+// updated, encoded for deletion, or nil if there are none.
+func staleBonding(current, updated *types.Bonding) *types.Bonding {
+	stale := new(types.Bonding)
+	stale.SetOpMode(types.OpModeDelete)
+
+	stale.Addresses = utils.StringSliceDiff(updated.Addresses, current.Addresses)
+	if len(stale.Addresses) == 0 {
+		stale.Addresses = nil
+	}
+	if current.Description != "" && updated.Description == "" {
+		stale.Description = current.Description
+	}
+	stale.Disable = current.Disable && !updated.Disable
+	if current.Mode != "" && updated.Mode == "" {
+		stale.Mode = current.Mode
+	}
+	if current.HashPolicy != "" && updated.HashPolicy == "" {
+		stale.HashPolicy = current.HashPolicy
+	}
+	if current.Primary != "" && updated.Primary == "" {
+		stale.Primary = current.Primary
+	}
+	if current.MTU != 0 && updated.MTU == 0 {
+		stale.MTU = current.MTU
+	}
+	stale.DHCPOptions = current.DHCPOptions.Removed(updated.DHCPOptions)
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	return stale
+}
+
+func contains(vals []string, val string) bool {
+	for _, elem := range vals {
+		if elem == val {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package bonding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const bondingConfig = `{"GET": {"interfaces": {
+	"bonding": {
+		"bond0": {"mode": "802.3ad", "hash-policy": "layer2+3", "mtu": "9000", "disable": null}
+	},
+	"ethernet": {
+		"eth1": {"bond-group": "bond0"},
+		"eth2": {"bond-group": "bond0"},
+		"eth3": {"bridge-group": {"bridge": "br0"}},
+		"eth4": {}
+	}
+}}, "success": true}`
+
+func TestBondingOperations(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		do       func(Client) error
+		expected []string
+		err      string
+	}{
+		{
+			name: "get",
+			do: func(c Client) error {
+				b, err := c.Get(context.Background(), "bond0")
+				if err != nil {
+					return err
+				}
+				require.Equal(t, []string{"eth1", "eth2"}, b.Members)
+				require.Equal(t, 9000, b.MTU)
+				require.True(t, b.Disable)
+				return nil
+			},
+		},
+		{
+			name: "create invalid",
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.Bonding{
+					ID:         "bond1",
+					Mode:       "lacp",
+					HashPolicy: "layer4",
+					Primary:    "eth5",
+					Members:    []string{"eth4", "eth4"},
+				})
+				return err
+			},
+			err: `mode: "lacp" must be one of 802.3ad, active-backup, adaptive-load-balance, broadcast, round-robin, transmit-load-balance, xor-hash; ` +
+				`hash-policy: "layer4" must be one of layer2, layer2+3, layer3+4; ` +
+				`primary: "eth5" must be a member of the bond; ` +
+				`members[1]: "eth4" is listed more than once`,
+		},
+		{
+			name: "create with a bridged member",
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.Bonding{ID: "bond1", Members: []string{"eth3"}})
+				return err
+			},
+			err: "The ethernet interface eth3 is already a member of bridge br0.",
+		},
+		{
+			name: "update",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background(), "bond0")
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "replace", Path: "/mode", Value: "active-backup"},
+					{Operation: "remove", Path: "/hash-policy"},
+					{Operation: "remove", Path: "/disable"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"bonding":{"bond0":{"mtu":"9000","mode":"active-backup"}}}},` +
+					`"DELETE":{"interfaces":{"bonding":{"bond0":{"disable":null,"hash-policy":null}}}}}`,
+			},
+		},
+		{
+			name: "add member",
+			do: func(c Client) error {
+				_, err := c.AddMember(context.Background(), "bond0", "eth4")
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"ethernet":{"eth4":{"bond-group":"bond0"}}}}}`,
+			},
+		},
+		{
+			name: "delete",
+			do: func(c Client) error {
+				return c.Delete(context.Background(), "bond0")
+			},
+			expected: []string{
+				`{"DELETE":{"interfaces":{"ethernet":{"eth1":{"bond-group":"bond0"},"eth2":{"bond-group":"bond0"}},"bonding":{"bond0":null}}}}`,
+			},
+		},
+	} {
+		apiClient := &apitest.Client{Config: bondingConfig}
+		err := test.do(&client{apiClient: apiClient})
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
@@ -0,0 +1,318 @@
+// Package bridge manages bridge interfaces and their member ethernet
+// interfaces.
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+// Client manages the properties and members of bridges. Their firewall
+// attachments are managed by the attachment client and their VLANs by the vif
+// client.
+type Client interface {
+	Get(context.Context, string) (*types.Bridge, error)
+	Create(context.Context, *types.Bridge) (*types.Bridge, error)
+	Update(context.Context, *types.Bridge, []jsonpatch.JsonPatchOperation) (*types.Bridge, error)
+	Delete(context.Context, string) error
+	AddMember(context.Context, string, string) (*types.Bridge, error)
+	RemoveMember(context.Context, string, string) (*types.Bridge, error)
+}
+
+type client struct {
+	apiClient api.Client
+}
+
+func New(httpClient *http.Client, host string) Client {
+	return &client{
+		apiClient: api.New(httpClient, host),
+	}
+}
+
+func (c *client) Get(ctx context.Context, id string) (*types.Bridge, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toBridge(id, op)
+}
+
+// Create creates the bridge and makes each of its members part of it in a
+// single commit. Members must exist and must not belong to another bridge or
+// bond.
+func (c *client) Create(ctx context.Context, bridge *types.Bridge) (*types.Bridge, error) {
+	if err := bridge.Validate(); err != nil {
+		return nil, err
+	}
+
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ifaces := interfacesOf(op)
+
+	if _, ok := ifaces.Bridge[bridge.ID]; ok {
+		return nil, fmt.Errorf("The bridge interface %s already exists.", bridge.ID)
+	}
+	for _, member := range bridge.Members {
+		if err := ifaces.EnsureNotMember(member); err != nil {
+			return nil, err
+		}
+	}
+
+	set := &types.Interfaces{
+		Bridge: map[string]*types.Bridge{
+			bridge.ID: bridge.Properties(),
+		},
+	}
+	setMembers(set, bridge.ID, bridge.Members)
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Interfaces: set,
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, bridge.ID)
+}
+
+// Update applies the patches to the properties of the current bridge, setting
+// the ones that changed and deleting the ones that were removed in a single
+// commit. Members are changed with AddMember and RemoveMember.
+func (c *client) Update(ctx context.Context, current *types.Bridge, patches []jsonpatch.JsonPatchOperation) (*types.Bridge, error) {
+	var bridge types.Bridge
+	if err := utils.Patch(current.Properties(), &bridge, patches); err != nil {
+		return nil, err
+	}
+	bridge.ID = current.ID
+	bridge.Members = current.Members
+
+	if err := bridge.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := new(api.Operation)
+
+	if !bridge.IsEmpty() {
+		in.Set = &api.Set{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					Bridge: map[string]*types.Bridge{
+						bridge.ID: bridge.Properties(),
+					},
+				},
+			},
+		}
+	}
+
+	if stale := staleBridge(current, &bridge); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					Bridge: map[string]*types.Bridge{
+						bridge.ID: stale,
+					},
+				},
+			},
+		}
+	}
+
+	if in.Set != nil || in.Delete != nil {
+		if _, err := c.apiClient.Post(ctx, in); err != nil {
+			return nil, err
+		}
+	}
+	return c.Get(ctx, bridge.ID)
+}
+
+// Delete removes the bridge and releases its members in a single commit. A
+// bridge that still has VLANs returns a *types.SubInterfacesError instead.
+func (c *client) Delete(ctx context.Context, id string) error {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return err
+	}
+	current, err := toBridge(id, op)
+	if err != nil {
+		return err
+	}
+	if err := op.Get.Interfaces.EnsureNoSubInterfaces(path(id)); err != nil {
+		return err
+	}
+
+	del := &types.Interfaces{
+		Bridge: map[string]*types.Bridge{
+			id: nil,
+		},
+	}
+	deleteMembers(del, current.Members)
+
+	_, err = c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Interfaces: del,
+			},
+		},
+	})
+	return err
+}
+
+// AddMember makes the ethernet interface a member of the bridge.
+func (c *client) AddMember(ctx context.Context, id, member string) (*types.Bridge, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := toBridge(id, op); err != nil {
+		return nil, err
+	}
+	if err := op.Get.Interfaces.EnsureNotMember(member); err != nil {
+		return nil, err
+	}
+
+	set := new(types.Interfaces)
+	setMembers(set, id, []string{member})
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Interfaces: set,
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, id)
+}
+
+// RemoveMember removes the ethernet interface from the bridge.
+func (c *client) RemoveMember(ctx context.Context, id, member string) (*types.Bridge, error) {
+	current, err := c.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !contains(current.Members, member) {
+		return nil, fmt.Errorf("The ethernet interface %s is not a member of bridge %s.", member, id)
+	}
+
+	del := new(types.Interfaces)
+	deleteMembers(del, []string{member})
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Interfaces: del,
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, id)
+}
+
+func path(id string) types.InterfacePath {
+	return types.InterfacePath{"bridge", id}
+}
+
+func interfacesOf(op *api.Operation) *types.Interfaces {
+	if op == nil || op.Get == nil || op.Get.Interfaces == nil {
+		return new(types.Interfaces)
+	}
+	return op.Get.Interfaces
+}
+
+func toBridge(id string, op *api.Operation) (*types.Bridge, error) {
+	if op == nil || op.Get == nil || op.Get.Interfaces == nil || op.Get.Interfaces.Bridge == nil {
+		return nil, errors.New("No bridge interfaces exist.")
+	}
+
+	bridge, ok := op.Get.Interfaces.Bridge[id]
+	if !ok || bridge == nil {
+		return nil, fmt.Errorf("The bridge interface %s does not exist.", id)
+	}
+
+	bridge.ID = id
+	bridge.Members = op.Get.Interfaces.BridgeMembers(id)
+	if bridge.Firewall != nil {
+		bridge.Firewall.Interface = path(id).String()
+		bridge.Firewall.Path = path(id)
+	}
+
+	return bridge, nil
+}
+
+// setMembers adds the bridge-group of each member to the interfaces.
+func setMembers(ifaces *types.Interfaces, id string, members []string) {
+	for _, member := range members {
+		if ifaces.Ethernet == nil {
+			ifaces.Ethernet = map[string]*types.Ethernet{}
+		}
+		ifaces.Ethernet[member] = &types.Ethernet{
+			BridgeGroup: &types.BridgeGroup{Bridge: id},
+		}
+	}
+}
+
+// deleteMembers adds the deletion of the bridge-group of each member to the
+// interfaces.
+func deleteMembers(ifaces *types.Interfaces, members []string) {
+	for _, member := range members {
+		if ifaces.Ethernet == nil {
+			ifaces.Ethernet = map[string]*types.Ethernet{}
+		}
+		e := &types.Ethernet{BridgeGroup: &types.BridgeGroup{}}
+		e.SetOpMode(types.OpModeDelete)
+		ifaces.Ethernet[member] = e
+	}
+}
+
+// staleBridge returns the properties that are set in current but not in
+// updated, encoded for deletion, or nil if there are none.
+func staleBridge(current, updated *types.Bridge) *types.Bridge {
+	stale := new(types.Bridge)
+	stale.SetOpMode(types.OpModeDelete)
+
+	stale.Addresses = utils.StringSliceDiff(updated.Addresses, current.Addresses)
+	if len(stale.Addresses) == 0 {
+		stale.Addresses = nil
+	}
+	if current.Description != "" && updated.Description == "" {
+		stale.Description = current.Description
+	}
+	stale.Disable = current.Disable && !updated.Disable
+	if current.STP != nil && updated.STP == nil {
+		stale.STP = current.STP
+	}
+	if current.Aging != 0 && updated.Aging == 0 {
+		stale.Aging = current.Aging
+	}
+	if current.Priority != 0 && updated.Priority == 0 {
+		stale.Priority = current.Priority
+	}
+	stale.DHCPOptions = current.DHCPOptions.Removed(updated.DHCPOptions)
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	return stale
+}
+
+func contains(vals []string, val string) bool {
+	for _, elem := range vals {
+		if elem == val {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package bridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	bridgeConfig = `{"GET": {"interfaces": {
+	"bridge": {
+		"br0": {"address": ["10.0.0.1/24"], "aging": "300", "stp": "true", "priority": "8192"},
+		"br1": {"vif": {"10": {}}}
+	},
+	"ethernet": {
+		"eth1": {"bridge-group": {"bridge": "br0"}},
+		"eth2": {"bridge-group": {"bridge": "br0"}},
+		"eth3": {"bond-group": "bond0"},
+		"eth4": {}
+	}
+}}, "success": true}`
+
+	createdBridgeConfig = `{"GET": {"interfaces": {
+	"bridge": {"br2": {"address": ["10.0.2.1/24"], "stp": "false"}},
+	"ethernet": {"eth4": {"bridge-group": {"bridge": "br2"}}}
+}}, "success": true}`
+)
+
+func TestBridgeOperations(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		committed string
+		do        func(Client) error
+		expected  []string
+		err       string
+	}{
+		{
+			name: "get",
+			do: func(c Client) error {
+				b, err := c.Get(context.Background(), "br0")
+				if err != nil {
+					return err
+				}
+				require.Equal(t, []string{"eth1", "eth2"}, b.Members)
+				require.True(t, *b.STP)
+				require.Equal(t, 300, b.Aging)
+				require.Equal(t, 8192, b.Priority)
+				return nil
+			},
+		},
+		{
+			name:      "create",
+			committed: createdBridgeConfig,
+			do: func(c Client) error {
+				stp := false
+				b, err := c.Create(context.Background(), &types.Bridge{
+					ID:        "br2",
+					Addresses: []string{"10.0.2.1/24"},
+					STP:       &stp,
+					Members:   []string{"eth4"},
+				})
+				if err != nil {
+					return err
+				}
+				require.Equal(t, []string{"eth4"}, b.Members)
+				return nil
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"ethernet":{"eth4":{"bridge-group":{"bridge":"br2"}}},"bridge":{"br2":{"stp":"false","address":["10.0.2.1/24"]}}}}}`,
+			},
+		},
+		{
+			name: "create with a bonded member",
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.Bridge{ID: "br2", Members: []string{"eth3"}})
+				return err
+			},
+			err: "The ethernet interface eth3 is already a member of bond bond0.",
+		},
+		{
+			name: "update",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background(), "br0")
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "replace", Path: "/aging", Value: "600"},
+					{Operation: "remove", Path: "/stp"},
+					{Operation: "remove", Path: "/priority"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"bridge":{"br0":{"aging":"600","address":["10.0.0.1/24"]}}}},` +
+					`"DELETE":{"interfaces":{"bridge":{"br0":{"priority":null,"stp":null}}}}}`,
+			},
+		},
+		{
+			name: "add member",
+			do: func(c Client) error {
+				_, err := c.AddMember(context.Background(), "br0", "eth4")
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"ethernet":{"eth4":{"bridge-group":{"bridge":"br0"}}}}}}`,
+			},
+		},
+		{
+			name: "remove member",
+			do: func(c Client) error {
+				_, err := c.RemoveMember(context.Background(), "br0", "eth2")
+				return err
+			},
+			expected: []string{
+				`{"DELETE":{"interfaces":{"ethernet":{"eth2":{"bridge-group":null}}}}}`,
+			},
+		},
+		{
+			name: "remove non-member",
+			do: func(c Client) error {
+				_, err := c.RemoveMember(context.Background(), "br0", "eth4")
+				return err
+			},
+			err: "The ethernet interface eth4 is not a member of bridge br0.",
+		},
+		{
+			name: "delete",
+			do: func(c Client) error {
+				return c.Delete(context.Background(), "br0")
+			},
+			expected: []string{
+				`{"DELETE":{"interfaces":{"ethernet":{"eth1":{"bridge-group":null},"eth2":{"bridge-group":null}},"bridge":{"br0":null}}}}`,
+			},
+		},
+		{
+			name: "delete with vlans",
+			do: func(c Client) error {
+				return c.Delete(context.Background(), "br1")
+			},
+			err: "The interface bridge br1 still has sub-interfaces: bridge br1 vif 10.",
+		},
+	} {
+		apiClient := &apitest.Client{Config: bridgeConfig, Committed: test.committed}
+		err := test.do(&client{apiClient: apiClient})
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/frankgreco/edge-sdk-go/interfaces/attachment"
 	"github.com/frankgreco/edge-sdk-go/internal/api"
 	"github.com/frankgreco/edge-sdk-go/internal/utils"
 	"github.com/frankgreco/edge-sdk-go/types"
@@ -15,6 +17,9 @@ import (
 
 type Client interface {
 	Get(context.Context, string) (*types.Ethernet, error)
+	Create(context.Context, *types.Ethernet) (*types.Ethernet, error)
+	Update(context.Context, *types.Ethernet, []jsonpatch.JsonPatchOperation) (*types.Ethernet, error)
+	Delete(context.Context, string) error
 	AttachFirewallRuleset(context.Context, string, *types.FirewallAttachment) (*types.FirewallAttachment, error)
 	UpdateFirewallRulesetAttachment(context.Context, *types.FirewallAttachment, []jsonpatch.JsonPatchOperation) (*types.FirewallAttachment, error)
 	DetachFirewallRuleset(context.Context, string) error
@@ -22,12 +27,15 @@ type Client interface {
 }
 
 type client struct {
-	apiClient api.Client
+	apiClient   api.Client
+	attachments attachment.Client
 }
 
 func New(httpClient *http.Client, host string) Client {
+	apiClient := api.New(httpClient, host)
 	return &client{
-		apiClient: api.New(httpClient, host),
+		apiClient:   apiClient,
+		attachments: attachment.NewWithAPIClient(apiClient),
 	}
 }
 
@@ -39,98 +47,107 @@ func (c *client) Get(ctx context.Context, id string) (*types.Ethernet, error) {
 	return toEthernet(id, op)
 }
 
-func (c *client) GetFirewallRulesetAttachment(ctx context.Context, id string) (*types.FirewallAttachment, error) {
-	ethernet, err := c.Get(ctx, id)
-	if err != nil {
+// Create configures the properties of the interface. The firewall attachment
+// and sub-interfaces are ignored; they are managed by their own methods and
+// clients.
+func (c *client) Create(ctx context.Context, ethernet *types.Ethernet) (*types.Ethernet, error) {
+	if err := ethernet.Validate(); err != nil {
 		return nil, err
 	}
-	return ethernet.Firewall, nil
-}
 
-func (c *client) AttachFirewallRuleset(ctx context.Context, id string, firewall *types.FirewallAttachment) (*types.FirewallAttachment, error) {
-	_, err := c.apiClient.Post(ctx, &api.Operation{
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
 		Set: &api.Set{
 			Resources: api.Resources{
 				Interfaces: &types.Interfaces{
 					Ethernet: map[string]*types.Ethernet{
-						id: {
-							Firewall: firewall,
-						},
+						ethernet.ID: ethernet.Properties(),
 					},
 				},
 			},
 		},
-	})
-	if err != nil {
+	}); err != nil {
 		return nil, err
 	}
-	return c.GetFirewallRulesetAttachment(ctx, id)
+	return c.Get(ctx, ethernet.ID)
 }
 
-func (c *client) UpdateFirewallRulesetAttachment(ctx context.Context, current *types.FirewallAttachment, patches []jsonpatch.JsonPatchOperation) (*types.FirewallAttachment, error) {
-	var a types.FirewallAttachment
-	if err := utils.Patch(current, &a, patches); err != nil {
+// Update applies the patches to the properties of the current interface,
+// setting the ones that changed and deleting the ones that were removed in a
+// single commit.
+func (c *client) Update(ctx context.Context, current *types.Ethernet, patches []jsonpatch.JsonPatchOperation) (*types.Ethernet, error) {
+	var ethernet types.Ethernet
+	if err := utils.Patch(current.Properties(), &ethernet, patches); err != nil {
 		return nil, err
 	}
+	ethernet.ID = current.ID
 
-	var del types.FirewallAttachment
-	{
-		empty := ""
-
-		if a.In != nil && *a.In != "" {
-			del.In = &empty
-		}
-		if a.Out != nil && *a.Out != "" {
-			del.Out = &empty
-		}
-		if a.Local != nil && *a.Local != "" {
-			del.Local = &empty
-		}
+	if err := ethernet.Validate(); err != nil {
+		return nil, err
 	}
 
-	in := &api.Operation{
-		Set: &api.Set{
+	in := new(api.Operation)
+
+	if !ethernet.IsEmpty() {
+		in.Set = &api.Set{
 			Resources: api.Resources{
 				Interfaces: &types.Interfaces{
 					Ethernet: map[string]*types.Ethernet{
-						current.Interface: {
-							Firewall: &a,
-						},
+						ethernet.ID: ethernet.Properties(),
 					},
 				},
 			},
-		},
+		}
 	}
 
-	if del.In != nil || del.Out != nil || del.Local != nil {
+	if stale := staleEthernet(current, &ethernet); stale != nil {
 		in.Delete = &api.Delete{
 			Resources: api.Resources{
 				Interfaces: &types.Interfaces{
 					Ethernet: map[string]*types.Ethernet{
-						current.Interface: {
-							Firewall: &del,
-						},
+						ethernet.ID: stale,
 					},
 				},
 			},
 		}
 	}
 
-	if _, err := c.apiClient.Post(ctx, in); err != nil {
-		return nil, err
+	if in.Set != nil || in.Delete != nil {
+		if _, err := c.apiClient.Post(ctx, in); err != nil {
+			return nil, err
+		}
 	}
-	return c.GetFirewallRulesetAttachment(ctx, current.Interface)
+	return c.Get(ctx, ethernet.ID)
 }
 
-func (c *client) DetachFirewallRuleset(ctx context.Context, id string) error {
-	_, err := c.apiClient.Post(ctx, &api.Operation{
+// Delete removes every configured property of the interface. The interface
+// itself is physical and is never removed, nor is its firewall attachment. An
+// interface that still has VLANs or PPPoE sessions returns a
+// *types.SubInterfacesError instead.
+func (c *client) Delete(ctx context.Context, id string) error {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return err
+	}
+	current, err := toEthernet(id, op)
+	if err != nil {
+		return err
+	}
+	if err := op.Get.Interfaces.EnsureNoSubInterfaces(path(id)); err != nil {
+		return err
+	}
+	if current.IsEmpty() {
+		return nil
+	}
+
+	del := current.Properties()
+	del.SetOpMode(types.OpModeDelete)
+
+	_, err = c.apiClient.Post(ctx, &api.Operation{
 		Delete: &api.Delete{
 			Resources: api.Resources{
 				Interfaces: &types.Interfaces{
 					Ethernet: map[string]*types.Ethernet{
-						id: {
-							Firewall: nil,
-						},
+						id: del,
 					},
 				},
 			},
@@ -139,6 +156,43 @@ func (c *client) DetachFirewallRuleset(ctx context.Context, id string) error {
 	return err
 }
 
+func (c *client) GetFirewallRulesetAttachment(ctx context.Context, id string) (*types.FirewallAttachment, error) {
+	ethernet, err := c.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return ethernet.Firewall, nil
+}
+
+func (c *client) AttachFirewallRuleset(ctx context.Context, id string, firewall *types.FirewallAttachment) (*types.FirewallAttachment, error) {
+	if _, err := c.attachments.Attach(ctx, path(id), firewall); err != nil {
+		return nil, err
+	}
+	return c.GetFirewallRulesetAttachment(ctx, id)
+}
+
+func (c *client) UpdateFirewallRulesetAttachment(ctx context.Context, current *types.FirewallAttachment, patches []jsonpatch.JsonPatchOperation) (*types.FirewallAttachment, error) {
+	// Interface is the path of the interface, e.g. ethernet eth0, though the
+	// bare id is accepted as well.
+	id := strings.TrimPrefix(current.Interface, "ethernet ")
+
+	tmp := *current
+	tmp.Path = path(id)
+
+	if _, err := c.attachments.Update(ctx, &tmp, patches); err != nil {
+		return nil, err
+	}
+	return c.GetFirewallRulesetAttachment(ctx, id)
+}
+
+func (c *client) DetachFirewallRuleset(ctx context.Context, id string) error {
+	return c.attachments.Detach(ctx, path(id))
+}
+
+func path(id string) types.InterfacePath {
+	return types.InterfacePath{"ethernet", id}
+}
+
 func toEthernet(id string, op *api.Operation) (*types.Ethernet, error) {
 	if op == nil || op.Get == nil || op.Get.Interfaces == nil || op.Get.Interfaces.Ethernet == nil {
 		return nil, errors.New("No ethernet interfaces exist.")
@@ -149,8 +203,47 @@ func toEthernet(id string, op *api.Operation) (*types.Ethernet, error) {
 		return nil, fmt.Errorf("The ethernet interface %s does not exist.", id)
 	}
 
-	ethernet.Firewall.Interface = id
-	// ethernet.Firewall.ID = ethernet.Firewall.Interface
+	ethernet.ID = id
+	if ethernet.Firewall != nil {
+		ethernet.Firewall.Interface = path(id).String()
+		ethernet.Firewall.Path = path(id)
+	}
 
 	return ethernet, nil
 }
+
+// staleEthernet returns the properties that are set in current but not in
+// updated, encoded for deletion, or nil if there are none.
+func staleEthernet(current, updated *types.Ethernet) *types.Ethernet {
+	stale := new(types.Ethernet)
+	stale.SetOpMode(types.OpModeDelete)
+
+	stale.Addresses = utils.StringSliceDiff(updated.Addresses, current.Addresses)
+	if len(stale.Addresses) == 0 {
+		stale.Addresses = nil
+	}
+	if current.Description != "" && updated.Description == "" {
+		stale.Description = current.Description
+	}
+	if current.Duplex != "" && updated.Duplex == "" {
+		stale.Duplex = current.Duplex
+	}
+	if current.Speed != "" && updated.Speed == "" {
+		stale.Speed = current.Speed
+	}
+	if current.MTU != 0 && updated.MTU == 0 {
+		stale.MTU = current.MTU
+	}
+	stale.Disable = current.Disable && !updated.Disable
+
+	stale.DHCPOptions = current.DHCPOptions.Removed(updated.DHCPOptions)
+
+	if current.IP != nil && current.IP.EnableProxyARP && (updated.IP == nil || !updated.IP.EnableProxyARP) {
+		stale.IP = &types.IP{EnableProxyARP: true}
+	}
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	return stale
+}
@@ -0,0 +1,151 @@
+package ethernet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/interfaces/attachment"
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const ethernetConfig = `{"GET": {"interfaces": {"ethernet": {
+	"eth0": {"address": ["dhcp"], "description": "WAN", "duplex": "auto", "speed": "auto", "mtu": "1500",
+		"dhcp-options": {"default-route": "update", "name-server": "no-update"}, "ip": {"enable-proxy-arp": null},
+		"firewall": {"in": {"name": "WAN_IN"}}},
+	"eth1": {"duplex": "auto", "speed": "auto"},
+	"eth2": {"address": ["192.168.2.1/24"], "vif": {"20": {"address": ["10.0.20.1/24"]}}}
+}}}, "success": true}`
+
+func TestEthernetOperations(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		do       func(Client) error
+		expected []string
+		err      string
+	}{
+		{
+			name: "get",
+			do: func(c Client) error {
+				e, err := c.Get(context.Background(), "eth0")
+				if err != nil {
+					return err
+				}
+				require.Equal(t, 1500, e.MTU)
+				require.True(t, e.IP.EnableProxyARP)
+				require.Equal(t, "ethernet eth0", e.Firewall.Interface)
+				return nil
+			},
+		},
+		{
+			name: "create",
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.Ethernet{
+					ID:          "eth1",
+					Addresses:   []string{"192.168.1.1/24"},
+					Description: "LAN",
+					Disable:     true,
+					MTU:         9000,
+					Firewall:    &types.FirewallAttachment{In: strptr("LAN_IN")},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"ethernet":{"eth1":{"disable":null,"mtu":"9000","address":["192.168.1.1/24"],"description":"LAN"}}}}}`,
+			},
+		},
+		{
+			name: "create invalid",
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.Ethernet{ID: "eth1", MTU: 10})
+				return err
+			},
+			err: "mtu: 10 must be between 68 and 9000",
+		},
+		{
+			name: "update",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background(), "eth0")
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "replace", Path: "/address/0", Value: "203.0.113.2/30"},
+					{Operation: "remove", Path: "/mtu"},
+					{Operation: "remove", Path: "/dhcp-options/name-server"},
+					{Operation: "remove", Path: "/ip"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"ethernet":{"eth0":{"address":["203.0.113.2/30"],"description":"WAN","dhcp-options":{"default-route":"update"},"duplex":"auto","speed":"auto"}}}},` +
+					`"DELETE":{"interfaces":{"ethernet":{"eth0":{"address":["dhcp"],"dhcp-options":{"name-server":null},"ip":{"enable-proxy-arp":null},"mtu":null}}}}}`,
+			},
+		},
+		{
+			name: "update attachment",
+			do: func(c Client) error {
+				current, err := c.GetFirewallRulesetAttachment(context.Background(), "eth0")
+				if err != nil {
+					return err
+				}
+				a, err := c.UpdateFirewallRulesetAttachment(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "add", Path: "/local", Value: map[string]string{"name": "WAN_LOCAL"}},
+				})
+				if err != nil {
+					return err
+				}
+				require.Equal(t, "ethernet eth0", a.Interface)
+				return nil
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"ethernet":{"eth0":{"firewall":{"in":{"name":"WAN_IN"},"local":{"name":"WAN_LOCAL"}}}}}}}`,
+			},
+		},
+		{
+			name: "delete",
+			do: func(c Client) error {
+				return c.Delete(context.Background(), "eth0")
+			},
+			expected: []string{
+				`{"DELETE":{"interfaces":{"ethernet":{"eth0":{"address":["dhcp"],"description":null,"dhcp-options":{"default-route":null,"name-server":null},"duplex":null,"ip":{"enable-proxy-arp":null},"mtu":null,"speed":null}}}}}`,
+			},
+		},
+		{
+			name: "delete missing",
+			do: func(c Client) error {
+				return c.Delete(context.Background(), "eth3")
+			},
+			err: "The ethernet interface eth3 does not exist.",
+		},
+		{
+			name: "delete with vlans",
+			do: func(c Client) error {
+				return c.Delete(context.Background(), "eth2")
+			},
+			err: "The interface ethernet eth2 still has sub-interfaces: ethernet eth2 vif 20.",
+		},
+	} {
+		apiClient := &apitest.Client{Config: ethernetConfig}
+		err := test.do(&client{
+			apiClient:   apiClient,
+			attachments: attachment.NewWithAPIClient(apiClient),
+		})
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
+
+func strptr(s string) *string {
+	return &s
+}
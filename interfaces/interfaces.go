@@ -3,18 +3,51 @@ package interfaces
 import (
 	"net/http"
 
+	"github.com/frankgreco/edge-sdk-go/interfaces/attachment"
+	"github.com/frankgreco/edge-sdk-go/interfaces/bonding"
+	"github.com/frankgreco/edge-sdk-go/interfaces/bridge"
 	"github.com/frankgreco/edge-sdk-go/interfaces/ethernet"
+	"github.com/frankgreco/edge-sdk-go/interfaces/loopback"
+	"github.com/frankgreco/edge-sdk-go/interfaces/openvpn"
+	"github.com/frankgreco/edge-sdk-go/interfaces/pppoe"
+	"github.com/frankgreco/edge-sdk-go/interfaces/pseudoethernet"
+	"github.com/frankgreco/edge-sdk-go/interfaces/switches"
+	"github.com/frankgreco/edge-sdk-go/interfaces/vif"
+	"github.com/frankgreco/edge-sdk-go/interfaces/vti"
+	"github.com/frankgreco/edge-sdk-go/interfaces/wireguard"
 )
 
 type Client struct {
-	Ethernet ethernet.Client
-	// Loopback
-	// VTI
-	// ...
+	Ethernet       ethernet.Client
+	Loopback       loopback.Client
+	Bridge         bridge.Client
+	Bonding        bonding.Client
+	PseudoEthernet pseudoethernet.Client
+	Switch         switches.Client
+	WireGuard      wireguard.Client
+	OpenVPN        openvpn.Client
+	VTI            vti.Client
+	// VIF manages the VLANs of any interface that can have them.
+	VIF vif.Client
+	// PPPoE manages the PPPoE sessions of ethernet interfaces and their VLANs.
+	PPPoE pppoe.Client
+	// Firewall manages the rulesets attached to any kind of interface.
+	Firewall attachment.Client
 }
 
 func New(httpClient *http.Client, baseURL string) *Client {
 	return &Client{
-		Ethernet: ethernet.New(httpClient, baseURL),
+		Ethernet:       ethernet.New(httpClient, baseURL),
+		Loopback:       loopback.New(httpClient, baseURL),
+		Bridge:         bridge.New(httpClient, baseURL),
+		Bonding:        bonding.New(httpClient, baseURL),
+		PseudoEthernet: pseudoethernet.New(httpClient, baseURL),
+		Switch:         switches.New(httpClient, baseURL),
+		WireGuard:      wireguard.New(httpClient, baseURL),
+		OpenVPN:        openvpn.New(httpClient, baseURL),
+		VTI:            vti.New(httpClient, baseURL),
+		VIF:            vif.New(httpClient, baseURL),
+		PPPoE:          pppoe.New(httpClient, baseURL),
+		Firewall:       attachment.New(httpClient, baseURL),
 	}
 }
@@ -0,0 +1,174 @@
+// Package loopback manages the addresses of the loopback interface.
+package loopback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+type Client interface {
+	Get(context.Context, string) (*types.Loopback, error)
+	Create(context.Context, *types.Loopback) (*types.Loopback, error)
+	Update(context.Context, *types.Loopback, []jsonpatch.JsonPatchOperation) (*types.Loopback, error)
+	Delete(context.Context, string) error
+}
+
+type client struct {
+	apiClient api.Client
+}
+
+func New(httpClient *http.Client, host string) Client {
+	return &client{
+		apiClient: api.New(httpClient, host),
+	}
+}
+
+func (c *client) Get(ctx context.Context, id string) (*types.Loopback, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toLoopback(id, op)
+}
+
+// Create configures the addresses and description of the interface.
+func (c *client) Create(ctx context.Context, loopback *types.Loopback) (*types.Loopback, error) {
+	if err := loopback.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					Loopback: map[string]*types.Loopback{
+						loopback.ID: loopback,
+					},
+				},
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, loopback.ID)
+}
+
+// Update applies the patches to the current interface, setting the properties
+// that changed and deleting the ones that were removed in a single commit.
+func (c *client) Update(ctx context.Context, current *types.Loopback, patches []jsonpatch.JsonPatchOperation) (*types.Loopback, error) {
+	var loopback types.Loopback
+	if err := utils.Patch(current, &loopback, patches); err != nil {
+		return nil, err
+	}
+	loopback.ID = current.ID
+
+	if err := loopback.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := new(api.Operation)
+
+	if !loopback.IsEmpty() {
+		in.Set = &api.Set{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					Loopback: map[string]*types.Loopback{
+						loopback.ID: &loopback,
+					},
+				},
+			},
+		}
+	}
+
+	if stale := staleLoopback(current, &loopback); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					Loopback: map[string]*types.Loopback{
+						loopback.ID: stale,
+					},
+				},
+			},
+		}
+	}
+
+	if in.Set != nil || in.Delete != nil {
+		if _, err := c.apiClient.Post(ctx, in); err != nil {
+			return nil, err
+		}
+	}
+	return c.Get(ctx, loopback.ID)
+}
+
+// Delete removes the addresses and description of the interface. The interface
+// itself always exists and is never removed.
+func (c *client) Delete(ctx context.Context, id string) error {
+	current, err := c.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if current.IsEmpty() {
+		return nil
+	}
+
+	current.SetOpMode(types.OpModeDelete)
+
+	_, err = c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					Loopback: map[string]*types.Loopback{
+						id: current,
+					},
+				},
+			},
+		},
+	})
+	return err
+}
+
+func toLoopback(id string, op *api.Operation) (*types.Loopback, error) {
+	if op == nil || op.Get == nil || op.Get.Interfaces == nil || op.Get.Interfaces.Loopback == nil {
+		return nil, errors.New("No loopback interfaces exist.")
+	}
+
+	loopback, ok := op.Get.Interfaces.Loopback[id]
+	if !ok {
+		return nil, fmt.Errorf("The loopback interface %s does not exist.", id)
+	}
+	// An unconfigured interface is returned as a valueless node.
+	if loopback == nil {
+		loopback = new(types.Loopback)
+	}
+
+	loopback.ID = id
+	return loopback, nil
+}
+
+// staleLoopback returns the properties that are set in current but not in
+// updated, encoded for deletion, or nil if there are none.
+func staleLoopback(current, updated *types.Loopback) *types.Loopback {
+	stale := new(types.Loopback)
+	stale.SetOpMode(types.OpModeDelete)
+
+	stale.Addresses = utils.StringSliceDiff(updated.Addresses, current.Addresses)
+	if len(stale.Addresses) == 0 {
+		stale.Addresses = nil
+	}
+	if current.Description != "" && updated.Description == "" {
+		stale.Description = current.Description
+	}
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	return stale
+}
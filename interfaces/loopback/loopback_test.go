@@ -0,0 +1,193 @@
+package loopback
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	loopbackConfig = `{"GET": {"interfaces": {
+	"loopback": {"lo": {"address": ["10.255.0.1/32", "10.255.0.2/32"], "description": "router id"}}
+}}, "success": true}`
+
+	unconfiguredLoopbackConfig = `{"GET": {"interfaces": {
+	"loopback": {"lo": null}
+}}, "success": true}`
+
+	createdLoopbackConfig = `{"GET": {"interfaces": {
+	"loopback": {"lo": {"address": ["10.255.0.1/32"]}}
+}}, "success": true}`
+)
+
+func TestLoopbackOperations(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		config    string
+		committed string
+		do        func(Client) error
+		expected  []string
+		err       string
+	}{
+		{
+			name: "get",
+			do: func(c Client) error {
+				l, err := c.Get(context.Background(), "lo")
+				if err != nil {
+					return err
+				}
+				require.Equal(t, []string{"10.255.0.1/32", "10.255.0.2/32"}, l.Addresses)
+				require.Equal(t, "router id", l.Description)
+				return nil
+			},
+		},
+		{
+			name:   "get unconfigured",
+			config: unconfiguredLoopbackConfig,
+			do: func(c Client) error {
+				l, err := c.Get(context.Background(), "lo")
+				if err != nil {
+					return err
+				}
+				require.Equal(t, "lo", l.ID)
+				require.True(t, l.IsEmpty())
+				return nil
+			},
+		},
+		{
+			name: "get missing",
+			do: func(c Client) error {
+				_, err := c.Get(context.Background(), "lo1")
+				return err
+			},
+			err: "The loopback interface lo1 does not exist.",
+		},
+		{
+			name:      "create",
+			config:    unconfiguredLoopbackConfig,
+			committed: createdLoopbackConfig,
+			do: func(c Client) error {
+				l, err := c.Create(context.Background(), &types.Loopback{
+					ID:        "lo",
+					Addresses: []string{"10.255.0.1/32"},
+				})
+				if err != nil {
+					return err
+				}
+				require.Equal(t, []string{"10.255.0.1/32"}, l.Addresses)
+				return nil
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"loopback":{"lo":{"address":["10.255.0.1/32"]}}}}}`,
+			},
+		},
+		{
+			name: "create invalid",
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.Loopback{ID: "lo0", Addresses: []string{"dhcp"}})
+				return err
+			},
+			err: `id: "lo0" must be lo; address[0]: "dhcp" must be an address in CIDR notation`,
+		},
+		{
+			name: "update",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background(), "lo")
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "replace", Path: "/address/1", Value: "10.255.0.3/32"},
+					{Operation: "remove", Path: "/description"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"loopback":{"lo":{"address":["10.255.0.1/32","10.255.0.3/32"]}}}},` +
+					`"DELETE":{"interfaces":{"loopback":{"lo":{"address":["10.255.0.2/32"],"description":null}}}}}`,
+			},
+		},
+		{
+			name: "update removing everything",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background(), "lo")
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/address"},
+					{Operation: "remove", Path: "/description"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"DELETE":{"interfaces":{"loopback":{"lo":{"address":["10.255.0.1/32","10.255.0.2/32"],"description":null}}}}}`,
+			},
+		},
+		{
+			name: "update invalid",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background(), "lo")
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "add", Path: "/address/-", Value: "10.255.0.3"},
+				})
+				return err
+			},
+			err: `address[2]: "10.255.0.3" must be an address in CIDR notation`,
+		},
+		{
+			name: "delete",
+			do: func(c Client) error {
+				return c.Delete(context.Background(), "lo")
+			},
+			expected: []string{
+				`{"DELETE":{"interfaces":{"loopback":{"lo":{"address":["10.255.0.1/32","10.255.0.2/32"],"description":null}}}}}`,
+			},
+		},
+		{
+			name:   "delete unconfigured",
+			config: unconfiguredLoopbackConfig,
+			do: func(c Client) error {
+				return c.Delete(context.Background(), "lo")
+			},
+		},
+	} {
+		config := loopbackConfig
+		if test.config != "" {
+			config = test.config
+		}
+		apiClient := &apitest.Client{Config: config, Committed: test.committed}
+		err := test.do(&client{apiClient: apiClient})
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
+
+func TestStaleLoopback(t *testing.T) {
+	current := &types.Loopback{
+		ID:          "lo",
+		Addresses:   []string{"10.255.0.1/32", "10.255.0.2/32"},
+		Description: "router id",
+	}
+
+	require.Nil(t, staleLoopback(current, current))
+
+	stale := staleLoopback(current, &types.Loopback{ID: "lo", Addresses: []string{"10.255.0.2/32"}})
+	require.Equal(t, []string{"10.255.0.1/32"}, stale.Addresses)
+	require.Equal(t, "router id", stale.Description)
+}
@@ -0,0 +1,216 @@
+// Package openvpn manages OpenVPN tunnel interfaces.
+package openvpn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+// Client manages the properties of OpenVPN interfaces. Their firewall
+// attachments are managed by the attachment client.
+type Client interface {
+	Get(context.Context, string) (*types.OpenVPN, error)
+	Create(context.Context, *types.OpenVPN) (*types.OpenVPN, error)
+	Update(context.Context, *types.OpenVPN, []jsonpatch.JsonPatchOperation) (*types.OpenVPN, error)
+	Delete(context.Context, string) error
+}
+
+type client struct {
+	apiClient api.Client
+}
+
+func New(httpClient *http.Client, host string) Client {
+	return &client{
+		apiClient: api.New(httpClient, host),
+	}
+}
+
+func (c *client) Get(ctx context.Context, id string) (*types.OpenVPN, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toOpenVPN(id, op)
+}
+
+func (c *client) Create(ctx context.Context, o *types.OpenVPN) (*types.OpenVPN, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := interfacesOf(op).OpenVPN[o.ID]; ok {
+		return nil, fmt.Errorf("The openvpn interface %s already exists.", o.ID)
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					OpenVPN: map[string]*types.OpenVPN{
+						o.ID: o.Properties(),
+					},
+				},
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, o.ID)
+}
+
+// Update applies the patches to the properties of the current interface,
+// setting the ones that changed and deleting the ones that were removed in a
+// single commit.
+func (c *client) Update(ctx context.Context, current *types.OpenVPN, patches []jsonpatch.JsonPatchOperation) (*types.OpenVPN, error) {
+	var o types.OpenVPN
+	if err := utils.Patch(current.Properties(), &o, patches); err != nil {
+		return nil, err
+	}
+	o.ID = current.ID
+
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					OpenVPN: map[string]*types.OpenVPN{
+						o.ID: o.Properties(),
+					},
+				},
+			},
+		},
+	}
+
+	if stale := staleOpenVPN(current, &o); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					OpenVPN: map[string]*types.OpenVPN{
+						o.ID: stale,
+					},
+				},
+			},
+		}
+	}
+
+	if _, err := c.apiClient.Post(ctx, in); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, o.ID)
+}
+
+// Delete removes the interface along with its firewall attachment.
+func (c *client) Delete(ctx context.Context, id string) error {
+	if _, err := c.Get(ctx, id); err != nil {
+		return err
+	}
+
+	_, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					OpenVPN: map[string]*types.OpenVPN{
+						id: nil,
+					},
+				},
+			},
+		},
+	})
+	return err
+}
+
+func path(id string) types.InterfacePath {
+	return types.InterfacePath{"openvpn", id}
+}
+
+func interfacesOf(op *api.Operation) *types.Interfaces {
+	if op == nil || op.Get == nil || op.Get.Interfaces == nil {
+		return new(types.Interfaces)
+	}
+	return op.Get.Interfaces
+}
+
+func toOpenVPN(id string, op *api.Operation) (*types.OpenVPN, error) {
+	if op == nil || op.Get == nil || op.Get.Interfaces == nil || op.Get.Interfaces.OpenVPN == nil {
+		return nil, errors.New("No openvpn interfaces exist.")
+	}
+
+	o, ok := op.Get.Interfaces.OpenVPN[id]
+	if !ok || o == nil {
+		return nil, fmt.Errorf("The openvpn interface %s does not exist.", id)
+	}
+
+	o.ID = id
+	if o.Firewall != nil {
+		o.Firewall.Interface = path(id).String()
+		o.Firewall.Path = path(id)
+	}
+
+	return o, nil
+}
+
+// staleOpenVPN returns the properties that are set in current but not in
+// updated, encoded for deletion, or nil if there are none.
+func staleOpenVPN(current, updated *types.OpenVPN) *types.OpenVPN {
+	stale := new(types.OpenVPN)
+	stale.SetOpMode(types.OpModeDelete)
+
+	stale.RemoteHosts = utils.StringSliceDiff(updated.RemoteHosts, current.RemoteHosts)
+	if len(stale.RemoteHosts) == 0 {
+		stale.RemoteHosts = nil
+	}
+	stale.Options = utils.StringSliceDiff(updated.Options, current.Options)
+	if len(stale.Options) == 0 {
+		stale.Options = nil
+	}
+
+	for _, leaf := range []struct {
+		current, updated string
+		stale            *string
+	}{
+		{current.Description, updated.Description, &stale.Description},
+		{current.LocalAddress, updated.LocalAddress, &stale.LocalAddress},
+		{current.RemoteAddress, updated.RemoteAddress, &stale.RemoteAddress},
+		{current.LocalHost, updated.LocalHost, &stale.LocalHost},
+		{current.Protocol, updated.Protocol, &stale.Protocol},
+		{current.SharedSecretKeyFile, updated.SharedSecretKeyFile, &stale.SharedSecretKeyFile},
+	} {
+		if leaf.current != "" && leaf.updated == "" {
+			*leaf.stale = leaf.current
+		}
+	}
+	if current.LocalPort != 0 && updated.LocalPort == 0 {
+		stale.LocalPort = current.LocalPort
+	}
+	if current.RemotePort != 0 && updated.RemotePort == 0 {
+		stale.RemotePort = current.RemotePort
+	}
+	stale.Disable = current.Disable && !updated.Disable
+
+	// Switching from TLS to a shared secret removes the whole tls node.
+	if current.TLS != nil && updated.TLS == nil {
+		stale.TLS = new(types.OpenVPNTLS)
+	} else {
+		stale.TLS = current.TLS.Removed(updated.TLS)
+	}
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	return stale
+}
@@ -0,0 +1,191 @@
+package openvpn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	openVPNConfig = `{"GET": {"interfaces": {"openvpn": {
+	"vtun0": {"mode": "site-to-site", "local-address": {"10.255.0.1": null}, "remote-address": "10.255.0.2", "remote-host": ["203.0.113.10"],
+		"remote-port": "1194", "protocol": "udp", "shared-secret-key-file": "/config/auth/vtun0.key", "openvpn-option": ["--keepalive 10 60", "--comp-lzo"],
+		"firewall": {"local": {"name": "VPN_LOCAL"}}},
+	"vtun1": {"mode": "site-to-site", "local-address": {"10.255.1.1": null}, "remote-address": "10.255.1.2",
+		"tls": {"role": "active", "ca-cert-file": "/config/auth/ca.crt", "cert-file": "/config/auth/vtun1.crt", "key-file": "/config/auth/vtun1.key", "crl-file": "/config/auth/crl.pem"}}
+}}}, "success": true}`
+
+	createdOpenVPNConfig = `{"GET": {"interfaces": {"openvpn": {
+	"vtun2": {"mode": "site-to-site"}
+}}}, "success": true}`
+)
+
+func TestOpenVPNOperations(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		committed string
+		do        func(Client) error
+		expected  []string
+		err       string
+	}{
+		{
+			name: "get",
+			do: func(c Client) error {
+				o, err := c.Get(context.Background(), "vtun0")
+				if err != nil {
+					return err
+				}
+				require.Equal(t, "10.255.0.1", o.LocalAddress)
+				require.Equal(t, 1194, o.RemotePort)
+				require.Equal(t, "openvpn vtun0", o.Firewall.Interface)
+				return nil
+			},
+		},
+		{
+			name: "get a missing interface",
+			do: func(c Client) error {
+				_, err := c.Get(context.Background(), "vtun9")
+				return err
+			},
+			err: "The openvpn interface vtun9 does not exist.",
+		},
+		{
+			name:      "create",
+			committed: createdOpenVPNConfig,
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.OpenVPN{
+					ID:            "vtun2",
+					Mode:          "site-to-site",
+					LocalAddress:  "10.255.2.1",
+					RemoteAddress: "10.255.2.2",
+					RemoteHosts:   []string{"vpn.example.com"},
+					LocalPort:     1195,
+					TLS: &types.OpenVPNTLS{
+						Role:       "passive",
+						CACertFile: "/config/auth/ca.crt",
+						CertFile:   "/config/auth/vtun2.crt",
+						KeyFile:    "/config/auth/vtun2.key",
+					},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"openvpn":{"vtun2":{"local-address":{"10.255.2.1":null},"local-port":"1195","mode":"site-to-site","remote-address":"10.255.2.2","remote-host":["vpn.example.com"],` +
+					`"tls":{"role":"passive","ca-cert-file":"/config/auth/ca.crt","cert-file":"/config/auth/vtun2.crt","key-file":"/config/auth/vtun2.key"}}}}}}`,
+			},
+		},
+		{
+			name: "create an existing interface",
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.OpenVPN{
+					ID:                  "vtun0",
+					Mode:                "site-to-site",
+					LocalAddress:        "10.255.0.1",
+					RemoteAddress:       "10.255.0.2",
+					SharedSecretKeyFile: "/config/auth/vtun0.key",
+				})
+				return err
+			},
+			err: "The openvpn interface vtun0 already exists.",
+		},
+		{
+			name: "create invalid",
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.OpenVPN{
+					ID:                  "vtun3",
+					Mode:                "site-to-site",
+					LocalAddress:        "10.255.3.1",
+					SharedSecretKeyFile: "vtun3.key",
+					TLS:                 &types.OpenVPNTLS{CACertFile: "/config/auth/ca.crt"},
+				})
+				return err
+			},
+			err: `shared-secret-key-file: "vtun3.key" must be the absolute path of a file; ` +
+				`tls.role: must be set for site-to-site tunnels; ` +
+				`remote-address: must be set for site-to-site tunnels; ` +
+				`shared-secret-key-file: exactly one of shared-secret-key-file and tls must be set`,
+		},
+		{
+			name: "update",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background(), "vtun0")
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/openvpn-option/1"},
+					{Operation: "remove", Path: "/protocol"},
+					{Operation: "replace", Path: "/remote-host/0", Value: "203.0.113.20"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"openvpn":{"vtun0":{"local-address":{"10.255.0.1":null},"remote-port":"1194","mode":"site-to-site","remote-address":"10.255.0.2",` +
+					`"remote-host":["203.0.113.20"],"shared-secret-key-file":"/config/auth/vtun0.key","openvpn-option":["--keepalive 10 60"]}}}},` +
+					`"DELETE":{"interfaces":{"openvpn":{"vtun0":{"openvpn-option":["--comp-lzo"],"protocol":null,"remote-host":["203.0.113.10"]}}}}}`,
+			},
+		},
+		{
+			name: "switch from tls to a shared secret",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background(), "vtun1")
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/tls"},
+					{Operation: "add", Path: "/shared-secret-key-file", Value: "/config/auth/vtun1.key"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"openvpn":{"vtun1":{"local-address":{"10.255.1.1":null},"mode":"site-to-site","remote-address":"10.255.1.2","shared-secret-key-file":"/config/auth/vtun1.key"}}}},` +
+					`"DELETE":{"interfaces":{"openvpn":{"vtun1":{"tls":null}}}}}`,
+			},
+		},
+		{
+			name: "remove a tls file",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background(), "vtun1")
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/tls/crl-file"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"openvpn":{"vtun1":{"local-address":{"10.255.1.1":null},"mode":"site-to-site","remote-address":"10.255.1.2",` +
+					`"tls":{"role":"active","ca-cert-file":"/config/auth/ca.crt","cert-file":"/config/auth/vtun1.crt","key-file":"/config/auth/vtun1.key"}}}}},` +
+					`"DELETE":{"interfaces":{"openvpn":{"vtun1":{"tls":{"crl-file":null}}}}}}`,
+			},
+		},
+		{
+			name: "delete",
+			do: func(c Client) error {
+				return c.Delete(context.Background(), "vtun0")
+			},
+			expected: []string{
+				`{"DELETE":{"interfaces":{"openvpn":{"vtun0":null}}}}`,
+			},
+		},
+	} {
+		apiClient := &apitest.Client{Config: openVPNConfig, Committed: test.committed}
+		err := test.do(&client{apiClient: apiClient})
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
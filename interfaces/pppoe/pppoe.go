@@ -0,0 +1,260 @@
+// Package pppoe manages PPPoE client sessions on ethernet interfaces and their
+// VLANs, such as interfaces ethernet eth0 pppoe 0.
+package pppoe
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/frankgreco/edge-sdk-go/interfaces/attachment"
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+type Client interface {
+	Get(context.Context, types.InterfacePath, string) (*types.PPPoE, error)
+	GetByName(context.Context, string) (*types.PPPoE, error)
+	List(context.Context, types.InterfacePath) ([]*types.PPPoE, error)
+	Create(context.Context, *types.PPPoE) (*types.PPPoE, error)
+	Update(context.Context, *types.PPPoE, []jsonpatch.JsonPatchOperation) (*types.PPPoE, error)
+	Delete(context.Context, types.InterfacePath, string) error
+
+	AttachFirewallRuleset(context.Context, types.InterfacePath, string, *types.FirewallAttachment) (*types.FirewallAttachment, error)
+	UpdateFirewallRulesetAttachment(context.Context, *types.FirewallAttachment, []jsonpatch.JsonPatchOperation) (*types.FirewallAttachment, error)
+	DetachFirewallRuleset(context.Context, types.InterfacePath, string) error
+	GetFirewallRulesetAttachment(context.Context, types.InterfacePath, string) (*types.FirewallAttachment, error)
+}
+
+type client struct {
+	apiClient   api.Client
+	attachments attachment.Client
+}
+
+func New(httpClient *http.Client, host string) Client {
+	apiClient := api.New(httpClient, host)
+	return &client{
+		apiClient:   apiClient,
+		attachments: attachment.NewWithAPIClient(apiClient),
+	}
+}
+
+// Get returns the PPPoE session with the id on the parent interface.
+func (c *client) Get(ctx context.Context, parent types.InterfacePath, id string) (*types.PPPoE, error) {
+	pppoes, err := c.pppoes(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := pppoes[id]
+	if !ok || p == nil {
+		return nil, fmt.Errorf("The interface %s does not exist.", parent.Child("pppoe", id))
+	}
+	return toPPPoE(parent, id, p), nil
+}
+
+// GetByName returns the PPPoE session the kernel names name, e.g. pppoe0.
+func (c *client) GetByName(ctx context.Context, name string) (*types.PPPoE, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := interfacesOf(op).PPPoEPath(name)
+	if err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, path.Parent(), path[len(path)-1])
+}
+
+// List returns every PPPoE session of the parent interface, ordered by
+// session number.
+func (c *client) List(ctx context.Context, parent types.InterfacePath) ([]*types.PPPoE, error) {
+	pppoes, err := c.pppoes(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []*types.PPPoE{}
+	for id, p := range pppoes {
+		if p != nil {
+			list = append(list, toPPPoE(parent, id, p))
+		}
+	}
+	sort.Slice(list, func(a, b int) bool {
+		x, _ := strconv.Atoi(list[a].ID)
+		y, _ := strconv.Atoi(list[b].ID)
+		return x < y
+	})
+	return list, nil
+}
+
+// Create creates the session on its parent interface, which must already
+// exist. The firewall attachment is ignored.
+func (c *client) Create(ctx context.Context, p *types.PPPoE) (*types.PPPoE, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	pppoes, err := c.pppoes(ctx, p.Parent)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := pppoes[p.ID]; ok {
+		return nil, fmt.Errorf("The interface %s already exists.", p.Path())
+	}
+
+	set := new(types.Interfaces)
+	if err := set.SetPPPoE(p.Parent, p.ID, p.Properties()); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Interfaces: set,
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, p.Parent, p.ID)
+}
+
+// Update applies the patches to the credentials and options of the current
+// session, setting the ones that changed and deleting the ones that were
+// removed in a single commit.
+func (c *client) Update(ctx context.Context, current *types.PPPoE, patches []jsonpatch.JsonPatchOperation) (*types.PPPoE, error) {
+	var p types.PPPoE
+	if err := utils.Patch(current.Properties(), &p, patches); err != nil {
+		return nil, err
+	}
+	p.ID = current.ID
+	p.Parent = current.Parent
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	set := new(types.Interfaces)
+	if err := set.SetPPPoE(p.Parent, p.ID, p.Properties()); err != nil {
+		return nil, err
+	}
+	in := &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Interfaces: set,
+			},
+		},
+	}
+
+	if stale := stalePPPoE(current, &p); stale != nil {
+		del := new(types.Interfaces)
+		if err := del.SetPPPoE(p.Parent, p.ID, stale); err != nil {
+			return nil, err
+		}
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Interfaces: del,
+			},
+		}
+	}
+
+	if _, err := c.apiClient.Post(ctx, in); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, p.Parent, p.ID)
+}
+
+// Delete removes the session along with its firewall attachment.
+func (c *client) Delete(ctx context.Context, parent types.InterfacePath, id string) error {
+	if _, err := c.Get(ctx, parent, id); err != nil {
+		return err
+	}
+
+	del := new(types.Interfaces)
+	if err := del.SetPPPoE(parent, id, nil); err != nil {
+		return err
+	}
+
+	_, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Interfaces: del,
+			},
+		},
+	})
+	return err
+}
+
+func (c *client) GetFirewallRulesetAttachment(ctx context.Context, parent types.InterfacePath, id string) (*types.FirewallAttachment, error) {
+	return c.attachments.Get(ctx, parent.Child("pppoe", id))
+}
+
+func (c *client) AttachFirewallRuleset(ctx context.Context, parent types.InterfacePath, id string, firewall *types.FirewallAttachment) (*types.FirewallAttachment, error) {
+	return c.attachments.Attach(ctx, parent.Child("pppoe", id), firewall)
+}
+
+func (c *client) UpdateFirewallRulesetAttachment(ctx context.Context, current *types.FirewallAttachment, patches []jsonpatch.JsonPatchOperation) (*types.FirewallAttachment, error) {
+	return c.attachments.Update(ctx, current, patches)
+}
+
+func (c *client) DetachFirewallRuleset(ctx context.Context, parent types.InterfacePath, id string) error {
+	return c.attachments.Detach(ctx, parent.Child("pppoe", id))
+}
+
+func (c *client) pppoes(ctx context.Context, parent types.InterfacePath) (map[string]*types.PPPoE, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return interfacesOf(op).PPPoEs(parent)
+}
+
+func interfacesOf(op *api.Operation) *types.Interfaces {
+	if op == nil || op.Get == nil || op.Get.Interfaces == nil {
+		return new(types.Interfaces)
+	}
+	return op.Get.Interfaces
+}
+
+func toPPPoE(parent types.InterfacePath, id string, p *types.PPPoE) *types.PPPoE {
+	p.ID = id
+	p.Parent = parent
+	if p.Firewall != nil {
+		p.Firewall.Path = p.Path()
+		p.Firewall.Interface = p.Path().String()
+	}
+	return p
+}
+
+// stalePPPoE returns the options that are set in current but not in updated,
+// encoded for deletion, or nil if there are none. The credentials are required
+// and are never deleted.
+func stalePPPoE(current, updated *types.PPPoE) *types.PPPoE {
+	stale := new(types.PPPoE)
+	stale.SetOpMode(types.OpModeDelete)
+
+	if current.Description != "" && updated.Description == "" {
+		stale.Description = current.Description
+	}
+	if current.MTU != 0 && updated.MTU == 0 {
+		stale.MTU = current.MTU
+	}
+	if current.DefaultRoute != "" && updated.DefaultRoute == "" {
+		stale.DefaultRoute = current.DefaultRoute
+	}
+	if current.NameServer != "" && updated.NameServer == "" {
+		stale.NameServer = current.NameServer
+	}
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	return stale
+}
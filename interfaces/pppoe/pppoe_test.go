@@ -0,0 +1,176 @@
+package pppoe
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/interfaces/attachment"
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	pppoeConfig = `{"GET": {"interfaces": {"ethernet": {
+	"eth0": {"pppoe": {"0": {"user-id": "customer@isp", "password": "hunter2", "mtu": "1492", "default-route": "auto", "name-server": "auto",
+		"firewall": {"in": {"name": "WAN_IN"}}}}},
+	"eth1": {"vif": {"201": {"pppoe": {"1": {"user-id": "backup@isp", "password": "secret"}}}}}
+}}}, "success": true}`
+
+	createdPPPoEConfig = `{"GET": {"interfaces": {"ethernet": {
+	"eth0": {"pppoe": {"2": {"user-id": "customer@isp", "password": "hunter2"}}}
+}}}, "success": true}`
+)
+
+func TestPPPoEOperations(t *testing.T) {
+	eth0 := types.InterfacePath{"ethernet", "eth0"}
+
+	for _, test := range []struct {
+		name      string
+		committed string
+		do        func(Client) error
+		expected  []string
+		err       string
+	}{
+		{
+			name: "get",
+			do: func(c Client) error {
+				p, err := c.Get(context.Background(), eth0, "0")
+				if err != nil {
+					return err
+				}
+				require.Equal(t, "hunter2", p.Password)
+				require.Equal(t, 1492, p.MTU)
+				require.Equal(t, "ethernet eth0 pppoe 0", p.Firewall.Interface)
+				return nil
+			},
+		},
+		{
+			name: "get by name",
+			do: func(c Client) error {
+				p, err := c.GetByName(context.Background(), "pppoe1")
+				if err != nil {
+					return err
+				}
+				require.Equal(t, "ethernet eth1 vif 201 pppoe 1", p.GetID())
+				require.Equal(t, "backup@isp", p.UserID)
+				return nil
+			},
+		},
+		{
+			name: "get by unknown name",
+			do: func(c Client) error {
+				_, err := c.GetByName(context.Background(), "pppoe2")
+				return err
+			},
+			err: "The interface pppoe2 does not exist.",
+		},
+		{
+			name: "create on a vlan of a non-ethernet interface",
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.PPPoE{
+					ID:       "0",
+					Parent:   types.InterfacePath{"bridge", "br0", "vif", "10"},
+					UserID:   "customer@isp",
+					Password: "hunter2",
+				})
+				return err
+			},
+			err: "interface: The interface bridge br0 vif 10 cannot have a pppoe.",
+		},
+		{
+			name:      "create",
+			committed: createdPPPoEConfig,
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.PPPoE{
+					ID:       "2",
+					Parent:   eth0,
+					UserID:   "customer@isp",
+					Password: "hunter2",
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"ethernet":{"eth0":{"pppoe":{"2":{"user-id":"customer@isp","password":"hunter2"}}}}}}}`,
+			},
+		},
+		{
+			name: "update credentials",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background(), eth0, "0")
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "replace", Path: "/password", Value: "correct horse"},
+					{Operation: "remove", Path: "/name-server"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"ethernet":{"eth0":{"pppoe":{"0":{"mtu":"1492","user-id":"customer@isp","password":"correct horse","default-route":"auto"}}}}}},` +
+					`"DELETE":{"interfaces":{"ethernet":{"eth0":{"pppoe":{"0":{"name-server":null}}}}}}}`,
+			},
+		},
+		{
+			name: "attach",
+			do: func(c Client) error {
+				_, err := c.AttachFirewallRuleset(context.Background(), eth0, "0", &types.FirewallAttachment{Local: strptr("WAN_LOCAL")})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"ethernet":{"eth0":{"pppoe":{"0":{"firewall":{"local":{"name":"WAN_LOCAL"}}}}}}}}}`,
+			},
+		},
+		{
+			name: "delete",
+			do: func(c Client) error {
+				return c.Delete(context.Background(), types.InterfacePath{"ethernet", "eth1", "vif", "201"}, "1")
+			},
+			expected: []string{
+				`{"DELETE":{"interfaces":{"ethernet":{"eth1":{"vif":{"201":{"pppoe":{"1":null}}}}}}}}`,
+			},
+		},
+	} {
+		apiClient := &apitest.Client{Config: pppoeConfig, Committed: test.committed}
+		err := test.do(&client{
+			apiClient:   apiClient,
+			attachments: attachment.NewWithAPIClient(apiClient),
+		})
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
+
+func TestPPPoEMasksPassword(t *testing.T) {
+	p := &types.PPPoE{
+		ID:       "0",
+		Parent:   types.InterfacePath{"ethernet", "eth0"},
+		UserID:   "customer@isp",
+		Password: "hunter2",
+	}
+
+	for _, s := range []string{
+		fmt.Sprint(p),
+		fmt.Sprintf("%v", *p),
+		fmt.Sprintf("%+v", p),
+		fmt.Sprintf("%#v", p),
+	} {
+		require.NotContains(t, s, "hunter2")
+		require.Contains(t, s, "********")
+	}
+}
+
+func strptr(s string) *string {
+	return &s
+}
@@ -0,0 +1,206 @@
+// Package pseudoethernet manages pseudo-ethernet (MAC VLAN) interfaces.
+package pseudoethernet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+// Client manages the properties of pseudo-ethernet interfaces. Their firewall
+// attachments are managed by the attachment client and their VLANs by the vif
+// client.
+type Client interface {
+	Get(context.Context, string) (*types.PseudoEthernet, error)
+	Create(context.Context, *types.PseudoEthernet) (*types.PseudoEthernet, error)
+	Update(context.Context, *types.PseudoEthernet, []jsonpatch.JsonPatchOperation) (*types.PseudoEthernet, error)
+	Delete(context.Context, string) error
+}
+
+type client struct {
+	apiClient api.Client
+}
+
+func New(httpClient *http.Client, host string) Client {
+	return &client{
+		apiClient: api.New(httpClient, host),
+	}
+}
+
+func (c *client) Get(ctx context.Context, id string) (*types.PseudoEthernet, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toPseudoEthernet(id, op)
+}
+
+// Create creates the interface on top of its link, which must be an existing
+// ethernet interface.
+func (c *client) Create(ctx context.Context, p *types.PseudoEthernet) (*types.PseudoEthernet, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ifaces := interfacesOf(op)
+
+	if _, ok := ifaces.PseudoEthernet[p.ID]; ok {
+		return nil, fmt.Errorf("The pseudo-ethernet interface %s already exists.", p.ID)
+	}
+	if _, ok := ifaces.Ethernet[p.Link]; !ok {
+		return nil, fmt.Errorf("The ethernet interface %s does not exist.", p.Link)
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					PseudoEthernet: map[string]*types.PseudoEthernet{
+						p.ID: p.Properties(),
+					},
+				},
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, p.ID)
+}
+
+// Update applies the patches to the properties of the current interface,
+// setting the ones that changed and deleting the ones that were removed in a
+// single commit.
+func (c *client) Update(ctx context.Context, current *types.PseudoEthernet, patches []jsonpatch.JsonPatchOperation) (*types.PseudoEthernet, error) {
+	var p types.PseudoEthernet
+	if err := utils.Patch(current.Properties(), &p, patches); err != nil {
+		return nil, err
+	}
+	p.ID = current.ID
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					PseudoEthernet: map[string]*types.PseudoEthernet{
+						p.ID: p.Properties(),
+					},
+				},
+			},
+		},
+	}
+
+	if stale := stalePseudoEthernet(current, &p); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					PseudoEthernet: map[string]*types.PseudoEthernet{
+						p.ID: stale,
+					},
+				},
+			},
+		}
+	}
+
+	if _, err := c.apiClient.Post(ctx, in); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, p.ID)
+}
+
+// Delete removes the interface along with its firewall attachment. An
+// interface that still has VLANs returns a *types.SubInterfacesError instead.
+func (c *client) Delete(ctx context.Context, id string) error {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := toPseudoEthernet(id, op); err != nil {
+		return err
+	}
+	if err := op.Get.Interfaces.EnsureNoSubInterfaces(path(id)); err != nil {
+		return err
+	}
+
+	_, err = c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					PseudoEthernet: map[string]*types.PseudoEthernet{
+						id: nil,
+					},
+				},
+			},
+		},
+	})
+	return err
+}
+
+func path(id string) types.InterfacePath {
+	return types.InterfacePath{"pseudo-ethernet", id}
+}
+
+func interfacesOf(op *api.Operation) *types.Interfaces {
+	if op == nil || op.Get == nil || op.Get.Interfaces == nil {
+		return new(types.Interfaces)
+	}
+	return op.Get.Interfaces
+}
+
+func toPseudoEthernet(id string, op *api.Operation) (*types.PseudoEthernet, error) {
+	if op == nil || op.Get == nil || op.Get.Interfaces == nil || op.Get.Interfaces.PseudoEthernet == nil {
+		return nil, errors.New("No pseudo-ethernet interfaces exist.")
+	}
+
+	p, ok := op.Get.Interfaces.PseudoEthernet[id]
+	if !ok || p == nil {
+		return nil, fmt.Errorf("The pseudo-ethernet interface %s does not exist.", id)
+	}
+
+	p.ID = id
+	if p.Firewall != nil {
+		p.Firewall.Interface = path(id).String()
+		p.Firewall.Path = path(id)
+	}
+
+	return p, nil
+}
+
+// stalePseudoEthernet returns the properties that are set in current but not
+// in updated, encoded for deletion, or nil if there are none.
+func stalePseudoEthernet(current, updated *types.PseudoEthernet) *types.PseudoEthernet {
+	stale := new(types.PseudoEthernet)
+	stale.SetOpMode(types.OpModeDelete)
+
+	stale.Addresses = utils.StringSliceDiff(updated.Addresses, current.Addresses)
+	if len(stale.Addresses) == 0 {
+		stale.Addresses = nil
+	}
+	if current.MAC != "" && updated.MAC == "" {
+		stale.MAC = current.MAC
+	}
+	if current.Description != "" && updated.Description == "" {
+		stale.Description = current.Description
+	}
+	stale.Disable = current.Disable && !updated.Disable
+	stale.DHCPOptions = current.DHCPOptions.Removed(updated.DHCPOptions)
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	return stale
+}
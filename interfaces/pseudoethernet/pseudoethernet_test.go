@@ -0,0 +1,104 @@
+package pseudoethernet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const pseudoEthernetConfig = `{"GET": {"interfaces": {
+	"ethernet": {"eth0": {}},
+	"pseudo-ethernet": {
+		"peth0": {"link": "eth0", "mac": "00:11:22:33:44:55", "address": ["dhcp"], "firewall": {"in": {"name": "WAN_IN"}}},
+		"peth1": {"link": "eth0", "vif": {"10": {}}}
+	}
+}}, "success": true}`
+
+func TestPseudoEthernetOperations(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		do       func(Client) error
+		expected []string
+		err      string
+	}{
+		{
+			name: "get",
+			do: func(c Client) error {
+				p, err := c.Get(context.Background(), "peth0")
+				if err != nil {
+					return err
+				}
+				require.Equal(t, "eth0", p.Link)
+				require.Equal(t, "pseudo-ethernet peth0", p.Firewall.Interface)
+				return nil
+			},
+		},
+		{
+			name: "create on a missing link",
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.PseudoEthernet{ID: "peth2", Link: "eth9"})
+				return err
+			},
+			err: "The ethernet interface eth9 does not exist.",
+		},
+		{
+			name: "create invalid",
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.PseudoEthernet{ID: "pe2", MAC: "zz"})
+				return err
+			},
+			err: `id: "pe2" must be peth followed by a number; link: must not be empty; mac: "zz" is not a valid MAC address`,
+		},
+		{
+			name: "update",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background(), "peth0")
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/mac"},
+					{Operation: "add", Path: "/description", Value: "second WAN"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"pseudo-ethernet":{"peth0":{"link":"eth0","address":["dhcp"],"description":"second WAN"}}}},` +
+					`"DELETE":{"interfaces":{"pseudo-ethernet":{"peth0":{"mac":null}}}}}`,
+			},
+		},
+		{
+			name: "delete",
+			do: func(c Client) error {
+				return c.Delete(context.Background(), "peth0")
+			},
+			expected: []string{
+				`{"DELETE":{"interfaces":{"pseudo-ethernet":{"peth0":null}}}}`,
+			},
+		},
+		{
+			name: "delete with vlans",
+			do: func(c Client) error {
+				return c.Delete(context.Background(), "peth1")
+			},
+			err: "The interface pseudo-ethernet peth1 still has sub-interfaces: pseudo-ethernet peth1 vif 10.",
+		},
+	} {
+		apiClient := &apitest.Client{Config: pseudoEthernetConfig}
+		err := test.do(&client{apiClient: apiClient})
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
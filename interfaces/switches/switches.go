@@ -0,0 +1,296 @@
+// Package switches manages the hardware switch of devices such as the
+// EdgeRouter X and the VLANs of its ports. The package is not named switch
+// because that is a keyword.
+package switches
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+// Client manages the properties of switches and the VLANs of their ports. The
+// switch itself is part of the hardware and can neither be created nor
+// deleted. Its firewall attachment is managed by the attachment client and its
+// VLAN interfaces by the vif client.
+type Client interface {
+	Get(context.Context, string) (*types.Switch, error)
+	Update(context.Context, *types.Switch, []jsonpatch.JsonPatchOperation) (*types.Switch, error)
+
+	GetPort(context.Context, string, string) (*types.SwitchPort, error)
+	SetAccessVLAN(context.Context, string, string, int) (*types.SwitchPort, error)
+	SetTrunkVLANs(context.Context, string, string, int, []int) (*types.SwitchPort, error)
+	ClearPortVLANs(context.Context, string, string) error
+}
+
+type client struct {
+	apiClient api.Client
+}
+
+func New(httpClient *http.Client, host string) Client {
+	return &client{
+		apiClient: api.New(httpClient, host),
+	}
+}
+
+func (c *client) Get(ctx context.Context, id string) (*types.Switch, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toSwitch(id, op)
+}
+
+// Update applies the patches to the properties of the current switch, setting
+// the ones that changed and deleting the ones that were removed in a single
+// commit.
+func (c *client) Update(ctx context.Context, current *types.Switch, patches []jsonpatch.JsonPatchOperation) (*types.Switch, error) {
+	var s types.Switch
+	if err := utils.Patch(current.Properties(), &s, patches); err != nil {
+		return nil, err
+	}
+	s.ID = current.ID
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := new(api.Operation)
+
+	if !s.IsEmpty() {
+		in.Set = &api.Set{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					Switch: map[string]*types.Switch{
+						s.ID: s.Properties(),
+					},
+				},
+			},
+		}
+	}
+
+	if stale := staleSwitch(current, &s); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					Switch: map[string]*types.Switch{
+						s.ID: stale,
+					},
+				},
+			},
+		}
+	}
+
+	if in.Set != nil || in.Delete != nil {
+		if _, err := c.apiClient.Post(ctx, in); err != nil {
+			return nil, err
+		}
+	}
+	return c.Get(ctx, s.ID)
+}
+
+// GetPort returns the VLANs of the port of the switch.
+func (c *client) GetPort(ctx context.Context, id, port string) (*types.SwitchPort, error) {
+	s, err := c.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return portOf(s, port)
+}
+
+// SetAccessVLAN makes the port an access port whose untagged frames belong to
+// the VLAN. Any tagged VLANs of the port are removed.
+func (c *client) SetAccessVLAN(ctx context.Context, id, port string, vlan int) (*types.SwitchPort, error) {
+	return c.setPortVLANs(ctx, id, &types.SwitchPort{
+		Switch:    id,
+		Interface: port,
+		PVID:      vlan,
+	})
+}
+
+// SetTrunkVLANs makes the port a trunk carrying the tagged VLANs. Untagged
+// frames belong to the native VLAN, or to none if it is zero.
+func (c *client) SetTrunkVLANs(ctx context.Context, id, port string, native int, tagged []int) (*types.SwitchPort, error) {
+	if len(tagged) == 0 {
+		return nil, errors.New("vid: a trunk must carry at least one tagged VLAN")
+	}
+	return c.setPortVLANs(ctx, id, &types.SwitchPort{
+		Switch:    id,
+		Interface: port,
+		PVID:      native,
+		VIDs:      tagged,
+	})
+}
+
+// ClearPortVLANs removes every VLAN of the port.
+func (c *client) ClearPortVLANs(ctx context.Context, id, port string) error {
+	if _, err := c.GetPort(ctx, id, port); err != nil {
+		return err
+	}
+
+	p := &types.SwitchPort{Interface: port}
+	p.SetOpMode(types.OpModeDelete)
+
+	_, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Interfaces: portsOf(id, p),
+			},
+		},
+	})
+	return err
+}
+
+// setPortVLANs replaces the VLANs of the port with the ones of updated in a
+// single commit. An ethernet interface that is not a port of the switch yet
+// is added to it by the same commit, unless it belongs to a bridge or bond.
+func (c *client) setPortVLANs(ctx context.Context, id string, updated *types.SwitchPort) (*types.SwitchPort, error) {
+	if err := updated.Validate(); err != nil {
+		return nil, err
+	}
+
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s, err := toSwitch(id, op)
+	if err != nil {
+		return nil, err
+	}
+	if !s.VLANAware {
+		return nil, fmt.Errorf("The switch %s is not VLAN aware.", id)
+	}
+
+	current, ok := s.Ports[updated.Interface]
+	if !ok {
+		if err := op.Get.Interfaces.EnsureNotMember(updated.Interface); err != nil {
+			return nil, err
+		}
+		current = new(types.SwitchPort)
+	}
+
+	in := &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Interfaces: portsOf(id, updated),
+			},
+		},
+	}
+
+	if stale := stalePort(current, updated); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Interfaces: portsOf(id, stale),
+			},
+		}
+	}
+
+	if _, err := c.apiClient.Post(ctx, in); err != nil {
+		return nil, err
+	}
+	return c.GetPort(ctx, id, updated.Interface)
+}
+
+// portsOf returns interfaces holding only the port of the switch.
+func portsOf(id string, port *types.SwitchPort) *types.Interfaces {
+	return &types.Interfaces{
+		Switch: map[string]*types.Switch{
+			id: {
+				Ports: map[string]*types.SwitchPort{
+					port.Interface: port,
+				},
+			},
+		},
+	}
+}
+
+func portOf(s *types.Switch, port string) (*types.SwitchPort, error) {
+	p, ok := s.Ports[port]
+	if !ok {
+		return nil, fmt.Errorf("The interface %s is not a port of switch %s.", port, s.ID)
+	}
+	return p, nil
+}
+
+func path(id string) types.InterfacePath {
+	return types.InterfacePath{"switch", id}
+}
+
+func toSwitch(id string, op *api.Operation) (*types.Switch, error) {
+	if op == nil || op.Get == nil || op.Get.Interfaces == nil || op.Get.Interfaces.Switch == nil {
+		return nil, errors.New("No switch interfaces exist.")
+	}
+
+	s, ok := op.Get.Interfaces.Switch[id]
+	if !ok || s == nil {
+		return nil, fmt.Errorf("The switch interface %s does not exist.", id)
+	}
+
+	s.ID = id
+	for _, p := range s.Ports {
+		p.Switch = id
+	}
+	if s.Firewall != nil {
+		s.Firewall.Interface = path(id).String()
+		s.Firewall.Path = path(id)
+	}
+
+	return s, nil
+}
+
+// staleSwitch returns the properties that are set in current but not in
+// updated, encoded for deletion, or nil if there are none.
+func staleSwitch(current, updated *types.Switch) *types.Switch {
+	stale := new(types.Switch)
+	stale.SetOpMode(types.OpModeDelete)
+
+	stale.Addresses = utils.StringSliceDiff(updated.Addresses, current.Addresses)
+	if len(stale.Addresses) == 0 {
+		stale.Addresses = nil
+	}
+	if current.Description != "" && updated.Description == "" {
+		stale.Description = current.Description
+	}
+	if current.MTU != 0 && updated.MTU == 0 {
+		stale.MTU = current.MTU
+	}
+	stale.VLANAware = current.VLANAware && !updated.VLANAware
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	return stale
+}
+
+// stalePort returns the VLANs of current that are not VLANs of updated,
+// encoded for deletion, or nil if there are none.
+func stalePort(current, updated *types.SwitchPort) *types.SwitchPort {
+	stale := &types.SwitchPort{Interface: current.Interface}
+	stale.SetOpMode(types.OpModeDelete)
+
+	if current.PVID != 0 && updated.PVID == 0 {
+		stale.PVID = current.PVID
+	}
+
+	tagged := map[int]bool{}
+	for _, vid := range updated.VIDs {
+		tagged[vid] = true
+	}
+	for _, vid := range current.VIDs {
+		if !tagged[vid] {
+			stale.VIDs = append(stale.VIDs, vid)
+		}
+	}
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	return stale
+}
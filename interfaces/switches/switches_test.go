@@ -0,0 +1,199 @@
+package switches
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	switchConfig = `{"GET": {"interfaces": {
+"ethernet": {
+	"eth0": {"bridge-group": {"bridge": "br0"}},
+	"eth1": {}, "eth2": {}, "eth3": {}, "eth4": {}, "eth5": {}
+},
+"switch": {
+	"switch0": {"address": ["192.168.1.1/24"], "mtu": "1500",
+		"switch-port": {"vlan-aware": "enable", "interface": {
+			"eth1": {"vlan": {"pvid": "10"}},
+			"eth2": {"vlan": {"pvid": "1", "vid": ["10", "20"]}},
+			"eth3": null
+		}},
+		"vif": {"10": {"address": ["10.0.10.1/24"]}}},
+	"switch1": {"switch-port": {"interface": {"eth4": null}}}
+}}}, "success": true}`
+
+	portAddedConfig = `{"GET": {"interfaces": {"switch": {
+	"switch0": {"switch-port": {"vlan-aware": "enable", "interface": {
+		"eth5": {"vlan": {"pvid": "10"}}
+	}}}
+}}}, "success": true}`
+)
+
+func TestSwitchOperations(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		do        func(Client) error
+		committed string
+		expected  []string
+		err       string
+	}{
+		{
+			name: "get",
+			do: func(c Client) error {
+				s, err := c.Get(context.Background(), "switch0")
+				if err != nil {
+					return err
+				}
+				require.True(t, s.VLANAware)
+				require.Len(t, s.Ports, 3)
+				require.Equal(t, []int{10, 20}, s.Ports["eth2"].VIDs)
+				require.Equal(t, "switch0", s.Ports["eth3"].Switch)
+				require.Contains(t, s.VIF, "10")
+				return nil
+			},
+		},
+		{
+			name: "update",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background(), "switch0")
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/switch-port"},
+					{Operation: "remove", Path: "/mtu"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"switch":{"switch0":{"address":["192.168.1.1/24"]}}}},` +
+					`"DELETE":{"interfaces":{"switch":{"switch0":{"mtu":null,"switch-port":{"vlan-aware":null}}}}}}`,
+			},
+		},
+		{
+			name: "get port",
+			do: func(c Client) error {
+				p, err := c.GetPort(context.Background(), "switch0", "eth1")
+				if err != nil {
+					return err
+				}
+				require.Equal(t, 10, p.PVID)
+				require.Empty(t, p.VIDs)
+				return nil
+			},
+		},
+		{
+			name: "get a missing port",
+			do: func(c Client) error {
+				_, err := c.GetPort(context.Background(), "switch0", "eth0")
+				return err
+			},
+			err: "The interface eth0 is not a port of switch switch0.",
+		},
+		{
+			name: "set access vlan",
+			do: func(c Client) error {
+				_, err := c.SetAccessVLAN(context.Background(), "switch0", "eth3", 20)
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"switch":{"switch0":{"switch-port":{"interface":{"eth3":{"vlan":{"pvid":"20"}}}}}}}}}`,
+			},
+		},
+		{
+			name: "set access vlan on a new port",
+			do: func(c Client) error {
+				p, err := c.SetAccessVLAN(context.Background(), "switch0", "eth5", 10)
+				if err != nil {
+					return err
+				}
+				require.Equal(t, 10, p.PVID)
+				return nil
+			},
+			committed: portAddedConfig,
+			expected: []string{
+				`{"SET":{"interfaces":{"switch":{"switch0":{"switch-port":{"interface":{"eth5":{"vlan":{"pvid":"10"}}}}}}}}}`,
+			},
+		},
+		{
+			name: "set access vlan on a bridge member",
+			do: func(c Client) error {
+				_, err := c.SetAccessVLAN(context.Background(), "switch0", "eth0", 10)
+				return err
+			},
+			err: "The ethernet interface eth0 is already a member of bridge br0.",
+		},
+		{
+			name: "set trunk vlans on a missing interface",
+			do: func(c Client) error {
+				_, err := c.SetTrunkVLANs(context.Background(), "switch0", "eth9", 0, []int{10})
+				return err
+			},
+			err: "The ethernet interface eth9 does not exist.",
+		},
+		{
+			name: "turn a trunk into an access port",
+			do: func(c Client) error {
+				_, err := c.SetAccessVLAN(context.Background(), "switch0", "eth2", 20)
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"switch":{"switch0":{"switch-port":{"interface":{"eth2":{"vlan":{"pvid":"20"}}}}}}}},` +
+					`"DELETE":{"interfaces":{"switch":{"switch0":{"switch-port":{"interface":{"eth2":{"vlan":{"vid":["10","20"]}}}}}}}}}`,
+			},
+		},
+		{
+			name: "set trunk vlans",
+			do: func(c Client) error {
+				_, err := c.SetTrunkVLANs(context.Background(), "switch0", "eth2", 0, []int{20, 30})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"switch":{"switch0":{"switch-port":{"interface":{"eth2":{"vlan":{"vid":["20","30"]}}}}}}}},` +
+					`"DELETE":{"interfaces":{"switch":{"switch0":{"switch-port":{"interface":{"eth2":{"vlan":{"pvid":null,"vid":["10"]}}}}}}}}}`,
+			},
+		},
+		{
+			name: "set invalid trunk vlans",
+			do: func(c Client) error {
+				_, err := c.SetTrunkVLANs(context.Background(), "switch0", "eth2", 10, []int{10, 5000})
+				return err
+			},
+			err: "vid[0]: 10 is already the pvid; vid[1]: 5000 must be between 1 and 4094",
+		},
+		{
+			name: "set vlans on a switch that is not vlan aware",
+			do: func(c Client) error {
+				_, err := c.SetAccessVLAN(context.Background(), "switch1", "eth4", 10)
+				return err
+			},
+			err: "The switch switch1 is not VLAN aware.",
+		},
+		{
+			name: "clear port vlans",
+			do: func(c Client) error {
+				return c.ClearPortVLANs(context.Background(), "switch0", "eth2")
+			},
+			expected: []string{
+				`{"DELETE":{"interfaces":{"switch":{"switch0":{"switch-port":{"interface":{"eth2":{"vlan":null}}}}}}}}`,
+			},
+		},
+	} {
+		apiClient := &apitest.Client{Config: switchConfig, Committed: test.committed}
+		err := test.do(&client{apiClient: apiClient})
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
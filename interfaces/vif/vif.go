@@ -0,0 +1,235 @@
+// Package vif manages VLAN sub-interfaces of any interface that can have them,
+// such as interfaces ethernet eth1 vif 20.
+package vif
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+// Client manages the properties of VLANs. Their firewall attachments are
+// managed by the attachment client using the path of the VLAN.
+type Client interface {
+	Get(context.Context, types.InterfacePath, string) (*types.VIF, error)
+	List(context.Context, types.InterfacePath) ([]*types.VIF, error)
+	Create(context.Context, *types.VIF) (*types.VIF, error)
+	Update(context.Context, *types.VIF, []jsonpatch.JsonPatchOperation) (*types.VIF, error)
+	Delete(context.Context, types.InterfacePath, string) error
+}
+
+type client struct {
+	apiClient api.Client
+}
+
+func New(httpClient *http.Client, host string) Client {
+	return NewWithAPIClient(api.New(httpClient, host))
+}
+
+// NewWithAPIClient is used by the interface clients that check for VLANs on
+// behalf of their own interface type.
+func NewWithAPIClient(apiClient api.Client) Client {
+	return &client{
+		apiClient: apiClient,
+	}
+}
+
+// Get returns the VLAN with the id on the parent interface.
+func (c *client) Get(ctx context.Context, parent types.InterfacePath, id string) (*types.VIF, error) {
+	vifs, err := c.vifs(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	v, ok := vifs[id]
+	if !ok || v == nil {
+		return nil, fmt.Errorf("The interface %s does not exist.", parent.Child("vif", id))
+	}
+	return toVIF(parent, id, v), nil
+}
+
+// List returns every VLAN of the parent interface, ordered by VLAN id.
+func (c *client) List(ctx context.Context, parent types.InterfacePath) ([]*types.VIF, error) {
+	vifs, err := c.vifs(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []*types.VIF{}
+	for id, v := range vifs {
+		if v != nil {
+			list = append(list, toVIF(parent, id, v))
+		}
+	}
+	sort.Slice(list, func(a, b int) bool {
+		x, _ := strconv.Atoi(list[a].ID)
+		y, _ := strconv.Atoi(list[b].ID)
+		return x < y
+	})
+	return list, nil
+}
+
+// Create creates the VLAN on its parent interface, which must already exist.
+// The firewall attachment and PPPoE sessions are ignored.
+func (c *client) Create(ctx context.Context, v *types.VIF) (*types.VIF, error) {
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+
+	vifs, err := c.vifs(ctx, v.Parent)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := vifs[v.ID]; ok {
+		return nil, fmt.Errorf("The interface %s already exists.", v.Path())
+	}
+
+	set := new(types.Interfaces)
+	if err := set.SetVIF(v.Parent, v.ID, v.Properties()); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Interfaces: set,
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, v.Parent, v.ID)
+}
+
+// Update applies the patches to the properties of the current VLAN, setting
+// the ones that changed and deleting the ones that were removed in a single
+// commit.
+func (c *client) Update(ctx context.Context, current *types.VIF, patches []jsonpatch.JsonPatchOperation) (*types.VIF, error) {
+	var v types.VIF
+	if err := utils.Patch(current.Properties(), &v, patches); err != nil {
+		return nil, err
+	}
+	v.ID = current.ID
+	v.Parent = current.Parent
+
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := new(api.Operation)
+
+	if !v.IsEmpty() {
+		set := new(types.Interfaces)
+		if err := set.SetVIF(v.Parent, v.ID, v.Properties()); err != nil {
+			return nil, err
+		}
+		in.Set = &api.Set{
+			Resources: api.Resources{
+				Interfaces: set,
+			},
+		}
+	}
+
+	if stale := staleVIF(current, &v); stale != nil {
+		del := new(types.Interfaces)
+		if err := del.SetVIF(v.Parent, v.ID, stale); err != nil {
+			return nil, err
+		}
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Interfaces: del,
+			},
+		}
+	}
+
+	if in.Set != nil || in.Delete != nil {
+		if _, err := c.apiClient.Post(ctx, in); err != nil {
+			return nil, err
+		}
+	}
+	return c.Get(ctx, v.Parent, v.ID)
+}
+
+// Delete removes the VLAN along with its firewall attachment. A VLAN that
+// still has PPPoE sessions is not removed.
+func (c *client) Delete(ctx context.Context, parent types.InterfacePath, id string) error {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if err := interfacesOf(op).EnsureNoSubInterfaces(parent.Child("vif", id)); err != nil {
+		return err
+	}
+
+	del := new(types.Interfaces)
+	if err := del.SetVIF(parent, id, nil); err != nil {
+		return err
+	}
+
+	_, err = c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Interfaces: del,
+			},
+		},
+	})
+	return err
+}
+
+func (c *client) vifs(ctx context.Context, parent types.InterfacePath) (map[string]*types.VIF, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return interfacesOf(op).VIFs(parent)
+}
+
+func interfacesOf(op *api.Operation) *types.Interfaces {
+	if op == nil || op.Get == nil || op.Get.Interfaces == nil {
+		return new(types.Interfaces)
+	}
+	return op.Get.Interfaces
+}
+
+func toVIF(parent types.InterfacePath, id string, v *types.VIF) *types.VIF {
+	v.ID = id
+	v.Parent = parent
+	if v.Firewall != nil {
+		v.Firewall.Path = v.Path()
+		v.Firewall.Interface = v.Path().String()
+	}
+	return v
+}
+
+// staleVIF returns the properties that are set in current but not in updated,
+// encoded for deletion, or nil if there are none.
+func staleVIF(current, updated *types.VIF) *types.VIF {
+	stale := new(types.VIF)
+	stale.SetOpMode(types.OpModeDelete)
+
+	stale.Addresses = utils.StringSliceDiff(updated.Addresses, current.Addresses)
+	if len(stale.Addresses) == 0 {
+		stale.Addresses = nil
+	}
+	if current.Description != "" && updated.Description == "" {
+		stale.Description = current.Description
+	}
+	if current.MTU != 0 && updated.MTU == 0 {
+		stale.MTU = current.MTU
+	}
+	stale.Disable = current.Disable && !updated.Disable
+	stale.DHCPOptions = current.DHCPOptions.Removed(updated.DHCPOptions)
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	return stale
+}
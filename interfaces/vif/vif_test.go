@@ -0,0 +1,164 @@
+package vif
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	vifConfig = `{"GET": {"interfaces": {"ethernet": {
+	"eth0": {"vif": {"2": {"pppoe": {"0": {}}}}},
+	"eth1": {"address": ["192.168.1.1/24"], "vif": {
+		"20": {"address": ["10.0.20.1/24"], "description": "IoT", "mtu": "1500", "firewall": {"in": {"name": "IOT_IN"}}},
+		"10": {"address": ["10.0.10.1/24"]}
+	}}
+}}}, "success": true}`
+
+	createdVIFConfig = `{"GET": {"interfaces": {"ethernet": {
+	"eth1": {"vif": {"30": {"address": ["10.0.30.1/24"], "description": "Guest"}}}
+}}}, "success": true}`
+)
+
+func TestVIFOperations(t *testing.T) {
+	eth1 := types.InterfacePath{"ethernet", "eth1"}
+
+	for _, test := range []struct {
+		name      string
+		committed string
+		do        func(Client) error
+		expected  []string
+		err       string
+	}{
+		{
+			name: "get",
+			do: func(c Client) error {
+				v, err := c.Get(context.Background(), eth1, "20")
+				if err != nil {
+					return err
+				}
+				require.Equal(t, "ethernet eth1 vif 20", v.GetID())
+				require.Equal(t, "ethernet eth1 vif 20", v.Firewall.Interface)
+				require.Equal(t, 1500, v.MTU)
+				return nil
+			},
+		},
+		{
+			name: "list",
+			do: func(c Client) error {
+				vifs, err := c.List(context.Background(), eth1)
+				if err != nil {
+					return err
+				}
+				require.Len(t, vifs, 2)
+				require.Equal(t, "10", vifs[0].ID)
+				require.Equal(t, "20", vifs[1].ID)
+				return nil
+			},
+		},
+		{
+			name: "get missing",
+			do: func(c Client) error {
+				_, err := c.Get(context.Background(), eth1, "30")
+				return err
+			},
+			err: "The interface ethernet eth1 vif 30 does not exist.",
+		},
+		{
+			name:      "create",
+			committed: createdVIFConfig,
+			do: func(c Client) error {
+				v, err := c.Create(context.Background(), &types.VIF{
+					ID:          "30",
+					Parent:      eth1,
+					Addresses:   []string{"10.0.30.1/24"},
+					Description: "Guest",
+				})
+				if err != nil {
+					return err
+				}
+				require.Equal(t, "Guest", v.Description)
+				return nil
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"ethernet":{"eth1":{"vif":{"30":{"address":["10.0.30.1/24"],"description":"Guest"}}}}}}}`,
+			},
+		},
+		{
+			name: "create existing",
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.VIF{ID: "20", Parent: eth1})
+				return err
+			},
+			err: "The interface ethernet eth1 vif 20 already exists.",
+		},
+		{
+			name: "create on a missing parent",
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.VIF{ID: "20", Parent: types.InterfacePath{"ethernet", "eth2"}})
+				return err
+			},
+			err: "The interface ethernet eth2 does not exist.",
+		},
+		{
+			name: "create invalid",
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.VIF{ID: "4095", Parent: eth1, Addresses: []string{"10.0.30.1"}})
+				return err
+			},
+			err: `id: 4095 must be between 0 and 4094; address[0]: "10.0.30.1" must be dhcp, dhcpv6 or an address in CIDR notation`,
+		},
+		{
+			name: "update",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background(), eth1, "20")
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "add", Path: "/address/-", Value: "dhcpv6"},
+					{Operation: "remove", Path: "/description"},
+					{Operation: "replace", Path: "/mtu", Value: "1496"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"ethernet":{"eth1":{"vif":{"20":{"mtu":"1496","address":["10.0.20.1/24","dhcpv6"]}}}}}},` +
+					`"DELETE":{"interfaces":{"ethernet":{"eth1":{"vif":{"20":{"description":null}}}}}}}`,
+			},
+		},
+		{
+			name: "delete",
+			do: func(c Client) error {
+				return c.Delete(context.Background(), eth1, "20")
+			},
+			expected: []string{
+				`{"DELETE":{"interfaces":{"ethernet":{"eth1":{"vif":{"20":null}}}}}}`,
+			},
+		},
+		{
+			name: "delete with pppoe",
+			do: func(c Client) error {
+				return c.Delete(context.Background(), types.InterfacePath{"ethernet", "eth0"}, "2")
+			},
+			err: "The interface ethernet eth0 vif 2 still has sub-interfaces: ethernet eth0 vif 2 pppoe 0.",
+		},
+	} {
+		apiClient := &apitest.Client{Config: vifConfig, Committed: test.committed}
+		err := test.do(NewWithAPIClient(apiClient))
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
@@ -0,0 +1,193 @@
+// Package vti manages the virtual tunnel interfaces used by route based IPsec.
+package vti
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+// Client manages the properties of VTI interfaces. Their firewall attachments
+// are managed by the attachment client.
+type Client interface {
+	Get(context.Context, string) (*types.VTI, error)
+	Create(context.Context, *types.VTI) (*types.VTI, error)
+	Update(context.Context, *types.VTI, []jsonpatch.JsonPatchOperation) (*types.VTI, error)
+	Delete(context.Context, string) error
+}
+
+type client struct {
+	apiClient api.Client
+}
+
+func New(httpClient *http.Client, host string) Client {
+	return &client{
+		apiClient: api.New(httpClient, host),
+	}
+}
+
+func (c *client) Get(ctx context.Context, id string) (*types.VTI, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toVTI(id, op)
+}
+
+func (c *client) Create(ctx context.Context, v *types.VTI) (*types.VTI, error) {
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := interfacesOf(op).VTI[v.ID]; ok {
+		return nil, fmt.Errorf("The vti interface %s already exists.", v.ID)
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					VTI: map[string]*types.VTI{
+						v.ID: v.Properties(),
+					},
+				},
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, v.ID)
+}
+
+// Update applies the patches to the properties of the current interface,
+// setting the ones that changed and deleting the ones that were removed in a
+// single commit.
+func (c *client) Update(ctx context.Context, current *types.VTI, patches []jsonpatch.JsonPatchOperation) (*types.VTI, error) {
+	var v types.VTI
+	if err := utils.Patch(current.Properties(), &v, patches); err != nil {
+		return nil, err
+	}
+	v.ID = current.ID
+
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := new(api.Operation)
+
+	if !v.IsEmpty() {
+		in.Set = &api.Set{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					VTI: map[string]*types.VTI{
+						v.ID: v.Properties(),
+					},
+				},
+			},
+		}
+	}
+
+	if stale := staleVTI(current, &v); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					VTI: map[string]*types.VTI{
+						v.ID: stale,
+					},
+				},
+			},
+		}
+	}
+
+	if in.Set != nil || in.Delete != nil {
+		if _, err := c.apiClient.Post(ctx, in); err != nil {
+			return nil, err
+		}
+	}
+	return c.Get(ctx, v.ID)
+}
+
+// Delete removes the interface along with its firewall attachment.
+func (c *client) Delete(ctx context.Context, id string) error {
+	if _, err := c.Get(ctx, id); err != nil {
+		return err
+	}
+
+	_, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					VTI: map[string]*types.VTI{
+						id: nil,
+					},
+				},
+			},
+		},
+	})
+	return err
+}
+
+func path(id string) types.InterfacePath {
+	return types.InterfacePath{"vti", id}
+}
+
+func interfacesOf(op *api.Operation) *types.Interfaces {
+	if op == nil || op.Get == nil || op.Get.Interfaces == nil {
+		return new(types.Interfaces)
+	}
+	return op.Get.Interfaces
+}
+
+func toVTI(id string, op *api.Operation) (*types.VTI, error) {
+	if op == nil || op.Get == nil || op.Get.Interfaces == nil || op.Get.Interfaces.VTI == nil {
+		return nil, errors.New("No vti interfaces exist.")
+	}
+
+	v, ok := op.Get.Interfaces.VTI[id]
+	if !ok || v == nil {
+		return nil, fmt.Errorf("The vti interface %s does not exist.", id)
+	}
+
+	v.ID = id
+	if v.Firewall != nil {
+		v.Firewall.Interface = path(id).String()
+		v.Firewall.Path = path(id)
+	}
+
+	return v, nil
+}
+
+// staleVTI returns the properties that are set in current but not in updated,
+// encoded for deletion, or nil if there are none.
+func staleVTI(current, updated *types.VTI) *types.VTI {
+	stale := new(types.VTI)
+	stale.SetOpMode(types.OpModeDelete)
+
+	stale.Addresses = utils.StringSliceDiff(updated.Addresses, current.Addresses)
+	if len(stale.Addresses) == 0 {
+		stale.Addresses = nil
+	}
+	if current.Description != "" && updated.Description == "" {
+		stale.Description = current.Description
+	}
+	stale.Disable = current.Disable && !updated.Disable
+	if current.MTU != 0 && updated.MTU == 0 {
+		stale.MTU = current.MTU
+	}
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	return stale
+}
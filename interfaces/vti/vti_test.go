@@ -0,0 +1,111 @@
+package vti
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	vtiConfig = `{"GET": {"interfaces": {"vti": {
+	"vti0": {"address": ["10.254.0.1/30"], "mtu": "1436", "description": "to branch", "firewall": {"in": {"name": "VPN_IN"}}}
+}}}, "success": true}`
+
+	createdVTIConfig = `{"GET": {"interfaces": {"vti": {
+	"vti1": {"address": ["10.254.1.1/30"]}
+}}}, "success": true}`
+)
+
+func TestVTIOperations(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		committed string
+		do        func(Client) error
+		expected  []string
+		err       string
+	}{
+		{
+			name: "get",
+			do: func(c Client) error {
+				v, err := c.Get(context.Background(), "vti0")
+				if err != nil {
+					return err
+				}
+				require.Equal(t, 1436, v.MTU)
+				require.Equal(t, "vti vti0", v.Firewall.Interface)
+				return nil
+			},
+		},
+		{
+			name:      "create",
+			committed: createdVTIConfig,
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.VTI{ID: "vti1", Addresses: []string{"10.254.1.1/30"}, MTU: 1400})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"vti":{"vti1":{"mtu":"1400","address":["10.254.1.1/30"]}}}}}`,
+			},
+		},
+		{
+			name: "create invalid",
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.VTI{ID: "tun1", Addresses: []string{"dhcp"}, MTU: 10000})
+				return err
+			},
+			err: `id: "tun1" must be vti followed by a number; address[0]: "dhcp" must be an address in CIDR notation; mtu: 10000 must be between 68 and 9000`,
+		},
+		{
+			name: "update",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background(), "vti0")
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/mtu"},
+					{Operation: "remove", Path: "/description"},
+					{Operation: "add", Path: "/disable", Value: nil},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"vti":{"vti0":{"disable":null,"address":["10.254.0.1/30"]}}}},` +
+					`"DELETE":{"interfaces":{"vti":{"vti0":{"description":null,"mtu":null}}}}}`,
+			},
+		},
+		{
+			name: "delete a missing interface",
+			do: func(c Client) error {
+				return c.Delete(context.Background(), "vti9")
+			},
+			err: "The vti interface vti9 does not exist.",
+		},
+		{
+			name: "delete",
+			do: func(c Client) error {
+				return c.Delete(context.Background(), "vti0")
+			},
+			expected: []string{
+				`{"DELETE":{"interfaces":{"vti":{"vti0":null}}}}`,
+			},
+		},
+	} {
+		apiClient := &apitest.Client{Config: vtiConfig, Committed: test.committed}
+		err := test.do(&client{apiClient: apiClient})
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
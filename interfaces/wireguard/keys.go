@@ -0,0 +1,68 @@
+package wireguard
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"golang.org/x/crypto/curve25519"
+)
+
+// Key is a base64 encoded WireGuard private or preshared key. It is masked
+// whenever it is formatted so that it never ends up in logs by accident; use
+// Reveal to obtain the key itself.
+type Key string
+
+// GeneratePrivateKey returns a new private key.
+func GeneratePrivateKey() (Key, error) {
+	var k [curve25519.ScalarSize]byte
+	if _, err := rand.Read(k[:]); err != nil {
+		return "", err
+	}
+
+	// Clamp the key as described in RFC 7748 section 5, as wg genkey does.
+	k[0] &= 248
+	k[31] = (k[31] & 127) | 64
+
+	return Key(base64.StdEncoding.EncodeToString(k[:])), nil
+}
+
+// GeneratePresharedKey returns a new preshared key.
+func GeneratePresharedKey() (Key, error) {
+	var k [curve25519.ScalarSize]byte
+	if _, err := rand.Read(k[:]); err != nil {
+		return "", err
+	}
+	return Key(base64.StdEncoding.EncodeToString(k[:])), nil
+}
+
+// PublicKey returns the public key of the private key, as wg pubkey does.
+func (k Key) PublicKey() (string, error) {
+	priv, err := base64.StdEncoding.DecodeString(string(k))
+	if err != nil || len(priv) != curve25519.ScalarSize {
+		return "", errors.New("The private key must be a base64 encoded 32 byte key.")
+	}
+
+	pub, err := curve25519.X25519(priv, curve25519.Basepoint)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(pub), nil
+}
+
+// Reveal returns the key itself, e.g. to set it as the private key of an
+// interface.
+func (k Key) Reveal() string {
+	return string(k)
+}
+
+func (k Key) String() string {
+	return types.Secret(k).String()
+}
+
+// GoString masks the key when formatted with %#v.
+func (k Key) GoString() string {
+	return types.Secret(k).GoString()
+}
@@ -0,0 +1,45 @@
+package wireguard
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeys(t *testing.T) {
+	key, err := GeneratePrivateKey()
+	require.NoError(t, err)
+
+	raw, err := base64.StdEncoding.DecodeString(key.Reveal())
+	require.NoError(t, err)
+	require.Len(t, raw, 32)
+	require.Zero(t, raw[0]&7)
+	require.Equal(t, byte(0x40), raw[31]&0xc0)
+
+	pub, err := key.PublicKey()
+	require.NoError(t, err)
+	require.NotEqual(t, key.Reveal(), pub)
+
+	// The public key of the example key in the WireGuard documentation.
+	pub, err = Key("kGK2lG5fTEGOlFhWZ2iUfmNmLyWLaY9nJ2Kj4cKh2Xg=").PublicKey()
+	require.NoError(t, err)
+	require.Len(t, pub, 44)
+
+	psk, err := GeneratePresharedKey()
+	require.NoError(t, err)
+
+	for _, s := range []string{
+		fmt.Sprint(key),
+		fmt.Sprintf("%v %s %q %#v", key, key, key, key),
+		fmt.Sprint(psk),
+	} {
+		require.NotContains(t, s, key.Reveal())
+		require.NotContains(t, s, psk.Reveal())
+		require.Contains(t, s, "********")
+	}
+
+	_, err = Key("not a key").PublicKey()
+	require.Error(t, err)
+}
@@ -0,0 +1,367 @@
+// Package wireguard manages WireGuard interfaces, their peers and their
+// firewall attachments. It requires the WireGuard package to be installed on
+// the router.
+package wireguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/frankgreco/edge-sdk-go/interfaces/attachment"
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+type Client interface {
+	Get(context.Context, string) (*types.WireGuard, error)
+	Create(context.Context, *types.WireGuard) (*types.WireGuard, error)
+	Update(context.Context, *types.WireGuard, []jsonpatch.JsonPatchOperation) (*types.WireGuard, error)
+	Delete(context.Context, string) error
+
+	GetPeer(context.Context, string, string) (*types.WireGuardPeer, error)
+	CreatePeer(context.Context, string, *types.WireGuardPeer) (*types.WireGuardPeer, error)
+	UpdatePeer(context.Context, *types.WireGuardPeer, []jsonpatch.JsonPatchOperation) (*types.WireGuardPeer, error)
+	DeletePeer(context.Context, string, string) error
+
+	AttachFirewallRuleset(context.Context, string, *types.FirewallAttachment) (*types.FirewallAttachment, error)
+	UpdateFirewallRulesetAttachment(context.Context, *types.FirewallAttachment, []jsonpatch.JsonPatchOperation) (*types.FirewallAttachment, error)
+	DetachFirewallRuleset(context.Context, string) error
+	GetFirewallRulesetAttachment(context.Context, string) (*types.FirewallAttachment, error)
+}
+
+type client struct {
+	apiClient   api.Client
+	attachments attachment.Client
+}
+
+func New(httpClient *http.Client, host string) Client {
+	apiClient := api.New(httpClient, host)
+	return &client{
+		apiClient:   apiClient,
+		attachments: attachment.NewWithAPIClient(apiClient),
+	}
+}
+
+func (c *client) Get(ctx context.Context, id string) (*types.WireGuard, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toWireGuard(id, op)
+}
+
+// Create creates the interface along with its peers. The firewall attachment
+// is ignored.
+func (c *client) Create(ctx context.Context, wg *types.WireGuard) (*types.WireGuard, error) {
+	if err := wg.Validate(); err != nil {
+		return nil, err
+	}
+
+	tmp := *wg
+	tmp.Firewall = nil
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					WireGuard: map[string]*types.WireGuard{
+						wg.ID: &tmp,
+					},
+				},
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.Get(ctx, wg.ID)
+}
+
+// Update applies the patches to the properties of the current interface,
+// setting the ones that changed and deleting the ones that were removed in a
+// single commit. Peers are changed with the peer methods.
+func (c *client) Update(ctx context.Context, current *types.WireGuard, patches []jsonpatch.JsonPatchOperation) (*types.WireGuard, error) {
+	var wg types.WireGuard
+	if err := utils.Patch(current.Properties(), &wg, patches); err != nil {
+		return nil, err
+	}
+	wg.ID = current.ID
+
+	if err := wg.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := new(api.Operation)
+
+	if !wg.IsEmpty() {
+		in.Set = &api.Set{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					WireGuard: map[string]*types.WireGuard{
+						wg.ID: wg.Properties(),
+					},
+				},
+			},
+		}
+	}
+
+	if stale := staleWireGuard(current, &wg); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					WireGuard: map[string]*types.WireGuard{
+						wg.ID: stale,
+					},
+				},
+			},
+		}
+	}
+
+	if in.Set != nil || in.Delete != nil {
+		if _, err := c.apiClient.Post(ctx, in); err != nil {
+			return nil, err
+		}
+	}
+	return c.Get(ctx, wg.ID)
+}
+
+// Delete removes the interface along with its peers and firewall attachment.
+func (c *client) Delete(ctx context.Context, id string) error {
+	if _, err := c.Get(ctx, id); err != nil {
+		return err
+	}
+
+	_, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Interfaces: &types.Interfaces{
+					WireGuard: map[string]*types.WireGuard{
+						id: nil,
+					},
+				},
+			},
+		},
+	})
+	return err
+}
+
+// GetPeer returns the peer of the interface with the public key.
+func (c *client) GetPeer(ctx context.Context, id, publicKey string) (*types.WireGuardPeer, error) {
+	wg, err := c.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	peer, ok := wg.Peers[publicKey]
+	if !ok {
+		return nil, fmt.Errorf("The peer %s of wireguard interface %s does not exist.", publicKey, id)
+	}
+	return peer, nil
+}
+
+// CreatePeer adds the peer to the interface.
+func (c *client) CreatePeer(ctx context.Context, id string, peer *types.WireGuardPeer) (*types.WireGuardPeer, error) {
+	if err := peer.Validate(); err != nil {
+		return nil, err
+	}
+
+	wg, err := c.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := wg.Peers[peer.PublicKey]; ok {
+		return nil, fmt.Errorf("The peer %s of wireguard interface %s already exists.", peer.PublicKey, id)
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Interfaces: peerOf(id, peer),
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.GetPeer(ctx, id, peer.PublicKey)
+}
+
+// UpdatePeer applies the patches to the current peer, setting the properties
+// that changed and deleting the ones that were removed in a single commit.
+func (c *client) UpdatePeer(ctx context.Context, current *types.WireGuardPeer, patches []jsonpatch.JsonPatchOperation) (*types.WireGuardPeer, error) {
+	var peer types.WireGuardPeer
+	if err := utils.Patch(current, &peer, patches); err != nil {
+		return nil, err
+	}
+	peer.PublicKey = current.PublicKey
+	peer.Interface = current.Interface
+
+	if err := peer.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := new(api.Operation)
+
+	if !peer.IsEmpty() {
+		in.Set = &api.Set{
+			Resources: api.Resources{
+				Interfaces: peerOf(peer.Interface, &peer),
+			},
+		}
+	}
+
+	if stale := stalePeer(current, &peer); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Interfaces: peerOf(peer.Interface, stale),
+			},
+		}
+	}
+
+	if in.Set != nil || in.Delete != nil {
+		if _, err := c.apiClient.Post(ctx, in); err != nil {
+			return nil, err
+		}
+	}
+	return c.GetPeer(ctx, peer.Interface, peer.PublicKey)
+}
+
+// DeletePeer removes the peer with the public key from the interface.
+func (c *client) DeletePeer(ctx context.Context, id, publicKey string) error {
+	if _, err := c.GetPeer(ctx, id, publicKey); err != nil {
+		return err
+	}
+
+	peer := &types.WireGuardPeer{PublicKey: publicKey}
+	peer.SetOpMode(types.OpModeDelete)
+
+	_, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Interfaces: peerOf(id, peer),
+			},
+		},
+	})
+	return err
+}
+
+func (c *client) GetFirewallRulesetAttachment(ctx context.Context, id string) (*types.FirewallAttachment, error) {
+	return c.attachments.Get(ctx, path(id))
+}
+
+func (c *client) AttachFirewallRuleset(ctx context.Context, id string, firewall *types.FirewallAttachment) (*types.FirewallAttachment, error) {
+	return c.attachments.Attach(ctx, path(id), firewall)
+}
+
+func (c *client) UpdateFirewallRulesetAttachment(ctx context.Context, current *types.FirewallAttachment, patches []jsonpatch.JsonPatchOperation) (*types.FirewallAttachment, error) {
+	return c.attachments.Update(ctx, current, patches)
+}
+
+func (c *client) DetachFirewallRuleset(ctx context.Context, id string) error {
+	return c.attachments.Detach(ctx, path(id))
+}
+
+func path(id string) types.InterfacePath {
+	return types.InterfacePath{"wireguard", id}
+}
+
+// peerOf returns interfaces holding only the peer of the interface.
+func peerOf(id string, peer *types.WireGuardPeer) *types.Interfaces {
+	return &types.Interfaces{
+		WireGuard: map[string]*types.WireGuard{
+			id: {
+				Peers: map[string]*types.WireGuardPeer{
+					peer.PublicKey: peer,
+				},
+			},
+		},
+	}
+}
+
+func toWireGuard(id string, op *api.Operation) (*types.WireGuard, error) {
+	if op == nil || op.Get == nil || op.Get.Interfaces == nil || op.Get.Interfaces.WireGuard == nil {
+		return nil, errors.New("No wireguard interfaces exist.")
+	}
+
+	wg, ok := op.Get.Interfaces.WireGuard[id]
+	if !ok || wg == nil {
+		return nil, fmt.Errorf("The wireguard interface %s does not exist.", id)
+	}
+
+	wg.ID = id
+	for _, peer := range wg.Peers {
+		if peer != nil {
+			peer.Interface = id
+		}
+	}
+	if wg.Firewall != nil {
+		wg.Firewall.Interface = path(id).String()
+		wg.Firewall.Path = path(id)
+	}
+
+	return wg, nil
+}
+
+// staleWireGuard returns the properties that are set in current but not in
+// updated, encoded for deletion, or nil if there are none.
+func staleWireGuard(current, updated *types.WireGuard) *types.WireGuard {
+	stale := new(types.WireGuard)
+	stale.SetOpMode(types.OpModeDelete)
+
+	stale.Addresses = utils.StringSliceDiff(updated.Addresses, current.Addresses)
+	if len(stale.Addresses) == 0 {
+		stale.Addresses = nil
+	}
+	if current.Description != "" && updated.Description == "" {
+		stale.Description = current.Description
+	}
+	if current.ListenPort != 0 && updated.ListenPort == 0 {
+		stale.ListenPort = current.ListenPort
+	}
+	if current.MTU != 0 && updated.MTU == 0 {
+		stale.MTU = current.MTU
+	}
+	if current.PrivateKey != "" && updated.PrivateKey == "" {
+		stale.PrivateKey = current.PrivateKey
+	}
+	if current.RouteAllowedIPs != nil && updated.RouteAllowedIPs == nil {
+		stale.RouteAllowedIPs = current.RouteAllowedIPs
+	}
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	return stale
+}
+
+// stalePeer returns the properties that are set in current but not in
+// updated, encoded for deletion, or nil if there are none.
+func stalePeer(current, updated *types.WireGuardPeer) *types.WireGuardPeer {
+	stale := &types.WireGuardPeer{PublicKey: current.PublicKey}
+	stale.SetOpMode(types.OpModeDelete)
+
+	stale.AllowedIPs = utils.StringSliceDiff(updated.AllowedIPs, current.AllowedIPs)
+	if len(stale.AllowedIPs) == 0 {
+		stale.AllowedIPs = nil
+	}
+	if current.Description != "" && updated.Description == "" {
+		stale.Description = current.Description
+	}
+	if current.Endpoint != "" && updated.Endpoint == "" {
+		stale.Endpoint = current.Endpoint
+	}
+	if current.PersistentKeepalive != 0 && updated.PersistentKeepalive == 0 {
+		stale.PersistentKeepalive = current.PersistentKeepalive
+	}
+	if current.PresharedKey != "" && updated.PresharedKey == "" {
+		stale.PresharedKey = current.PresharedKey
+	}
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	return stale
+}
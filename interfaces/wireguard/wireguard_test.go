@@ -0,0 +1,248 @@
+package wireguard
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/interfaces/attachment"
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	privateKey = "kGK2lG5fTEGOlFhWZ2iUfmNmLyWLaY9nJ2Kj4cKh2Xg="
+	peerKey    = "xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg="
+	newPeerKey = "TrMvSoP4jYQlY6RIzBgbssQqY3vxI2Pi+y71lOWWXX0="
+
+	wireguardConfig = `{"GET": {"interfaces": {"wireguard": {
+	"wg0": {"address": ["10.10.0.1/24"], "listen-port": "51820", "private-key": "kGK2lG5fTEGOlFhWZ2iUfmNmLyWLaY9nJ2Kj4cKh2Xg=", "route-allowed-ips": "true",
+		"peer": {"xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg=": {"allowed-ips": ["10.10.0.2/32", "192.168.2.0/24"], "endpoint": "vpn.example.com:51820", "persistent-keepalive": "25"}},
+		"firewall": {"in": {"name": "WG_IN"}}}
+}}}, "success": true}`
+
+	createdPeerConfig = `{"GET": {"interfaces": {"wireguard": {
+	"wg0": {"peer": {"TrMvSoP4jYQlY6RIzBgbssQqY3vxI2Pi+y71lOWWXX0=": {"allowed-ips": ["10.10.0.3/32"]}}}
+}}}, "success": true}`
+
+	createdWireGuardConfig = `{"GET": {"interfaces": {"wireguard": {
+	"wg1": {"address": ["10.20.0.1/24"]}
+}}}, "success": true}`
+)
+
+func TestWireGuardOperations(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		committed string
+		do        func(Client) error
+		expected  []string
+		err       string
+	}{
+		{
+			name: "get",
+			do: func(c Client) error {
+				wg, err := c.Get(context.Background(), "wg0")
+				if err != nil {
+					return err
+				}
+				require.Equal(t, 51820, wg.ListenPort)
+				require.True(t, *wg.RouteAllowedIPs)
+				require.Equal(t, "wg0", wg.Peers[peerKey].Interface)
+				require.Equal(t, 25, wg.Peers[peerKey].PersistentKeepalive)
+				require.Equal(t, "wireguard wg0", wg.Firewall.Interface)
+				return nil
+			},
+		},
+		{
+			name: "get a missing interface",
+			do: func(c Client) error {
+				_, err := c.Get(context.Background(), "wg9")
+				return err
+			},
+			err: "The wireguard interface wg9 does not exist.",
+		},
+		{
+			name:      "create",
+			committed: createdWireGuardConfig,
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.WireGuard{
+					ID:         "wg1",
+					Addresses:  []string{"10.20.0.1/24"},
+					ListenPort: 51821,
+					PrivateKey: "/config/auth/wg1.key",
+					Peers: map[string]*types.WireGuardPeer{
+						peerKey: {AllowedIPs: []string{"10.20.0.2/32"}},
+					},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"wireguard":{"wg1":{"listen-port":"51821","address":["10.20.0.1/24"],"private-key":"/config/auth/wg1.key","peer":{"xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg=":{"allowed-ips":["10.20.0.2/32"]}}}}}}}`,
+			},
+		},
+		{
+			name: "create an invalid interface",
+			do: func(c Client) error {
+				_, err := c.Create(context.Background(), &types.WireGuard{ID: "wg1", PrivateKey: "not a key"})
+				return err
+			},
+			err: "private-key: must be a base64 encoded 32 byte key or the path of a file holding one",
+		},
+		{
+			name: "update",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background(), "wg0")
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "add", Path: "/description", Value: "road warriors"},
+					{Operation: "remove", Path: "/route-allowed-ips"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"wireguard":{"wg0":{"listen-port":"51820","address":["10.10.0.1/24"],"description":"road warriors","private-key":"kGK2lG5fTEGOlFhWZ2iUfmNmLyWLaY9nJ2Kj4cKh2Xg="}}}},"DELETE":{"interfaces":{"wireguard":{"wg0":{"route-allowed-ips":null}}}}}`,
+			},
+		},
+		{
+			name: "delete",
+			do: func(c Client) error {
+				return c.Delete(context.Background(), "wg0")
+			},
+			expected: []string{
+				`{"DELETE":{"interfaces":{"wireguard":{"wg0":null}}}}`,
+			},
+		},
+		{
+			name: "get peer",
+			do: func(c Client) error {
+				peer, err := c.GetPeer(context.Background(), "wg0", peerKey)
+				if err != nil {
+					return err
+				}
+				require.Equal(t, "vpn.example.com:51820", peer.Endpoint)
+				return nil
+			},
+		},
+		{
+			name: "get a missing peer",
+			do: func(c Client) error {
+				_, err := c.GetPeer(context.Background(), "wg0", newPeerKey)
+				return err
+			},
+			err: "The peer " + newPeerKey + " of wireguard interface wg0 does not exist.",
+		},
+		{
+			name:      "create peer",
+			committed: createdPeerConfig,
+			do: func(c Client) error {
+				_, err := c.CreatePeer(context.Background(), "wg0", &types.WireGuardPeer{
+					PublicKey:  newPeerKey,
+					AllowedIPs: []string{"10.10.0.3/32"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"wireguard":{"wg0":{"peer":{"TrMvSoP4jYQlY6RIzBgbssQqY3vxI2Pi+y71lOWWXX0=":{"allowed-ips":["10.10.0.3/32"]}}}}}}}`,
+			},
+		},
+		{
+			name: "create an existing peer",
+			do: func(c Client) error {
+				_, err := c.CreatePeer(context.Background(), "wg0", &types.WireGuardPeer{
+					PublicKey:  peerKey,
+					AllowedIPs: []string{"10.10.0.2/32"},
+				})
+				return err
+			},
+			err: "The peer " + peerKey + " of wireguard interface wg0 already exists.",
+		},
+		{
+			name: "update peer",
+			do: func(c Client) error {
+				current, err := c.GetPeer(context.Background(), "wg0", peerKey)
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdatePeer(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/allowed-ips/1"},
+					{Operation: "remove", Path: "/persistent-keepalive"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"wireguard":{"wg0":{"peer":{"xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg=":{"allowed-ips":["10.10.0.2/32"],"endpoint":"vpn.example.com:51820"}}}}}},"DELETE":{"interfaces":{"wireguard":{"wg0":{"peer":{"xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg=":{"allowed-ips":["192.168.2.0/24"],"persistent-keepalive":null}}}}}}}`,
+			},
+		},
+		{
+			name: "delete peer",
+			do: func(c Client) error {
+				return c.DeletePeer(context.Background(), "wg0", peerKey)
+			},
+			expected: []string{
+				`{"DELETE":{"interfaces":{"wireguard":{"wg0":{"peer":{"xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg=":null}}}}}}`,
+			},
+		},
+		{
+			name: "attach",
+			do: func(c Client) error {
+				local := "WG_LOCAL"
+				_, err := c.AttachFirewallRuleset(context.Background(), "wg0", &types.FirewallAttachment{Local: &local})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"interfaces":{"wireguard":{"wg0":{"firewall":{"local":{"name":"WG_LOCAL"}}}}}}}`,
+			},
+		},
+		{
+			name: "detach",
+			do: func(c Client) error {
+				return c.DetachFirewallRuleset(context.Background(), "wg0")
+			},
+			expected: []string{
+				`{"DELETE":{"interfaces":{"wireguard":{"wg0":{"firewall":null}}}}}`,
+			},
+		},
+	} {
+		apiClient := &apitest.Client{Config: wireguardConfig, Committed: test.committed}
+		err := test.do(&client{
+			apiClient:   apiClient,
+			attachments: attachment.NewWithAPIClient(apiClient),
+		})
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
+
+func TestWireGuardMasksKeys(t *testing.T) {
+	wg := &types.WireGuard{
+		ID:         "wg0",
+		PrivateKey: privateKey,
+		Peers: map[string]*types.WireGuardPeer{
+			peerKey: {PublicKey: peerKey, PresharedKey: newPeerKey},
+		},
+	}
+
+	for _, s := range []string{
+		fmt.Sprint(wg),
+		fmt.Sprintf("%v", *wg),
+		fmt.Sprintf("%+v", wg),
+		fmt.Sprintf("%#v", wg),
+		fmt.Sprint(wg.Peers[peerKey]),
+	} {
+		require.NotContains(t, s, privateKey)
+		require.NotContains(t, s, newPeerKey)
+		require.Contains(t, s, "********")
+	}
+}
@@ -22,8 +22,12 @@ type Delete struct {
 }
 
 type Resources struct {
-	Firewall   *types.Firewall   `json:"firewall,omitempty"`
-	Interfaces *types.Interfaces `json:"interfaces,omitempty"`
+	Firewall    *types.Firewall    `json:"firewall,omitempty"`
+	Interfaces  *types.Interfaces  `json:"interfaces,omitempty"`
+	Service     *types.Service     `json:"service,omitempty"`
+	System      *types.System      `json:"system,omitempty"`
+	PortForward *types.PortForward `json:"port-forward,omitempty"`
+	Protocols   *types.Protocols   `json:"protocols,omitempty"`
 }
 
 type Commit struct {
@@ -0,0 +1,70 @@
+// Package apitest provides a fake of the EdgeOS API clients for use in tests.
+package apitest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+)
+
+// Client records every posted operation and serves Config for every get.
+// Once something has been posted, Committed is served instead, if set. The
+// output of data sources is served from DataSources and the output of show
+// commands from Outputs.
+type Client struct {
+	Config      string
+	Committed   string
+	DataSources map[string]string
+	Outputs     map[string]string
+	Posted      []string
+}
+
+var (
+	_ api.Client        = (*Client)(nil)
+	_ api.DataClient    = (*Client)(nil)
+	_ api.CommandClient = (*Client)(nil)
+)
+
+// Get serves Config, or Committed once something has been posted.
+func (c *Client) Get(context.Context) (*api.Operation, error) {
+	config := c.Config
+	if len(c.Posted) > 0 && c.Committed != "" {
+		config = c.Committed
+	}
+
+	op := new(api.Operation)
+	if err := json.Unmarshal([]byte(config), op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// Post records the JSON encoding of in and reports success.
+func (c *Client) Post(_ context.Context, in *api.Operation) (*api.Operation, error) {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	c.Posted = append(c.Posted, string(data))
+	return &api.Operation{Success: true}, nil
+}
+
+// Data decodes the output of the named data source into out.
+func (c *Client) Data(_ context.Context, name string, out interface{}) error {
+	output, ok := c.DataSources[name]
+	if !ok {
+		return fmt.Errorf("unknown data source %s", name)
+	}
+	return json.Unmarshal([]byte(output), out)
+}
+
+// Run returns the output of command.
+func (c *Client) Run(_ context.Context, command string) (string, error) {
+	output, ok := c.Outputs[command]
+	if !ok {
+		return "", fmt.Errorf("unknown command %s", command)
+	}
+	return output, nil
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// opCommandWrapper runs an operational mode command, such as show ip bgp
+// summary, outside of an interactive shell.
+const opCommandWrapper = "/opt/vyatta/bin/vyatta-op-cmd-wrapper"
+
+// CommandClient runs operational mode commands on the router and returns
+// their output. It is used for state that the data API does not expose.
+type CommandClient interface {
+	Run(context.Context, string) (string, error)
+}
+
+type sshClient struct {
+	addr   string
+	config *ssh.ClientConfig
+}
+
+// NewSSH returns a CommandClient that runs every command in its own SSH
+// connection to the host of baseURL on port 22, using the same credentials as
+// the web interface. The host key of the router is verified by
+// hostKeyCallback, e.g. one returned by knownhosts.New.
+func NewSSH(baseURL, username, password string, hostKeyCallback ssh.HostKeyCallback) (CommandClient, error) {
+	if hostKeyCallback == nil {
+		return nil, errors.New("A host key callback is required to verify the router.")
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("Could not find the host of %s.", baseURL)
+	}
+
+	return &sshClient{
+		addr: net.JoinHostPort(u.Hostname(), "22"),
+		config: &ssh.ClientConfig{
+			User:            username,
+			Auth:            []ssh.AuthMethod{ssh.Password(password)},
+			HostKeyCallback: hostKeyCallback,
+		},
+	}, nil
+}
+
+func (c *sshClient) Run(ctx context.Context, command string) (string, error) {
+	conn, err := new(net.Dialer).DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return "", err
+	}
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, c.addr, c.config)
+	if err != nil {
+		conn.Close()
+		return "", err
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return "", err
+	}
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Run(opCommandWrapper + " " + command)
+	}()
+
+	select {
+	case <-ctx.Done():
+		client.Close()
+		return "", ctx.Err()
+	case err := <-done:
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return "", fmt.Errorf("Could not run %s: %s", command, msg)
+			}
+			return "", fmt.Errorf("Could not run %s: %s", command, err.Error())
+		}
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestNewSSH(t *testing.T) {
+	_, err := NewSSH("https://192.168.1.1", "ubnt", "ubnt", nil)
+	require.EqualError(t, err, "A host key callback is required to verify the router.")
+
+	_, err = NewSSH("/api", "ubnt", "ubnt", ssh.InsecureIgnoreHostKey())
+	require.EqualError(t, err, "Could not find the host of /api.")
+
+	c, err := NewSSH("https://192.168.1.1:8443", "ubnt", "ubnt", ssh.InsecureIgnoreHostKey())
+	require.NoError(t, err)
+	require.Equal(t, "192.168.1.1:22", c.(*sshClient).addr)
+	require.Equal(t, "ubnt", c.(*sshClient).config.User)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// DataClient reads the operational data EdgeOS exposes through its data API,
+// such as dhcp_leases, as opposed to configuration.
+type DataClient interface {
+	Data(context.Context, string, interface{}) error
+}
+
+type dataResponse struct {
+	Success json.RawMessage `json:"success"`
+	Error   string          `json:"error,omitempty"`
+	Output  json.RawMessage `json:"output"`
+}
+
+// Data decodes the output of the named data source into out.
+func (c *client) Data(ctx context.Context, name string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/edge/data.json?data="+url.QueryEscape(name), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return decodeData(name, data, out)
+}
+
+func decodeData(name string, data []byte, out interface{}) error {
+	var resp dataResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("Could not unmarshal %s from data %s: %s", name, string(data), err.Error())
+	}
+
+	// Depending on the firmware, success is either "1" or true.
+	switch string(resp.Success) {
+	case `"1"`, `1`, `true`:
+	default:
+		if resp.Error != "" {
+			return fmt.Errorf("Could not read %s: %s", name, resp.Error)
+		}
+		return fmt.Errorf("Could not read %s for a unknown reason.", name)
+	}
+
+	if err := json.Unmarshal(resp.Output, out); err != nil {
+		return fmt.Errorf("Could not unmarshal %s from data %s: %s", name, string(resp.Output), err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/edge/data.json", r.URL.Path)
+		switch r.URL.Query().Get("data") {
+		case "dhcp_stats":
+			w.Write([]byte(`{"success": "1", "output": {"dhcp-server-stats": {"LAN": {"pool_size": "100"}}}}`))
+		default:
+			w.Write([]byte(`{"success": "0", "error": "unknown data source"}`))
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.Client(), server.URL).(DataClient)
+
+	var out map[string]map[string]map[string]string
+	require.NoError(t, c.Data(context.Background(), "dhcp_stats", &out))
+	require.Equal(t, "100", out["dhcp-server-stats"]["LAN"]["pool_size"])
+
+	require.EqualError(t, c.Data(context.Background(), "bogus", &out), "Could not read bogus: unknown data source")
+}
@@ -0,0 +1,321 @@
+// Package nat manages the source, masquerade and destination NAT rules of the
+// router.
+package nat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+type Client interface {
+	GetRule(context.Context, int) (*types.NATRule, error)
+	ListRules(context.Context) ([]*types.NATRule, error)
+	CreateRule(context.Context, *types.NATRule) (*types.NATRule, error)
+	UpdateRule(context.Context, *types.NATRule, []jsonpatch.JsonPatchOperation) (*types.NATRule, error)
+	DeleteRule(context.Context, int) error
+}
+
+type client struct {
+	apiClient api.Client
+}
+
+func New(httpClient *http.Client, host string) Client {
+	return &client{
+		apiClient: api.New(httpClient, host),
+	}
+}
+
+func (c *client) GetRule(ctx context.Context, priority int) (*types.NATRule, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toRule(priority, op)
+}
+
+// ListRules returns every NAT rule ordered by priority.
+func (c *client) ListRules(ctx context.Context) ([]*types.NATRule, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []*types.NATRule{}
+	if nat := natOf(op); nat != nil {
+		rules = append(rules, nat.Rules...)
+	}
+	return rules, nil
+}
+
+// CreateRule creates the rule after ensuring the priority is free and every
+// group the rule refers to exists.
+func (c *client) CreateRule(ctx context.Context, r *types.NATRule) (*types.NATRule, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := toRule(r.Priority, op); err == nil {
+		return nil, fmt.Errorf("The NAT rule %d already exists.", r.Priority)
+	}
+
+	if err := ensureGroups(r, op); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Service: ruleOf(r),
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.GetRule(ctx, r.Priority)
+}
+
+// UpdateRule applies the patches to the current rule, setting the nodes that
+// changed and deleting the ones that were removed in a single commit.
+func (c *client) UpdateRule(ctx context.Context, current *types.NATRule, patches []jsonpatch.JsonPatchOperation) (*types.NATRule, error) {
+	if len(patches) == 0 {
+		return current, nil
+	}
+
+	// The priority is not part of the encoded rule, so a patch of it would
+	// otherwise be dropped without notice.
+	for _, patch := range patches {
+		if patch.Path == "/priority" {
+			return nil, fmt.Errorf("The priority of NAT rule %d cannot be patched, delete and recreate the rule instead.", current.Priority)
+		}
+	}
+
+	var rule types.NATRule
+	if err := utils.Patch(current, &rule, patches); err != nil {
+		return nil, err
+	}
+	rule.Priority = current.Priority
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureGroups(&rule, op); err != nil {
+		return nil, err
+	}
+
+	in := &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Service: ruleOf(&rule),
+			},
+		},
+	}
+
+	if stale := staleRule(current, &rule); stale != nil {
+		stale.SetOpMode(types.OpModeDelete)
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Service: ruleOf(stale),
+			},
+		}
+	}
+
+	if _, err := c.apiClient.Post(ctx, in); err != nil {
+		return nil, err
+	}
+	return c.GetRule(ctx, current.Priority)
+}
+
+func (c *client) DeleteRule(ctx context.Context, priority int) error {
+	if _, err := c.GetRule(ctx, priority); err != nil {
+		return err
+	}
+
+	rule := &types.NATRule{
+		Priority: priority,
+	}
+	rule.SetOpMode(types.OpModeDelete)
+
+	_, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Service: ruleOf(rule),
+			},
+		},
+	})
+	return err
+}
+
+// ruleOf returns a service holding only the rule.
+func ruleOf(r *types.NATRule) *types.Service {
+	return &types.Service{
+		NAT: &types.NAT{
+			Rules: []*types.NATRule{r},
+		},
+	}
+}
+
+func natOf(op *api.Operation) *types.NAT {
+	if op == nil || op.Get == nil || op.Get.Service == nil {
+		return nil
+	}
+	return op.Get.Service.NAT
+}
+
+func toRule(priority int, op *api.Operation) (*types.NATRule, error) {
+	nat := natOf(op)
+	if nat == nil || len(nat.Rules) == 0 {
+		return nil, errors.New("No NAT rules exist.")
+	}
+
+	for _, rule := range nat.Rules {
+		if rule != nil && rule.Priority == priority {
+			return rule, nil
+		}
+	}
+	return nil, fmt.Errorf("The NAT rule %d does not exist.", priority)
+}
+
+// ensureGroups returns an error if the rule refers to a group that does not
+// exist, as EdgeOS would otherwise reject the commit.
+func ensureGroups(r *types.NATRule, op *api.Operation) error {
+	groups := new(types.Groups)
+	if op != nil && op.Get != nil && op.Get.Firewall != nil && op.Get.Firewall.Groups != nil {
+		groups = op.Get.Firewall.Groups
+	}
+
+	address, port := r.Groups()
+	for _, name := range address {
+		if _, ok := groups.Address[name]; !ok {
+			return fmt.Errorf("The address group %s does not exist.", name)
+		}
+	}
+	for _, name := range port {
+		if _, ok := groups.Port[name]; !ok {
+			return fmt.Errorf("The port group %s does not exist.", name)
+		}
+	}
+	return nil
+}
+
+// staleRule returns a rule containing the nodes that are set in current but no
+// longer set in updated. Nodes that are merely changed are overwritten by the
+// SET operation and are not included. The translation of current is stale as a
+// whole when the type changes, as the node holding it changes too. If nothing
+// is stale, nil is returned.
+func staleRule(current, updated *types.NATRule) *types.NATRule {
+	stale := &types.NATRule{
+		Priority: current.Priority,
+		Type:     current.Type,
+	}
+	isStale := false
+
+	for _, leaf := range []struct {
+		current, updated string
+		stale            *string
+	}{
+		{current.Description, updated.Description, &stale.Description},
+		{current.InboundInterface, updated.InboundInterface, &stale.InboundInterface},
+		{current.OutboundInterface, updated.OutboundInterface, &stale.OutboundInterface},
+		{current.Protocol, updated.Protocol, &stale.Protocol},
+	} {
+		if leaf.current != "" && leaf.updated == "" {
+			*leaf.stale = leaf.current
+			isStale = true
+		}
+	}
+
+	if current.Log && !updated.Log {
+		stale.Log = true
+		isStale = true
+	}
+	if current.Exclude && !updated.Exclude {
+		stale.Exclude = true
+		isStale = true
+	}
+
+	if e := staleEndpoint(current.Source, updated.Source); e != nil {
+		stale.Source = e
+		isStale = true
+	}
+	if e := staleEndpoint(current.Destination, updated.Destination); e != nil {
+		stale.Destination = e
+		isStale = true
+	}
+
+	if t := current.Translation; t != nil {
+		var u types.NATTranslation
+		if updated.Translation != nil && updated.Type == current.Type {
+			u = *updated.Translation
+		}
+
+		s := new(types.NATTranslation)
+		if t.Address != "" && u.Address == "" {
+			s.Address = t.Address
+		}
+		if t.Port != nil && u.Port == nil {
+			s.Port = t.Port
+		}
+		if s.Address != "" || s.Port != nil {
+			stale.Translation = s
+			isStale = true
+		}
+	}
+
+	if !isStale {
+		return nil
+	}
+	return stale
+}
+
+func staleEndpoint(current, updated *types.NATEndpoint) *types.NATEndpoint {
+	if current == nil {
+		return nil
+	}
+
+	var u types.NATEndpoint
+	if updated != nil {
+		u = *updated
+	}
+
+	e := types.NATEndpoint{
+		Address:      staleString(current.Address, u.Address),
+		AddressGroup: staleString(current.AddressGroup, u.AddressGroup),
+		PortGroup:    staleString(current.PortGroup, u.PortGroup),
+	}
+	if current.Port != nil && u.Port == nil {
+		e.Port = current.Port
+	}
+
+	if e == (types.NATEndpoint{}) {
+		return nil
+	}
+	return &e
+}
+
+func staleString(current, updated *string) *string {
+	if current == nil || *current == "" {
+		return nil
+	}
+	if updated == nil || *updated == "" {
+		return current
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package nat
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	natConfig = `{"GET": {
+	"firewall": {"group": {
+		"address-group": {"servers": {"address": ["192.168.1.10"]}},
+		"port-group": {"web": {"port": ["80", "443"]}}
+	}},
+	"service": {"nat": {"rule": {
+		"5010": {"type": "masquerade", "description": "masquerade for WAN", "outbound-interface": "eth0", "log": "disable"},
+		"1": {"type": "destination", "description": "https", "inbound-interface": "eth0", "protocol": "tcp", "destination": {"address": "203.0.113.1", "port": "443"}, "inside-address": {"address": "192.168.1.10", "port": "8443"}, "log": "enable"},
+		"5000": {"type": "source", "outbound-interface": "eth0", "source": {"address": "192.168.1.0/24"}, "exclude": null}
+	}}}
+}, "success": true}`
+
+	createdRuleConfig = `{"GET": {"service": {"nat": {"rule": {
+	"10": {"type": "destination", "inbound-interface": "eth0", "protocol": "tcp", "destination": {"group": {"port-group": "web"}}, "inside-address": {"address": "192.168.1.10"}}
+}}}}, "success": true}`
+)
+
+func strptr(s string) *string {
+	return &s
+}
+
+func TestNATOperations(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		committed string
+		do        func(Client) error
+		expected  []string
+		err       string
+	}{
+		{
+			name: "get",
+			do: func(c Client) error {
+				r, err := c.GetRule(context.Background(), 1)
+				if err != nil {
+					return err
+				}
+				require.Equal(t, "203.0.113.1", *r.Destination.Address)
+				require.Equal(t, &types.PortRange{From: 443, To: 443}, r.Destination.Port)
+				require.Equal(t, &types.NATTranslation{Address: "192.168.1.10", Port: &types.PortRange{From: 8443, To: 8443}}, r.Translation)
+				require.True(t, r.Log)
+				return nil
+			},
+		},
+		{
+			name: "list",
+			do: func(c Client) error {
+				rules, err := c.ListRules(context.Background())
+				if err != nil {
+					return err
+				}
+				require.Len(t, rules, 3)
+				require.Equal(t, []int{1, 5000, 5010}, []int{rules[0].Priority, rules[1].Priority, rules[2].Priority})
+				require.True(t, rules[1].Exclude)
+				require.False(t, rules[2].Log)
+				return nil
+			},
+		},
+		{
+			name: "get a missing rule",
+			do: func(c Client) error {
+				_, err := c.GetRule(context.Background(), 20)
+				return err
+			},
+			err: "The NAT rule 20 does not exist.",
+		},
+		{
+			name:      "create",
+			committed: createdRuleConfig,
+			do: func(c Client) error {
+				_, err := c.CreateRule(context.Background(), &types.NATRule{
+					Priority:         10,
+					Type:             types.NATTypeDestination,
+					InboundInterface: "eth0",
+					Protocol:         "tcp",
+					Destination: &types.NATEndpoint{
+						PortGroup: strptr("web"),
+					},
+					Translation: &types.NATTranslation{
+						Address: "192.168.1.10",
+					},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"service":{"nat":{"rule":{"10":{"inside-address":{"address":"192.168.1.10"},"type":"destination","inbound-interface":"eth0","protocol":"tcp","destination":{"group":{"port-group":"web"}}}}}}}}`,
+			},
+		},
+		{
+			name: "create an existing rule",
+			do: func(c Client) error {
+				_, err := c.CreateRule(context.Background(), &types.NATRule{
+					Priority:          5010,
+					Type:              types.NATTypeMasquerade,
+					OutboundInterface: "eth1",
+				})
+				return err
+			},
+			err: "The NAT rule 5010 already exists.",
+		},
+		{
+			name: "create with a missing group",
+			do: func(c Client) error {
+				_, err := c.CreateRule(context.Background(), &types.NATRule{
+					Priority:          5020,
+					Type:              types.NATTypeSource,
+					OutboundInterface: "eth0",
+					Source: &types.NATEndpoint{
+						AddressGroup: strptr("printers"),
+					},
+					Translation: &types.NATTranslation{
+						Address: "203.0.113.2",
+					},
+				})
+				return err
+			},
+			err: "The address group printers does not exist.",
+		},
+		{
+			name: "create an invalid rule",
+			do: func(c Client) error {
+				_, err := c.CreateRule(context.Background(), &types.NATRule{
+					Priority:          20,
+					Type:              types.NATTypeDestination,
+					OutboundInterface: "eth0",
+					Destination: &types.NATEndpoint{
+						Port: &types.PortRange{From: 80, To: 80},
+					},
+				})
+				return err
+			},
+			err: `inbound-interface: is required for destination rules; outbound-interface: is not supported by destination rules; ` +
+				`destination.port: requires protocol tcp, udp, tcp_udp, got ""; inside-address.address: is required for destination rules that are not excluded`,
+		},
+		{
+			name: "update",
+			do: func(c Client) error {
+				current, err := c.GetRule(context.Background(), 1)
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateRule(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/description"},
+					{Operation: "remove", Path: "/destination/address"},
+					{Operation: "add", Path: "/destination/group", Value: map[string]string{"address-group": "servers"}},
+					{Operation: "remove", Path: "/inside-address/port"},
+					{Operation: "remove", Path: "/log"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"service":{"nat":{"rule":{"1":{"inside-address":{"address":"192.168.1.10"},"type":"destination","inbound-interface":"eth0","protocol":"tcp","destination":{"port":"443","group":{"address-group":"servers"}}}}}}},` +
+					`"DELETE":{"service":{"nat":{"rule":{"1":{"description":null,"destination":{"address":null},"inside-address":{"port":null},"log":null}}}}}}`,
+			},
+		},
+		{
+			name: "update the type",
+			do: func(c Client) error {
+				current, err := c.GetRule(context.Background(), 5010)
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateRule(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "replace", Path: "/type", Value: "source"},
+					{Operation: "add", Path: "/outside-address", Value: map[string]string{"address": "203.0.113.1"}},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"service":{"nat":{"rule":{"5010":{"outside-address":{"address":"203.0.113.1"},"type":"source","description":"masquerade for WAN","outbound-interface":"eth0"}}}}}}`,
+			},
+		},
+		{
+			name: "update the priority",
+			do: func(c Client) error {
+				current, err := c.GetRule(context.Background(), 1)
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateRule(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "add", Path: "/priority", Value: 2},
+				})
+				return err
+			},
+			err: "The priority of NAT rule 1 cannot be patched, delete and recreate the rule instead.",
+		},
+		{
+			name: "update to a missing group",
+			do: func(c Client) error {
+				current, err := c.GetRule(context.Background(), 5000)
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateRule(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "add", Path: "/destination", Value: map[string]interface{}{"group": map[string]string{"port-group": "mail"}}},
+					{Operation: "add", Path: "/protocol", Value: "tcp"},
+				})
+				return err
+			},
+			err: "The port group mail does not exist.",
+		},
+		{
+			name: "delete",
+			do: func(c Client) error {
+				return c.DeleteRule(context.Background(), 5000)
+			},
+			expected: []string{
+				`{"DELETE":{"service":{"nat":{"rule":{"5000":null}}}}}`,
+			},
+		},
+		{
+			name: "delete a missing rule",
+			do: func(c Client) error {
+				return c.DeleteRule(context.Background(), 20)
+			},
+			err: "The NAT rule 20 does not exist.",
+		},
+	} {
+		apiClient := &apitest.Client{Config: natConfig, Committed: test.committed}
+		err := test.do(&client{apiClient: apiClient})
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
@@ -0,0 +1,347 @@
+// Package ospf manages the OSPF instance of the router and its areas, and
+// reads the state of the adjacencies with the neighbors.
+package ospf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+const neighborCommand = "show ip ospf neighbor"
+
+type Client interface {
+	Get(context.Context) (*types.OSPF, error)
+	Update(context.Context, *types.OSPF, []jsonpatch.JsonPatchOperation) (*types.OSPF, error)
+	Delete(context.Context) error
+
+	GetArea(context.Context, string) (*types.OSPFArea, error)
+	ListAreas(context.Context) ([]*types.OSPFArea, error)
+	CreateArea(context.Context, *types.OSPFArea) (*types.OSPFArea, error)
+	UpdateArea(context.Context, *types.OSPFArea, []jsonpatch.JsonPatchOperation) (*types.OSPFArea, error)
+	DeleteArea(context.Context, string) error
+
+	NeighborStatus(context.Context) (types.OSPFNeighborStatuses, error)
+}
+
+type client struct {
+	apiClient     api.Client
+	commandClient api.CommandClient
+}
+
+// New returns a client that cannot read the state of the adjacencies, as that
+// requires running show commands.
+func New(httpClient *http.Client, host string) Client {
+	return NewWithAPIClient(api.New(httpClient, host), nil)
+}
+
+// NewWithAPIClient returns a client that reads the state of the adjacencies
+// through the command client, if set.
+func NewWithAPIClient(apiClient api.Client, commandClient api.CommandClient) Client {
+	return &client{
+		apiClient:     apiClient,
+		commandClient: commandClient,
+	}
+}
+
+// Get returns the OSPF instance including its areas. A router that does not
+// run OSPF returns an empty instance.
+func (c *client) Get(ctx context.Context) (*types.OSPF, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if o := ospfOf(op); o != nil {
+		return o, nil
+	}
+	return new(types.OSPF), nil
+}
+
+// Update applies the patches to the router ID, passive interfaces and
+// redistributions of the current instance, setting the ones that changed and
+// deleting the ones that were removed in a single commit. Patching an empty
+// instance enables OSPF. Areas are managed with the area methods.
+func (c *client) Update(ctx context.Context, current *types.OSPF, patches []jsonpatch.JsonPatchOperation) (*types.OSPF, error) {
+	var o types.OSPF
+	if err := utils.Patch(current.Properties(), &o, patches); err != nil {
+		return nil, err
+	}
+
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := new(api.Operation)
+
+	if !o.IsEmpty() {
+		in.Set = &api.Set{
+			Resources: api.Resources{
+				Protocols: &types.Protocols{OSPF: &o},
+			},
+		}
+	}
+
+	if stale := staleOSPF(current, &o); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Protocols: &types.Protocols{OSPF: stale},
+			},
+		}
+	}
+
+	if in.Set != nil || in.Delete != nil {
+		if _, err := c.apiClient.Post(ctx, in); err != nil {
+			return nil, err
+		}
+	}
+	return c.Get(ctx)
+}
+
+// Delete removes the OSPF instance along with its areas.
+func (c *client) Delete(ctx context.Context) error {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if ospfOf(op) == nil {
+		return errors.New("OSPF is not configured.")
+	}
+
+	// An instance without any property is deleted entirely.
+	o := new(types.OSPF)
+	o.SetOpMode(types.OpModeDelete)
+
+	_, err = c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Protocols: &types.Protocols{OSPF: o},
+			},
+		},
+	})
+	return err
+}
+
+func (c *client) GetArea(ctx context.Context, id string) (*types.OSPFArea, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toArea(id, op)
+}
+
+// ListAreas returns the areas ordered by ID.
+func (c *client) ListAreas(ctx context.Context) ([]*types.OSPFArea, error) {
+	o, err := c.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	areas := []*types.OSPFArea{}
+	for _, a := range o.Areas {
+		if a != nil {
+			areas = append(areas, a)
+		}
+	}
+	sort.Slice(areas, func(i, j int) bool {
+		return areas[i].ID < areas[j].ID
+	})
+	return areas, nil
+}
+
+// CreateArea creates the area, enabling OSPF if it is not running yet.
+func (c *client) CreateArea(ctx context.Context, a *types.OSPFArea) (*types.OSPFArea, error) {
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.GetArea(ctx, a.ID); err == nil {
+		return nil, fmt.Errorf("The OSPF area %s already exists.", a.ID)
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Protocols: areaOf(a.ID, a),
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.GetArea(ctx, a.ID)
+}
+
+// UpdateArea applies the patches to the current area, setting its networks and
+// deleting the ones that were removed in a single commit. The ID cannot be
+// patched.
+func (c *client) UpdateArea(ctx context.Context, current *types.OSPFArea, patches []jsonpatch.JsonPatchOperation) (*types.OSPFArea, error) {
+	var a types.OSPFArea
+	if err := utils.Patch(current, &a, patches); err != nil {
+		return nil, err
+	}
+	a.ID = current.ID
+
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Protocols: areaOf(a.ID, &a),
+			},
+		},
+	}
+
+	// An area without any network is deleted entirely, so only removed
+	// networks are encoded.
+	if stale := utils.StringSliceDiff(a.Networks, current.Networks); len(stale) > 0 {
+		networks := &types.OSPFArea{Networks: stale}
+		networks.SetOpMode(types.OpModeDelete)
+
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Protocols: areaOf(a.ID, networks),
+			},
+		}
+	}
+
+	if _, err := c.apiClient.Post(ctx, in); err != nil {
+		return nil, err
+	}
+	return c.GetArea(ctx, a.ID)
+}
+
+func (c *client) DeleteArea(ctx context.Context, id string) error {
+	if _, err := c.GetArea(ctx, id); err != nil {
+		return err
+	}
+
+	_, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Protocols: areaOf(id, nil),
+			},
+		},
+	})
+	return err
+}
+
+// NeighborStatus returns the adjacencies with the neighbors ordered by router
+// ID. A neighbor on several interfaces has an adjacency on each of them.
+func (c *client) NeighborStatus(ctx context.Context) (types.OSPFNeighborStatuses, error) {
+	if c.commandClient == nil {
+		return nil, errors.New("The client cannot run show commands.")
+	}
+
+	out, err := c.commandClient.Run(ctx, neighborCommand)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses types.OSPFNeighborStatuses
+	if err := statuses.UnmarshalText([]byte(out)); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+func ospfOf(op *api.Operation) *types.OSPF {
+	if op == nil || op.Get == nil || op.Get.Protocols == nil {
+		return nil
+	}
+	return op.Get.Protocols.OSPF
+}
+
+func toArea(id string, op *api.Operation) (*types.OSPFArea, error) {
+	o := ospfOf(op)
+	if o == nil || len(o.Areas) == 0 {
+		return nil, errors.New("No OSPF areas exist.")
+	}
+
+	a, ok := o.Areas[id]
+	if !ok || a == nil {
+		return nil, fmt.Errorf("The OSPF area %s does not exist.", id)
+	}
+	return a, nil
+}
+
+// areaOf returns protocols holding only the area.
+func areaOf(id string, a *types.OSPFArea) *types.Protocols {
+	return &types.Protocols{
+		OSPF: &types.OSPF{
+			Areas: map[string]*types.OSPFArea{
+				id: a,
+			},
+		},
+	}
+}
+
+// staleOSPF returns the properties that are set in current but not in
+// updated, encoded for deletion, or nil if there are none. Redistributions
+// that were removed are deleted as a whole.
+func staleOSPF(current, updated *types.OSPF) *types.OSPF {
+	stale := &types.OSPF{
+		PassiveInterfaces: utils.StringSliceDiff(updated.PassiveInterfaces, current.PassiveInterfaces),
+	}
+	if current.RouterID != "" && updated.RouterID == "" {
+		stale.RouterID = current.RouterID
+	}
+
+	if current.Redistribute != nil {
+		u := updated.Redistribute
+		if u == nil {
+			u = new(types.OSPFRedistribute)
+		}
+		r := &types.OSPFRedistribute{
+			Connected: staleRedistribution(current.Redistribute.Connected, u.Connected),
+			Static:    staleRedistribution(current.Redistribute.Static, u.Static),
+			Kernel:    staleRedistribution(current.Redistribute.Kernel, u.Kernel),
+			BGP:       staleRedistribution(current.Redistribute.BGP, u.BGP),
+		}
+		if !r.IsEmpty() {
+			stale.Redistribute = r
+		}
+	}
+
+	// An empty instance encodes as null and would delete OSPF.
+	if stale.IsEmpty() {
+		return nil
+	}
+	stale.SetOpMode(types.OpModeDelete)
+	return stale
+}
+
+// staleRedistribution returns the redistribution, which is deleted as a whole
+// when empty, if it was removed, or its properties that were removed.
+func staleRedistribution(current, updated *types.OSPFRedistribution) *types.OSPFRedistribution {
+	if current == nil {
+		return nil
+	}
+	if updated == nil {
+		return new(types.OSPFRedistribution)
+	}
+
+	stale := new(types.OSPFRedistribution)
+	if current.Metric != 0 && updated.Metric == 0 {
+		stale.Metric = current.Metric
+	}
+	if current.MetricType != 0 && updated.MetricType == 0 {
+		stale.MetricType = current.MetricType
+	}
+	if current.RouteMap != "" && updated.RouteMap == "" {
+		stale.RouteMap = current.RouteMap
+	}
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	return stale
+}
@@ -0,0 +1,280 @@
+package ospf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	ospfConfig = `{"GET": {"protocols": {"ospf": {
+	"parameters": {"router-id": "10.255.0.1"},
+	"passive-interface": ["eth0", "eth2"],
+	"redistribute": {
+		"connected": null,
+		"static": {"metric": "20", "metric-type": "1", "route-map": "STATIC"}
+	},
+	"area": {
+		"0.0.0.0": {"network": ["10.1.0.0/24", "10.2.0.0/24"]},
+		"1": null
+	}
+}}}, "success": true}`
+
+	emptyConfig = `{"GET": {}, "success": true}`
+
+	neighborOutput = `
+    Neighbor ID Pri State           Dead Time Address         Interface            RXmtL RqstL DBsmL
+10.255.0.3        1 ExStart/DROther   35.004s 10.2.0.3        eth2:10.2.0.1            0     0     0
+10.255.0.2        1 Full/DR           38.123s 10.1.0.2        eth1:10.1.0.1            0     0     0
+10.255.0.2        0 Full/-            31.500s 10.9.0.2        vtun0:10.9.0.1           0     0     0
+`
+)
+
+func TestOperations(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		config    string
+		committed string
+		do        func(Client) error
+		expected  []string
+		err       string
+	}{
+		{
+			name: "get",
+			do: func(c Client) error {
+				o, err := c.Get(context.Background())
+				if err != nil {
+					return err
+				}
+				require.Equal(t, "10.255.0.1", o.RouterID)
+				require.Equal(t, []string{"eth0", "eth2"}, o.PassiveInterfaces)
+				require.Equal(t, &types.OSPFRedistribution{}, o.Redistribute.Connected)
+				require.Equal(t, 20, o.Redistribute.Static.Metric)
+				require.Equal(t, 1, o.Redistribute.Static.MetricType)
+				require.Nil(t, o.Redistribute.Kernel)
+				return nil
+			},
+		},
+		{
+			name:      "enable",
+			config:    emptyConfig,
+			committed: ospfConfig,
+			do: func(c Client) error {
+				current, err := c.Get(context.Background())
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "add", Path: "/parameters", Value: map[string]string{"router-id": "10.255.0.1"}},
+					{Operation: "add", Path: "/redistribute", Value: map[string]interface{}{"connected": nil}},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"protocols":{"ospf":{"parameters":{"router-id":"10.255.0.1"},"redistribute":{"connected":null}}}}}`,
+			},
+		},
+		{
+			name: "update",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background())
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/passive-interface/1"},
+					{Operation: "remove", Path: "/redistribute/connected"},
+					{Operation: "remove", Path: "/redistribute/static/route-map"},
+					{Operation: "add", Path: "/redistribute/bgp", Value: map[string]string{"metric-type": "1"}},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"protocols":{"ospf":{"parameters":{"router-id":"10.255.0.1"},"passive-interface":["eth0"],` +
+					`"redistribute":{"static":{"metric":"20","metric-type":"1"},"bgp":{"metric-type":"1"}}}}},` +
+					`"DELETE":{"protocols":{"ospf":{"passive-interface":["eth2"],"redistribute":{"connected":null,"static":{"route-map":null}}}}}}`,
+			},
+		},
+		{
+			name: "update with an invalid redistribution",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background())
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "add", Path: "/parameters/router-id", Value: "router"},
+					{Operation: "add", Path: "/redistribute/kernel", Value: map[string]string{"metric": "16777215", "metric-type": "3"}},
+				})
+				return err
+			},
+			err: `parameters.router-id: "router" is not a valid IPv4 address; ` +
+				`redistribute.kernel.metric: 16777215 must be between 0 and 16777214; redistribute.kernel.metric-type: 3 must be 1 or 2`,
+		},
+		{
+			name: "delete",
+			do: func(c Client) error {
+				return c.Delete(context.Background())
+			},
+			expected: []string{
+				`{"DELETE":{"protocols":{"ospf":null}}}`,
+			},
+		},
+		{
+			name:   "delete without OSPF",
+			config: emptyConfig,
+			do: func(c Client) error {
+				return c.Delete(context.Background())
+			},
+			err: "OSPF is not configured.",
+		},
+	} {
+		config := ospfConfig
+		if test.config != "" {
+			config = test.config
+		}
+		apiClient := &apitest.Client{Config: config, Committed: test.committed}
+		err := test.do(NewWithAPIClient(apiClient, apiClient))
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
+
+func TestAreaOperations(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		config    string
+		committed string
+		do        func(Client) error
+		expected  []string
+		err       string
+	}{
+		{
+			name: "list",
+			do: func(c Client) error {
+				areas, err := c.ListAreas(context.Background())
+				if err != nil {
+					return err
+				}
+				require.Equal(t, []*types.OSPFArea{
+					{ID: "0.0.0.0", Networks: []string{"10.1.0.0/24", "10.2.0.0/24"}},
+					{ID: "1"},
+				}, areas)
+				return nil
+			},
+		},
+		{
+			name:   "get without OSPF",
+			config: emptyConfig,
+			do: func(c Client) error {
+				_, err := c.GetArea(context.Background(), "0.0.0.0")
+				return err
+			},
+			err: "No OSPF areas exist.",
+		},
+		{
+			name:      "create",
+			config:    emptyConfig,
+			committed: ospfConfig,
+			do: func(c Client) error {
+				_, err := c.CreateArea(context.Background(), &types.OSPFArea{
+					ID:       "0.0.0.0",
+					Networks: []string{"10.1.0.0/24"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"protocols":{"ospf":{"area":{"0.0.0.0":{"network":["10.1.0.0/24"]}}}}}}`,
+			},
+		},
+		{
+			name: "create an existing area",
+			do: func(c Client) error {
+				_, err := c.CreateArea(context.Background(), &types.OSPFArea{ID: "1"})
+				return err
+			},
+			err: "The OSPF area 1 already exists.",
+		},
+		{
+			name: "create an invalid area",
+			do: func(c Client) error {
+				_, err := c.CreateArea(context.Background(), &types.OSPFArea{
+					ID:       "backbone",
+					Networks: []string{"10.3.0.1/24"},
+				})
+				return err
+			},
+			err: `id: "backbone" must be an IPv4 address or a number; network[0]: "10.3.0.1/24" has host bits set, use 10.3.0.0/24`,
+		},
+		{
+			name: "update",
+			do: func(c Client) error {
+				current, err := c.GetArea(context.Background(), "0.0.0.0")
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateArea(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "replace", Path: "/network/0", Value: "10.3.0.0/24"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"protocols":{"ospf":{"area":{"0.0.0.0":{"network":["10.3.0.0/24","10.2.0.0/24"]}}}}},` +
+					`"DELETE":{"protocols":{"ospf":{"area":{"0.0.0.0":{"network":["10.1.0.0/24"]}}}}}}`,
+			},
+		},
+		{
+			name: "delete",
+			do: func(c Client) error {
+				return c.DeleteArea(context.Background(), "1")
+			},
+			expected: []string{
+				`{"DELETE":{"protocols":{"ospf":{"area":{"1":null}}}}}`,
+			},
+		},
+	} {
+		config := ospfConfig
+		if test.config != "" {
+			config = test.config
+		}
+		apiClient := &apitest.Client{Config: config, Committed: test.committed}
+		err := test.do(NewWithAPIClient(apiClient, apiClient))
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
+
+func TestNeighborStatus(t *testing.T) {
+	apiClient := &apitest.Client{Config: ospfConfig, Outputs: map[string]string{
+		neighborCommand: neighborOutput,
+	}}
+
+	statuses, err := NewWithAPIClient(apiClient, apiClient).NeighborStatus(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, types.OSPFNeighborStatuses{
+		{RouterID: "10.255.0.2", Priority: 1, State: "Full", Role: "DR", DeadTime: "38.123s", Address: "10.1.0.2", Interface: "eth1"},
+		{RouterID: "10.255.0.2", State: "Full", DeadTime: "31.500s", Address: "10.9.0.2", Interface: "vtun0"},
+		{RouterID: "10.255.0.3", Priority: 1, State: "ExStart", Role: "DROther", DeadTime: "35.004s", Address: "10.2.0.3", Interface: "eth2"},
+	}, statuses)
+	require.Len(t, statuses.Lookup("10.255.0.2"), 2)
+	require.False(t, statuses.Lookup("10.255.0.3")[0].IsFull())
+}
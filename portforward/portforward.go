@@ -0,0 +1,238 @@
+// Package portforward manages the simplified port forwarding of the router
+// and reports the firewall rules EdgeOS generates for it.
+package portforward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+type Client interface {
+	Get(context.Context) (*types.PortForward, error)
+	Update(context.Context, *types.PortForward, []jsonpatch.JsonPatchOperation) (*types.PortForward, error)
+
+	GetForward(context.Context, int) (*types.PortForwardRule, error)
+	AddForward(context.Context, *types.PortForwardRule) (*types.PortForwardRule, error)
+	RemoveForward(context.Context, int) error
+
+	FirewallRules(context.Context) ([]*types.Rule, error)
+}
+
+type client struct {
+	apiClient api.Client
+}
+
+func New(httpClient *http.Client, host string) Client {
+	return &client{
+		apiClient: api.New(httpClient, host),
+	}
+}
+
+// Get returns the port forwarding along with its rules. A router without port
+// forwarding returns an empty port forwarding.
+func (c *client) Get(ctx context.Context) (*types.PortForward, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if op == nil || op.Get == nil || op.Get.PortForward == nil {
+		return new(types.PortForward), nil
+	}
+	return op.Get.PortForward, nil
+}
+
+// Update applies the patches to the interfaces, hairpin NAT and auto firewall
+// of the current port forwarding, setting the properties that changed and
+// deleting the ones that were removed in a single commit. Rules are managed
+// with AddForward and RemoveForward.
+func (c *client) Update(ctx context.Context, current *types.PortForward, patches []jsonpatch.JsonPatchOperation) (*types.PortForward, error) {
+	var p types.PortForward
+	if err := utils.Patch(current.Properties(), &p, patches); err != nil {
+		return nil, err
+	}
+
+	// The rules are validated too, as they require the interfaces.
+	tmp := p
+	tmp.Rules = current.Rules
+	if err := tmp.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := new(api.Operation)
+
+	if !p.IsEmpty() {
+		in.Set = &api.Set{
+			Resources: api.Resources{
+				PortForward: &p,
+			},
+		}
+	}
+
+	if stale := staleProperties(current, &p); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				PortForward: stale,
+			},
+		}
+	}
+
+	if in.Set != nil || in.Delete != nil {
+		if _, err := c.apiClient.Post(ctx, in); err != nil {
+			return nil, err
+		}
+	}
+	return c.Get(ctx)
+}
+
+func (c *client) GetForward(ctx context.Context, number int) (*types.PortForwardRule, error) {
+	p, err := c.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toRule(number, p)
+}
+
+// AddForward adds the rule after ensuring port forwarding has its interfaces
+// and that no other rule forwards the same original port.
+func (c *client) AddForward(ctx context.Context, r *types.PortForwardRule) (*types.PortForwardRule, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	p, err := c.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.IsConfigured() {
+		return nil, errors.New("Port forwarding needs a WAN and a LAN interface before forwards can be added.")
+	}
+
+	for _, rule := range p.Rules {
+		if rule.Number == r.Number {
+			return nil, fmt.Errorf("The port forward rule %d already exists.", r.Number)
+		}
+		if overlaps(rule, r) {
+			return nil, fmt.Errorf("The original port %s is already forwarded by rule %d.", portString(r.OriginalPort), rule.Number)
+		}
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				PortForward: &types.PortForward{
+					Rules: []*types.PortForwardRule{r},
+				},
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.GetForward(ctx, r.Number)
+}
+
+func (c *client) RemoveForward(ctx context.Context, number int) error {
+	if _, err := c.GetForward(ctx, number); err != nil {
+		return err
+	}
+
+	del := &types.PortForward{
+		Rules: []*types.PortForwardRule{
+			{Number: number},
+		},
+	}
+	del.SetOpMode(types.OpModeDelete)
+
+	_, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				PortForward: del,
+			},
+		},
+	})
+	return err
+}
+
+// FirewallRules returns the implicit firewall rules EdgeOS generates for the
+// forwards when the auto firewall is enabled, ordered by rule number. They are
+// not part of any ruleset and cannot be changed.
+func (c *client) FirewallRules(ctx context.Context) ([]*types.Rule, error) {
+	p, err := c.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return p.FirewallRules(), nil
+}
+
+func toRule(number int, p *types.PortForward) (*types.PortForwardRule, error) {
+	if len(p.Rules) == 0 {
+		return nil, errors.New("No port forward rules exist.")
+	}
+
+	rule := p.Rule(number)
+	if rule == nil {
+		return nil, fmt.Errorf("The port forward rule %d does not exist.", number)
+	}
+	return rule, nil
+}
+
+// overlaps reports whether both rules forward a shared original port of a
+// shared protocol.
+func overlaps(a, b *types.PortForwardRule) bool {
+	if a.OriginalPort == nil || b.OriginalPort == nil {
+		return false
+	}
+	if a.OriginalPort.To < b.OriginalPort.From || b.OriginalPort.To < a.OriginalPort.From {
+		return false
+	}
+	return protocolsOverlap(a.Protocol, b.Protocol)
+}
+
+// protocolsOverlap reports whether two protocols share tcp or udp. An empty
+// protocol is the default of tcp_udp.
+func protocolsOverlap(a, b string) bool {
+	if a == "" || a == "tcp_udp" || b == "" || b == "tcp_udp" {
+		return true
+	}
+	return a == b
+}
+
+func portString(r *types.PortRange) string {
+	if r.From == r.To {
+		return fmt.Sprintf("%d", r.From)
+	}
+	return fmt.Sprintf("%d-%d", r.From, r.To)
+}
+
+// staleProperties returns the properties that are set in current but not in
+// updated, encoded for deletion, or nil if there are none.
+func staleProperties(current, updated *types.PortForward) *types.PortForward {
+	stale := new(types.PortForward)
+	stale.SetOpMode(types.OpModeDelete)
+
+	if current.WANInterface != "" && updated.WANInterface == "" {
+		stale.WANInterface = current.WANInterface
+	}
+	if current.HairpinNAT != nil && updated.HairpinNAT == nil {
+		stale.HairpinNAT = current.HairpinNAT
+	}
+	if current.AutoFirewall != nil && updated.AutoFirewall == nil {
+		stale.AutoFirewall = current.AutoFirewall
+	}
+	if diff := utils.StringSliceDiff(updated.LANInterfaces, current.LANInterfaces); len(diff) > 0 {
+		stale.LANInterfaces = diff
+	}
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	return stale
+}
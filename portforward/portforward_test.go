@@ -0,0 +1,222 @@
+package portforward
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	portForwardConfig = `{"GET": {"port-forward": {
+	"auto-firewall": "enable",
+	"hairpin-nat": "disable",
+	"lan-interface": ["eth1", "switch0"],
+	"wan-interface": "eth0",
+	"rule": {
+		"2": {"description": "web", "forward-to": {"address": "192.168.1.10", "port": "8080-8081"}, "original-port": "80-81", "protocol": "tcp"},
+		"1": {"forward-to": {"address": "192.168.1.20"}, "original-port": "2222"}
+	}
+}}, "success": true}`
+
+	addedForwardConfig = `{"GET": {"port-forward": {
+	"lan-interface": ["eth1"],
+	"wan-interface": "eth0",
+	"rule": {"3": {"forward-to": {"address": "192.168.1.30"}, "original-port": "53", "protocol": "udp"}}
+}}, "success": true}`
+)
+
+func TestPortForwardOperations(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		config    string
+		committed string
+		do        func(Client) error
+		expected  []string
+		err       string
+	}{
+		{
+			name: "get",
+			do: func(c Client) error {
+				p, err := c.Get(context.Background())
+				if err != nil {
+					return err
+				}
+				require.True(t, *p.AutoFirewall)
+				require.False(t, *p.HairpinNAT)
+				require.Len(t, p.Rules, 2)
+				require.Equal(t, 1, p.Rules[0].Number)
+				require.Equal(t, &types.PortRange{From: 8080, To: 8081}, p.Rules[1].ForwardTo.Port)
+				return nil
+			},
+		},
+		{
+			name:   "get without port forwarding",
+			config: `{"GET": {}, "success": true}`,
+			do: func(c Client) error {
+				p, err := c.Get(context.Background())
+				if err != nil {
+					return err
+				}
+				require.True(t, p.IsEmpty())
+				require.Empty(t, p.FirewallRules())
+				return nil
+			},
+		},
+		{
+			name: "firewall rules",
+			do: func(c Client) error {
+				rules, err := c.FirewallRules(context.Background())
+				if err != nil {
+					return err
+				}
+				require.Len(t, rules, 2)
+				require.Equal(t, "port-forward rule 1", *rules[0].Description)
+				require.Equal(t, "tcp_udp", rules[0].Protocol)
+				require.Equal(t, "192.168.1.20", *rules[0].Destination.Address)
+				require.Equal(t, &types.PortRange{From: 2222, To: 2222}, rules[0].Destination.Port)
+				require.Equal(t, "port-forward rule 2: web", *rules[1].Description)
+				require.Equal(t, &types.PortRange{From: 8080, To: 8081}, rules[1].Destination.Port)
+				require.NoError(t, rules[1].Validate())
+				return nil
+			},
+		},
+		{
+			name: "update",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background())
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "replace", Path: "/hairpin-nat", Value: "enable"},
+					{Operation: "remove", Path: "/auto-firewall"},
+					{Operation: "remove", Path: "/lan-interface/1"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"port-forward":{"hairpin-nat":"enable","wan-interface":"eth0","lan-interface":["eth1"]}},` +
+					`"DELETE":{"port-forward":{"auto-firewall":null,"lan-interface":["switch0"]}}}`,
+			},
+		},
+		{
+			name: "update removes an interface the rules need",
+			do: func(c Client) error {
+				current, err := c.Get(context.Background())
+				if err != nil {
+					return err
+				}
+				_, err = c.Update(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/wan-interface"},
+				})
+				return err
+			},
+			err: "wan-interface: is required when rules are defined",
+		},
+		{
+			name:      "add",
+			config:    `{"GET": {"port-forward": {"lan-interface": ["eth1"], "wan-interface": "eth0"}}, "success": true}`,
+			committed: addedForwardConfig,
+			do: func(c Client) error {
+				_, err := c.AddForward(context.Background(), &types.PortForwardRule{
+					Number:       3,
+					OriginalPort: &types.PortRange{From: 53, To: 53},
+					Protocol:     "udp",
+					ForwardTo: &types.PortForwardTarget{
+						Address: "192.168.1.30",
+					},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"port-forward":{"rule":{"3":{"original-port":"53","protocol":"udp","forward-to":{"address":"192.168.1.30"}}}}}}`,
+			},
+		},
+		{
+			name: "add an overlapping forward",
+			do: func(c Client) error {
+				_, err := c.AddForward(context.Background(), &types.PortForwardRule{
+					Number:       3,
+					OriginalPort: &types.PortRange{From: 81, To: 90},
+					Protocol:     "tcp",
+					ForwardTo: &types.PortForwardTarget{
+						Address: "192.168.1.30",
+					},
+				})
+				return err
+			},
+			err: "The original port 81-90 is already forwarded by rule 2.",
+		},
+		{
+			name:   "add without interfaces",
+			config: `{"GET": {}, "success": true}`,
+			do: func(c Client) error {
+				_, err := c.AddForward(context.Background(), &types.PortForwardRule{
+					Number:       1,
+					OriginalPort: &types.PortRange{From: 443, To: 443},
+					ForwardTo: &types.PortForwardTarget{
+						Address: "192.168.1.10",
+					},
+				})
+				return err
+			},
+			err: "Port forwarding needs a WAN and a LAN interface before forwards can be added.",
+		},
+		{
+			name: "add an invalid forward",
+			do: func(c Client) error {
+				_, err := c.AddForward(context.Background(), &types.PortForwardRule{
+					Number:       4,
+					OriginalPort: &types.PortRange{From: 1000, To: 1010},
+					Protocol:     "icmp",
+					ForwardTo: &types.PortForwardTarget{
+						Address: "192.168.1.0/24",
+						Port:    &types.PortRange{From: 2000, To: 2001},
+					},
+				})
+				return err
+			},
+			err: `protocol: "icmp" must be one of tcp, udp, tcp_udp; ` +
+				`forward-to.address: "192.168.1.0/24" is not a valid IPv4 address; ` +
+				`forward-to.port: range 2000-2001 must be as long as the original port range 1000-1010`,
+		},
+		{
+			name: "remove",
+			do: func(c Client) error {
+				return c.RemoveForward(context.Background(), 2)
+			},
+			expected: []string{
+				`{"DELETE":{"port-forward":{"rule":{"2":null}}}}`,
+			},
+		},
+		{
+			name: "remove a missing forward",
+			do: func(c Client) error {
+				return c.RemoveForward(context.Background(), 5)
+			},
+			err: "The port forward rule 5 does not exist.",
+		},
+	} {
+		config := test.config
+		if config == "" {
+			config = portForwardConfig
+		}
+
+		apiClient := &apitest.Client{Config: config, Committed: test.committed}
+		err := test.do(&client{apiClient: apiClient})
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
@@ -0,0 +1,589 @@
+// Package routes manages the static routes and interface routes of the main
+// routing table and of the additional tables used for policy based routing,
+// and reads the routing table of the router to verify they are installed.
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api"
+	"github.com/frankgreco/edge-sdk-go/internal/utils"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+const routesData = "routes"
+
+// Client manages routes by table and destination, where table 0 is the main
+// routing table.
+type Client interface {
+	GetRoute(context.Context, int, string) (*types.StaticRoute, error)
+	ListRoutes(context.Context, int) ([]*types.StaticRoute, error)
+	CreateRoute(context.Context, *types.StaticRoute) (*types.StaticRoute, error)
+	UpdateRoute(context.Context, *types.StaticRoute, []jsonpatch.JsonPatchOperation) (*types.StaticRoute, error)
+	DeleteRoute(context.Context, int, string) error
+
+	GetInterfaceRoute(context.Context, int, string) (*types.InterfaceRoute, error)
+	ListInterfaceRoutes(context.Context, int) ([]*types.InterfaceRoute, error)
+	CreateInterfaceRoute(context.Context, *types.InterfaceRoute) (*types.InterfaceRoute, error)
+	UpdateInterfaceRoute(context.Context, *types.InterfaceRoute, []jsonpatch.JsonPatchOperation) (*types.InterfaceRoute, error)
+	DeleteInterfaceRoute(context.Context, int, string) error
+
+	GetTable(context.Context, int) (*types.RoutingTable, error)
+	ListTables(context.Context) ([]*types.RoutingTable, error)
+	UpdateTable(context.Context, *types.RoutingTable, []jsonpatch.JsonPatchOperation) (*types.RoutingTable, error)
+	DeleteTable(context.Context, int) error
+
+	KernelRoutes(context.Context) (types.KernelRoutes, error)
+}
+
+type client struct {
+	apiClient  api.Client
+	dataClient api.DataClient
+}
+
+func New(httpClient *http.Client, host string) Client {
+	return NewWithAPIClient(api.New(httpClient, host))
+}
+
+// NewWithAPIClient is used by clients that manage routes on behalf of their
+// own resources. The routing table is read through the API client if it is
+// also a api.DataClient.
+func NewWithAPIClient(apiClient api.Client) Client {
+	dataClient, _ := apiClient.(api.DataClient)
+	return &client{
+		apiClient:  apiClient,
+		dataClient: dataClient,
+	}
+}
+
+func (c *client) GetRoute(ctx context.Context, table int, dst string) (*types.StaticRoute, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	routes, _, err := routesOf(table, op)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := routes[dst]
+	if !ok || r == nil {
+		return nil, fmt.Errorf("The static route %s does not exist%s.", dst, inTable(table))
+	}
+	return r, nil
+}
+
+// ListRoutes returns the static routes of the table ordered by destination.
+func (c *client) ListRoutes(ctx context.Context, table int) ([]*types.StaticRoute, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	routes, _, err := routesOf(table, op)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []*types.StaticRoute{}
+	for _, r := range routes {
+		if r != nil {
+			list = append(list, r)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Destination < list[j].Destination
+	})
+	return list, nil
+}
+
+// CreateRoute creates the route in its table, creating the table if it does
+// not exist yet.
+func (c *client) CreateRoute(ctx context.Context, r *types.StaticRoute) (*types.StaticRoute, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.GetRoute(ctx, r.Table, r.Destination); err == nil {
+		return nil, fmt.Errorf("The static route %s already exists%s.", r.Destination, inTable(r.Table))
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Protocols: routeOf(r.Table, r.Destination, r),
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.GetRoute(ctx, r.Table, r.Destination)
+}
+
+// UpdateRoute applies the patches to the current route, setting the next hops
+// that changed and deleting the ones that were removed in a single commit.
+// The destination and table cannot be patched.
+func (c *client) UpdateRoute(ctx context.Context, current *types.StaticRoute, patches []jsonpatch.JsonPatchOperation) (*types.StaticRoute, error) {
+	var r types.StaticRoute
+	if err := utils.Patch(current, &r, patches); err != nil {
+		return nil, err
+	}
+	r.Destination = current.Destination
+	r.Table = current.Table
+
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Protocols: routeOf(r.Table, r.Destination, &r),
+			},
+		},
+	}
+
+	if stale := staleRoute(current, &r); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Protocols: routeOf(r.Table, r.Destination, stale),
+			},
+		}
+	}
+
+	if _, err := c.apiClient.Post(ctx, in); err != nil {
+		return nil, err
+	}
+	return c.GetRoute(ctx, r.Table, r.Destination)
+}
+
+func (c *client) DeleteRoute(ctx context.Context, table int, dst string) error {
+	if _, err := c.GetRoute(ctx, table, dst); err != nil {
+		return err
+	}
+
+	_, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Protocols: routeOf(table, dst, nil),
+			},
+		},
+	})
+	return err
+}
+
+func (c *client) GetInterfaceRoute(ctx context.Context, table int, dst string) (*types.InterfaceRoute, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, routes, err := routesOf(table, op)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := routes[dst]
+	if !ok || r == nil {
+		return nil, fmt.Errorf("The interface route %s does not exist%s.", dst, inTable(table))
+	}
+	return r, nil
+}
+
+// ListInterfaceRoutes returns the interface routes of the table ordered by
+// destination.
+func (c *client) ListInterfaceRoutes(ctx context.Context, table int) ([]*types.InterfaceRoute, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, routes, err := routesOf(table, op)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []*types.InterfaceRoute{}
+	for _, r := range routes {
+		if r != nil {
+			list = append(list, r)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Destination < list[j].Destination
+	})
+	return list, nil
+}
+
+// CreateInterfaceRoute creates the route in its table, creating the table if
+// it does not exist yet.
+func (c *client) CreateInterfaceRoute(ctx context.Context, r *types.InterfaceRoute) (*types.InterfaceRoute, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.GetInterfaceRoute(ctx, r.Table, r.Destination); err == nil {
+		return nil, fmt.Errorf("The interface route %s already exists%s.", r.Destination, inTable(r.Table))
+	}
+
+	if _, err := c.apiClient.Post(ctx, &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Protocols: interfaceRouteOf(r.Table, r.Destination, r),
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return c.GetInterfaceRoute(ctx, r.Table, r.Destination)
+}
+
+// UpdateInterfaceRoute applies the patches to the current route, setting the
+// interfaces that changed and deleting the ones that were removed in a single
+// commit. The destination and table cannot be patched.
+func (c *client) UpdateInterfaceRoute(ctx context.Context, current *types.InterfaceRoute, patches []jsonpatch.JsonPatchOperation) (*types.InterfaceRoute, error) {
+	var r types.InterfaceRoute
+	if err := utils.Patch(current, &r, patches); err != nil {
+		return nil, err
+	}
+	r.Destination = current.Destination
+	r.Table = current.Table
+
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := &api.Operation{
+		Set: &api.Set{
+			Resources: api.Resources{
+				Protocols: interfaceRouteOf(r.Table, r.Destination, &r),
+			},
+		},
+	}
+
+	if stale := staleInterfaceRoute(current, &r); stale != nil {
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Protocols: interfaceRouteOf(r.Table, r.Destination, stale),
+			},
+		}
+	}
+
+	if _, err := c.apiClient.Post(ctx, in); err != nil {
+		return nil, err
+	}
+	return c.GetInterfaceRoute(ctx, r.Table, r.Destination)
+}
+
+func (c *client) DeleteInterfaceRoute(ctx context.Context, table int, dst string) error {
+	if _, err := c.GetInterfaceRoute(ctx, table, dst); err != nil {
+		return err
+	}
+
+	_, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Protocols: interfaceRouteOf(table, dst, nil),
+			},
+		},
+	})
+	return err
+}
+
+func (c *client) GetTable(ctx context.Context, id int) (*types.RoutingTable, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toTable(id, op)
+}
+
+// ListTables returns the additional routing tables ordered by number.
+func (c *client) ListTables(ctx context.Context) ([]*types.RoutingTable, error) {
+	op, err := c.apiClient.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := []*types.RoutingTable{}
+	if s := staticOf(op); s != nil {
+		for _, t := range s.Tables {
+			if t != nil {
+				tables = append(tables, t)
+			}
+		}
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].ID < tables[j].ID
+	})
+	return tables, nil
+}
+
+// UpdateTable applies the patches to the description of the current table.
+// Routes are managed with the route methods.
+func (c *client) UpdateTable(ctx context.Context, current *types.RoutingTable, patches []jsonpatch.JsonPatchOperation) (*types.RoutingTable, error) {
+	var t types.RoutingTable
+	if err := utils.Patch(current.Properties(), &t, patches); err != nil {
+		return nil, err
+	}
+	t.ID = current.ID
+
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	in := new(api.Operation)
+
+	if t.Description != "" {
+		in.Set = &api.Set{
+			Resources: api.Resources{
+				Protocols: tableOf(t.ID, &t),
+			},
+		}
+	} else if current.Description != "" {
+		stale := &types.RoutingTable{
+			Description: current.Description,
+		}
+		stale.SetOpMode(types.OpModeDelete)
+		in.Delete = &api.Delete{
+			Resources: api.Resources{
+				Protocols: tableOf(t.ID, stale),
+			},
+		}
+	}
+
+	if in.Set != nil || in.Delete != nil {
+		if _, err := c.apiClient.Post(ctx, in); err != nil {
+			return nil, err
+		}
+	}
+	return c.GetTable(ctx, t.ID)
+}
+
+// DeleteTable removes the table along with its routes.
+func (c *client) DeleteTable(ctx context.Context, id int) error {
+	if _, err := c.GetTable(ctx, id); err != nil {
+		return err
+	}
+
+	_, err := c.apiClient.Post(ctx, &api.Operation{
+		Delete: &api.Delete{
+			Resources: api.Resources{
+				Protocols: tableOf(id, nil),
+			},
+		},
+	})
+	return err
+}
+
+// KernelRoutes returns the routing table of the router, ordered by
+// destination. It reports the routes that are actually in use, including
+// connected and dynamic routes, rather than the configured ones.
+func (c *client) KernelRoutes(ctx context.Context) (types.KernelRoutes, error) {
+	if c.dataClient == nil {
+		return nil, errors.New("The API client cannot read operational data.")
+	}
+
+	var routes types.KernelRoutes
+	if err := c.dataClient.Data(ctx, routesData, &routes); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+func staticOf(op *api.Operation) *types.StaticRouting {
+	if op == nil || op.Get == nil || op.Get.Protocols == nil {
+		return nil
+	}
+	return op.Get.Protocols.Static
+}
+
+// routesOf returns the routes of the table. The main table always exists,
+// even without any route.
+func routesOf(table int, op *api.Operation) (map[string]*types.StaticRoute, map[string]*types.InterfaceRoute, error) {
+	if table == 0 {
+		s := staticOf(op)
+		if s == nil {
+			return nil, nil, nil
+		}
+		return s.Routes, s.InterfaceRoutes, nil
+	}
+
+	t, err := toTable(table, op)
+	if err != nil {
+		return nil, nil, err
+	}
+	return t.Routes, t.InterfaceRoutes, nil
+}
+
+func toTable(id int, op *api.Operation) (*types.RoutingTable, error) {
+	s := staticOf(op)
+	if s == nil || len(s.Tables) == 0 {
+		return nil, errors.New("No routing tables exist.")
+	}
+
+	t, ok := s.Tables[strconv.Itoa(id)]
+	if !ok || t == nil {
+		return nil, fmt.Errorf("The routing table %d does not exist.", id)
+	}
+	return t, nil
+}
+
+// inTable names the table in errors about routes of additional tables.
+func inTable(table int) string {
+	if table == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" in table %d", table)
+}
+
+// tableOf returns protocols holding only the table.
+func tableOf(id int, t *types.RoutingTable) *types.Protocols {
+	return &types.Protocols{
+		Static: &types.StaticRouting{
+			Tables: map[string]*types.RoutingTable{
+				strconv.Itoa(id): t,
+			},
+		},
+	}
+}
+
+// routeOf returns protocols holding only the route of the table.
+func routeOf(table int, dst string, r *types.StaticRoute) *types.Protocols {
+	routes := map[string]*types.StaticRoute{
+		dst: r,
+	}
+	if table == 0 {
+		return &types.Protocols{
+			Static: &types.StaticRouting{
+				Routes: routes,
+			},
+		}
+	}
+	return tableOf(table, &types.RoutingTable{
+		Routes: routes,
+	})
+}
+
+// interfaceRouteOf returns protocols holding only the interface route of the
+// table.
+func interfaceRouteOf(table int, dst string, r *types.InterfaceRoute) *types.Protocols {
+	routes := map[string]*types.InterfaceRoute{
+		dst: r,
+	}
+	if table == 0 {
+		return &types.Protocols{
+			Static: &types.StaticRouting{
+				InterfaceRoutes: routes,
+			},
+		}
+	}
+	return tableOf(table, &types.RoutingTable{
+		InterfaceRoutes: routes,
+	})
+}
+
+// staleRoute returns the nodes that are set in current but not in updated,
+// encoded for deletion, or nil if there are none. Next hops and a blackhole
+// that were removed are deleted as a whole.
+func staleRoute(current, updated *types.StaticRoute) *types.StaticRoute {
+	stale := &types.StaticRoute{
+		NextHops: map[string]*types.NextHop{},
+	}
+
+	if current.Description != "" && updated.Description == "" {
+		stale.Description = current.Description
+	}
+
+	for addr, hop := range current.NextHops {
+		u, ok := updated.NextHops[addr]
+		if !ok || u == nil {
+			stale.NextHops[addr] = new(types.NextHop)
+			continue
+		}
+		s := &types.NextHop{
+			Description: staleString(hop.Description, u.Description),
+			Distance:    staleInt(hop.Distance, u.Distance),
+			Disable:     hop.Disable && !u.Disable,
+		}
+		if !s.IsEmpty() {
+			stale.NextHops[addr] = s
+		}
+	}
+	if len(stale.NextHops) == 0 {
+		stale.NextHops = nil
+	}
+
+	if b := current.Blackhole; b != nil {
+		if updated.Blackhole == nil {
+			stale.Blackhole = new(types.Blackhole)
+		} else if s := (&types.Blackhole{
+			Description: staleString(b.Description, updated.Blackhole.Description),
+			Distance:    staleInt(b.Distance, updated.Blackhole.Distance),
+		}); !s.IsEmpty() {
+			stale.Blackhole = s
+		}
+	}
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	stale.SetOpMode(types.OpModeDelete)
+	return stale
+}
+
+// staleInterfaceRoute is staleRoute for interface routes.
+func staleInterfaceRoute(current, updated *types.InterfaceRoute) *types.InterfaceRoute {
+	stale := &types.InterfaceRoute{
+		NextHops: map[string]*types.NextHopInterface{},
+	}
+
+	if current.Description != "" && updated.Description == "" {
+		stale.Description = current.Description
+	}
+
+	for iface, hop := range current.NextHops {
+		u, ok := updated.NextHops[iface]
+		if !ok || u == nil {
+			stale.NextHops[iface] = new(types.NextHopInterface)
+			continue
+		}
+		s := &types.NextHopInterface{
+			Description: staleString(hop.Description, u.Description),
+			Distance:    staleInt(hop.Distance, u.Distance),
+			Disable:     hop.Disable && !u.Disable,
+		}
+		if !s.IsEmpty() {
+			stale.NextHops[iface] = s
+		}
+	}
+	if len(stale.NextHops) == 0 {
+		stale.NextHops = nil
+	}
+
+	if stale.IsEmpty() {
+		return nil
+	}
+	stale.SetOpMode(types.OpModeDelete)
+	return stale
+}
+
+func staleString(current, updated string) string {
+	if current != "" && updated == "" {
+		return current
+	}
+	return ""
+}
+
+func staleInt(current, updated int) int {
+	if current != 0 && updated == 0 {
+		return current
+	}
+	return 0
+}
@@ -0,0 +1,286 @@
+package routes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frankgreco/edge-sdk-go/internal/api/apitest"
+	"github.com/frankgreco/edge-sdk-go/types"
+
+	"github.com/mattbaird/jsonpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	routesConfig = `{"GET": {"protocols": {"static": {
+	"route": {
+		"0.0.0.0/0": {"next-hop": {"203.0.113.1": {"distance": "1"}, "198.51.100.1": {"distance": "10", "description": "backup"}}},
+		"10.0.0.0/8": {"description": "bogons", "blackhole": null}
+	},
+	"interface-route": {
+		"10.10.0.0/16": {"next-hop-interface": {"wg0": null}}
+	},
+	"table": {
+		"10": {"description": "via vpn", "route": {"0.0.0.0/0": {"next-hop": {"10.8.0.1": null}}}}
+	}
+}}}, "success": true}`
+
+	createdRouteConfig = `{"GET": {"protocols": {"static": {"table": {
+	"20": {"route": {"192.168.50.0/24": {"next-hop": {"192.168.1.2": {"distance": "5"}}}}}
+}}}}, "success": true}`
+
+	createdInterfaceRouteConfig = `{"GET": {"protocols": {"static": {"table": {
+	"10": {"interface-route": {"10.20.0.0/16": {"next-hop-interface": {"vtun0": null}}}}
+}}}}, "success": true}`
+)
+
+func TestRouteOperations(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		committed string
+		do        func(Client) error
+		expected  []string
+		err       string
+	}{
+		{
+			name: "get",
+			do: func(c Client) error {
+				r, err := c.GetRoute(context.Background(), 0, "0.0.0.0/0")
+				if err != nil {
+					return err
+				}
+				require.Equal(t, 10, r.NextHops["198.51.100.1"].Distance)
+				require.Equal(t, "198.51.100.1", r.NextHops["198.51.100.1"].Address)
+
+				r, err = c.GetRoute(context.Background(), 0, "10.0.0.0/8")
+				if err != nil {
+					return err
+				}
+				require.Equal(t, &types.Blackhole{}, r.Blackhole)
+				return nil
+			},
+		},
+		{
+			name: "list the routes of a table",
+			do: func(c Client) error {
+				routes, err := c.ListRoutes(context.Background(), 10)
+				if err != nil {
+					return err
+				}
+				require.Len(t, routes, 1)
+				require.Equal(t, 10, routes[0].Table)
+				require.Equal(t, "10.8.0.1", routes[0].NextHops["10.8.0.1"].Address)
+				return nil
+			},
+		},
+		{
+			name: "get a route of a missing table",
+			do: func(c Client) error {
+				_, err := c.GetRoute(context.Background(), 30, "0.0.0.0/0")
+				return err
+			},
+			err: "The routing table 30 does not exist.",
+		},
+		{
+			name:      "create in a new table",
+			committed: createdRouteConfig,
+			do: func(c Client) error {
+				_, err := c.CreateRoute(context.Background(), &types.StaticRoute{
+					Destination: "192.168.50.0/24",
+					Table:       20,
+					NextHops: map[string]*types.NextHop{
+						"192.168.1.2": {Distance: 5},
+					},
+				})
+				if err == nil {
+					// The main table does not exist in the committed config
+					// and behaves like an empty table.
+					_, err = c.ListRoutes(context.Background(), 0)
+				}
+				return err
+			},
+			expected: []string{
+				`{"SET":{"protocols":{"static":{"table":{"20":{"route":{"192.168.50.0/24":{"next-hop":{"192.168.1.2":{"distance":"5"}}}}}}}}}}`,
+			},
+		},
+		{
+			name: "create an existing route",
+			do: func(c Client) error {
+				_, err := c.CreateRoute(context.Background(), &types.StaticRoute{
+					Destination: "0.0.0.0/0",
+					Table:       10,
+					Blackhole:   &types.Blackhole{},
+				})
+				return err
+			},
+			err: "The static route 0.0.0.0/0 already exists in table 10.",
+		},
+		{
+			name: "create an invalid route",
+			do: func(c Client) error {
+				_, err := c.CreateRoute(context.Background(), &types.StaticRoute{
+					Destination: "192.168.1.1/24",
+					Table:       300,
+					NextHops: map[string]*types.NextHop{
+						"gateway": {Distance: 256},
+					},
+					Blackhole: &types.Blackhole{},
+				})
+				return err
+			},
+			err: `destination: "192.168.1.1/24" has host bits set, use 192.168.1.0/24; table: 300 must be between 1 and 250; ` +
+				`blackhole: must not be set together with next-hop; next-hop[gateway]: "gateway" is not a valid IPv4 address; ` +
+				`next-hop[gateway].distance: 256 must be between 1 and 255`,
+		},
+		{
+			name: "update",
+			do: func(c Client) error {
+				current, err := c.GetRoute(context.Background(), 0, "0.0.0.0/0")
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateRoute(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/next-hop/203.0.113.1"},
+					{Operation: "remove", Path: "/next-hop/198.51.100.1/description"},
+					{Operation: "add", Path: "/next-hop/192.0.2.1", Value: map[string]string{"distance": "5"}},
+					{Operation: "add", Path: "/description", Value: "default"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"protocols":{"static":{"route":{"0.0.0.0/0":{"description":"default","next-hop":{"192.0.2.1":{"distance":"5"},"198.51.100.1":{"distance":"10"}}}}}}},` +
+					`"DELETE":{"protocols":{"static":{"route":{"0.0.0.0/0":{"next-hop":{"198.51.100.1":{"description":null},"203.0.113.1":null}}}}}}}`,
+			},
+		},
+		{
+			name: "turn a route into a blackhole",
+			do: func(c Client) error {
+				current, err := c.GetRoute(context.Background(), 10, "0.0.0.0/0")
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateRoute(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/next-hop"},
+					{Operation: "add", Path: "/blackhole", Value: map[string]string{"distance": "250"}},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"protocols":{"static":{"table":{"10":{"route":{"0.0.0.0/0":{"blackhole":{"distance":"250"}}}}}}}},` +
+					`"DELETE":{"protocols":{"static":{"table":{"10":{"route":{"0.0.0.0/0":{"next-hop":{"10.8.0.1":null}}}}}}}}}`,
+			},
+		},
+		{
+			name: "delete",
+			do: func(c Client) error {
+				return c.DeleteRoute(context.Background(), 0, "10.0.0.0/8")
+			},
+			expected: []string{
+				`{"DELETE":{"protocols":{"static":{"route":{"10.0.0.0/8":null}}}}}`,
+			},
+		},
+		{
+			name: "delete a missing route",
+			do: func(c Client) error {
+				return c.DeleteRoute(context.Background(), 0, "172.16.0.0/12")
+			},
+			err: "The static route 172.16.0.0/12 does not exist.",
+		},
+		{
+			name: "update an interface route",
+			do: func(c Client) error {
+				current, err := c.GetInterfaceRoute(context.Background(), 0, "10.10.0.0/16")
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateInterfaceRoute(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/next-hop-interface/wg0"},
+					{Operation: "add", Path: "/next-hop-interface/wg1", Value: map[string]interface{}{"disable": nil}},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"protocols":{"static":{"interface-route":{"10.10.0.0/16":{"next-hop-interface":{"wg1":{"disable":null}}}}}}},` +
+					`"DELETE":{"protocols":{"static":{"interface-route":{"10.10.0.0/16":{"next-hop-interface":{"wg0":null}}}}}}}`,
+			},
+		},
+		{
+			name:      "create an interface route",
+			committed: createdInterfaceRouteConfig,
+			do: func(c Client) error {
+				_, err := c.CreateInterfaceRoute(context.Background(), &types.InterfaceRoute{
+					Destination: "10.20.0.0/16",
+					Table:       10,
+					NextHops: map[string]*types.NextHopInterface{
+						"vtun0": {},
+					},
+				})
+				return err
+			},
+			expected: []string{
+				`{"SET":{"protocols":{"static":{"table":{"10":{"interface-route":{"10.20.0.0/16":{"next-hop-interface":{"vtun0":null}}}}}}}}}`,
+			},
+		},
+		{
+			name: "update a table",
+			do: func(c Client) error {
+				current, err := c.GetTable(context.Background(), 10)
+				if err != nil {
+					return err
+				}
+				_, err = c.UpdateTable(context.Background(), current, []jsonpatch.JsonPatchOperation{
+					{Operation: "remove", Path: "/description"},
+				})
+				return err
+			},
+			expected: []string{
+				`{"DELETE":{"protocols":{"static":{"table":{"10":{"description":null}}}}}}`,
+			},
+		},
+		{
+			name: "delete a table",
+			do: func(c Client) error {
+				return c.DeleteTable(context.Background(), 10)
+			},
+			expected: []string{
+				`{"DELETE":{"protocols":{"static":{"table":{"10":null}}}}}`,
+			},
+		},
+	} {
+		apiClient := &apitest.Client{Config: routesConfig, Committed: test.committed}
+		err := test.do(NewWithAPIClient(apiClient))
+
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.name)
+			require.Empty(t, apiClient.Posted, test.name)
+			continue
+		}
+
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, apiClient.Posted, test.name)
+	}
+}
+
+func TestKernelRoutes(t *testing.T) {
+	c := NewWithAPIClient(&apitest.Client{Config: routesConfig, DataSources: map[string]string{
+		"routes": `[
+	{"pfx": "192.168.1.0/24", "nh": [{"t": "C>*", "intf": "eth1"}]},
+	{"pfx": "10.0.0.0/8", "nh": [{"t": "S>*", "intf": "Null0"}]},
+	{"pfx": "0.0.0.0/0", "nh": [{"t": "S>*", "via": "203.0.113.1", "intf": "eth0"}, {"t": "S", "via": "198.51.100.1", "intf": "eth2"}]},
+	{"pfx": "10.0.0.0/24", "nh": [{"t": "O>*", "via": "10.1.0.2", "intf": "eth3"}]}
+]`,
+	}})
+
+	routes, err := c.KernelRoutes(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, types.KernelRoutes{
+		{Destination: "0.0.0.0/0", Protocol: "static", NextHop: "203.0.113.1", Interface: "eth0", Selected: true, Installed: true},
+		{Destination: "0.0.0.0/0", Protocol: "static", NextHop: "198.51.100.1", Interface: "eth2"},
+		{Destination: "10.0.0.0/8", Protocol: "static", Interface: "Null0", Selected: true, Installed: true},
+		{Destination: "10.0.0.0/24", Protocol: "ospf", NextHop: "10.1.0.2", Interface: "eth3", Selected: true, Installed: true},
+		{Destination: "192.168.1.0/24", Protocol: "connected", Interface: "eth1", Selected: true, Installed: true},
+	}, routes)
+
+	require.Len(t, routes.Lookup("0.0.0.0/0"), 1)
+	require.Empty(t, routes.Lookup("172.16.0.0/12"))
+}
@@ -0,0 +1,73 @@
+package types
+
+// BGP is the BGP instance of the router, e.g. protocols bgp 65000. EdgeOS runs
+// a single instance, which is identified by the local AS number. Networks are
+// the prefixes announced to the neighbors, which are keyed by address.
+type BGP struct {
+	ASN       int                     `json:"-" tfsdk:"asn"`
+	RouterID  string                  `json:"-" tfsdk:"router_id"`
+	Networks  []string                `json:"-" tfsdk:"networks"`
+	Neighbors map[string]*BGPNeighbor `json:"neighbor,omitempty" tfsdk:"-"`
+	opMode    OpMode
+}
+
+// BGPNeighbor is a peer of the BGP instance, e.g. protocols bgp 65000 neighbor
+// 10.0.0.2. It is an external peer unless its AS is the local one. Shutdown
+// peers are configured but no session is established with them.
+type BGPNeighbor struct {
+	Address      string     `json:"-" tfsdk:"address"`
+	RemoteAS     int        `json:"-" tfsdk:"remote_as"`
+	Description  string     `json:"description,omitempty" tfsdk:"description"`
+	UpdateSource string     `json:"update-source,omitempty" tfsdk:"update_source"`
+	Shutdown     bool       `json:"-" tfsdk:"shutdown"`
+	RouteMap     *BGPPolicy `json:"route-map,omitempty" tfsdk:"route_map"`
+	PrefixList   *BGPPolicy `json:"prefix-list,omitempty" tfsdk:"prefix_list"`
+	opMode       OpMode
+}
+
+// BGPPolicy names the route maps or prefix lists that filter the routes
+// received from a neighbor (import) and announced to it (export).
+type BGPPolicy struct {
+	Import string `json:"import,omitempty" tfsdk:"import"`
+	Export string `json:"export,omitempty" tfsdk:"export"`
+}
+
+// SetOpMode controls how the instance is encoded. When set to OpModeDelete, a
+// set router ID names a node to delete and networks are deleted by value.
+// Neighbors are never encoded in this mode.
+func (b *BGP) SetOpMode(m OpMode) {
+	(*b).opMode = m
+}
+
+// Properties returns a copy of the instance without its neighbors.
+func (b *BGP) Properties() *BGP {
+	tmp := *b
+	tmp.Neighbors = nil
+	return &tmp
+}
+
+// IsEmpty reports whether none of the properties of the instance are set.
+func (b *BGP) IsEmpty() bool {
+	return b.RouterID == "" && len(b.Networks) == 0 && len(b.Neighbors) == 0
+}
+
+// SetOpMode controls how the neighbor is encoded. When set to OpModeDelete,
+// every set property names a node to delete. A neighbor without any property
+// is deleted entirely.
+func (n *BGPNeighbor) SetOpMode(m OpMode) {
+	(*n).opMode = m
+}
+
+// IsEmpty reports whether none of the properties of the neighbor are set.
+func (n *BGPNeighbor) IsEmpty() bool {
+	return n.RemoteAS == 0 &&
+		n.Description == "" &&
+		n.UpdateSource == "" &&
+		!n.Shutdown &&
+		n.RouteMap.isEmpty() &&
+		n.PrefixList.isEmpty()
+}
+
+func (p *BGPPolicy) isEmpty() bool {
+	return p == nil || (p.Import == "" && p.Export == "")
+}
@@ -0,0 +1,167 @@
+package types
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+type bgpParameters struct {
+	RouterID string `json:"router-id,omitempty"`
+}
+
+func (b *BGP) MarshalJSON() ([]byte, error) {
+	if b.opMode == OpModeDelete {
+		return json.Marshal(b.deleteNodes())
+	}
+
+	var params *bgpParameters
+	if b.RouterID != "" {
+		params = &bgpParameters{RouterID: b.RouterID}
+	}
+
+	type Alias BGP
+	return json.Marshal(&struct {
+		Parameters *bgpParameters         `json:"parameters,omitempty"`
+		Networks   map[string]interface{} `json:"network,omitempty"`
+		*Alias
+	}{
+		Parameters: params,
+		Networks:   networkNodes(b.Networks),
+		Alias:      (*Alias)(b),
+	})
+}
+
+func (b *BGP) UnmarshalJSON(data []byte) error {
+	type Alias BGP
+	aux := &struct {
+		Parameters *bgpParameters             `json:"parameters"`
+		Networks   map[string]json.RawMessage `json:"network"`
+		*Alias
+	}{
+		Alias: (*Alias)(b),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.Parameters != nil {
+		b.RouterID = aux.Parameters.RouterID
+	}
+
+	b.Networks = nil
+	for n := range aux.Networks {
+		b.Networks = append(b.Networks, n)
+	}
+	sort.Strings(b.Networks)
+
+	for addr, n := range b.Neighbors {
+		if n != nil {
+			n.Address = addr
+		}
+	}
+	return nil
+}
+
+// deleteNodes returns the nodes of the instance that should be deleted.
+func (b *BGP) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if b.RouterID != "" {
+		nodes["parameters"] = map[string]interface{}{
+			"router-id": nil,
+		}
+	}
+	if len(b.Networks) > 0 {
+		nodes["network"] = networkNodes(b.Networks)
+	}
+
+	return nodes
+}
+
+// networkNodes encodes networks, which are nodes without any property, e.g.
+// network 10.0.0.0/24 { }.
+func networkNodes(networks []string) map[string]interface{} {
+	if len(networks) == 0 {
+		return nil
+	}
+	nodes := map[string]interface{}{}
+	for _, n := range networks {
+		nodes[n] = nil
+	}
+	return nodes
+}
+
+func (n *BGPNeighbor) MarshalJSON() ([]byte, error) {
+	if n.opMode == OpModeDelete {
+		if n.IsEmpty() {
+			return []byte("null"), nil
+		}
+		return json.Marshal(n.deleteNodes())
+	}
+
+	type Alias BGPNeighbor
+	return json.Marshal(&struct {
+		RemoteAS string `json:"remote-as,omitempty"`
+		Shutdown *null  `json:"shutdown,omitempty"`
+		*Alias
+	}{
+		RemoteAS: itoa(n.RemoteAS),
+		Shutdown: flag(n.Shutdown),
+		Alias:    (*Alias)(n),
+	})
+}
+
+func (n *BGPNeighbor) UnmarshalJSON(data []byte) (err error) {
+	type Alias BGPNeighbor
+	aux := &struct {
+		RemoteAS string `json:"remote-as"`
+		Shutdown null   `json:"shutdown"`
+		*Alias
+	}{
+		Alias: (*Alias)(n),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	n.Shutdown = aux.Shutdown.val
+	n.RemoteAS, err = atoi("remote-as", aux.RemoteAS)
+	return err
+}
+
+// deleteNodes returns the nodes of the neighbor that should be deleted.
+func (n *BGPNeighbor) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if n.RemoteAS != 0 {
+		nodes["remote-as"] = nil
+	}
+	if n.Description != "" {
+		nodes["description"] = nil
+	}
+	if n.UpdateSource != "" {
+		nodes["update-source"] = nil
+	}
+	if n.Shutdown {
+		nodes["shutdown"] = nil
+	}
+	if !n.RouteMap.isEmpty() {
+		nodes["route-map"] = n.RouteMap.deleteNodes()
+	}
+	if !n.PrefixList.isEmpty() {
+		nodes["prefix-list"] = n.PrefixList.deleteNodes()
+	}
+
+	return nodes
+}
+
+func (p *BGPPolicy) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+	if p.Import != "" {
+		nodes["import"] = nil
+	}
+	if p.Export != "" {
+		nodes["export"] = nil
+	}
+	return nodes
+}
@@ -0,0 +1,37 @@
+package types
+
+// BGPNeighborEstablished is the state of a session that exchanges routes.
+const BGPNeighborEstablished = "Established"
+
+// BGPNeighborStatus is the session with a BGP neighbor, as reported by show ip
+// bgp summary. State is the state of the session, e.g. Active or Idle, and
+// Uptime is how long it has been in that state, e.g. 01:55:03 or never.
+// Prefixes are only received on established sessions.
+type BGPNeighborStatus struct {
+	Address          string `json:"address"`
+	RemoteAS         int    `json:"remote-as"`
+	State            string `json:"state"`
+	Uptime           string `json:"uptime"`
+	PrefixesReceived int    `json:"prefixes-received"`
+	MessagesReceived int    `json:"messages-received"`
+	MessagesSent     int    `json:"messages-sent"`
+}
+
+// BGPNeighborStatuses is the output of show ip bgp summary, ordered by
+// address.
+type BGPNeighborStatuses []*BGPNeighborStatus
+
+// IsEstablished reports whether routes are exchanged with the neighbor.
+func (s *BGPNeighborStatus) IsEstablished() bool {
+	return s.State == BGPNeighborEstablished
+}
+
+// Lookup returns the status of the neighbor, or nil if it is not configured.
+func (statuses BGPNeighborStatuses) Lookup(address string) *BGPNeighborStatus {
+	for _, s := range statuses {
+		if s.Address == address {
+			return s
+		}
+	}
+	return nil
+}
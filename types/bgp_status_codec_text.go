@@ -0,0 +1,89 @@
+package types
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UnmarshalText decodes the output of show ip bgp summary. Every neighbor is a
+// row of the table following the header starting with Neighbor, e.g.
+//
+//	Neighbor        V    AS MsgRcvd MsgSent   TblVer  InQ OutQ Up/Down  State/PfxRcd
+//	10.0.0.2        4 65001     120     118        5    0    0 01:55:03        3
+//	10.0.0.3        4 65002       0       0        0    0    0 never    Active
+//
+// where the last column is the number of received prefixes if the session is
+// established and the state of the session otherwise.
+func (statuses *BGPNeighborStatuses) UnmarshalText(text []byte) error {
+	out := BGPNeighborStatuses{}
+
+	inTable := false
+	scanner := bufio.NewScanner(bytes.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !inTable {
+			inTable = strings.HasPrefix(line, "Neighbor")
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "Total") {
+			break
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 10 {
+			return fmt.Errorf("malformed BGP neighbor: %s", line)
+		}
+
+		s := &BGPNeighborStatus{
+			Address: fields[0],
+			Uptime:  fields[8],
+		}
+		for _, n := range []struct {
+			val string
+			i   *int
+		}{
+			{fields[2], &s.RemoteAS},
+			{fields[3], &s.MessagesReceived},
+			{fields[4], &s.MessagesSent},
+		} {
+			i, err := strconv.Atoi(n.val)
+			if err != nil {
+				return fmt.Errorf("malformed BGP neighbor: %s", line)
+			}
+			*n.i = i
+		}
+
+		if prefixes, err := strconv.Atoi(fields[9]); err == nil {
+			s.State = BGPNeighborEstablished
+			s.PrefixesReceived = prefixes
+		} else {
+			// The state of shutdown neighbors is Idle (Admin).
+			s.State = strings.Join(fields[9:], " ")
+		}
+		out = append(out, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return lessAddress(out[i].Address, out[j].Address)
+	})
+	*statuses = out
+	return nil
+}
+
+// lessAddress orders IPv4 and IPv6 addresses numerically, falling back to text
+// for anything that is not an address.
+func lessAddress(a, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return a < b
+	}
+	return bytes.Compare(ipA.To16(), ipB.To16()) < 0
+}
@@ -0,0 +1,88 @@
+package types
+
+import (
+	"fmt"
+	"net"
+	"sort"
+)
+
+// AS numbers are 4 bytes, whose upper bound overflows an int on 32 bit
+// platforms.
+const (
+	minASN int64 = 1
+	maxASN int64 = 4294967295
+)
+
+// Validate checks the AS number, router ID and networks of the instance as
+// well as its neighbors.
+func (b *BGP) Validate() error {
+	v := new(validator)
+	validateASN(v, "asn", b.ASN)
+
+	if b.RouterID != "" && !isIPv4(b.RouterID) {
+		v.add("parameters.router-id", "%q is not a valid IPv4 address", b.RouterID)
+	}
+	for i, n := range b.Networks {
+		validateNetwork(v, fmt.Sprintf("network[%d]", i), n)
+	}
+
+	addrs := make([]string, 0, len(b.Neighbors))
+	for addr := range b.Neighbors {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	for _, addr := range addrs {
+		if n := b.Neighbors[addr]; n != nil {
+			validateNeighbor(v, fmt.Sprintf("neighbor[%s]", addr), addr, n)
+		}
+	}
+	return v.err()
+}
+
+// Validate checks the address, remote AS and policies of the neighbor. The
+// update source is either a local address or an interface.
+func (n *BGPNeighbor) Validate() error {
+	v := new(validator)
+	validateNeighbor(v, "", n.Address, n)
+	return v.err()
+}
+
+func validateNeighbor(v *validator, prefix, addr string, n *BGPNeighbor) {
+	if net.ParseIP(addr) == nil {
+		v.add(join(prefix, "address"), "%q is not a valid IP address", addr)
+	}
+
+	if n.RemoteAS == 0 {
+		v.add(join(prefix, "remote-as"), "must be set")
+	} else {
+		validateASN(v, join(prefix, "remote-as"), n.RemoteAS)
+	}
+
+	if n.UpdateSource != "" && net.ParseIP(n.UpdateSource) == nil {
+		v.validateName(join(prefix, "update-source"), n.UpdateSource)
+	}
+
+	for _, policy := range []struct {
+		field string
+		p     *BGPPolicy
+	}{
+		{"route-map", n.RouteMap},
+		{"prefix-list", n.PrefixList},
+	} {
+		if policy.p == nil {
+			continue
+		}
+		if policy.p.Import != "" {
+			v.validateName(join(prefix, policy.field+".import"), policy.p.Import)
+		}
+		if policy.p.Export != "" {
+			v.validateName(join(prefix, policy.field+".export"), policy.p.Export)
+		}
+	}
+}
+
+func validateASN(v *validator, field string, asn int) {
+	if int64(asn) < minASN || int64(asn) > maxASN {
+		v.add(field, "%d must be between %d and %d", asn, minASN, maxASN)
+	}
+}
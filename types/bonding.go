@@ -0,0 +1,53 @@
+package types
+
+// Bonding is a link aggregation interface, e.g. interfaces bonding bond0.
+// Members are the ethernet interfaces whose bond-group names the bond; they
+// are not part of the bonding node itself.
+type Bonding struct {
+	ID          string              `json:"-" tfsdk:"id"`
+	Addresses   []string            `json:"address,omitempty" tfsdk:"-"`
+	Description string              `json:"description,omitempty" tfsdk:"-"`
+	DHCPOptions *DHCPOptions        `json:"dhcp-options,omitempty" tfsdk:"-"`
+	Disable     bool                `json:"-" tfsdk:"-"`
+	Mode        string              `json:"mode,omitempty" tfsdk:"-"`
+	HashPolicy  string              `json:"hash-policy,omitempty" tfsdk:"-"`
+	Primary     string              `json:"primary,omitempty" tfsdk:"-"`
+	MTU         int                 `json:"-" tfsdk:"-"`
+	Members     []string            `json:"-" tfsdk:"-"`
+	Firewall    *FirewallAttachment `json:"firewall,omitempty" tfsdk:"-"`
+	VIF         map[string]*VIF     `json:"vif,omitempty" tfsdk:"-"`
+	opMode      OpMode
+}
+
+func (b *Bonding) GetID() string {
+	return b.ID
+}
+
+// SetOpMode controls how the bond is encoded. When set to OpModeDelete, every
+// set property names a node to delete, and addresses are deleted by value.
+func (b *Bonding) SetOpMode(m OpMode) {
+	(*b).opMode = m
+}
+
+// Properties returns a copy of the bond without its members, firewall
+// attachment and VLANs.
+func (b *Bonding) Properties() *Bonding {
+	tmp := *b
+	tmp.Members = nil
+	tmp.Firewall = nil
+	tmp.VIF = nil
+	return &tmp
+}
+
+// IsEmpty reports whether none of the properties of the bond are set. The
+// members, firewall attachment and VLANs are not considered.
+func (b *Bonding) IsEmpty() bool {
+	return len(b.Addresses) == 0 &&
+		b.Description == "" &&
+		b.DHCPOptions == nil &&
+		!b.Disable &&
+		b.Mode == "" &&
+		b.HashPolicy == "" &&
+		b.Primary == "" &&
+		b.MTU == 0
+}
@@ -0,0 +1,70 @@
+package types
+
+import "encoding/json"
+
+func (b *Bonding) MarshalJSON() ([]byte, error) {
+	if b.opMode == OpModeDelete {
+		return json.Marshal(b.deleteNodes())
+	}
+
+	type Alias Bonding
+	return json.Marshal(&struct {
+		Disable *null  `json:"disable,omitempty"`
+		MTU     string `json:"mtu,omitempty"`
+		*Alias
+	}{
+		Disable: flag(b.Disable),
+		MTU:     itoa(b.MTU),
+		Alias:   (*Alias)(b),
+	})
+}
+
+func (b *Bonding) UnmarshalJSON(data []byte) (err error) {
+	type Alias Bonding
+	aux := &struct {
+		Disable null   `json:"disable"`
+		MTU     string `json:"mtu,omitempty"`
+		*Alias
+	}{
+		Alias: (*Alias)(b),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	b.Disable = aux.Disable.val
+	b.MTU, err = atoi("mtu", aux.MTU)
+	return err
+}
+
+// deleteNodes returns the nodes of the bond that should be deleted.
+func (b *Bonding) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if len(b.Addresses) > 0 {
+		nodes["address"] = b.Addresses
+	}
+	if b.Description != "" {
+		nodes["description"] = nil
+	}
+	if b.Disable {
+		nodes["disable"] = nil
+	}
+	if b.Mode != "" {
+		nodes["mode"] = nil
+	}
+	if b.HashPolicy != "" {
+		nodes["hash-policy"] = nil
+	}
+	if b.Primary != "" {
+		nodes["primary"] = nil
+	}
+	if b.MTU != 0 {
+		nodes["mtu"] = nil
+	}
+	if b.DHCPOptions != nil {
+		nodes["dhcp-options"] = b.DHCPOptions.deleteNodes()
+	}
+
+	return nodes
+}
@@ -0,0 +1,52 @@
+package types
+
+// Bridge is a bridge interface, e.g. interfaces bridge br0. Members are the
+// ethernet interfaces whose bridge-group names the bridge; they are not part
+// of the bridge node itself.
+type Bridge struct {
+	ID          string              `json:"-" tfsdk:"id"`
+	Addresses   []string            `json:"address,omitempty" tfsdk:"-"`
+	Description string              `json:"description,omitempty" tfsdk:"-"`
+	DHCPOptions *DHCPOptions        `json:"dhcp-options,omitempty" tfsdk:"-"`
+	Disable     bool                `json:"-" tfsdk:"-"`
+	STP         *bool               `json:"-" tfsdk:"-"`
+	Aging       int                 `json:"-" tfsdk:"-"`
+	Priority    int                 `json:"-" tfsdk:"-"`
+	Members     []string            `json:"-" tfsdk:"-"`
+	Firewall    *FirewallAttachment `json:"firewall,omitempty" tfsdk:"-"`
+	VIF         map[string]*VIF     `json:"vif,omitempty" tfsdk:"-"`
+	opMode      OpMode
+}
+
+func (b *Bridge) GetID() string {
+	return b.ID
+}
+
+// SetOpMode controls how the bridge is encoded. When set to OpModeDelete,
+// every set property names a node to delete, and addresses are deleted by
+// value.
+func (b *Bridge) SetOpMode(m OpMode) {
+	(*b).opMode = m
+}
+
+// Properties returns a copy of the bridge without its members, firewall
+// attachment and VLANs.
+func (b *Bridge) Properties() *Bridge {
+	tmp := *b
+	tmp.Members = nil
+	tmp.Firewall = nil
+	tmp.VIF = nil
+	return &tmp
+}
+
+// IsEmpty reports whether none of the properties of the bridge are set. The
+// members, firewall attachment and VLANs are not considered.
+func (b *Bridge) IsEmpty() bool {
+	return len(b.Addresses) == 0 &&
+		b.Description == "" &&
+		b.DHCPOptions == nil &&
+		!b.Disable &&
+		b.STP == nil &&
+		b.Aging == 0 &&
+		b.Priority == 0
+}
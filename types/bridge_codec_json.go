@@ -0,0 +1,93 @@
+package types
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+func (b *Bridge) MarshalJSON() ([]byte, error) {
+	if b.opMode == OpModeDelete {
+		return json.Marshal(b.deleteNodes())
+	}
+
+	var stp string
+	{
+		if b.STP != nil {
+			stp = strconv.FormatBool(*b.STP)
+		}
+	}
+
+	type Alias Bridge
+	return json.Marshal(&struct {
+		Aging    string `json:"aging,omitempty"`
+		Disable  *null  `json:"disable,omitempty"`
+		Priority string `json:"priority,omitempty"`
+		STP      string `json:"stp,omitempty"`
+		*Alias
+	}{
+		Aging:    itoa(b.Aging),
+		Disable:  flag(b.Disable),
+		Priority: itoa(b.Priority),
+		STP:      stp,
+		Alias:    (*Alias)(b),
+	})
+}
+
+func (b *Bridge) UnmarshalJSON(data []byte) (err error) {
+	type Alias Bridge
+	aux := &struct {
+		Aging    string `json:"aging,omitempty"`
+		Disable  null   `json:"disable"`
+		Priority string `json:"priority,omitempty"`
+		STP      string `json:"stp,omitempty"`
+		*Alias
+	}{
+		Alias: (*Alias)(b),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	b.Disable = aux.Disable.val
+
+	if aux.STP != "" {
+		stp := aux.STP == "true"
+		b.STP = &stp
+	}
+	if b.Aging, err = atoi("aging", aux.Aging); err != nil {
+		return err
+	}
+	if b.Priority, err = atoi("priority", aux.Priority); err != nil {
+		return err
+	}
+	return nil
+}
+
+// deleteNodes returns the nodes of the bridge that should be deleted.
+func (b *Bridge) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if len(b.Addresses) > 0 {
+		nodes["address"] = b.Addresses
+	}
+	if b.Description != "" {
+		nodes["description"] = nil
+	}
+	if b.Disable {
+		nodes["disable"] = nil
+	}
+	if b.STP != nil {
+		nodes["stp"] = nil
+	}
+	if b.Aging != 0 {
+		nodes["aging"] = nil
+	}
+	if b.Priority != 0 {
+		nodes["priority"] = nil
+	}
+	if b.DHCPOptions != nil {
+		nodes["dhcp-options"] = b.DHCPOptions.deleteNodes()
+	}
+
+	return nodes
+}
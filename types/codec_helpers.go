@@ -1,5 +1,10 @@
 package types
 
+import (
+	"fmt"
+	"strconv"
+)
+
 type null struct {
 	val bool
 }
@@ -17,3 +22,31 @@ func (n *null) MarshalJSON() ([]byte, error) {
 	}
 	return []byte("null"), nil
 }
+
+// flag encodes a valueless leaf such as disable, which is omitted when false.
+func flag(set bool) *null {
+	if !set {
+		return nil
+	}
+	return &null{true}
+}
+
+// itoa encodes a numeric leaf, which is omitted when zero.
+func itoa(i int) string {
+	if i == 0 {
+		return ""
+	}
+	return strconv.Itoa(i)
+}
+
+// atoi decodes a numeric leaf, where a missing leaf is zero.
+func atoi(name, val string) (int, error) {
+	if val == "" {
+		return 0, nil
+	}
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, fmt.Errorf("malformed %s: %v", name, val)
+	}
+	return i, nil
+}
@@ -0,0 +1,56 @@
+package types
+
+import "time"
+
+// DHCPLease is a lease handed out by the DHCP server, as reported by the
+// dhcp_leases data source. Pool is the shared network the lease belongs to.
+// Expiration is the wall time of the router, carried in UTC because the data
+// source does not report the time zone of the router, and is zero for leases
+// that never expire. Use ExpirationIn to place it in the router's time zone.
+type DHCPLease struct {
+	IPAddress  string    `json:"ip-address"`
+	MACAddress string    `json:"mac-address"`
+	Hostname   string    `json:"hostname,omitempty"`
+	Expiration time.Time `json:"expiration"`
+	Pool       string    `json:"pool"`
+}
+
+// DHCPPoolStats is the utilisation of the addresses of a shared network, as
+// reported by the dhcp_stats data source.
+type DHCPPoolStats struct {
+	SharedNetwork string `json:"shared-network"`
+	PoolSize      int    `json:"pool-size"`
+	Leased        int    `json:"leased"`
+	Available     int    `json:"available"`
+}
+
+// Utilization returns the fraction of the pool that is leased, between 0 and
+// 1. An empty pool has no utilisation.
+func (s *DHCPPoolStats) Utilization() float64 {
+	if s.PoolSize == 0 {
+		return 0
+	}
+	return float64(s.Leased) / float64(s.PoolSize)
+}
+
+// ExpirationIn returns the expiration of the lease as a wall time in loc, the
+// time zone of the router. A lease that never expires has the zero time.
+func (l *DHCPLease) ExpirationIn(loc *time.Location) time.Time {
+	if l.Expiration.IsZero() {
+		return time.Time{}
+	}
+	e := l.Expiration
+	return time.Date(e.Year(), e.Month(), e.Day(), e.Hour(), e.Minute(), e.Second(), e.Nanosecond(), loc)
+}
+
+// StaticMapping returns a static mapping named name that reserves the address
+// of the lease for its MAC address. The subnet is left for the caller to fill
+// in.
+func (l *DHCPLease) StaticMapping(name string) *StaticMapping {
+	return &StaticMapping{
+		Name:          name,
+		SharedNetwork: l.Pool,
+		IPAddress:     l.IPAddress,
+		MACAddress:    l.MACAddress,
+	}
+}
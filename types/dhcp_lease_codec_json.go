@@ -0,0 +1,113 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// leaseTimeLayout is the layout of the lease expiration reported by EdgeOS.
+// It is in the time zone of the router, which the data source does not
+// report, so expirations are decoded as UTC; see DHCPLease.ExpirationIn.
+const leaseTimeLayout = "2006/01/02 15:04:05"
+
+// leaseNeverExpires is reported instead of an expiration by leases that never
+// expire, such as those of BOOTP clients.
+const leaseNeverExpires = "never"
+
+// DHCPLeases is the output of the dhcp_leases data source, ordered by address.
+type DHCPLeases []*DHCPLease
+
+// DHCPStats is the output of the dhcp_stats data source, ordered by shared
+// network.
+type DHCPStats []*DHCPPoolStats
+
+func (l *DHCPLeases) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Leases map[string]map[string]struct {
+			Expiration string `json:"expiration"`
+			Pool       string `json:"pool"`
+			MAC        string `json:"mac"`
+			Hostname   string `json:"client-hostname"`
+		} `json:"dhcp-server-leases"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	leases := DHCPLeases{}
+	for network, byIP := range aux.Leases {
+		for ip, lease := range byIP {
+			pool := lease.Pool
+			if pool == "" {
+				pool = network
+			}
+			expiration, err := parseLeaseExpiration(lease.Expiration)
+			if err != nil {
+				return err
+			}
+
+			leases = append(leases, &DHCPLease{
+				IPAddress:  ip,
+				MACAddress: lease.MAC,
+				Hostname:   lease.Hostname,
+				Expiration: expiration,
+				Pool:       pool,
+			})
+		}
+	}
+	sort.Slice(leases, func(i, j int) bool {
+		return compareIPv4(leases[i].IPAddress, leases[j].IPAddress) < 0
+	})
+
+	*l = leases
+	return nil
+}
+
+// parseLeaseExpiration decodes the expiration of a lease, where a lease that
+// never expires has the zero time.
+func parseLeaseExpiration(val string) (time.Time, error) {
+	if val == "" || val == leaseNeverExpires {
+		return time.Time{}, nil
+	}
+	expiration, err := time.Parse(leaseTimeLayout, val)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed lease expiration: %v", val)
+	}
+	return expiration, nil
+}
+
+func (s *DHCPStats) UnmarshalJSON(data []byte) (err error) {
+	var aux struct {
+		Stats map[string]struct {
+			PoolSize  string `json:"pool_size"`
+			Leased    string `json:"leased"`
+			Available string `json:"available"`
+		} `json:"dhcp-server-stats"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	stats := DHCPStats{}
+	for network, pool := range aux.Stats {
+		p := &DHCPPoolStats{SharedNetwork: network}
+		if p.PoolSize, err = atoi("pool_size", pool.PoolSize); err != nil {
+			return err
+		}
+		if p.Leased, err = atoi("leased", pool.Leased); err != nil {
+			return err
+		}
+		if p.Available, err = atoi("available", pool.Available); err != nil {
+			return err
+		}
+		stats = append(stats, p)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].SharedNetwork < stats[j].SharedNetwork
+	})
+
+	*s = stats
+	return nil
+}
@@ -0,0 +1,129 @@
+package types
+
+import "sort"
+
+// DHCPServer is the DHCP server of the router, e.g. service dhcp-server. Shared
+// networks are keyed by name.
+type DHCPServer struct {
+	Disabled       bool                      `json:"-"`
+	SharedNetworks map[string]*SharedNetwork `json:"shared-network-name,omitempty"`
+}
+
+// SharedNetwork is a DHCP shared network, e.g. service dhcp-server
+// shared-network-name LAN. Subnets are keyed by their CIDR.
+type SharedNetwork struct {
+	Name          string                 `json:"-" tfsdk:"name"`
+	Authoritative bool                   `json:"-" tfsdk:"-"`
+	Description   string                 `json:"description,omitempty" tfsdk:"-"`
+	Disable       bool                   `json:"-" tfsdk:"-"`
+	Subnets       map[string]*DHCPSubnet `json:"subnet,omitempty" tfsdk:"-"`
+	opMode        OpMode
+}
+
+// DHCPSubnet is a subnet served by a shared network, e.g. service dhcp-server
+// shared-network-name LAN subnet 192.168.1.0/24. Static mappings are keyed by
+// name.
+type DHCPSubnet struct {
+	Subnet         string                    `json:"-" tfsdk:"id"`
+	SharedNetwork  string                    `json:"-" tfsdk:"shared_network"`
+	DefaultRouter  string                    `json:"default-router,omitempty" tfsdk:"-"`
+	DNSServers     []string                  `json:"dns-server,omitempty" tfsdk:"-"`
+	DomainName     string                    `json:"domain-name,omitempty" tfsdk:"-"`
+	Lease          int                       `json:"-" tfsdk:"-"`
+	Ranges         []*DHCPRange              `json:"-" tfsdk:"-"`
+	StaticMappings map[string]*StaticMapping `json:"static-mapping,omitempty" tfsdk:"-"`
+	opMode         OpMode
+}
+
+// DHCPRange is a range of addresses a subnet hands out, e.g. start
+// 192.168.1.100 stop 192.168.1.199.
+type DHCPRange struct {
+	Start string `json:"start" tfsdk:"start"`
+	Stop  string `json:"stop" tfsdk:"stop"`
+}
+
+// StaticMapping reserves an address of a subnet for a MAC address, e.g.
+// static-mapping printer.
+type StaticMapping struct {
+	Name          string `json:"-" tfsdk:"name"`
+	SharedNetwork string `json:"-" tfsdk:"shared_network"`
+	Subnet        string `json:"-" tfsdk:"subnet"`
+	IPAddress     string `json:"ip-address,omitempty" tfsdk:"ip_address"`
+	MACAddress    string `json:"mac-address,omitempty" tfsdk:"mac_address"`
+	opMode        OpMode
+}
+
+// SetOpMode controls how the shared network is encoded. When set to
+// OpModeDelete, every set property names a node to delete. Subnets are never
+// encoded in this mode.
+func (n *SharedNetwork) SetOpMode(m OpMode) {
+	(*n).opMode = m
+}
+
+// Properties returns a copy of the shared network without its subnets.
+func (n *SharedNetwork) Properties() *SharedNetwork {
+	tmp := *n
+	tmp.Subnets = nil
+	return &tmp
+}
+
+// IsEmpty reports whether none of the properties of the shared network are
+// set. Subnets are not considered.
+func (n *SharedNetwork) IsEmpty() bool {
+	return !n.Authoritative &&
+		n.Description == "" &&
+		!n.Disable
+}
+
+// SetOpMode controls how the subnet is encoded. When set to OpModeDelete,
+// every set property names a node to delete, while DNS servers are deleted by
+// value and ranges by their start. Static mappings are never encoded in this
+// mode.
+func (s *DHCPSubnet) SetOpMode(m OpMode) {
+	(*s).opMode = m
+}
+
+// Properties returns a copy of the subnet without its static mappings.
+func (s *DHCPSubnet) Properties() *DHCPSubnet {
+	tmp := *s
+	tmp.StaticMappings = nil
+	return &tmp
+}
+
+// IsEmpty reports whether none of the properties of the subnet are set.
+// Static mappings are not considered.
+func (s *DHCPSubnet) IsEmpty() bool {
+	return s.DefaultRouter == "" &&
+		len(s.DNSServers) == 0 &&
+		s.DomainName == "" &&
+		s.Lease == 0 &&
+		len(s.Ranges) == 0
+}
+
+// SortedStaticMappings returns the static mappings of the subnet ordered by
+// name.
+func (s *DHCPSubnet) SortedStaticMappings() []*StaticMapping {
+	mappings := make([]*StaticMapping, 0, len(s.StaticMappings))
+	for _, m := range s.StaticMappings {
+		if m != nil {
+			mappings = append(mappings, m)
+		}
+	}
+	sort.Slice(mappings, func(i, j int) bool {
+		return mappings[i].Name < mappings[j].Name
+	})
+	return mappings
+}
+
+// SetOpMode controls how the static mapping is encoded. When set to
+// OpModeDelete, every set property names a node to delete, and a mapping
+// without any deletes the whole mapping.
+func (m *StaticMapping) SetOpMode(mode OpMode) {
+	(*m).opMode = mode
+}
+
+// IsEmpty reports whether neither the address nor the MAC address of the
+// mapping are set.
+func (m *StaticMapping) IsEmpty() bool {
+	return m.IPAddress == "" && m.MACAddress == ""
+}
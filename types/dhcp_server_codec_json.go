@@ -0,0 +1,234 @@
+package types
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+const (
+	authoritativeEnable  = "enable"
+	authoritativeDisable = "disable"
+)
+
+type dhcpRangeStop struct {
+	Stop string `json:"stop,omitempty"`
+}
+
+func (d *DHCPServer) MarshalJSON() ([]byte, error) {
+	var disabled string
+	{
+		if d.Disabled {
+			disabled = "true"
+		}
+	}
+
+	type Alias DHCPServer
+	return json.Marshal(&struct {
+		Disabled string `json:"disabled,omitempty"`
+		*Alias
+	}{
+		Disabled: disabled,
+		Alias:    (*Alias)(d),
+	})
+}
+
+func (d *DHCPServer) UnmarshalJSON(data []byte) error {
+	type Alias DHCPServer
+	aux := &struct {
+		Disabled string `json:"disabled,omitempty"`
+		*Alias
+	}{
+		Alias: (*Alias)(d),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	d.Disabled = aux.Disabled == "true"
+	for name, n := range d.SharedNetworks {
+		if n == nil {
+			continue
+		}
+		n.Name = name
+		for cidr, s := range n.Subnets {
+			if s == nil {
+				continue
+			}
+			s.Subnet = cidr
+			s.SharedNetwork = name
+			for id, m := range s.StaticMappings {
+				if m == nil {
+					continue
+				}
+				m.Name = id
+				m.SharedNetwork = name
+				m.Subnet = cidr
+			}
+		}
+	}
+	return nil
+}
+
+func (n *SharedNetwork) MarshalJSON() ([]byte, error) {
+	if n.opMode == OpModeDelete {
+		return json.Marshal(n.deleteNodes())
+	}
+
+	var authoritative string
+	{
+		if n.Authoritative {
+			authoritative = authoritativeEnable
+		}
+	}
+
+	type Alias SharedNetwork
+	return json.Marshal(&struct {
+		Authoritative string `json:"authoritative,omitempty"`
+		Disable       *null  `json:"disable,omitempty"`
+		*Alias
+	}{
+		Authoritative: authoritative,
+		Disable:       flag(n.Disable),
+		Alias:         (*Alias)(n),
+	})
+}
+
+func (n *SharedNetwork) UnmarshalJSON(data []byte) error {
+	type Alias SharedNetwork
+	aux := &struct {
+		Authoritative string `json:"authoritative,omitempty"`
+		Disable       null   `json:"disable"`
+		*Alias
+	}{
+		Alias: (*Alias)(n),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	n.Authoritative = aux.Authoritative == authoritativeEnable
+	n.Disable = aux.Disable.val
+	return nil
+}
+
+// deleteNodes returns the nodes of the shared network that should be deleted.
+func (n *SharedNetwork) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if n.Authoritative {
+		nodes["authoritative"] = nil
+	}
+	if n.Description != "" {
+		nodes["description"] = nil
+	}
+	if n.Disable {
+		nodes["disable"] = nil
+	}
+
+	return nodes
+}
+
+func (s *DHCPSubnet) MarshalJSON() ([]byte, error) {
+	if s.opMode == OpModeDelete {
+		return json.Marshal(s.deleteNodes())
+	}
+
+	// start is a tag node keyed by the first address of the range.
+	var ranges map[string]*dhcpRangeStop
+	{
+		if len(s.Ranges) > 0 {
+			ranges = map[string]*dhcpRangeStop{}
+			for _, r := range s.Ranges {
+				ranges[r.Start] = &dhcpRangeStop{Stop: r.Stop}
+			}
+		}
+	}
+
+	type Alias DHCPSubnet
+	return json.Marshal(&struct {
+		Lease  string                    `json:"lease,omitempty"`
+		Ranges map[string]*dhcpRangeStop `json:"start,omitempty"`
+		*Alias
+	}{
+		Lease:  itoa(s.Lease),
+		Ranges: ranges,
+		Alias:  (*Alias)(s),
+	})
+}
+
+func (s *DHCPSubnet) UnmarshalJSON(data []byte) (err error) {
+	type Alias DHCPSubnet
+	aux := &struct {
+		Lease  string                    `json:"lease,omitempty"`
+		Ranges map[string]*dhcpRangeStop `json:"start,omitempty"`
+		*Alias
+	}{
+		Alias: (*Alias)(s),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if s.Lease, err = atoi("lease", aux.Lease); err != nil {
+		return err
+	}
+
+	s.Ranges = nil
+	for start, r := range aux.Ranges {
+		stop := ""
+		if r != nil {
+			stop = r.Stop
+		}
+		s.Ranges = append(s.Ranges, &DHCPRange{Start: start, Stop: stop})
+	}
+	sort.Slice(s.Ranges, func(i, j int) bool {
+		return compareIPv4(s.Ranges[i].Start, s.Ranges[j].Start) < 0
+	})
+	return nil
+}
+
+// deleteNodes returns the nodes of the subnet that should be deleted.
+func (s *DHCPSubnet) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if s.DefaultRouter != "" {
+		nodes["default-router"] = nil
+	}
+	if len(s.DNSServers) > 0 {
+		nodes["dns-server"] = s.DNSServers
+	}
+	if s.DomainName != "" {
+		nodes["domain-name"] = nil
+	}
+	if s.Lease != 0 {
+		nodes["lease"] = nil
+	}
+	if len(s.Ranges) > 0 {
+		ranges := map[string]interface{}{}
+		for _, r := range s.Ranges {
+			ranges[r.Start] = nil
+		}
+		nodes["start"] = ranges
+	}
+
+	return nodes
+}
+
+func (m *StaticMapping) MarshalJSON() ([]byte, error) {
+	if m.opMode == OpModeDelete {
+		if m.IsEmpty() {
+			return []byte("null"), nil
+		}
+		nodes := map[string]interface{}{}
+		if m.IPAddress != "" {
+			nodes["ip-address"] = nil
+		}
+		if m.MACAddress != "" {
+			nodes["mac-address"] = nil
+		}
+		return json.Marshal(nodes)
+	}
+
+	type Alias StaticMapping
+	return json.Marshal((*Alias)(m))
+}
@@ -0,0 +1,140 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+)
+
+const minLease = 120
+
+// Validate checks the name and every subnet of the shared network.
+func (n *SharedNetwork) Validate() error {
+	v := new(validator)
+	v.validateName("name", n.Name)
+
+	for cidr, s := range n.Subnets {
+		if s == nil {
+			continue
+		}
+		tmp := *s
+		tmp.Subnet = cidr
+		tmp.validate(v, fmt.Sprintf("subnet[%s]", cidr))
+	}
+	return v.err()
+}
+
+// Validate checks that the subnet is an IPv4 network and that the default
+// router, ranges and static mappings are addresses within it. Ranges must not
+// overlap and static mappings must not share an address or MAC address.
+func (s *DHCPSubnet) Validate() error {
+	v := new(validator)
+	s.validate(v, "")
+	return v.err()
+}
+
+func (s *DHCPSubnet) validate(v *validator, prefix string) {
+	_, network, err := net.ParseCIDR(s.Subnet)
+	if err != nil || network.IP.To4() == nil || network.String() != s.Subnet {
+		v.add(join(prefix, "subnet"), "%q must be an IPv4 network in CIDR notation, e.g. 192.168.1.0/24", s.Subnet)
+		network = nil
+	}
+
+	if s.DefaultRouter != "" {
+		v.validateSubnetAddress(join(prefix, "default-router"), s.DefaultRouter, network)
+	}
+	for i, dns := range s.DNSServers {
+		if !isIPv4(dns) {
+			v.add(join(prefix, fmt.Sprintf("dns-server[%d]", i)), "%q is not a valid IPv4 address", dns)
+		}
+	}
+	if s.DomainName != "" && strings.ContainsAny(s.DomainName, " \t\n\"'/") {
+		v.add(join(prefix, "domain-name"), "%q is not a valid domain name", s.DomainName)
+	}
+	if s.Lease != 0 && s.Lease < minLease {
+		v.add(join(prefix, "lease"), "%d must be at least %d seconds", s.Lease, minLease)
+	}
+
+	for i, r := range s.Ranges {
+		field := join(prefix, fmt.Sprintf("start[%d]", i))
+		if !v.validateSubnetAddress(field, r.Start, network) || !v.validateSubnetAddress(field, r.Stop, network) {
+			continue
+		}
+		if compareIPv4(r.Start, r.Stop) > 0 {
+			v.add(field, "range %s-%s starts after it ends", r.Start, r.Stop)
+			continue
+		}
+		for _, other := range s.Ranges[:i] {
+			if compareIPv4(r.Start, other.Stop) <= 0 && compareIPv4(other.Start, r.Stop) <= 0 {
+				v.add(field, "range %s-%s overlaps range %s-%s", r.Start, r.Stop, other.Start, other.Stop)
+			}
+		}
+	}
+
+	addresses := map[string]string{}
+	macs := map[string]string{}
+	for _, m := range s.SortedStaticMappings() {
+		field := join(prefix, fmt.Sprintf("static-mapping[%s]", m.Name))
+		m.validate(v, field, network)
+
+		if other, ok := addresses[m.IPAddress]; ok && m.IPAddress != "" {
+			v.add(field, "ip-address %s is already mapped by %s", m.IPAddress, other)
+		}
+		if other, ok := macs[strings.ToLower(m.MACAddress)]; ok && m.MACAddress != "" {
+			v.add(field, "mac-address %s is already mapped by %s", m.MACAddress, other)
+		}
+		addresses[m.IPAddress] = m.Name
+		macs[strings.ToLower(m.MACAddress)] = m.Name
+	}
+}
+
+// Validate checks the name, address and MAC address of the static mapping.
+// The address is checked against the subnet of the mapping if it is set.
+func (m *StaticMapping) Validate() error {
+	v := new(validator)
+
+	var network *net.IPNet
+	if m.Subnet != "" {
+		if _, n, err := net.ParseCIDR(m.Subnet); err == nil {
+			network = n
+		}
+	}
+	m.validate(v, "", network)
+	return v.err()
+}
+
+func (m *StaticMapping) validate(v *validator, prefix string, network *net.IPNet) {
+	if prefix == "" {
+		v.validateName("name", m.Name)
+	}
+	if m.IPAddress == "" {
+		v.add(join(prefix, "ip-address"), "must not be empty")
+	} else {
+		v.validateSubnetAddress(join(prefix, "ip-address"), m.IPAddress, network)
+	}
+	if m.MACAddress == "" {
+		v.add(join(prefix, "mac-address"), "must not be empty")
+	} else if _, err := net.ParseMAC(m.MACAddress); err != nil {
+		v.add(join(prefix, "mac-address"), "%q is not a valid MAC address", m.MACAddress)
+	}
+}
+
+// validateSubnetAddress ensures val is an IPv4 address and, if network is
+// known, that it is within network. It reports whether val is valid.
+func (v *validator) validateSubnetAddress(field, val string, network *net.IPNet) bool {
+	if !isIPv4(val) {
+		v.add(field, "%q is not a valid IPv4 address", val)
+		return false
+	}
+	if network != nil && !network.Contains(net.ParseIP(val)) {
+		v.add(field, "%s is not within %s", val, network)
+		return false
+	}
+	return true
+}
+
+// compareIPv4 compares two IPv4 addresses numerically.
+func compareIPv4(a, b string) int {
+	return bytes.Compare(net.ParseIP(a).To4(), net.ParseIP(b).To4())
+}
@@ -0,0 +1,70 @@
+package types
+
+// DNS is the DNS node of the services, e.g. service dns.
+type DNS struct {
+	Forwarding *DNSForwarding `json:"forwarding,omitempty"`
+}
+
+// DNSForwarding is the DNS forwarder of the router, e.g. service dns
+// forwarding. Name servers are used in addition to the ones learned through
+// DHCP on the DHCP interfaces and, if System is set, the system name servers.
+// Options are passed to dnsmasq as they are, e.g. address=/nas.lan/10.0.0.2.
+type DNSForwarding struct {
+	CacheSize   int      `json:"-" tfsdk:"-"`
+	ListenOn    []string `json:"listen-on,omitempty" tfsdk:"-"`
+	NameServers []string `json:"name-server,omitempty" tfsdk:"-"`
+	Options     []string `json:"options,omitempty" tfsdk:"-"`
+	System      bool     `json:"-" tfsdk:"-"`
+	DHCP        []string `json:"dhcp,omitempty" tfsdk:"-"`
+	opMode      OpMode
+}
+
+// System is the system node of the configuration. Only the parts the SDK
+// manages are modelled.
+type System struct {
+	StaticHostMapping *StaticHostMapping `json:"static-host-mapping,omitempty"`
+}
+
+// StaticHostMapping holds the entries of the hosts file of the router, e.g.
+// system static-host-mapping. Hosts are keyed by name.
+type StaticHostMapping struct {
+	Hosts map[string]*HostMapping `json:"host-name,omitempty"`
+}
+
+// HostMapping resolves a host name and its aliases to an address, e.g. system
+// static-host-mapping host-name nas.lan.
+type HostMapping struct {
+	HostName string   `json:"-" tfsdk:"id"`
+	Inet     string   `json:"inet,omitempty" tfsdk:"inet"`
+	Aliases  []string `json:"alias,omitempty" tfsdk:"aliases"`
+	opMode   OpMode
+}
+
+// SetOpMode controls how the forwarder is encoded. When set to OpModeDelete,
+// every set property names a node to delete, and lists are deleted by value.
+func (f *DNSForwarding) SetOpMode(m OpMode) {
+	(*f).opMode = m
+}
+
+// IsEmpty reports whether none of the properties of the forwarder are set.
+func (f *DNSForwarding) IsEmpty() bool {
+	return f.CacheSize == 0 &&
+		len(f.ListenOn) == 0 &&
+		len(f.NameServers) == 0 &&
+		len(f.Options) == 0 &&
+		!f.System &&
+		len(f.DHCP) == 0
+}
+
+// SetOpMode controls how the host is encoded. When set to OpModeDelete, a set
+// address names a node to delete and aliases are deleted by value. A host
+// with neither deletes the whole host.
+func (h *HostMapping) SetOpMode(m OpMode) {
+	(*h).opMode = m
+}
+
+// IsEmpty reports whether neither the address nor any alias of the host are
+// set.
+func (h *HostMapping) IsEmpty() bool {
+	return h.Inet == "" && len(h.Aliases) == 0
+}
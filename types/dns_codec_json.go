@@ -0,0 +1,97 @@
+package types
+
+import "encoding/json"
+
+func (f *DNSForwarding) MarshalJSON() ([]byte, error) {
+	if f.opMode == OpModeDelete {
+		return json.Marshal(f.deleteNodes())
+	}
+
+	type Alias DNSForwarding
+	return json.Marshal(&struct {
+		CacheSize string `json:"cache-size,omitempty"`
+		System    *null  `json:"system,omitempty"`
+		*Alias
+	}{
+		CacheSize: itoa(f.CacheSize),
+		System:    flag(f.System),
+		Alias:     (*Alias)(f),
+	})
+}
+
+func (f *DNSForwarding) UnmarshalJSON(data []byte) (err error) {
+	type Alias DNSForwarding
+	aux := &struct {
+		CacheSize string `json:"cache-size,omitempty"`
+		System    null   `json:"system"`
+		*Alias
+	}{
+		Alias: (*Alias)(f),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	f.System = aux.System.val
+	f.CacheSize, err = atoi("cache-size", aux.CacheSize)
+	return err
+}
+
+// deleteNodes returns the nodes of the forwarder that should be deleted.
+func (f *DNSForwarding) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if f.CacheSize != 0 {
+		nodes["cache-size"] = nil
+	}
+	if len(f.ListenOn) > 0 {
+		nodes["listen-on"] = f.ListenOn
+	}
+	if len(f.NameServers) > 0 {
+		nodes["name-server"] = f.NameServers
+	}
+	if len(f.Options) > 0 {
+		nodes["options"] = f.Options
+	}
+	if f.System {
+		nodes["system"] = nil
+	}
+	if len(f.DHCP) > 0 {
+		nodes["dhcp"] = f.DHCP
+	}
+
+	return nodes
+}
+
+func (s *StaticHostMapping) UnmarshalJSON(data []byte) error {
+	type Alias StaticHostMapping
+	if err := json.Unmarshal(data, (*Alias)(s)); err != nil {
+		return err
+	}
+
+	for name, h := range s.Hosts {
+		if h != nil {
+			h.HostName = name
+		}
+	}
+	return nil
+}
+
+func (h *HostMapping) MarshalJSON() ([]byte, error) {
+	if h.opMode == OpModeDelete {
+		if h.IsEmpty() {
+			return []byte("null"), nil
+		}
+		nodes := map[string]interface{}{}
+		if h.Inet != "" {
+			nodes["inet"] = nil
+		}
+		if len(h.Aliases) > 0 {
+			nodes["alias"] = h.Aliases
+		}
+		return json.Marshal(nodes)
+	}
+
+	type Alias HostMapping
+	return json.Marshal((*Alias)(h))
+}
@@ -0,0 +1,89 @@
+package types
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+const maxCacheSize = 10000
+
+// Validate checks the cache size, interfaces, name servers and options of the
+// forwarder.
+func (f *DNSForwarding) Validate() error {
+	v := new(validator)
+
+	if f.CacheSize < 0 || f.CacheSize > maxCacheSize {
+		v.add("cache-size", "%d must be between 0 and %d", f.CacheSize, maxCacheSize)
+	}
+	for i, iface := range f.ListenOn {
+		v.validateName(fmt.Sprintf("listen-on[%d]", i), iface)
+	}
+	for i, ns := range f.NameServers {
+		if net.ParseIP(ns) == nil {
+			v.add(fmt.Sprintf("name-server[%d]", i), "%q is not a valid IP address", ns)
+		}
+	}
+	for i, opt := range f.Options {
+		field := fmt.Sprintf("options[%d]", i)
+		if opt == "" {
+			v.add(field, "must not be empty")
+		} else if strings.ContainsAny(opt, "\n\"'") {
+			v.add(field, "%q must not contain newlines or quotes", opt)
+		}
+	}
+	for i, iface := range f.DHCP {
+		v.validateName(fmt.Sprintf("dhcp[%d]", i), iface)
+	}
+	return v.err()
+}
+
+// Validate checks the host name, address and aliases of the host.
+func (h *HostMapping) Validate() error {
+	v := new(validator)
+	v.validateHostName("host-name", h.HostName)
+
+	if h.Inet == "" {
+		v.add("inet", "must not be empty")
+	} else if net.ParseIP(h.Inet) == nil {
+		v.add("inet", "%q is not a valid IP address", h.Inet)
+	}
+
+	seen := map[string]bool{strings.ToLower(h.HostName): true}
+	for i, alias := range h.Aliases {
+		field := fmt.Sprintf("alias[%d]", i)
+		v.validateHostName(field, alias)
+		if seen[strings.ToLower(alias)] {
+			v.add(field, "%q is listed more than once", alias)
+		}
+		seen[strings.ToLower(alias)] = true
+	}
+	return v.err()
+}
+
+// validateHostName ensures name is made of dot separated labels of letters,
+// digits and hyphens that neither start nor end with a hyphen.
+func (v *validator) validateHostName(field, name string) {
+	if name == "" {
+		v.add(field, "must not be empty")
+		return
+	}
+	for _, label := range strings.Split(name, ".") {
+		if !isHostLabel(label) {
+			v.add(field, "%q is not a valid host name", name)
+			return
+		}
+	}
+}
+
+func isHostLabel(label string) bool {
+	if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, r := range label {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
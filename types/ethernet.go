@@ -8,29 +8,136 @@ type DHCPOptions struct {
 	NameServer           string `json:"name-server,omitempty"`
 }
 
+// Removed returns the options that are set in o but not in updated, or nil if
+// there are none. If updated is nil, every option was removed and an empty
+// set of options is returned.
+func (o *DHCPOptions) Removed(updated *DHCPOptions) *DHCPOptions {
+	if o == nil {
+		return nil
+	}
+	if updated == nil {
+		return &DHCPOptions{}
+	}
+
+	removed := DHCPOptions{}
+	if o.DefaultRoute != "" && updated.DefaultRoute == "" {
+		removed.DefaultRoute = o.DefaultRoute
+	}
+	if o.DefaultRouteDistance != 0 && updated.DefaultRouteDistance == 0 {
+		removed.DefaultRouteDistance = o.DefaultRouteDistance
+	}
+	if o.NameServer != "" && updated.NameServer == "" {
+		removed.NameServer = o.NameServer
+	}
+	if removed == (DHCPOptions{}) {
+		return nil
+	}
+	return &removed
+}
+
 type IP struct {
-	EnableProxyARP interface{} `json:"enable-proxy-arp,omitempty"` // don't know what this is yet and api respose has it as null
+	EnableProxyARP bool `json:"-"`
 }
 
+// FirewallAttachment is the set of rulesets attached to an interface. In, Out
+// and Local name IPv4 rulesets (firewall name) while IPv6In, IPv6Out and
+// IPv6Local name IPv6 rulesets (firewall ipv6-name).
 type FirewallAttachment struct {
 	ID        tftypes.String `json:"-" tfsdk:"id"`
 	Interface string         `json:"-" tfsdk:"interface"`
+	Path      InterfacePath  `json:"-" tfsdk:"-"`
 	In        *string        `json:"in,omitempty" tfsdk:"in"`
 	Out       *string        `json:"out,omitempty" tfsdk:"out"`
 	Local     *string        `json:"local,omitempty" tfsdk:"local"`
+	IPv6In    *string        `json:"-" tfsdk:"ipv6_in"`
+	IPv6Out   *string        `json:"-" tfsdk:"ipv6_out"`
+	IPv6Local *string        `json:"-" tfsdk:"ipv6_local"`
+	opMode    OpMode
 }
 
+// Ethernet is an ethernet interface. Addresses holds static addresses in CIDR
+// notation as well as dhcp and dhcpv6 for addresses obtained from a DHCP
+// server.
 type Ethernet struct {
 	ID          string              `json:"-" tfsdk:"id"`
 	Addresses   []string            `json:"address,omitempty" tfsdk:"-"`
 	Description string              `json:"description,omitempty" tfsdk:"-"`
 	DHCPOptions *DHCPOptions        `json:"dhcp-options,omitempty" tfsdk:"-"`
+	Disable     bool                `json:"-" tfsdk:"-"`
 	Duplex      string              `json:"duplex,omitempty" tfsdk:"-"`
 	Speed       string              `json:"speed,omitempty" tfsdk:"-"`
+	MTU         int                 `json:"-" tfsdk:"-"`
 	IP          *IP                 `json:"ip,omitempty" tfsdk:"-"`
-	Firewall    *FirewallAttachment `json:"firewall" tfsdk:"-"`
+	BridgeGroup *BridgeGroup        `json:"bridge-group,omitempty" tfsdk:"-"`
+	BondGroup   string              `json:"bond-group,omitempty" tfsdk:"-"`
+	Firewall    *FirewallAttachment `json:"firewall,omitempty" tfsdk:"-"`
+	VIF         map[string]*VIF     `json:"vif,omitempty" tfsdk:"-"`
+	PPPoE       map[string]*PPPoE   `json:"pppoe,omitempty" tfsdk:"-"`
+	opMode      OpMode
+}
+
+// BridgeGroup makes an interface a member of a bridge.
+type BridgeGroup struct {
+	Bridge string `json:"bridge,omitempty"`
+}
+
+func (e *Ethernet) GetID() string {
+	return e.ID
+}
+
+// SetOpMode controls how the interface is encoded. When set to OpModeDelete,
+// every set property names a node to delete, and addresses are deleted by
+// value. Firewall attachments and sub-interfaces are managed by their own
+// clients and are never encoded in this mode.
+func (e *Ethernet) SetOpMode(m OpMode) {
+	(*e).opMode = m
+}
+
+// Properties returns a copy of the interface without its firewall attachment,
+// sub-interfaces and bridge or bond membership, which are managed by the
+// bridge and bonding clients.
+func (e *Ethernet) Properties() *Ethernet {
+	tmp := *e
+	tmp.BridgeGroup = nil
+	tmp.BondGroup = ""
+	tmp.Firewall = nil
+	tmp.VIF = nil
+	tmp.PPPoE = nil
+	return &tmp
+}
+
+// IsEmpty reports whether none of the properties of the interface are set.
+// The firewall attachment, sub-interfaces and bridge or bond membership are
+// not considered.
+func (e *Ethernet) IsEmpty() bool {
+	return len(e.Addresses) == 0 &&
+		e.Description == "" &&
+		e.DHCPOptions == nil &&
+		!e.Disable &&
+		e.Duplex == "" &&
+		e.Speed == "" &&
+		e.MTU == 0 &&
+		e.IP == nil
 }
 
 func (a *FirewallAttachment) GetID() string {
 	return a.Interface
 }
+
+// SetOpMode controls how the attachment is encoded. When set to OpModeDelete,
+// every non-nil direction names a ruleset to detach, where an empty name
+// detaches whatever is attached. An attachment without any direction removes
+// the firewall node entirely.
+func (a *FirewallAttachment) SetOpMode(m OpMode) {
+	(*a).opMode = m
+}
+
+// IsEmpty reports whether no ruleset is attached in any direction.
+func (a *FirewallAttachment) IsEmpty() bool {
+	for _, name := range []*string{a.In, a.Out, a.Local, a.IPv6In, a.IPv6Out, a.IPv6Local} {
+		if name != nil && *name != "" {
+			return false
+		}
+	}
+	return true
+}
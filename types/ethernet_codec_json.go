@@ -2,11 +2,13 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
 	"strconv"
 )
 
 type apiFirewallDetails struct {
-	Name string `json:"name"`
+	Name     string `json:"name,omitempty"`
+	IPv6Name string `json:"ipv6-name,omitempty"`
 }
 
 type apiFirewall struct {
@@ -16,25 +18,68 @@ type apiFirewall struct {
 }
 
 func (f *FirewallAttachment) MarshalJSON() ([]byte, error) {
-	af := new(apiFirewall)
+	if f.opMode == OpModeDelete {
+		return f.marshalDelete()
+	}
 
-	if f.In != nil && *f.In != "" {
-		af.In = &apiFirewallDetails{
-			Name: *f.In,
+	details := func(name, ipv6Name *string) *apiFirewallDetails {
+		var d apiFirewallDetails
+		if name != nil {
+			d.Name = *name
 		}
-	}
-	if f.Out != nil && *f.Out != "" {
-		af.Out = &apiFirewallDetails{
-			Name: *f.Out,
+		if ipv6Name != nil {
+			d.IPv6Name = *ipv6Name
+		}
+		if d.Name == "" && d.IPv6Name == "" {
+			return nil
 		}
+		return &d
 	}
-	if f.Local != nil && *f.Local != "" {
-		af.Local = &apiFirewallDetails{
-			Name: *f.Local,
+
+	return json.Marshal(&apiFirewall{
+		In:    details(f.In, f.IPv6In),
+		Out:   details(f.Out, f.IPv6Out),
+		Local: details(f.Local, f.IPv6Local),
+	})
+}
+
+// marshalDelete encodes the nodes to delete. A named ruleset is deleted by
+// value so that the delete does nothing if another ruleset was attached in the
+// meantime.
+func (f *FirewallAttachment) marshalDelete() ([]byte, error) {
+	nodes := map[string]interface{}{}
+
+	for _, dir := range []struct {
+		direction      string
+		name, ipv6Name *string
+	}{
+		{"in", f.In, f.IPv6In},
+		{"out", f.Out, f.IPv6Out},
+		{"local", f.Local, f.IPv6Local},
+	} {
+		details := map[string]interface{}{}
+		if dir.name != nil {
+			details["name"] = deleteValue(*dir.name)
+		}
+		if dir.ipv6Name != nil {
+			details["ipv6-name"] = deleteValue(*dir.ipv6Name)
+		}
+		if len(details) > 0 {
+			nodes[dir.direction] = details
 		}
 	}
 
-	return json.Marshal(af)
+	if len(nodes) == 0 {
+		return []byte("null"), nil
+	}
+	return json.Marshal(nodes)
+}
+
+func deleteValue(val string) interface{} {
+	if val == "" {
+		return nil
+	}
+	return val
 }
 
 func (f *FirewallAttachment) UnmarshalJSON(data []byte) (err error) {
@@ -44,26 +89,42 @@ func (f *FirewallAttachment) UnmarshalJSON(data []byte) (err error) {
 		return err
 	}
 
-	if ap.In != nil && ap.In.Name != "" {
-		f.In = &ap.In.Name
-	}
-	if ap.Out != nil && ap.Out.Name != "" {
-		f.Out = &ap.Out.Name
-	}
-	if ap.Local != nil && ap.Local.Name != "" {
-		f.Local = &ap.Local.Name
+	for _, dir := range []struct {
+		details        *apiFirewallDetails
+		name, ipv6Name **string
+	}{
+		{ap.In, &f.In, &f.IPv6In},
+		{ap.Out, &f.Out, &f.IPv6Out},
+		{ap.Local, &f.Local, &f.IPv6Local},
+	} {
+		if dir.details == nil {
+			continue
+		}
+		if name := dir.details.Name; name != "" {
+			*dir.name = &name
+		}
+		if name := dir.details.IPv6Name; name != "" {
+			*dir.ipv6Name = &name
+		}
 	}
 
 	return nil
 }
 
 func (o *DHCPOptions) MarshalJSON() ([]byte, error) {
+	var distance string
+	{
+		if o.DefaultRouteDistance != 0 {
+			distance = strconv.Itoa(o.DefaultRouteDistance)
+		}
+	}
+
 	type Alias DHCPOptions
 	return json.Marshal(&struct {
 		DefaultRouteDistance string `json:"default-route-distance,omitempty"`
 		*Alias
 	}{
-		DefaultRouteDistance: strconv.Itoa(o.DefaultRouteDistance),
+		DefaultRouteDistance: distance,
 		Alias:                (*Alias)(o),
 	})
 }
@@ -79,6 +140,9 @@ func (o *DHCPOptions) UnmarshalJSON(data []byte) (err error) {
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
+	if aux.DefaultRouteDistance == "" {
+		return nil
+	}
 	i, err := strconv.Atoi(aux.DefaultRouteDistance)
 	if err != nil {
 		return err
@@ -86,3 +150,147 @@ func (o *DHCPOptions) UnmarshalJSON(data []byte) (err error) {
 	o.DefaultRouteDistance = i
 	return nil
 }
+
+func (e *Ethernet) MarshalJSON() ([]byte, error) {
+	if e.opMode == OpModeDelete {
+		return json.Marshal(e.deleteNodes())
+	}
+
+	var mtu string
+	{
+		if e.MTU != 0 {
+			mtu = strconv.Itoa(e.MTU)
+		}
+	}
+
+	var disable *null
+	{
+		if e.Disable {
+			disable = &null{true}
+		}
+	}
+
+	type Alias Ethernet
+	return json.Marshal(&struct {
+		Disable *null  `json:"disable,omitempty"`
+		MTU     string `json:"mtu,omitempty"`
+		*Alias
+	}{
+		Disable: disable,
+		MTU:     mtu,
+		Alias:   (*Alias)(e),
+	})
+}
+
+func (e *Ethernet) UnmarshalJSON(data []byte) (err error) {
+	type Alias Ethernet
+	aux := &struct {
+		Disable null   `json:"disable"`
+		MTU     string `json:"mtu,omitempty"`
+		*Alias
+	}{
+		Alias: (*Alias)(e),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	e.Disable = aux.Disable.val
+
+	if aux.MTU != "" {
+		i, err := strconv.Atoi(aux.MTU)
+		if err != nil {
+			return fmt.Errorf("malformed mtu: %v", aux.MTU)
+		}
+		e.MTU = i
+	}
+
+	return nil
+}
+
+// deleteNodes returns the nodes of the interface that should be deleted.
+func (e *Ethernet) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if len(e.Addresses) > 0 {
+		nodes["address"] = e.Addresses
+	}
+	if e.Description != "" {
+		nodes["description"] = nil
+	}
+	if e.Disable {
+		nodes["disable"] = nil
+	}
+	if e.Duplex != "" {
+		nodes["duplex"] = nil
+	}
+	if e.Speed != "" {
+		nodes["speed"] = nil
+	}
+	if e.MTU != 0 {
+		nodes["mtu"] = nil
+	}
+	if e.DHCPOptions != nil {
+		nodes["dhcp-options"] = e.DHCPOptions.deleteNodes()
+	}
+	if e.BridgeGroup != nil {
+		nodes["bridge-group"] = nil
+	}
+	if e.BondGroup != "" {
+		nodes["bond-group"] = e.BondGroup
+	}
+	if ip := e.IP; ip != nil {
+		if ip.EnableProxyARP {
+			nodes["ip"] = map[string]interface{}{"enable-proxy-arp": nil}
+		} else {
+			nodes["ip"] = nil
+		}
+	}
+
+	return nodes
+}
+
+// deleteNodes returns the options that should be deleted, or nil to delete the
+// dhcp-options node when no option is set.
+func (o *DHCPOptions) deleteNodes() map[string]interface{} {
+	options := map[string]interface{}{}
+	if o.DefaultRoute != "" {
+		options["default-route"] = nil
+	}
+	if o.DefaultRouteDistance != 0 {
+		options["default-route-distance"] = nil
+	}
+	if o.NameServer != "" {
+		options["name-server"] = nil
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	return options
+}
+
+func (ip *IP) MarshalJSON() ([]byte, error) {
+	var proxyARP *null
+	{
+		if ip.EnableProxyARP {
+			proxyARP = &null{true}
+		}
+	}
+
+	return json.Marshal(&struct {
+		EnableProxyARP *null `json:"enable-proxy-arp,omitempty"`
+	}{
+		EnableProxyARP: proxyARP,
+	})
+}
+
+func (ip *IP) UnmarshalJSON(data []byte) (err error) {
+	var aux struct {
+		EnableProxyARP null `json:"enable-proxy-arp"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	ip.EnableProxyARP = aux.EnableProxyARP.val
+	return nil
+}
@@ -1,9 +1,109 @@
 package types
 
 import (
+	"encoding/json"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestFirewallAttachmentMarshalJSON(t *testing.T) {
+	empty := ""
+
+	for _, test := range []struct {
+		name     string
+		a        *FirewallAttachment
+		delete   bool
+		expected string
+	}{
+		{
+			name: "ipv4 and ipv6",
+			a: &FirewallAttachment{
+				In:        strptr("WAN_IN"),
+				IPv6In:    strptr("WAN6_IN"),
+				Local:     strptr("WAN_LOCAL"),
+				IPv6Out:   strptr("WAN6_OUT"),
+				Out:       &empty,
+				Interface: "ethernet eth0",
+			},
+			expected: `{"in":{"name":"WAN_IN","ipv6-name":"WAN6_IN"},"out":{"ipv6-name":"WAN6_OUT"},"local":{"name":"WAN_LOCAL"}}`,
+		},
+		{
+			name:     "empty",
+			a:        &FirewallAttachment{},
+			expected: `{}`,
+		},
+		{
+			name:     "delete everything",
+			a:        &FirewallAttachment{},
+			delete:   true,
+			expected: `null`,
+		},
+		{
+			name: "delete by value and by node",
+			a: &FirewallAttachment{
+				In:        strptr("WAN_IN"),
+				IPv6Local: &empty,
+			},
+			delete:   true,
+			expected: `{"in":{"name":"WAN_IN"},"local":{"ipv6-name":null}}`,
+		},
+	} {
+		if test.delete {
+			test.a.SetOpMode(OpModeDelete)
+		}
+		data, err := json.Marshal(test.a)
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, string(data), test.name)
+	}
+}
+
+func TestFirewallAttachmentUnmarshalJSON(t *testing.T) {
+	var a FirewallAttachment
+	require.NoError(t, json.Unmarshal([]byte(`{"in":{"name":"WAN_IN","ipv6-name":"WAN6_IN"},"local":{"ipv6-name":"WAN6_LOCAL"}}`), &a))
+	require.Equal(t, FirewallAttachment{
+		In:        strptr("WAN_IN"),
+		IPv6In:    strptr("WAN6_IN"),
+		IPv6Local: strptr("WAN6_LOCAL"),
+	}, a)
+}
+
+func TestDHCPOptionsJSON(t *testing.T) {
+	var o DHCPOptions
+	require.NoError(t, json.Unmarshal([]byte(`{"default-route":"update"}`), &o))
+	require.Equal(t, DHCPOptions{DefaultRoute: "update"}, o)
+
+	data, err := json.Marshal(&o)
+	require.NoError(t, err)
+	require.Equal(t, `{"default-route":"update"}`, string(data))
+
+	o.DefaultRouteDistance = 210
+	data, err = json.Marshal(&o)
+	require.NoError(t, err)
+	require.Equal(t, `{"default-route-distance":"210","default-route":"update"}`, string(data))
+}
+
+func TestEthernetJSON(t *testing.T) {
+	var e Ethernet
+	require.NoError(t, json.Unmarshal([]byte(`{"address":["dhcp"],"description":"WAN","disable":null,"duplex":"auto","speed":"auto","mtu":"1500","ip":{"enable-proxy-arp":null},"dhcp-options":{"default-route":"update"}}`), &e))
+	require.Equal(t, Ethernet{
+		Addresses:   []string{"dhcp"},
+		Description: "WAN",
+		Disable:     true,
+		Duplex:      "auto",
+		Speed:       "auto",
+		MTU:         1500,
+		IP:          &IP{EnableProxyARP: true},
+		DHCPOptions: &DHCPOptions{DefaultRoute: "update"},
+	}, e)
+
+	data, err := json.Marshal(&e)
+	require.NoError(t, err)
+	require.Equal(t, `{"disable":null,"mtu":"1500","address":["dhcp"],"description":"WAN","dhcp-options":{"default-route":"update"},"duplex":"auto","speed":"auto","ip":{"enable-proxy-arp":null}}`, string(data))
 
+	e.SetOpMode(OpModeDelete)
+	e.DHCPOptions = &DHCPOptions{}
+	data, err = json.Marshal(&e)
+	require.NoError(t, err)
+	require.Equal(t, `{"address":["dhcp"],"description":null,"dhcp-options":null,"disable":null,"duplex":null,"ip":{"enable-proxy-arp":null},"mtu":null,"speed":null}`, string(data))
 }
@@ -0,0 +1,53 @@
+package types
+
+import "fmt"
+
+const (
+	minMTU = 68
+	maxMTU = 9000
+
+	minDistance = 1
+	maxDistance = 255
+)
+
+var (
+	speeds        = []string{"auto", "10", "100", "1000", "10000"}
+	duplexes      = []string{"auto", "half", "full"}
+	dhcpUpdateOpt = []string{"update", "no-update"}
+)
+
+// Validate checks the addresses, MTU, link settings and DHCP client options of
+// the interface. The firewall attachment and sub-interfaces are validated by
+// their own clients.
+func (e *Ethernet) Validate() error {
+	v := new(validator)
+	v.validateName("id", e.ID)
+
+	for i, addr := range e.Addresses {
+		v.validateInterfaceAddress(fmt.Sprintf("address[%d]", i), addr, true)
+	}
+	v.validateMTU("mtu", e.MTU)
+
+	v.validateOneOf("speed", e.Speed, speeds)
+	v.validateOneOf("duplex", e.Duplex, duplexes)
+	// EdgeOS only accepts speed and duplex together, and either both or neither
+	// must be auto.
+	if (e.Speed == "") != (e.Duplex == "") {
+		v.add("speed", "speed and duplex must be set together")
+	} else if (e.Speed == "auto") != (e.Duplex == "auto") {
+		v.add("speed", "speed and duplex must both be auto or both be set explicitly")
+	}
+
+	if o := e.DHCPOptions; o != nil {
+		o.validate(v, "dhcp-options")
+	}
+	return v.err()
+}
+
+func (o *DHCPOptions) validate(v *validator, prefix string) {
+	v.validateOneOf(join(prefix, "default-route"), o.DefaultRoute, dhcpUpdateOpt)
+	v.validateOneOf(join(prefix, "name-server"), o.NameServer, dhcpUpdateOpt)
+	if d := o.DefaultRouteDistance; d != 0 && (d < minDistance || d > maxDistance) {
+		v.add(join(prefix, "default-route-distance"), "%d must be between %d and %d", d, minDistance, maxDistance)
+	}
+}
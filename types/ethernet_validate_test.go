@@ -0,0 +1,34 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEthernetValidate(t *testing.T) {
+	require.NoError(t, (&Ethernet{
+		ID:          "eth0",
+		Addresses:   []string{"dhcp", "192.168.1.1/24", "2001:db8::1/64"},
+		Speed:       "1000",
+		Duplex:      "full",
+		MTU:         1500,
+		DHCPOptions: &DHCPOptions{DefaultRoute: "no-update", DefaultRouteDistance: 210},
+	}).Validate())
+
+	require.EqualError(t, (&Ethernet{
+		ID:          "eth0",
+		Addresses:   []string{"192.168.1.1", "dhcp4"},
+		Speed:       "auto",
+		Duplex:      "full",
+		MTU:         9001,
+		DHCPOptions: &DHCPOptions{NameServer: "keep", DefaultRouteDistance: 256},
+	}).Validate(), `address[0]: "192.168.1.1" must be dhcp, dhcpv6 or an address in CIDR notation; `+
+		`address[1]: "dhcp4" must be dhcp, dhcpv6 or an address in CIDR notation; `+
+		`mtu: 9001 must be between 68 and 9000; `+
+		`speed: speed and duplex must both be auto or both be set explicitly; `+
+		`dhcp-options.name-server: "keep" must be one of update, no-update; `+
+		`dhcp-options.default-route-distance: 256 must be between 1 and 255`)
+
+	require.EqualError(t, (&Ethernet{ID: "eth0", Speed: "100"}).Validate(), `speed: speed and duplex must be set together`)
+}
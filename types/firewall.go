@@ -97,6 +97,7 @@ type Rule struct {
 	State       *State       `json:"state" tfsdk:"state"`
 	Log         *bool        `json:"-" tfsdk:"log"`
 	codecMode   CodecMode
+	opMode      OpMode
 }
 
 type Ruleset struct {
@@ -144,6 +145,14 @@ func (r *Rule) SetCodecMode(c CodecMode) {
 	(*r).codecMode = c
 }
 
+// SetOpMode controls how the rule is encoded in the remote codec mode. When
+// set to OpModeDelete, every non-empty field names a node that should be
+// removed from the rule rather than a value that should be set. A rule with
+// only a priority is removed entirely.
+func (r *Rule) SetOpMode(m OpMode) {
+	(*r).opMode = m
+}
+
 func (s *Source) toPort() string {
 	return s.Port.toPort()
 }
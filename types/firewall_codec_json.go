@@ -311,6 +311,10 @@ func (r *Rule) MarshalJSON() ([]byte, error) {
 				Log:      toEnableDisable(r.Log),
 				Alias:    (*Alias)(r),
 			}
+		} else if r.opMode == OpModeDelete {
+			if nodes := r.deleteNodes(); len(nodes) > 0 {
+				data = nodes
+			}
 		} else {
 			if r.Protocol == "*" {
 				r.Protocol = ""
@@ -348,6 +352,64 @@ func (r *Rule) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// deleteNodes returns the nodes of the rule that should be deleted. A rule
+// without any nodes is deleted entirely. Nested
+// source and destination nodes are deleted leaf by leaf so that a partial
+// change does not remove the sibling values that are still in use.
+func (r *Rule) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if r.Description != nil {
+		nodes["description"] = nil
+	}
+	if r.Action != "" {
+		nodes["action"] = nil
+	}
+	if r.Protocol != "" && r.Protocol != "*" {
+		nodes["protocol"] = nil
+	}
+	if r.Log != nil {
+		nodes["log"] = nil
+	}
+	if r.State != nil {
+		nodes["state"] = nil
+	}
+	if r.Source != nil {
+		nodes["source"] = deleteEndpointNodes(r.Source.Address, r.Source.AddressGroup, r.Source.PortGroup, r.Source.Port, r.Source.MAC)
+	}
+	if r.Destination != nil {
+		nodes["destination"] = deleteEndpointNodes(r.Destination.Address, r.Destination.AddressGroup, r.Destination.PortGroup, r.Destination.Port, nil)
+	}
+
+	return nodes
+}
+
+func deleteEndpointNodes(address, addressGroup, portGroup *string, port *PortRange, mac *string) map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if address != nil {
+		nodes["address"] = nil
+	}
+	if port != nil {
+		nodes["port"] = nil
+	}
+	if mac != nil {
+		nodes["mac-address"] = nil
+	}
+	if addressGroup != nil || portGroup != nil {
+		g := map[string]interface{}{}
+		if addressGroup != nil {
+			g["address-group"] = nil
+		}
+		if portGroup != nil {
+			g["port-group"] = nil
+		}
+		nodes["group"] = g
+	}
+
+	return nodes
+}
+
 func toBoolPtr(val string) *bool {
 	t, f := true, false
 
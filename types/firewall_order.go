@@ -0,0 +1,272 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// MinRulePriority is the lowest priority EdgeOS accepts for a rule.
+	MinRulePriority = 1
+	// MaxRulePriority is the highest priority EdgeOS accepts for a rule.
+	MaxRulePriority = 9999
+	// DefaultRuleStep is the distance between priorities used when rules
+	// have to be renumbered to make room for an inserted rule.
+	DefaultRuleStep = 10
+)
+
+// RuleRef identifies an existing rule in a ruleset. If Priority is non-zero the
+// rule is located by priority, otherwise it is located by Description.
+type RuleRef struct {
+	Priority    int
+	Description string
+}
+
+func (ref RuleRef) String() string {
+	if ref.Priority != 0 {
+		return fmt.Sprintf("rule %d", ref.Priority)
+	}
+	return fmt.Sprintf("rule %q", ref.Description)
+}
+
+// InsertOption configures how a rule is inserted into a ruleset.
+type InsertOption func(*insertOptions)
+
+type insertOptions struct {
+	step int
+}
+
+// WithRuleStep sets the distance between priorities used when a rule is
+// appended after the last rule or the rules have to be renumbered. It
+// defaults to DefaultRuleStep.
+func WithRuleStep(step int) InsertOption {
+	return func(o *insertOptions) {
+		o.step = step
+	}
+}
+
+// Clone returns a deep copy of the ruleset, so that the copy can be reordered
+// and changed while the ruleset still describes the configuration it was read
+// from, e.g. to build the desired ruleset passed to SyncRules.
+func (rs *Ruleset) Clone() *Ruleset {
+	tmp := *rs
+	tmp.Description = cloneString(rs.Description)
+	tmp.DefaultLogging = cloneBool(rs.DefaultLogging)
+	if rs.Rules != nil {
+		tmp.Rules = make([]*Rule, len(rs.Rules))
+		for i, rule := range rs.Rules {
+			tmp.Rules[i] = rule.Clone()
+		}
+	}
+	return &tmp
+}
+
+// Clone returns a deep copy of the rule.
+func (r *Rule) Clone() *Rule {
+	if r == nil {
+		return nil
+	}
+
+	tmp := *r
+	tmp.Description = cloneString(r.Description)
+	tmp.Log = cloneBool(r.Log)
+	if s := r.Source; s != nil {
+		tmp.Source = &Source{
+			Address:      cloneString(s.Address),
+			AddressGroup: cloneString(s.AddressGroup),
+			PortGroup:    cloneString(s.PortGroup),
+			Port:         clonePortRange(s.Port),
+			MAC:          cloneString(s.MAC),
+		}
+	}
+	if d := r.Destination; d != nil {
+		tmp.Destination = &Destination{
+			Address:      cloneString(d.Address),
+			AddressGroup: cloneString(d.AddressGroup),
+			PortGroup:    cloneString(d.PortGroup),
+			Port:         clonePortRange(d.Port),
+		}
+	}
+	if st := r.State; st != nil {
+		tmp.State = &State{
+			Established: cloneBool(st.Established),
+			Invalid:     cloneBool(st.Invalid),
+			New:         cloneBool(st.New),
+			Related:     cloneBool(st.Related),
+		}
+	}
+	return &tmp
+}
+
+func cloneString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	tmp := *s
+	return &tmp
+}
+
+func cloneBool(b *bool) *bool {
+	if b == nil {
+		return nil
+	}
+	tmp := *b
+	return &tmp
+}
+
+func clonePortRange(r *PortRange) *PortRange {
+	if r == nil {
+		return nil
+	}
+	tmp := *r
+	return &tmp
+}
+
+// SortRules orders the rules of the ruleset by priority.
+func (rs *Ruleset) SortRules() {
+	rs.Rules = rs.sortedRules()
+}
+
+// sortedRules returns the rules ordered by priority without reordering the
+// rules of the ruleset.
+func (rs *Ruleset) sortedRules() []*Rule {
+	rules := make([]*Rule, len(rs.Rules))
+	copy(rules, rs.Rules)
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Priority < rules[j].Priority
+	})
+	return rules
+}
+
+// FindRule returns the index of the rule referenced by ref in the rules
+// ordered by priority. The ruleset is left unchanged.
+func (rs *Ruleset) FindRule(ref RuleRef) (int, error) {
+	found := -1
+	for i, rule := range rs.sortedRules() {
+		var match bool
+		if ref.Priority != 0 {
+			match = rule.Priority == ref.Priority
+		} else {
+			match = rule.Description != nil && *rule.Description == ref.Description
+		}
+		if !match {
+			continue
+		}
+		if found >= 0 {
+			return -1, fmt.Errorf("The %s is ambiguous in ruleset %s.", ref, rs.Name)
+		}
+		found = i
+	}
+
+	if found < 0 {
+		return -1, fmt.Errorf("The %s does not exist in ruleset %s.", ref, rs.Name)
+	}
+	return found, nil
+}
+
+// InsertRuleBefore inserts r immediately before the rule referenced by ref.
+// See InsertRuleAt for how the priority of r is chosen.
+func (rs *Ruleset) InsertRuleBefore(ref RuleRef, r *Rule, opts ...InsertOption) error {
+	i, err := rs.FindRule(ref)
+	if err != nil {
+		return err
+	}
+	return rs.InsertRuleAt(i, r, opts...)
+}
+
+// InsertRuleAfter inserts r immediately after the rule referenced by ref.
+// See InsertRuleAt for how the priority of r is chosen.
+func (rs *Ruleset) InsertRuleAfter(ref RuleRef, r *Rule, opts ...InsertOption) error {
+	i, err := rs.FindRule(ref)
+	if err != nil {
+		return err
+	}
+	return rs.InsertRuleAt(i+1, r, opts...)
+}
+
+// InsertRuleAt inserts r so that it becomes the i-th rule in priority order.
+// The priority of r is overwritten with a free priority between its
+// neighbours. If there is no free priority, every rule is renumbered using
+// the step of WithRuleStep while preserving order. The rules of the ruleset
+// are replaced by renumbered copies only if the insert succeeds.
+func (rs *Ruleset) InsertRuleAt(i int, r *Rule, opts ...InsertOption) error {
+	o := &insertOptions{
+		step: DefaultRuleStep,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.step < 1 {
+		return fmt.Errorf("The renumbering step must be positive, got %d.", o.step)
+	}
+
+	sorted := rs.sortedRules()
+	if i < 0 || i > len(sorted) {
+		return fmt.Errorf("The position %d is out of range for ruleset %s.", i, rs.Name)
+	}
+
+	lower, upper := MinRulePriority-1, MaxRulePriority+1
+	if i > 0 {
+		lower = sorted[i-1].Priority
+	}
+	if i < len(sorted) {
+		upper = sorted[i].Priority
+	}
+
+	rules := make([]*Rule, 0, len(sorted)+1)
+	rules = append(rules, sorted[:i]...)
+	rules = append(rules, r)
+	rules = append(rules, sorted[i:]...)
+
+	if upper-lower > 1 {
+		if i == len(sorted) && lower+o.step < upper {
+			r.Priority = lower + o.step
+		} else {
+			r.Priority = lower + (upper-lower)/2
+		}
+		rs.Rules = rules
+		return nil
+	}
+
+	// Renumber copies of the existing rules so that they are left unchanged
+	// if the rules do not fit.
+	tmp := &Ruleset{Name: rs.Name, Rules: rules}
+	for j, rule := range tmp.Rules {
+		if j != i {
+			tmp.Rules[j] = rule.Clone()
+		}
+	}
+	if err := tmp.renumber(o.step); err != nil {
+		return err
+	}
+	rs.Rules = tmp.Rules
+	return nil
+}
+
+// Renumber assigns the priorities step, 2*step, 3*step, ... to the rules while
+// preserving their order. The rules of the ruleset are replaced by
+// renumbered copies only if the step fits every rule.
+func (rs *Ruleset) Renumber(step int) error {
+	tmp := rs.Clone()
+	tmp.SortRules()
+	if err := tmp.renumber(step); err != nil {
+		return err
+	}
+	rs.Rules = tmp.Rules
+	return nil
+}
+
+// renumber assigns priorities in the current order of the rules.
+func (rs *Ruleset) renumber(step int) error {
+	if step < 1 {
+		return fmt.Errorf("The renumbering step must be positive, got %d.", step)
+	}
+	if len(rs.Rules)*step > MaxRulePriority {
+		return fmt.Errorf("The ruleset %s has too many rules to be renumbered with a step of %d.", rs.Name, step)
+	}
+
+	for i, rule := range rs.Rules {
+		rule.Priority = (i + 1) * step
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulesetInsertRule(t *testing.T) {
+	newRuleset := func(priorities ...int) *Ruleset {
+		rs := &Ruleset{Name: "WAN_IN"}
+		for _, p := range priorities {
+			rs.Rules = append(rs.Rules, &Rule{
+				Priority:    p,
+				Description: strptr("existing"),
+			})
+		}
+		rs.Rules[0].Description = strptr("first")
+		return rs
+	}
+
+	priorities := func(rs *Ruleset) []int {
+		vals := []int{}
+		for _, r := range rs.Rules {
+			vals = append(vals, r.Priority)
+		}
+		return vals
+	}
+
+	for _, test := range []struct {
+		name       string
+		ruleset    *Ruleset
+		insert     func(*Ruleset, *Rule) error
+		expected   []int
+		inserted   int
+		shouldFail bool
+	}{
+		{
+			name:    "before with a gap",
+			ruleset: newRuleset(10, 20),
+			insert: func(rs *Ruleset, r *Rule) error {
+				return rs.InsertRuleBefore(RuleRef{Priority: 20}, r)
+			},
+			expected: []int{10, 15, 20},
+			inserted: 15,
+		},
+		{
+			name:    "after the last rule",
+			ruleset: newRuleset(10, 20),
+			insert: func(rs *Ruleset, r *Rule) error {
+				return rs.InsertRuleAfter(RuleRef{Priority: 20}, r)
+			},
+			expected: []int{10, 20, 30},
+			inserted: 30,
+		},
+		{
+			name:    "before the first rule by description",
+			ruleset: newRuleset(10, 20),
+			insert: func(rs *Ruleset, r *Rule) error {
+				return rs.InsertRuleBefore(RuleRef{Description: "first"}, r)
+			},
+			expected: []int{5, 10, 20},
+			inserted: 5,
+		},
+		{
+			name:    "between adjacent rules renumbers",
+			ruleset: newRuleset(10, 11, 12),
+			insert: func(rs *Ruleset, r *Rule) error {
+				return rs.InsertRuleAfter(RuleRef{Priority: 10}, r)
+			},
+			expected: []int{10, 20, 30, 40},
+			inserted: 20,
+		},
+		{
+			name:    "ambiguous description",
+			ruleset: newRuleset(10, 20, 30),
+			insert: func(rs *Ruleset, r *Rule) error {
+				return rs.InsertRuleAfter(RuleRef{Description: "existing"}, r)
+			},
+			shouldFail: true,
+		},
+		{
+			name:    "missing priority",
+			ruleset: newRuleset(10),
+			insert: func(rs *Ruleset, r *Rule) error {
+				return rs.InsertRuleAfter(RuleRef{Priority: 15}, r)
+			},
+			shouldFail: true,
+		},
+	} {
+		rule := new(Rule)
+		err := test.insert(test.ruleset, rule)
+		if test.shouldFail {
+			require.Error(t, err, test.name)
+			continue
+		}
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.expected, priorities(test.ruleset), test.name)
+		require.Equal(t, test.inserted, rule.Priority, test.name)
+	}
+}
+
+func TestRulesetRenumber(t *testing.T) {
+	rs := &Ruleset{
+		Rules: []*Rule{
+			{Priority: 7, Description: strptr("b")},
+			{Priority: 3, Description: strptr("a")},
+			{Priority: 9, Description: strptr("c")},
+		},
+	}
+
+	require.NoError(t, rs.Renumber(100))
+	for i, expected := range []string{"a", "b", "c"} {
+		require.Equal(t, expected, *rs.Rules[i].Description)
+		require.Equal(t, (i+1)*100, rs.Rules[i].Priority)
+	}
+
+	require.Error(t, rs.Renumber(0))
+	require.Error(t, rs.Renumber(5000))
+}
+
+func TestRulesetInsertRuleWithStep(t *testing.T) {
+	rs := &Ruleset{
+		Name: "WAN_IN",
+		Rules: []*Rule{
+			{Priority: 1},
+			{Priority: 2},
+		},
+	}
+
+	appended := new(Rule)
+	require.NoError(t, rs.InsertRuleAt(2, appended, WithRuleStep(100)))
+	require.Equal(t, 102, appended.Priority)
+
+	inserted := new(Rule)
+	require.NoError(t, rs.InsertRuleAfter(RuleRef{Priority: 1}, inserted, WithRuleStep(1000)))
+	require.Equal(t, 2000, inserted.Priority)
+	for i, expected := range []int{1000, 2000, 3000, 4000} {
+		require.Equal(t, expected, rs.Rules[i].Priority)
+	}
+
+	require.Error(t, rs.InsertRuleAt(0, new(Rule), WithRuleStep(0)))
+}
+
+func TestRulesetInsertRuleFailureLeavesRuleset(t *testing.T) {
+	first, second := &Rule{Priority: 1}, &Rule{Priority: 2}
+	rs := &Ruleset{Name: "WAN_IN", Rules: []*Rule{second, first}}
+
+	err := rs.InsertRuleAfter(RuleRef{Priority: 1}, new(Rule), WithRuleStep(5000))
+	require.Error(t, err)
+	require.Equal(t, []*Rule{second, first}, rs.Rules)
+	require.Equal(t, 1, first.Priority)
+	require.Equal(t, 2, second.Priority)
+
+	require.Error(t, rs.Renumber(5000))
+	require.Equal(t, []*Rule{second, first}, rs.Rules)
+}
+
+func TestRulesetClone(t *testing.T) {
+	rs := &Ruleset{
+		Name:        "WAN_IN",
+		Description: strptr("inbound"),
+		Rules: []*Rule{
+			{
+				Priority:    10,
+				Description: strptr("ssh"),
+				Destination: &Destination{Port: &PortRange{From: 22, To: 22}},
+			},
+		},
+	}
+
+	clone := rs.Clone()
+	require.Equal(t, rs, clone)
+
+	require.NoError(t, clone.Renumber(100))
+	*clone.Description = "changed"
+	clone.Rules[0].Destination.Port.From = 2222
+
+	require.Equal(t, 10, rs.Rules[0].Priority)
+	require.Equal(t, "inbound", *rs.Description)
+	require.Equal(t, 22, rs.Rules[0].Destination.Port.From)
+}
@@ -0,0 +1,193 @@
+package types
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+const (
+	minPort = 1
+	maxPort = 65535
+)
+
+var (
+	ruleActions = []string{"accept", "drop", "reject"}
+	// portProtocols are the only protocols that a port or port group may be
+	// combined with.
+	portProtocols = []string{"tcp", "udp", "tcp_udp"}
+)
+
+// Validate checks the ruleset and every rule it contains.
+func (rs *Ruleset) Validate() error {
+	v := new(validator)
+	rs.validate(v)
+	return v.err()
+}
+
+func (rs *Ruleset) validate(v *validator) {
+	v.validateName("name", rs.Name)
+
+	if rs.DefaultAction != "" && !contains(ruleActions, rs.DefaultAction) {
+		v.add("default-action", "%q must be one of %s", rs.DefaultAction, strings.Join(ruleActions, ", "))
+	}
+
+	seen := map[int]bool{}
+	for i, rule := range rs.Rules {
+		if rule == nil {
+			v.add(fmt.Sprintf("rule[%d]", i), "must not be null")
+			continue
+		}
+
+		prefix := fmt.Sprintf("rule[%d]", rule.Priority)
+		if seen[rule.Priority] {
+			v.add(prefix, "is defined more than once")
+		}
+		seen[rule.Priority] = true
+
+		rule.validate(v, prefix)
+	}
+}
+
+// Validate checks the rule on its own. Group references are not resolved.
+func (r *Rule) Validate() error {
+	v := new(validator)
+	r.validate(v, "")
+	return v.err()
+}
+
+func (r *Rule) validate(v *validator, prefix string) {
+	if r.Priority < MinRulePriority || r.Priority > MaxRulePriority {
+		v.add(join(prefix, "priority"), "%d must be between %d and %d", r.Priority, MinRulePriority, MaxRulePriority)
+	}
+
+	if !contains(ruleActions, r.Action) {
+		v.add(join(prefix, "action"), "%q must be one of %s", r.Action, strings.Join(ruleActions, ", "))
+	}
+
+	allowsPorts := contains(portProtocols, r.Protocol)
+
+	if s := r.Source; s != nil {
+		validateEndpoint(v, join(prefix, "source"), s.Address, s.AddressGroup, s.PortGroup, s.Port, allowsPorts, r.Protocol)
+		if s.MAC != nil {
+			if _, err := net.ParseMAC(*s.MAC); err != nil {
+				v.add(join(prefix, "source.mac-address"), "%q is not a valid MAC address", *s.MAC)
+			}
+		}
+	}
+
+	if d := r.Destination; d != nil {
+		validateEndpoint(v, join(prefix, "destination"), d.Address, d.AddressGroup, d.PortGroup, d.Port, allowsPorts, r.Protocol)
+	}
+}
+
+func validateEndpoint(v *validator, prefix string, address, addressGroup, portGroup *string, port *PortRange, allowsPorts bool, protocol string) {
+	hasAddress := address != nil && *address != ""
+	hasAddressGroup := addressGroup != nil && *addressGroup != ""
+
+	if hasAddress && hasAddressGroup {
+		v.add(prefix, "address and group.address-group are mutually exclusive")
+	}
+
+	if hasAddress {
+		v.validateIPv4(join(prefix, "address"), strings.TrimPrefix(*address, "!"))
+	}
+
+	if hasAddressGroup {
+		v.validateName(join(prefix, "group.address-group"), *addressGroup)
+	}
+
+	if port != nil {
+		if !allowsPorts {
+			v.add(join(prefix, "port"), "requires protocol %s, got %q", strings.Join(portProtocols, ", "), protocol)
+		}
+		port.validate(v, join(prefix, "port"))
+	}
+
+	if portGroup != nil && *portGroup != "" {
+		if !allowsPorts {
+			v.add(join(prefix, "group.port-group"), "requires protocol %s, got %q", strings.Join(portProtocols, ", "), protocol)
+		}
+		v.validateName(join(prefix, "group.port-group"), *portGroup)
+	}
+}
+
+func (r *PortRange) validate(v *validator, field string) {
+	validatePort(v, field, r.From)
+	if r.To != r.From {
+		validatePort(v, field, r.To)
+	}
+	if r.From > r.To {
+		v.add(field, "range %s starts after it ends", r.toPort())
+	}
+}
+
+func validatePort(v *validator, field string, port int) {
+	if port < minPort || port > maxPort {
+		v.add(field, "%d must be between %d and %d", port, minPort, maxPort)
+	}
+}
+
+// Validate checks the name of the address group and that every entry is an
+// IPv4 address, CIDR or range.
+func (g *AddressGroup) Validate() error {
+	v := new(validator)
+	v.validateName("name", g.Name)
+	for i, cidr := range g.Cidrs {
+		v.validateIPv4(fmt.Sprintf("address[%d]", i), cidr)
+	}
+	return v.err()
+}
+
+// Validate checks the name of the port group and every port and port range.
+func (g *PortGroup) Validate() error {
+	v := new(validator)
+	v.validateName("name", g.Name)
+	for i, port := range g.Ports {
+		validatePort(v, fmt.Sprintf("port[%d]", i), port)
+	}
+	// Ranges are encoded after the individual ports.
+	for i, portRange := range g.Ranges {
+		if portRange == nil {
+			continue
+		}
+		portRange.validate(v, fmt.Sprintf("port[%d]", len(g.Ports)+i))
+	}
+	return v.err()
+}
+
+// Validate checks that every attached ruleset name is usable and, if set, that
+// the path names a supported interface.
+func (a *FirewallAttachment) Validate() error {
+	v := new(validator)
+	if a.Path != nil {
+		if err := a.Path.Validate(); err != nil {
+			v.add("interface", "%v", err)
+		}
+	}
+	for _, dir := range []struct {
+		field string
+		name  *string
+	}{
+		{"in.name", a.In},
+		{"out.name", a.Out},
+		{"local.name", a.Local},
+		{"in.ipv6-name", a.IPv6In},
+		{"out.ipv6-name", a.IPv6Out},
+		{"local.ipv6-name", a.IPv6Local},
+	} {
+		if dir.name != nil && *dir.name != "" {
+			v.validateName(dir.field, *dir.name)
+		}
+	}
+	return v.err()
+}
+
+func contains(vals []string, val string) bool {
+	for _, elem := range vals {
+		if elem == val {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulesetValidate(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		rs       *Ruleset
+		expected []string
+	}{
+		{
+			name: "valid",
+			rs: &Ruleset{
+				Name:          "WAN_IN",
+				DefaultAction: "drop",
+				Rules: []*Rule{
+					{
+						Priority: 10,
+						Action:   "accept",
+						Protocol: "tcp",
+						Source: &Source{
+							Address: strptr("!192.168.1.0/24"),
+							MAC:     strptr("00:11:22:33:44:55"),
+						},
+						Destination: &Destination{
+							AddressGroup: strptr("servers"),
+							Port:         &PortRange{From: 8000, To: 8080},
+						},
+					},
+					{
+						Priority: 20,
+						Action:   "drop",
+						Protocol: "*",
+					},
+				},
+			},
+		},
+		{
+			name: "every rule is reported",
+			rs: &Ruleset{
+				Name:          "WAN IN",
+				DefaultAction: "allow",
+				Rules: []*Rule{
+					{
+						Priority: 10,
+						Action:   "accept",
+						Protocol: "icmp",
+						Destination: &Destination{
+							Port: &PortRange{From: 80, To: 80},
+						},
+					},
+					{
+						Priority: 20,
+						Action:   "permit",
+						Protocol: "udp",
+						Source: &Source{
+							Address:      strptr("10.0.0.300"),
+							AddressGroup: strptr("clients"),
+							Port:         &PortRange{From: 70000, To: 53},
+							MAC:          strptr("zz"),
+						},
+					},
+					{
+						Priority: 20,
+						Action:   "drop",
+					},
+				},
+			},
+			expected: []string{
+				`name: "WAN IN" must not contain whitespace or quotes`,
+				`default-action: "allow" must be one of accept, drop, reject`,
+				`rule[10].destination.port: requires protocol tcp, udp, tcp_udp, got "icmp"`,
+				`rule[20].action: "permit" must be one of accept, drop, reject`,
+				`rule[20].source: address and group.address-group are mutually exclusive`,
+				`rule[20].source.address: "10.0.0.300" is not a valid IPv4 address, CIDR or range`,
+				`rule[20].source.port: 70000 must be between 1 and 65535`,
+				`rule[20].source.port: range 70000-53 starts after it ends`,
+				`rule[20].source.mac-address: "zz" is not a valid MAC address`,
+				`rule[20]: is defined more than once`,
+			},
+		},
+	} {
+		err := test.rs.Validate()
+		if len(test.expected) == 0 {
+			require.NoError(t, err, test.name)
+			continue
+		}
+
+		require.IsType(t, ValidationError{}, err, test.name)
+		msgs := []string{}
+		for _, fieldErr := range err.(ValidationError) {
+			msgs = append(msgs, fieldErr.Error())
+		}
+		require.Equal(t, test.expected, msgs, test.name)
+	}
+}
+
+func TestGroupValidate(t *testing.T) {
+	require.NoError(t, (&AddressGroup{
+		Name:  "servers",
+		Cidrs: []string{"10.0.0.1", "10.0.1.0/24", "10.0.2.1-10.0.2.9"},
+	}).Validate())
+
+	require.EqualError(t, (&AddressGroup{
+		Name:  "servers",
+		Cidrs: []string{"10.0.0.1", "10.0.1.0/33", "fe80::1"},
+	}).Validate(), `address[1]: "10.0.1.0/33" is not a valid IPv4 address, CIDR or range; address[2]: "fe80::1" is not a valid IPv4 address, CIDR or range`)
+
+	require.NoError(t, (&PortGroup{
+		Name:  "web",
+		Ports: []int{80, 443},
+	}).WithRanges(8000, 8080).Validate())
+
+	require.EqualError(t, (&PortGroup{
+		Name:  "",
+		Ports: []int{0},
+	}).WithRanges(9000, 8000).Validate(), `name: must not be empty; port[0]: 0 must be between 1 and 65535; port[1]: range 9000-8000 starts after it ends`)
+}
@@ -0,0 +1,523 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// InterfacePath addresses an interface below the interfaces node by type and
+// name followed by the sub-interfaces leading to it, e.g. "ethernet eth0",
+// "ethernet eth0 vif 20" or "ethernet eth0 pppoe 0".
+type InterfacePath []string
+
+// subInterfaces lists the sub-interfaces each interface type may have.
+var subInterfaces = map[string][]string{
+	"bonding":         {"vif"},
+	"bridge":          {"vif"},
+	"ethernet":        {"vif", "pppoe"},
+	"openvpn":         nil,
+	"pseudo-ethernet": {"vif"},
+	"switch":          {"vif"},
+	"vti":             nil,
+	"wireguard":       nil,
+}
+
+// ParseInterfacePath parses a path such as "ethernet eth0 vif 20".
+func ParseInterfacePath(s string) (InterfacePath, error) {
+	p := InterfacePath(strings.Fields(s))
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p InterfacePath) String() string {
+	return strings.Join(p, " ")
+}
+
+// Type returns the type of the top level interface, e.g. ethernet.
+func (p InterfacePath) Type() string {
+	if len(p) == 0 {
+		return ""
+	}
+	return p[0]
+}
+
+// Name returns the name the kernel gives the interface, e.g. eth0.20 for
+// "ethernet eth0 vif 20" and pppoe0 for "ethernet eth0 pppoe 0".
+func (p InterfacePath) Name() string {
+	if len(p) < 2 {
+		return ""
+	}
+	name := p[1]
+	for i := 2; i+1 < len(p); i += 2 {
+		switch p[i] {
+		case "vif":
+			name += "." + p[i+1]
+		case "pppoe":
+			name = "pppoe" + p[i+1]
+		}
+	}
+	return name
+}
+
+// Parent returns the path of the interface the sub-interface belongs to, or
+// nil for a top level interface.
+func (p InterfacePath) Parent() InterfacePath {
+	if len(p) <= 2 {
+		return nil
+	}
+	return append(InterfacePath{}, p[:len(p)-2]...)
+}
+
+// Child returns the path of a sub-interface, e.g. Child("vif", "20").
+func (p InterfacePath) Child(kind, id string) InterfacePath {
+	return append(append(InterfacePath{}, p...), kind, id)
+}
+
+// Validate ensures the path names a kind of interface EdgeOS supports, such as
+// a VLAN of an ethernet interface or a PPPoE session on a VLAN.
+func (p InterfacePath) Validate() error {
+	if len(p) < 2 || len(p)%2 != 0 {
+		return fmt.Errorf("The interface path %q must be a type and name followed by sub-interface kind and id pairs.", p.String())
+	}
+
+	allowed, ok := subInterfaces[p[0]]
+	if !ok {
+		return fmt.Errorf("The interface type %s is not supported.", p[0])
+	}
+	if strings.TrimSpace(p[1]) == "" {
+		return fmt.Errorf("The interface path %q has an empty name.", p.String())
+	}
+
+	for i := 2; i < len(p); i += 2 {
+		kind, id := p[i], p[i+1]
+		if !contains(allowed, kind) {
+			return fmt.Errorf("The interface %s cannot have a %s.", p[:i].String(), kind)
+		}
+		if n, err := strconv.Atoi(id); err != nil || n < 0 {
+			return fmt.Errorf("The %s id %q must be a non-negative number.", kind, id)
+		}
+
+		allowed = nil
+		if kind == "vif" && p[0] == "ethernet" {
+			allowed = []string{"pppoe"}
+		}
+	}
+	return nil
+}
+
+// Attachment returns the firewall attachment of the interface at the path. It
+// returns nil if the interface has no attachment and an error if the interface
+// does not exist.
+func (i *Interfaces) Attachment(path InterfacePath) (*FirewallAttachment, error) {
+	n, err := i.lookup(path, false)
+	if err != nil {
+		return nil, err
+	}
+	return *n.firewall, nil
+}
+
+// SetAttachment sets the firewall attachment of the interface at the path,
+// creating the interfaces along the path as needed. It is meant for building
+// the SET and DELETE sections of an operation.
+func (i *Interfaces) SetAttachment(path InterfacePath, a *FirewallAttachment) error {
+	n, err := i.lookup(path, true)
+	if err != nil {
+		return err
+	}
+	*n.firewall = a
+	return nil
+}
+
+// VIFs returns the VLANs of the interface at the path, keyed by VLAN id. It
+// returns an error if the interface does not exist or cannot have VLANs.
+func (i *Interfaces) VIFs(parent InterfacePath) (map[string]*VIF, error) {
+	n, err := i.lookup(parent, false)
+	if err != nil {
+		return nil, err
+	}
+	if n.vifs == nil {
+		return nil, fmt.Errorf("The interface %s cannot have a vif.", parent)
+	}
+	return *n.vifs, nil
+}
+
+// SetVIF sets the VLAN with the id of v on the interface at the path, creating
+// the interfaces along the path as needed. It is meant for building the SET
+// and DELETE sections of an operation.
+func (i *Interfaces) SetVIF(parent InterfacePath, id string, v *VIF) error {
+	if err := parent.Child("vif", id).Validate(); err != nil {
+		return err
+	}
+	n, err := i.lookup(parent, true)
+	if err != nil {
+		return err
+	}
+	if *n.vifs == nil {
+		*n.vifs = map[string]*VIF{}
+	}
+	(*n.vifs)[id] = v
+	return nil
+}
+
+// PPPoEs returns the PPPoE sessions of the interface at the path, keyed by
+// session number. It returns an error if the interface does not exist or
+// cannot have PPPoE sessions.
+func (i *Interfaces) PPPoEs(parent InterfacePath) (map[string]*PPPoE, error) {
+	n, err := i.lookup(parent, false)
+	if err != nil {
+		return nil, err
+	}
+	if n.pppoes == nil {
+		return nil, fmt.Errorf("The interface %s cannot have a pppoe.", parent)
+	}
+	return *n.pppoes, nil
+}
+
+// SetPPPoE sets the PPPoE session with the id on the interface at the path,
+// creating the interfaces along the path as needed. It is meant for building
+// the SET and DELETE sections of an operation.
+func (i *Interfaces) SetPPPoE(parent InterfacePath, id string, p *PPPoE) error {
+	if err := parent.Child("pppoe", id).Validate(); err != nil {
+		return err
+	}
+	n, err := i.lookup(parent, true)
+	if err != nil {
+		return err
+	}
+	if *n.pppoes == nil {
+		*n.pppoes = map[string]*PPPoE{}
+	}
+	(*n.pppoes)[id] = p
+	return nil
+}
+
+// PPPoEPath returns the path of the PPPoE session the kernel names name, e.g.
+// ethernet eth0 pppoe 0 for pppoe0.
+func (i *Interfaces) PPPoEPath(name string) (InterfacePath, error) {
+	id := strings.TrimPrefix(name, "pppoe")
+	if id == name {
+		return nil, fmt.Errorf("The interface %s is not a PPPoE session.", name)
+	}
+
+	for ethID, e := range i.Ethernet {
+		if e == nil {
+			continue
+		}
+		if _, ok := e.PPPoE[id]; ok {
+			return InterfacePath{"ethernet", ethID, "pppoe", id}, nil
+		}
+		for vifID, v := range e.VIF {
+			if v == nil {
+				continue
+			}
+			if _, ok := v.PPPoE[id]; ok {
+				return InterfacePath{"ethernet", ethID, "vif", vifID, "pppoe", id}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("The interface %s does not exist.", name)
+}
+
+// SubInterfaces returns the paths of the VLANs and PPPoE sessions directly
+// below the interface at the path, ordered by path.
+func (i *Interfaces) SubInterfaces(path InterfacePath) ([]InterfacePath, error) {
+	n, err := i.lookup(path, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var children []InterfacePath
+	if n.vifs != nil {
+		for id := range *n.vifs {
+			children = append(children, path.Child("vif", id))
+		}
+	}
+	if n.pppoes != nil {
+		for id := range *n.pppoes {
+			children = append(children, path.Child("pppoe", id))
+		}
+	}
+
+	sort.Slice(children, func(a, b int) bool {
+		return lessPath(children[a], children[b])
+	})
+	return children, nil
+}
+
+// SubInterfacesError is returned when deleting an interface that still has
+// VLANs or PPPoE sessions.
+type SubInterfacesError struct {
+	Interface     InterfacePath
+	SubInterfaces []InterfacePath
+}
+
+func (e *SubInterfacesError) Error() string {
+	names := make([]string, len(e.SubInterfaces))
+	for i, p := range e.SubInterfaces {
+		names[i] = p.String()
+	}
+	return fmt.Sprintf("The interface %s still has sub-interfaces: %s.", e.Interface, strings.Join(names, ", "))
+}
+
+// EnsureNoSubInterfaces returns a *SubInterfacesError if the interface at the
+// path still has VLANs or PPPoE sessions, and an error if it does not exist.
+func (i *Interfaces) EnsureNoSubInterfaces(path InterfacePath) error {
+	children, err := i.SubInterfaces(path)
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 {
+		return &SubInterfacesError{
+			Interface:     path,
+			SubInterfaces: children,
+		}
+	}
+	return nil
+}
+
+// interfaceNode holds the fields of an interface that lookup resolves. vifs and
+// pppoes are nil if the interface cannot have that kind of sub-interface.
+type interfaceNode struct {
+	firewall **FirewallAttachment
+	vifs     *map[string]*VIF
+	pppoes   *map[string]*PPPoE
+}
+
+// lookup resolves the interface at the path.
+func (i *Interfaces) lookup(path InterfacePath, create bool) (*interfaceNode, error) {
+	if err := path.Validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		fw     **FirewallAttachment
+		vifs   *map[string]*VIF
+		pppoes *map[string]*PPPoE
+		id     = path[1]
+	)
+
+	switch path[0] {
+	case "bonding":
+		if i.Bonding == nil && create {
+			i.Bonding = map[string]*Bonding{}
+		}
+		if i.Bonding[id] == nil && create {
+			i.Bonding[id] = &Bonding{}
+		}
+		if b := i.Bonding[id]; b != nil {
+			fw, vifs = &b.Firewall, &b.VIF
+		}
+	case "bridge":
+		if i.Bridge == nil && create {
+			i.Bridge = map[string]*Bridge{}
+		}
+		if i.Bridge[id] == nil && create {
+			i.Bridge[id] = &Bridge{}
+		}
+		if b := i.Bridge[id]; b != nil {
+			fw, vifs = &b.Firewall, &b.VIF
+		}
+	case "ethernet":
+		if i.Ethernet == nil && create {
+			i.Ethernet = map[string]*Ethernet{}
+		}
+		if i.Ethernet[id] == nil && create {
+			i.Ethernet[id] = &Ethernet{}
+		}
+		if e := i.Ethernet[id]; e != nil {
+			fw, vifs, pppoes = &e.Firewall, &e.VIF, &e.PPPoE
+		}
+	case "openvpn":
+		if i.OpenVPN == nil && create {
+			i.OpenVPN = map[string]*OpenVPN{}
+		}
+		if i.OpenVPN[id] == nil && create {
+			i.OpenVPN[id] = &OpenVPN{}
+		}
+		if o := i.OpenVPN[id]; o != nil {
+			fw = &o.Firewall
+		}
+	case "pseudo-ethernet":
+		if i.PseudoEthernet == nil && create {
+			i.PseudoEthernet = map[string]*PseudoEthernet{}
+		}
+		if i.PseudoEthernet[id] == nil && create {
+			i.PseudoEthernet[id] = &PseudoEthernet{}
+		}
+		if p := i.PseudoEthernet[id]; p != nil {
+			fw, vifs = &p.Firewall, &p.VIF
+		}
+	case "switch":
+		if i.Switch == nil && create {
+			i.Switch = map[string]*Switch{}
+		}
+		if i.Switch[id] == nil && create {
+			i.Switch[id] = &Switch{}
+		}
+		if s := i.Switch[id]; s != nil {
+			fw, vifs = &s.Firewall, &s.VIF
+		}
+	case "vti":
+		if i.VTI == nil && create {
+			i.VTI = map[string]*VTI{}
+		}
+		if i.VTI[id] == nil && create {
+			i.VTI[id] = &VTI{}
+		}
+		if v := i.VTI[id]; v != nil {
+			fw = &v.Firewall
+		}
+	case "wireguard":
+		if i.WireGuard == nil && create {
+			i.WireGuard = map[string]*WireGuard{}
+		}
+		if i.WireGuard[id] == nil && create {
+			i.WireGuard[id] = &WireGuard{}
+		}
+		if w := i.WireGuard[id]; w != nil {
+			fw = &w.Firewall
+		}
+	}
+
+	for rest := path[2:]; fw != nil && len(rest) > 0; rest = rest[2:] {
+		kind, id := rest[0], rest[1]
+
+		switch kind {
+		case "vif":
+			if *vifs == nil && create {
+				*vifs = map[string]*VIF{}
+			}
+			if (*vifs)[id] == nil && create {
+				(*vifs)[id] = &VIF{}
+			}
+			v := (*vifs)[id]
+			fw, vifs, pppoes = nil, nil, nil
+			if v != nil {
+				fw = &v.Firewall
+				if path[0] == "ethernet" {
+					pppoes = &v.PPPoE
+				}
+			}
+		case "pppoe":
+			if *pppoes == nil && create {
+				*pppoes = map[string]*PPPoE{}
+			}
+			if (*pppoes)[id] == nil && create {
+				(*pppoes)[id] = &PPPoE{}
+			}
+			p := (*pppoes)[id]
+			fw, vifs, pppoes = nil, nil, nil
+			if p != nil {
+				fw = &p.Firewall
+			}
+		}
+	}
+
+	if fw == nil {
+		return nil, fmt.Errorf("The interface %s does not exist.", path)
+	}
+	return &interfaceNode{firewall: fw, vifs: vifs, pppoes: pppoes}, nil
+}
+
+// Attachments returns the firewall attachment of every interface that has one,
+// with Path set, ordered by path.
+func (i *Interfaces) Attachments() []*FirewallAttachment {
+	var attachments []*FirewallAttachment
+
+	add := func(path InterfacePath, a *FirewallAttachment, vifs map[string]*VIF, pppoes map[string]*PPPoE) {
+		if a != nil {
+			a.Path = path
+			attachments = append(attachments, a)
+		}
+		for id, p := range pppoes {
+			if p != nil && p.Firewall != nil {
+				p.Firewall.Path = path.Child("pppoe", id)
+				attachments = append(attachments, p.Firewall)
+			}
+		}
+		for id, v := range vifs {
+			if v == nil {
+				continue
+			}
+			vifPath := path.Child("vif", id)
+			if v.Firewall != nil {
+				v.Firewall.Path = vifPath
+				attachments = append(attachments, v.Firewall)
+			}
+			for pppoeID, p := range v.PPPoE {
+				if p != nil && p.Firewall != nil {
+					p.Firewall.Path = vifPath.Child("pppoe", pppoeID)
+					attachments = append(attachments, p.Firewall)
+				}
+			}
+		}
+	}
+
+	for id, b := range i.Bonding {
+		if b != nil {
+			add(InterfacePath{"bonding", id}, b.Firewall, b.VIF, nil)
+		}
+	}
+	for id, b := range i.Bridge {
+		if b != nil {
+			add(InterfacePath{"bridge", id}, b.Firewall, b.VIF, nil)
+		}
+	}
+	for id, e := range i.Ethernet {
+		if e != nil {
+			add(InterfacePath{"ethernet", id}, e.Firewall, e.VIF, e.PPPoE)
+		}
+	}
+	for id, o := range i.OpenVPN {
+		if o != nil {
+			add(InterfacePath{"openvpn", id}, o.Firewall, nil, nil)
+		}
+	}
+	for id, p := range i.PseudoEthernet {
+		if p != nil {
+			add(InterfacePath{"pseudo-ethernet", id}, p.Firewall, p.VIF, nil)
+		}
+	}
+	for id, s := range i.Switch {
+		if s != nil {
+			add(InterfacePath{"switch", id}, s.Firewall, s.VIF, nil)
+		}
+	}
+	for id, v := range i.VTI {
+		if v != nil {
+			add(InterfacePath{"vti", id}, v.Firewall, nil, nil)
+		}
+	}
+	for id, w := range i.WireGuard {
+		if w != nil {
+			add(InterfacePath{"wireguard", id}, w.Firewall, nil, nil)
+		}
+	}
+
+	sort.Slice(attachments, func(a, b int) bool {
+		return lessPath(attachments[a].Path, attachments[b].Path)
+	})
+
+	return attachments
+}
+
+// lessPath orders paths element by element, comparing numeric ids as numbers
+// so that vif 9 comes before vif 10.
+func lessPath(a, b InterfacePath) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		x, errA := strconv.Atoi(a[i])
+		y, errB := strconv.Atoi(b[i])
+		if errA == nil && errB == nil {
+			return x < y
+		}
+		return a[i] < b[i]
+	}
+	return len(a) < len(b)
+}
@@ -0,0 +1,110 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterfacePath(t *testing.T) {
+	for _, test := range []struct {
+		path string
+		name string
+		err  string
+	}{
+		{path: "ethernet eth0", name: "eth0"},
+		{path: "ethernet eth1 vif 20", name: "eth1.20"},
+		{path: "ethernet eth0 pppoe 0", name: "pppoe0"},
+		{path: "ethernet eth0 vif 7 pppoe 1", name: "pppoe1"},
+		{path: "switch switch0 vif 10", name: "switch0.10"},
+		{path: "wireguard wg0", name: "wg0"},
+		{path: "ethernet", err: `The interface path "ethernet" must be a type and name followed by sub-interface kind and id pairs.`},
+		{path: "tunnel tun0", err: "The interface type tunnel is not supported."},
+		{path: "wireguard wg0 vif 10", err: "The interface wireguard wg0 cannot have a vif."},
+		{path: "bridge br0 vif 10 pppoe 0", err: "The interface bridge br0 vif 10 cannot have a pppoe."},
+		{path: "ethernet eth0 pppoe 0 vif 10", err: "The interface ethernet eth0 pppoe 0 cannot have a vif."},
+		{path: "ethernet eth0 vif ten", err: `The vif id "ten" must be a non-negative number.`},
+	} {
+		p, err := ParseInterfacePath(test.path)
+		if test.err != "" {
+			require.EqualError(t, err, test.err, test.path)
+			continue
+		}
+		require.NoError(t, err, test.path)
+		require.Equal(t, test.path, p.String())
+		require.Equal(t, test.name, p.Name(), test.path)
+	}
+}
+
+func TestInterfacesAttachments(t *testing.T) {
+	var ifaces Interfaces
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"ethernet": {
+			"eth0": {"firewall": {"in": {"name": "WAN_IN"}}, "pppoe": {"0": {"firewall": {"local": {"name": "WAN_LOCAL", "ipv6-name": "WAN6_LOCAL"}}}}},
+			"eth1": {"vif": {"10": {"firewall": {"in": {"name": "LAN_IN"}}}, "9": {"firewall": {"out": {"name": "LAN_OUT"}}}}}
+		},
+		"switch": {"switch0": {"vif": {"20": {"firewall": {"in": {"name": "IOT_IN"}}}}}},
+		"wireguard": {"wg0": {"firewall": {"in": {"name": "WG_IN"}}}}
+	}`), &ifaces))
+
+	var paths []string
+	for _, a := range ifaces.Attachments() {
+		paths = append(paths, a.Path.String())
+	}
+	require.Equal(t, []string{
+		"ethernet eth0",
+		"ethernet eth0 pppoe 0",
+		"ethernet eth1 vif 9",
+		"ethernet eth1 vif 10",
+		"switch switch0 vif 20",
+		"wireguard wg0",
+	}, paths)
+
+	a, err := ifaces.Attachment(InterfacePath{"ethernet", "eth0", "pppoe", "0"})
+	require.NoError(t, err)
+	require.Equal(t, "WAN6_LOCAL", *a.IPv6Local)
+
+	a, err = ifaces.Attachment(InterfacePath{"bridge", "br0"})
+	require.EqualError(t, err, "The interface bridge br0 does not exist.")
+	require.Nil(t, a)
+
+	var set Interfaces
+	require.NoError(t, set.SetAttachment(InterfacePath{"ethernet", "eth1", "vif", "20", "pppoe", "1"}, &FirewallAttachment{In: strptr("WAN_IN")}))
+	data, err := json.Marshal(&set)
+	require.NoError(t, err)
+	require.Equal(t, `{"ethernet":{"eth1":{"vif":{"20":{"pppoe":{"1":{"firewall":{"in":{"name":"WAN_IN"}}}}}}}}}`, string(data))
+}
+
+func TestInterfacesSubInterfaces(t *testing.T) {
+	var ifaces Interfaces
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"ethernet": {
+			"eth0": {"pppoe": {"0": {}}},
+			"eth1": {"vif": {"10": {"address": ["10.0.10.1/24"]}, "9": {}}},
+			"eth2": {}
+		},
+		"wireguard": {"wg0": {}}
+	}`), &ifaces))
+
+	require.EqualError(t, ifaces.EnsureNoSubInterfaces(InterfacePath{"ethernet", "eth1"}),
+		"The interface ethernet eth1 still has sub-interfaces: ethernet eth1 vif 9, ethernet eth1 vif 10.")
+	require.EqualError(t, ifaces.EnsureNoSubInterfaces(InterfacePath{"ethernet", "eth0"}),
+		"The interface ethernet eth0 still has sub-interfaces: ethernet eth0 pppoe 0.")
+	require.NoError(t, ifaces.EnsureNoSubInterfaces(InterfacePath{"ethernet", "eth2"}))
+	require.NoError(t, ifaces.EnsureNoSubInterfaces(InterfacePath{"wireguard", "wg0"}))
+
+	vifs, err := ifaces.VIFs(InterfacePath{"ethernet", "eth1"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.10.1/24"}, vifs["10"].Addresses)
+
+	_, err = ifaces.VIFs(InterfacePath{"wireguard", "wg0"})
+	require.EqualError(t, err, "The interface wireguard wg0 cannot have a vif.")
+
+	var set Interfaces
+	require.EqualError(t, set.SetVIF(InterfacePath{"wireguard", "wg0"}, "20", &VIF{}), "The interface wireguard wg0 cannot have a vif.")
+	require.NoError(t, set.SetVIF(InterfacePath{"ethernet", "eth1"}, "20", nil))
+	data, err := json.Marshal(&set)
+	require.NoError(t, err)
+	require.Equal(t, `{"ethernet":{"eth1":{"vif":{"20":null}}}}`, string(data))
+}
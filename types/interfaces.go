@@ -1,5 +1,60 @@
 package types
 
+import (
+	"fmt"
+	"sort"
+)
+
 type Interfaces struct {
-	Ethernet map[string]*Ethernet `json:"ethernet,omitempty"`
+	Ethernet       map[string]*Ethernet       `json:"ethernet,omitempty"`
+	Loopback       map[string]*Loopback       `json:"loopback,omitempty"`
+	Bonding        map[string]*Bonding        `json:"bonding,omitempty"`
+	Bridge         map[string]*Bridge         `json:"bridge,omitempty"`
+	OpenVPN        map[string]*OpenVPN        `json:"openvpn,omitempty"`
+	PseudoEthernet map[string]*PseudoEthernet `json:"pseudo-ethernet,omitempty"`
+	Switch         map[string]*Switch         `json:"switch,omitempty"`
+	VTI            map[string]*VTI            `json:"vti,omitempty"`
+	WireGuard      map[string]*WireGuard      `json:"wireguard,omitempty"`
+}
+
+// BridgeMembers returns the ethernet interfaces that are members of the
+// bridge, ordered by name.
+func (i *Interfaces) BridgeMembers(bridge string) []string {
+	var members []string
+	for id, e := range i.Ethernet {
+		if e != nil && e.BridgeGroup != nil && e.BridgeGroup.Bridge == bridge {
+			members = append(members, id)
+		}
+	}
+	sort.Strings(members)
+	return members
+}
+
+// BondingMembers returns the ethernet interfaces that are members of the bond,
+// ordered by name.
+func (i *Interfaces) BondingMembers(bond string) []string {
+	var members []string
+	for id, e := range i.Ethernet {
+		if e != nil && e.BondGroup == bond {
+			members = append(members, id)
+		}
+	}
+	sort.Strings(members)
+	return members
+}
+
+// EnsureNotMember returns an error if the ethernet interface does not exist or
+// is already a member of a bridge or bond.
+func (i *Interfaces) EnsureNotMember(ethernet string) error {
+	e, ok := i.Ethernet[ethernet]
+	if !ok || e == nil {
+		return fmt.Errorf("The ethernet interface %s does not exist.", ethernet)
+	}
+	if e.BridgeGroup != nil && e.BridgeGroup.Bridge != "" {
+		return fmt.Errorf("The ethernet interface %s is already a member of bridge %s.", ethernet, e.BridgeGroup.Bridge)
+	}
+	if e.BondGroup != "" {
+		return fmt.Errorf("The ethernet interface %s is already a member of bond %s.", ethernet, e.BondGroup)
+	}
+	return nil
 }
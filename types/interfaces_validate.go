@@ -0,0 +1,130 @@
+package types
+
+import (
+	"fmt"
+	"net"
+)
+
+const (
+	minAging = 1
+	maxAging = 1000000
+
+	maxBridgePriority = 65535
+)
+
+var (
+	bondingModes = []string{
+		"802.3ad",
+		"active-backup",
+		"adaptive-load-balance",
+		"broadcast",
+		"round-robin",
+		"transmit-load-balance",
+		"xor-hash",
+	}
+	hashPolicies = []string{"layer2", "layer2+3", "layer3+4"}
+)
+
+// Validate checks the name and addresses of the loopback interface.
+func (l *Loopback) Validate() error {
+	v := new(validator)
+	if l.ID != "lo" {
+		v.add("id", "%q must be lo", l.ID)
+	}
+	for i, addr := range l.Addresses {
+		v.validateInterfaceAddress(fmt.Sprintf("address[%d]", i), addr, false)
+	}
+	return v.err()
+}
+
+// Validate checks the name, addresses, spanning tree settings and members of
+// the bridge.
+func (b *Bridge) Validate() error {
+	v := new(validator)
+	v.validateInterfaceName("id", b.ID, "br")
+
+	for i, addr := range b.Addresses {
+		v.validateInterfaceAddress(fmt.Sprintf("address[%d]", i), addr, true)
+	}
+	if b.Aging != 0 && (b.Aging < minAging || b.Aging > maxAging) {
+		v.add("aging", "%d must be between %d and %d", b.Aging, minAging, maxAging)
+	}
+	if b.Priority < 0 || b.Priority > maxBridgePriority {
+		v.add("priority", "%d must be between 0 and %d", b.Priority, maxBridgePriority)
+	}
+	if o := b.DHCPOptions; o != nil {
+		o.validate(v, "dhcp-options")
+	}
+	validateMembers(v, b.Members)
+	return v.err()
+}
+
+// Validate checks the name, addresses, mode, hash policy and members of the
+// bond.
+func (b *Bonding) Validate() error {
+	v := new(validator)
+	v.validateInterfaceName("id", b.ID, "bond")
+
+	for i, addr := range b.Addresses {
+		v.validateInterfaceAddress(fmt.Sprintf("address[%d]", i), addr, true)
+	}
+	v.validateOneOf("mode", b.Mode, bondingModes)
+	v.validateOneOf("hash-policy", b.HashPolicy, hashPolicies)
+	v.validateMTU("mtu", b.MTU)
+	if b.Primary != "" && len(b.Members) > 0 && !contains(b.Members, b.Primary) {
+		v.add("primary", "%q must be a member of the bond", b.Primary)
+	}
+	if o := b.DHCPOptions; o != nil {
+		o.validate(v, "dhcp-options")
+	}
+	validateMembers(v, b.Members)
+	return v.err()
+}
+
+// Validate checks the name, link, MAC address and addresses of the interface.
+func (p *PseudoEthernet) Validate() error {
+	v := new(validator)
+	v.validateInterfaceName("id", p.ID, "peth")
+
+	if p.Link == "" {
+		v.add("link", "must not be empty")
+	}
+	if p.MAC != "" {
+		if _, err := net.ParseMAC(p.MAC); err != nil {
+			v.add("mac", "%q is not a valid MAC address", p.MAC)
+		}
+	}
+	for i, addr := range p.Addresses {
+		v.validateInterfaceAddress(fmt.Sprintf("address[%d]", i), addr, true)
+	}
+	if o := p.DHCPOptions; o != nil {
+		o.validate(v, "dhcp-options")
+	}
+	return v.err()
+}
+
+// Validate checks the name, addresses and MTU of the interface.
+func (t *VTI) Validate() error {
+	v := new(validator)
+	v.validateInterfaceName("id", t.ID, "vti")
+
+	for i, addr := range t.Addresses {
+		v.validateInterfaceAddress(fmt.Sprintf("address[%d]", i), addr, false)
+	}
+	v.validateMTU("mtu", t.MTU)
+	return v.err()
+}
+
+// validateMembers ensures every member names an ethernet interface at most
+// once.
+func validateMembers(v *validator, members []string) {
+	seen := map[string]bool{}
+	for i, m := range members {
+		field := fmt.Sprintf("members[%d]", i)
+		v.validateInterfaceName(field, m, "eth")
+		if seen[m] {
+			v.add(field, "%q is listed more than once", m)
+		}
+		seen[m] = true
+	}
+}
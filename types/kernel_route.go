@@ -0,0 +1,32 @@
+package types
+
+// KernelRoute is a next hop of a route in the routing table of the router, as
+// reported by the routes data source. A route with several next hops is
+// reported once per next hop. Protocol is the source of the route, e.g.
+// static, connected, kernel, ospf or bgp. Selected routes are the best route
+// to their destination and installed routes are in the forwarding table of
+// the kernel.
+type KernelRoute struct {
+	Destination string `json:"destination"`
+	Protocol    string `json:"protocol"`
+	NextHop     string `json:"next-hop,omitempty"`
+	Interface   string `json:"interface,omitempty"`
+	Selected    bool   `json:"selected"`
+	Installed   bool   `json:"installed"`
+}
+
+// KernelRoutes is the output of the routes data source, ordered by
+// destination.
+type KernelRoutes []*KernelRoute
+
+// Lookup returns the installed next hops of the destination, which is empty if
+// the destination is not routed.
+func (routes KernelRoutes) Lookup(destination string) []*KernelRoute {
+	found := []*KernelRoute{}
+	for _, r := range routes {
+		if r.Destination == destination && r.Installed {
+			found = append(found, r)
+		}
+	}
+	return found
+}
@@ -0,0 +1,80 @@
+package types
+
+import (
+	"encoding/json"
+	"net"
+	"sort"
+	"strings"
+)
+
+// routeProtocols maps the route type codes of the routes data source to the
+// protocol of the route.
+var routeProtocols = map[byte]string{
+	'B': "bgp",
+	'C': "connected",
+	'K': "kernel",
+	'O': "ospf",
+	'R': "rip",
+	'S': "static",
+}
+
+// UnmarshalJSON decodes the routes data source, where the type of each next
+// hop is the code of the protocol followed by > if the route is selected and
+// * if it is installed, e.g. S>*.
+func (routes *KernelRoutes) UnmarshalJSON(data []byte) error {
+	var aux []struct {
+		Prefix   string `json:"pfx"`
+		NextHops []struct {
+			Type      string `json:"t"`
+			Via       string `json:"via"`
+			Interface string `json:"intf"`
+		} `json:"nh"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	out := KernelRoutes{}
+	for _, route := range aux {
+		for _, hop := range route.NextHops {
+			protocol := ""
+			if hop.Type != "" {
+				protocol = routeProtocols[hop.Type[0]]
+				if protocol == "" {
+					protocol = hop.Type[:1]
+				}
+			}
+
+			out = append(out, &KernelRoute{
+				Destination: route.Prefix,
+				Protocol:    protocol,
+				NextHop:     hop.Via,
+				Interface:   hop.Interface,
+				Selected:    strings.Contains(hop.Type, ">"),
+				Installed:   strings.Contains(hop.Type, "*"),
+			})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return lessPrefix(out[i].Destination, out[j].Destination)
+	})
+
+	*routes = out
+	return nil
+}
+
+// lessPrefix orders IPv4 prefixes by address and then by length, falling back
+// to the strings for anything else.
+func lessPrefix(a, b string) bool {
+	ipA, netA, errA := net.ParseCIDR(a)
+	ipB, netB, errB := net.ParseCIDR(b)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+	if c := compareIPv4(ipA.String(), ipB.String()); c != 0 {
+		return c < 0
+	}
+	lenA, _ := netA.Mask.Size()
+	lenB, _ := netB.Mask.Size()
+	return lenA < lenB
+}
@@ -0,0 +1,25 @@
+package types
+
+// Loopback is the loopback interface, interfaces loopback lo.
+type Loopback struct {
+	ID          string   `json:"-" tfsdk:"id"`
+	Addresses   []string `json:"address,omitempty" tfsdk:"-"`
+	Description string   `json:"description,omitempty" tfsdk:"-"`
+	opMode      OpMode
+}
+
+func (l *Loopback) GetID() string {
+	return l.ID
+}
+
+// SetOpMode controls how the interface is encoded. When set to OpModeDelete,
+// every set property names a node to delete, and addresses are deleted by
+// value.
+func (l *Loopback) SetOpMode(m OpMode) {
+	(*l).opMode = m
+}
+
+// IsEmpty reports whether none of the properties of the interface are set.
+func (l *Loopback) IsEmpty() bool {
+	return len(l.Addresses) == 0 && l.Description == ""
+}
@@ -0,0 +1,19 @@
+package types
+
+import "encoding/json"
+
+func (l *Loopback) MarshalJSON() ([]byte, error) {
+	type Alias Loopback
+	if l.opMode != OpModeDelete {
+		return json.Marshal((*Alias)(l))
+	}
+
+	nodes := map[string]interface{}{}
+	if len(l.Addresses) > 0 {
+		nodes["address"] = l.Addresses
+	}
+	if l.Description != "" {
+		nodes["description"] = nil
+	}
+	return json.Marshal(nodes)
+}
@@ -0,0 +1,85 @@
+package types
+
+// NAT is the NAT node of the services, e.g. service nat. Rules are ordered by
+// priority and keyed by it in JSON.
+type NAT struct {
+	Rules []*NATRule `json:"-"`
+}
+
+// NATRule is a single NAT rule, e.g. service nat rule 5010. Source rules
+// rewrite the source of traffic leaving the outbound interface to the
+// translation address, masquerade rules rewrite it to the address of the
+// outbound interface and destination rules rewrite the destination of traffic
+// entering the inbound interface. Excluded rules stop matching traffic from
+// being translated by later rules.
+type NATRule struct {
+	Priority          int             `json:"-" tfsdk:"priority"`
+	Type              string          `json:"type,omitempty" tfsdk:"type"`
+	Description       string          `json:"description,omitempty" tfsdk:"description"`
+	InboundInterface  string          `json:"inbound-interface,omitempty" tfsdk:"inbound_interface"`
+	OutboundInterface string          `json:"outbound-interface,omitempty" tfsdk:"outbound_interface"`
+	Protocol          string          `json:"protocol,omitempty" tfsdk:"protocol"`
+	Source            *NATEndpoint    `json:"source,omitempty" tfsdk:"source"`
+	Destination       *NATEndpoint    `json:"destination,omitempty" tfsdk:"destination"`
+	Translation       *NATTranslation `json:"-" tfsdk:"translation"`
+	Log               bool            `json:"-" tfsdk:"log"`
+	Exclude           bool            `json:"-" tfsdk:"exclude"`
+	opMode            OpMode
+}
+
+// NATEndpoint matches the source or destination of the traffic a NAT rule
+// applies to, either by address or by firewall group.
+type NATEndpoint struct {
+	Address      *string    `json:"address,omitempty" tfsdk:"address"`
+	AddressGroup *string    `json:"-" tfsdk:"address_group"`
+	PortGroup    *string    `json:"-" tfsdk:"port_group"`
+	Port         *PortRange `json:"-" tfsdk:"port"`
+}
+
+// NATTranslation is the address and port matching traffic is translated to. It
+// is the inside-address of destination rules and the outside-address of
+// source rules.
+type NATTranslation struct {
+	Address string     `json:"address,omitempty" tfsdk:"address"`
+	Port    *PortRange `json:"-" tfsdk:"port"`
+}
+
+// The types of NAT rules.
+const (
+	NATTypeSource      = "source"
+	NATTypeDestination = "destination"
+	NATTypeMasquerade  = "masquerade"
+)
+
+// SetOpMode controls how the rule is encoded. When set to OpModeDelete, every
+// set property other than the type names a node to delete. The type only
+// decides which translation node is deleted. A rule with only a priority is
+// deleted entirely.
+func (r *NATRule) SetOpMode(m OpMode) {
+	(*r).opMode = m
+}
+
+// Groups returns the names of the address and port groups the rule refers to.
+func (r *NATRule) Groups() (address, port []string) {
+	for _, e := range []*NATEndpoint{r.Source, r.Destination} {
+		if e == nil {
+			continue
+		}
+		if e.AddressGroup != nil && *e.AddressGroup != "" {
+			address = append(address, *e.AddressGroup)
+		}
+		if e.PortGroup != nil && *e.PortGroup != "" {
+			port = append(port, *e.PortGroup)
+		}
+	}
+	return address, port
+}
+
+// translationNode is the name of the node holding the translation of the
+// rule.
+func (r *NATRule) translationNode() string {
+	if r.Type == NATTypeDestination {
+		return "inside-address"
+	}
+	return "outside-address"
+}
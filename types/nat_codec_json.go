@@ -0,0 +1,195 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+func (n *NAT) MarshalJSON() ([]byte, error) {
+	rules := map[string]*NATRule{}
+	for _, rule := range n.Rules {
+		if rule != nil {
+			rules[strconv.Itoa(rule.Priority)] = rule
+		}
+	}
+	if len(rules) == 0 {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(&struct {
+		Rules map[string]*NATRule `json:"rule"`
+	}{
+		Rules: rules,
+	})
+}
+
+func (n *NAT) UnmarshalJSON(data []byte) error {
+	aux := &struct {
+		Rules map[string]*NATRule `json:"rule"`
+	}{}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	n.Rules = nil
+	for k, rule := range aux.Rules {
+		if rule == nil {
+			continue
+		}
+		i, err := strconv.Atoi(k)
+		if err != nil {
+			return fmt.Errorf("malformed NAT rule priority: %v", k)
+		}
+		rule.Priority = i
+		n.Rules = append(n.Rules, rule)
+	}
+	sort.Slice(n.Rules, func(i, j int) bool {
+		return n.Rules[i].Priority < n.Rules[j].Priority
+	})
+	return nil
+}
+
+func (r *NATRule) MarshalJSON() ([]byte, error) {
+	if r.opMode == OpModeDelete {
+		if nodes := r.deleteNodes(); len(nodes) > 0 {
+			return json.Marshal(nodes)
+		}
+		return []byte("null"), nil
+	}
+
+	var inside, outside *NATTranslation
+	if r.Translation != nil {
+		if r.translationNode() == "inside-address" {
+			inside = r.Translation
+		} else {
+			outside = r.Translation
+		}
+	}
+
+	var log string
+	if r.Log {
+		log = enable
+	}
+
+	type Alias NATRule
+	return json.Marshal(&struct {
+		InsideAddress  *NATTranslation `json:"inside-address,omitempty"`
+		OutsideAddress *NATTranslation `json:"outside-address,omitempty"`
+		Log            string          `json:"log,omitempty"`
+		Exclude        *null           `json:"exclude,omitempty"`
+		*Alias
+	}{
+		InsideAddress:  inside,
+		OutsideAddress: outside,
+		Log:            log,
+		Exclude:        flag(r.Exclude),
+		Alias:          (*Alias)(r),
+	})
+}
+
+func (r *NATRule) UnmarshalJSON(data []byte) error {
+	type Alias NATRule
+	aux := &struct {
+		InsideAddress  *NATTranslation `json:"inside-address"`
+		OutsideAddress *NATTranslation `json:"outside-address"`
+		Log            string          `json:"log"`
+		Exclude        null            `json:"exclude"`
+		*Alias
+	}{
+		Alias: (*Alias)(r),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.Translation = aux.InsideAddress
+	if r.Translation == nil {
+		r.Translation = aux.OutsideAddress
+	}
+	r.Log = aux.Log == enable
+	r.Exclude = aux.Exclude.val
+	return nil
+}
+
+// deleteNodes returns the nodes of the rule that should be deleted. Nested
+// endpoint and translation nodes are deleted leaf by leaf so that a partial
+// change does not remove the sibling values that are still in use.
+func (r *NATRule) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	for _, leaf := range []struct {
+		name string
+		set  bool
+	}{
+		{"description", r.Description != ""},
+		{"inbound-interface", r.InboundInterface != ""},
+		{"outbound-interface", r.OutboundInterface != ""},
+		{"protocol", r.Protocol != ""},
+		{"log", r.Log},
+		{"exclude", r.Exclude},
+	} {
+		if leaf.set {
+			nodes[leaf.name] = nil
+		}
+	}
+
+	if s := r.Source; s != nil {
+		nodes["source"] = deleteEndpointNodes(s.Address, s.AddressGroup, s.PortGroup, s.Port, nil)
+	}
+	if d := r.Destination; d != nil {
+		nodes["destination"] = deleteEndpointNodes(d.Address, d.AddressGroup, d.PortGroup, d.Port, nil)
+	}
+	if t := r.Translation; t != nil {
+		translation := map[string]interface{}{}
+		if t.Address != "" {
+			translation["address"] = nil
+		}
+		if t.Port != nil {
+			translation["port"] = nil
+		}
+		nodes[r.translationNode()] = translation
+	}
+
+	return nodes
+}
+
+// The endpoints of a NAT rule are encoded like the destination of a firewall
+// rule.
+func (e *NATEndpoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal((*Destination)(e))
+}
+
+func (e *NATEndpoint) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, (*Destination)(e))
+}
+
+func (t *NATTranslation) MarshalJSON() ([]byte, error) {
+	type Alias NATTranslation
+	return json.Marshal(&struct {
+		Port string `json:"port,omitempty"`
+		*Alias
+	}{
+		Port:  t.Port.toPort(),
+		Alias: (*Alias)(t),
+	})
+}
+
+func (t *NATTranslation) UnmarshalJSON(data []byte) (err error) {
+	type Alias NATTranslation
+	aux := &struct {
+		Port string `json:"port"`
+		*Alias
+	}{
+		Alias: (*Alias)(t),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if t.Port, err = fromPort(aux.Port); err != nil {
+		return fmt.Errorf("Error setting translation ports %s from json `%s`: %s", aux.Port, string(data), err.Error())
+	}
+	return nil
+}
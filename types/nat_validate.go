@@ -0,0 +1,102 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+var natTypes = []string{NATTypeSource, NATTypeDestination, NATTypeMasquerade}
+
+// Validate checks every rule of the NAT.
+func (n *NAT) Validate() error {
+	v := new(validator)
+
+	seen := map[int]bool{}
+	for i, rule := range n.Rules {
+		if rule == nil {
+			v.add(fmt.Sprintf("rule[%d]", i), "must not be null")
+			continue
+		}
+
+		prefix := fmt.Sprintf("rule[%d]", rule.Priority)
+		if seen[rule.Priority] {
+			v.add(prefix, "is defined more than once")
+		}
+		seen[rule.Priority] = true
+
+		rule.validate(v, prefix)
+	}
+	return v.err()
+}
+
+// Validate checks the rule on its own. Group references are not resolved.
+func (r *NATRule) Validate() error {
+	v := new(validator)
+	r.validate(v, "")
+	return v.err()
+}
+
+func (r *NATRule) validate(v *validator, prefix string) {
+	if r.Priority < MinRulePriority || r.Priority > MaxRulePriority {
+		v.add(join(prefix, "priority"), "%d must be between %d and %d", r.Priority, MinRulePriority, MaxRulePriority)
+	}
+
+	if !contains(natTypes, r.Type) {
+		v.add(join(prefix, "type"), "%q must be one of %s", r.Type, strings.Join(natTypes, ", "))
+	}
+
+	// Destination NAT happens before routing, so only the inbound interface is
+	// known, while source NAT happens after it.
+	switch r.Type {
+	case NATTypeDestination:
+		if r.InboundInterface == "" {
+			v.add(join(prefix, "inbound-interface"), "is required for %s rules", r.Type)
+		}
+		if r.OutboundInterface != "" {
+			v.add(join(prefix, "outbound-interface"), "is not supported by %s rules", r.Type)
+		}
+	case NATTypeSource, NATTypeMasquerade:
+		if r.OutboundInterface == "" {
+			v.add(join(prefix, "outbound-interface"), "is required for %s rules", r.Type)
+		}
+		if r.InboundInterface != "" {
+			v.add(join(prefix, "inbound-interface"), "is not supported by %s rules", r.Type)
+		}
+	}
+	if r.InboundInterface != "" {
+		v.validateName(join(prefix, "inbound-interface"), r.InboundInterface)
+	}
+	if r.OutboundInterface != "" {
+		v.validateName(join(prefix, "outbound-interface"), r.OutboundInterface)
+	}
+
+	allowsPorts := contains(portProtocols, r.Protocol)
+
+	if s := r.Source; s != nil {
+		validateEndpoint(v, join(prefix, "source"), s.Address, s.AddressGroup, s.PortGroup, s.Port, allowsPorts, r.Protocol)
+	}
+	if d := r.Destination; d != nil {
+		validateEndpoint(v, join(prefix, "destination"), d.Address, d.AddressGroup, d.PortGroup, d.Port, allowsPorts, r.Protocol)
+	}
+
+	field := join(prefix, r.translationNode())
+	switch {
+	case r.Type == NATTypeMasquerade:
+		if r.Translation != nil {
+			v.add(field, "is not supported by %s rules", r.Type)
+		}
+	case r.Translation == nil || r.Translation.Address == "":
+		if !r.Exclude && contains(natTypes, r.Type) {
+			v.add(join(field, "address"), "is required for %s rules that are not excluded", r.Type)
+		}
+	default:
+		v.validateIPv4(join(field, "address"), r.Translation.Address)
+	}
+
+	if r.Translation != nil && r.Translation.Port != nil {
+		if !allowsPorts {
+			v.add(join(field, "port"), "requires protocol %s, got %q", strings.Join(portProtocols, ", "), r.Protocol)
+		}
+		r.Translation.Port.validate(v, join(field, "port"))
+	}
+}
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNATValidate(t *testing.T) {
+	address := "!192.168.1.0/24"
+
+	require.NoError(t, (&NAT{Rules: []*NATRule{
+		{Priority: 5000, Type: NATTypeSource, OutboundInterface: "eth0", Source: &NATEndpoint{Address: &address}, Exclude: true},
+		{Priority: 5010, Type: NATTypeMasquerade, OutboundInterface: "eth0"},
+		{Priority: 1, Type: NATTypeDestination, InboundInterface: "eth0", Protocol: "tcp_udp", Translation: &NATTranslation{Address: "10.0.0.2", Port: &PortRange{From: 53, To: 53}}},
+	}}).Validate())
+
+	require.EqualError(t, (&NAT{Rules: []*NATRule{
+		{Priority: 5010, Type: NATTypeMasquerade, OutboundInterface: "eth0", Translation: &NATTranslation{Address: "203.0.113.1"}},
+		{Priority: 5010, Type: "static", InboundInterface: "eth 0"},
+		{Priority: 0, Type: NATTypeSource, OutboundInterface: "eth0", Protocol: "icmp", Translation: &NATTranslation{Address: "203.0.113.0/33", Port: &PortRange{From: 2000, To: 1000}}},
+	}}).Validate(), `rule[5010].outside-address: is not supported by masquerade rules; `+
+		`rule[5010]: is defined more than once; `+
+		`rule[5010].type: "static" must be one of source, destination, masquerade; `+
+		`rule[5010].inbound-interface: "eth 0" must not contain whitespace or quotes; `+
+		`rule[0].priority: 0 must be between 1 and 9999; `+
+		`rule[0].outside-address.address: "203.0.113.0/33" is not a valid IPv4 address, CIDR or range; `+
+		`rule[0].outside-address.port: requires protocol tcp, udp, tcp_udp, got "icmp"; `+
+		`rule[0].outside-address.port: range 2000-1000 starts after it ends`)
+}
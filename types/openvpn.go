@@ -0,0 +1,103 @@
+package types
+
+// OpenVPN is an OpenVPN tunnel interface, e.g. interfaces openvpn vtun0.
+// Site-to-site tunnels authenticate with either a shared secret key file or
+// TLS, while client and server tunnels always use TLS.
+type OpenVPN struct {
+	ID                  string              `json:"-" tfsdk:"id"`
+	Mode                string              `json:"mode,omitempty" tfsdk:"-"`
+	Description         string              `json:"description,omitempty" tfsdk:"-"`
+	Disable             bool                `json:"-" tfsdk:"-"`
+	LocalAddress        string              `json:"-" tfsdk:"-"`
+	RemoteAddress       string              `json:"remote-address,omitempty" tfsdk:"-"`
+	LocalHost           string              `json:"local-host,omitempty" tfsdk:"-"`
+	LocalPort           int                 `json:"-" tfsdk:"-"`
+	RemoteHosts         []string            `json:"remote-host,omitempty" tfsdk:"-"`
+	RemotePort          int                 `json:"-" tfsdk:"-"`
+	Protocol            string              `json:"protocol,omitempty" tfsdk:"-"`
+	SharedSecretKeyFile string              `json:"shared-secret-key-file,omitempty" tfsdk:"-"`
+	TLS                 *OpenVPNTLS         `json:"tls,omitempty" tfsdk:"-"`
+	Options             []string            `json:"openvpn-option,omitempty" tfsdk:"-"`
+	Firewall            *FirewallAttachment `json:"firewall,omitempty" tfsdk:"-"`
+	opMode              OpMode
+}
+
+// OpenVPNTLS holds the paths of the files on the router used to authenticate
+// a tunnel with TLS. Role is only used by site-to-site tunnels.
+type OpenVPNTLS struct {
+	Role       string `json:"role,omitempty" tfsdk:"role"`
+	CACertFile string `json:"ca-cert-file,omitempty" tfsdk:"ca_cert_file"`
+	CertFile   string `json:"cert-file,omitempty" tfsdk:"cert_file"`
+	KeyFile    string `json:"key-file,omitempty" tfsdk:"key_file"`
+	DHFile     string `json:"dh-file,omitempty" tfsdk:"dh_file"`
+	CRLFile    string `json:"crl-file,omitempty" tfsdk:"crl_file"`
+}
+
+func (o *OpenVPN) GetID() string {
+	return o.ID
+}
+
+// SetOpMode controls how the interface is encoded. When set to OpModeDelete,
+// every set property names a node to delete, and remote hosts and options are
+// deleted by value.
+func (o *OpenVPN) SetOpMode(m OpMode) {
+	(*o).opMode = m
+}
+
+// Properties returns a copy of the interface without its firewall attachment.
+func (o *OpenVPN) Properties() *OpenVPN {
+	tmp := *o
+	tmp.Firewall = nil
+	return &tmp
+}
+
+// IsEmpty reports whether none of the properties of the interface are set.
+// The firewall attachment is not considered.
+func (o *OpenVPN) IsEmpty() bool {
+	return o.Mode == "" &&
+		o.Description == "" &&
+		!o.Disable &&
+		o.LocalAddress == "" &&
+		o.RemoteAddress == "" &&
+		o.LocalHost == "" &&
+		o.LocalPort == 0 &&
+		len(o.RemoteHosts) == 0 &&
+		o.RemotePort == 0 &&
+		o.Protocol == "" &&
+		o.SharedSecretKeyFile == "" &&
+		o.TLS == nil &&
+		len(o.Options) == 0
+}
+
+// Removed returns the TLS settings that are set in t but not in updated, or
+// nil if there are none.
+func (t *OpenVPNTLS) Removed(updated *OpenVPNTLS) *OpenVPNTLS {
+	if t == nil {
+		return nil
+	}
+	if updated == nil {
+		updated = new(OpenVPNTLS)
+	}
+
+	removed := new(OpenVPNTLS)
+	for _, f := range []struct {
+		current, updated string
+		removed          *string
+	}{
+		{t.Role, updated.Role, &removed.Role},
+		{t.CACertFile, updated.CACertFile, &removed.CACertFile},
+		{t.CertFile, updated.CertFile, &removed.CertFile},
+		{t.KeyFile, updated.KeyFile, &removed.KeyFile},
+		{t.DHFile, updated.DHFile, &removed.DHFile},
+		{t.CRLFile, updated.CRLFile, &removed.CRLFile},
+	} {
+		if f.current != "" && f.updated == "" {
+			*f.removed = f.current
+		}
+	}
+
+	if *removed == (OpenVPNTLS{}) {
+		return nil
+	}
+	return removed
+}
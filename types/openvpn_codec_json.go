@@ -0,0 +1,130 @@
+package types
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+func (o *OpenVPN) MarshalJSON() ([]byte, error) {
+	if o.opMode == OpModeDelete {
+		return json.Marshal(o.deleteNodes())
+	}
+
+	// local-address is a tag node whose children are only used by tap
+	// devices.
+	var localAddress map[string]*null
+	{
+		if o.LocalAddress != "" {
+			localAddress = map[string]*null{o.LocalAddress: {true}}
+		}
+	}
+
+	type Alias OpenVPN
+	return json.Marshal(&struct {
+		Disable      *null            `json:"disable,omitempty"`
+		LocalAddress map[string]*null `json:"local-address,omitempty"`
+		LocalPort    string           `json:"local-port,omitempty"`
+		RemotePort   string           `json:"remote-port,omitempty"`
+		*Alias
+	}{
+		Disable:      flag(o.Disable),
+		LocalAddress: localAddress,
+		LocalPort:    itoa(o.LocalPort),
+		RemotePort:   itoa(o.RemotePort),
+		Alias:        (*Alias)(o),
+	})
+}
+
+func (o *OpenVPN) UnmarshalJSON(data []byte) (err error) {
+	type Alias OpenVPN
+	aux := &struct {
+		Disable      null                       `json:"disable"`
+		LocalAddress map[string]json.RawMessage `json:"local-address,omitempty"`
+		LocalPort    string                     `json:"local-port,omitempty"`
+		RemotePort   string                     `json:"remote-port,omitempty"`
+		*Alias
+	}{
+		Alias: (*Alias)(o),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	o.Disable = aux.Disable.val
+	if len(aux.LocalAddress) > 0 {
+		addrs := make([]string, 0, len(aux.LocalAddress))
+		for addr := range aux.LocalAddress {
+			addrs = append(addrs, addr)
+		}
+		sort.Strings(addrs)
+		o.LocalAddress = addrs[0]
+	}
+	if o.LocalPort, err = atoi("local-port", aux.LocalPort); err != nil {
+		return err
+	}
+	o.RemotePort, err = atoi("remote-port", aux.RemotePort)
+	return err
+}
+
+// deleteNodes returns the nodes of the interface that should be deleted.
+func (o *OpenVPN) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	for _, leaf := range []struct {
+		name string
+		set  bool
+	}{
+		{"mode", o.Mode != ""},
+		{"description", o.Description != ""},
+		{"disable", o.Disable},
+		{"local-address", o.LocalAddress != ""},
+		{"remote-address", o.RemoteAddress != ""},
+		{"local-host", o.LocalHost != ""},
+		{"local-port", o.LocalPort != 0},
+		{"remote-port", o.RemotePort != 0},
+		{"protocol", o.Protocol != ""},
+		{"shared-secret-key-file", o.SharedSecretKeyFile != ""},
+	} {
+		if leaf.set {
+			nodes[leaf.name] = nil
+		}
+	}
+	if len(o.RemoteHosts) > 0 {
+		nodes["remote-host"] = o.RemoteHosts
+	}
+	if len(o.Options) > 0 {
+		nodes["openvpn-option"] = o.Options
+	}
+	if o.TLS != nil {
+		nodes["tls"] = o.TLS.deleteNodes()
+	}
+
+	return nodes
+}
+
+// deleteNodes returns the TLS settings that should be deleted, or nil, which
+// deletes the whole tls node, if none are set.
+func (t *OpenVPNTLS) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	for _, leaf := range []struct {
+		name string
+		val  string
+	}{
+		{"role", t.Role},
+		{"ca-cert-file", t.CACertFile},
+		{"cert-file", t.CertFile},
+		{"key-file", t.KeyFile},
+		{"dh-file", t.DHFile},
+		{"crl-file", t.CRLFile},
+	} {
+		if leaf.val != "" {
+			nodes[leaf.name] = nil
+		}
+	}
+
+	if len(nodes) == 0 {
+		return nil
+	}
+	return nodes
+}
@@ -0,0 +1,121 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+const openVPNSiteToSite = "site-to-site"
+
+var (
+	openVPNModes     = []string{"client", "server", openVPNSiteToSite}
+	openVPNProtocols = []string{"tcp-active", "tcp-passive", "udp"}
+	openVPNTLSRoles  = []string{"active", "passive"}
+)
+
+// Validate checks the name, mode, addresses, ports and authentication of the
+// tunnel. Site-to-site tunnels need their local and remote tunnel addresses
+// and either a shared secret key file or TLS, while client and server tunnels
+// need TLS with a CA certificate.
+func (o *OpenVPN) Validate() error {
+	v := new(validator)
+	v.validateInterfaceName("id", o.ID, "vtun")
+
+	if o.Mode == "" {
+		v.add("mode", "must not be empty")
+	}
+	v.validateOneOf("mode", o.Mode, openVPNModes)
+	v.validateOneOf("protocol", o.Protocol, openVPNProtocols)
+
+	if o.LocalAddress != "" && !isIPv4(o.LocalAddress) {
+		v.add("local-address", "%q is not a valid IPv4 address", o.LocalAddress)
+	}
+	if o.RemoteAddress != "" && !isIPv4(o.RemoteAddress) {
+		v.add("remote-address", "%q is not a valid IPv4 address", o.RemoteAddress)
+	}
+	if o.LocalHost != "" && !isIPv4(o.LocalHost) {
+		v.add("local-host", "%q is not a valid IPv4 address", o.LocalHost)
+	}
+	for i, host := range o.RemoteHosts {
+		v.validateName(fmt.Sprintf("remote-host[%d]", i), host)
+	}
+	if o.LocalPort != 0 {
+		validatePort(v, "local-port", o.LocalPort)
+	}
+	if o.RemotePort != 0 {
+		validatePort(v, "remote-port", o.RemotePort)
+	}
+	for i, opt := range o.Options {
+		if !strings.HasPrefix(opt, "--") {
+			v.add(fmt.Sprintf("openvpn-option[%d]", i), "%q must start with --", opt)
+		}
+	}
+
+	if o.SharedSecretKeyFile != "" {
+		v.validateFile("shared-secret-key-file", o.SharedSecretKeyFile)
+	}
+	if o.TLS != nil {
+		o.TLS.validate(v, "tls", o.Mode)
+	}
+
+	switch o.Mode {
+	case openVPNSiteToSite:
+		if o.LocalAddress == "" {
+			v.add("local-address", "must be set for %s tunnels", o.Mode)
+		}
+		if o.RemoteAddress == "" {
+			v.add("remote-address", "must be set for %s tunnels", o.Mode)
+		}
+		if (o.SharedSecretKeyFile == "") == (o.TLS == nil) {
+			v.add("shared-secret-key-file", "exactly one of shared-secret-key-file and tls must be set")
+		}
+	case "client", "server":
+		if o.SharedSecretKeyFile != "" {
+			v.add("shared-secret-key-file", "must not be set for %s tunnels", o.Mode)
+		}
+		if o.TLS == nil {
+			v.add("tls", "must be set for %s tunnels", o.Mode)
+		}
+		if o.Mode == "client" && len(o.RemoteHosts) == 0 {
+			v.add("remote-host", "must be set for client tunnels")
+		}
+	}
+	return v.err()
+}
+
+func (t *OpenVPNTLS) validate(v *validator, prefix, mode string) {
+	v.validateOneOf(join(prefix, "role"), t.Role, openVPNTLSRoles)
+	if mode == openVPNSiteToSite && t.Role == "" {
+		v.add(join(prefix, "role"), "must be set for %s tunnels", mode)
+	}
+	if t.CACertFile == "" {
+		v.add(join(prefix, "ca-cert-file"), "must not be empty")
+	}
+
+	for _, f := range []struct {
+		name, path string
+	}{
+		{"ca-cert-file", t.CACertFile},
+		{"cert-file", t.CertFile},
+		{"key-file", t.KeyFile},
+		{"dh-file", t.DHFile},
+		{"crl-file", t.CRLFile},
+	} {
+		if f.path != "" {
+			v.validateFile(join(prefix, f.name), f.path)
+		}
+	}
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		v.add(join(prefix, "key-file"), "cert-file and key-file must be set together")
+	}
+	if mode == "server" && t.DHFile == "" {
+		v.add(join(prefix, "dh-file"), "must be set for server tunnels")
+	}
+}
+
+// validateFile ensures path is the absolute path of a file on the router.
+func (v *validator) validateFile(field, path string) {
+	if !strings.HasPrefix(path, "/") || strings.HasSuffix(path, "/") {
+		v.add(field, "%q must be the absolute path of a file", path)
+	}
+}
@@ -0,0 +1,34 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenVPNValidate(t *testing.T) {
+	require.NoError(t, (&OpenVPN{
+		ID:          "vtun0",
+		Mode:        "client",
+		RemoteHosts: []string{"vpn.example.com"},
+		Protocol:    "tcp-active",
+		TLS:         &OpenVPNTLS{CACertFile: "/config/auth/ca.crt"},
+		Options:     []string{"--comp-lzo"},
+	}).Validate())
+
+	require.EqualError(t, (&OpenVPN{
+		ID:                  "vtun1",
+		Mode:                "server",
+		LocalPort:           70000,
+		SharedSecretKeyFile: "/config/auth/secret",
+		TLS:                 &OpenVPNTLS{Role: "both", CACertFile: "/config/auth/ca.crt", CertFile: "/config/auth/server.crt"},
+		Options:             []string{"comp-lzo"},
+	}).Validate(), `local-port: 70000 must be between 1 and 65535; `+
+		`openvpn-option[0]: "comp-lzo" must start with --; `+
+		`tls.role: "both" must be one of active, passive; `+
+		`tls.key-file: cert-file and key-file must be set together; `+
+		`tls.dh-file: must be set for server tunnels; `+
+		`shared-secret-key-file: must not be set for server tunnels`)
+
+	require.EqualError(t, (&OpenVPN{ID: "vtun2"}).Validate(), `mode: must not be empty`)
+}
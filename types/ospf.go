@@ -0,0 +1,92 @@
+package types
+
+// OSPF is the OSPF instance of the router, e.g. protocols ospf. Areas are keyed
+// by their ID. No hellos are sent on passive interfaces, while their networks
+// are still advertised.
+type OSPF struct {
+	RouterID          string               `json:"-" tfsdk:"router_id"`
+	PassiveInterfaces []string             `json:"passive-interface,omitempty" tfsdk:"passive_interfaces"`
+	Redistribute      *OSPFRedistribute    `json:"redistribute,omitempty" tfsdk:"redistribute"`
+	Areas             map[string]*OSPFArea `json:"area,omitempty" tfsdk:"-"`
+	opMode            OpMode
+}
+
+// OSPFArea is an area of the OSPF instance, e.g. protocols ospf area 0.0.0.0.
+// OSPF is enabled on the interfaces with an address in one of its networks.
+type OSPFArea struct {
+	ID       string   `json:"-" tfsdk:"id"`
+	Networks []string `json:"network,omitempty" tfsdk:"networks"`
+	opMode   OpMode
+}
+
+// OSPFRedistribute selects the routes of other sources that are advertised as
+// external routes, e.g. protocols ospf redistribute connected.
+type OSPFRedistribute struct {
+	Connected *OSPFRedistribution `json:"connected,omitempty" tfsdk:"connected"`
+	Static    *OSPFRedistribution `json:"static,omitempty" tfsdk:"static"`
+	Kernel    *OSPFRedistribution `json:"kernel,omitempty" tfsdk:"kernel"`
+	BGP       *OSPFRedistribution `json:"bgp,omitempty" tfsdk:"bgp"`
+}
+
+// OSPFRedistribution is how the routes of a source are advertised. Routes are
+// advertised with the default metric and metric type 2 unless set, and can be
+// filtered by a route map.
+type OSPFRedistribution struct {
+	Metric     int    `json:"-" tfsdk:"metric"`
+	MetricType int    `json:"-" tfsdk:"metric_type"`
+	RouteMap   string `json:"route-map,omitempty" tfsdk:"route_map"`
+	opMode     OpMode
+}
+
+// SetOpMode controls how the instance is encoded. When set to OpModeDelete, a
+// set router ID names a node to delete and passive interfaces are deleted by
+// value. Redistributions are encoded in the same mode, where one without any
+// property is deleted as a whole. Areas are never encoded in this mode and an
+// instance without any property is deleted entirely.
+func (o *OSPF) SetOpMode(m OpMode) {
+	(*o).opMode = m
+	if o.Redistribute != nil {
+		for _, r := range o.Redistribute.sources() {
+			if r != nil {
+				r.opMode = m
+			}
+		}
+	}
+}
+
+// Properties returns a copy of the instance without its areas.
+func (o *OSPF) Properties() *OSPF {
+	tmp := *o
+	tmp.Areas = nil
+	return &tmp
+}
+
+// IsEmpty reports whether none of the properties of the instance are set.
+func (o *OSPF) IsEmpty() bool {
+	return o.RouterID == "" &&
+		len(o.PassiveInterfaces) == 0 &&
+		(o.Redistribute == nil || o.Redistribute.IsEmpty()) &&
+		len(o.Areas) == 0
+}
+
+// SetOpMode controls how the area is encoded. When set to OpModeDelete,
+// networks are deleted by value. An area without any network is deleted
+// entirely.
+func (a *OSPFArea) SetOpMode(m OpMode) {
+	(*a).opMode = m
+}
+
+// IsEmpty reports whether no source is redistributed.
+func (r *OSPFRedistribute) IsEmpty() bool {
+	return r.Connected == nil && r.Static == nil && r.Kernel == nil && r.BGP == nil
+}
+
+func (r *OSPFRedistribute) sources() []*OSPFRedistribution {
+	return []*OSPFRedistribution{r.Connected, r.Static, r.Kernel, r.BGP}
+}
+
+// IsEmpty reports whether none of the properties of the redistribution are
+// set.
+func (r *OSPFRedistribution) IsEmpty() bool {
+	return r.Metric == 0 && r.MetricType == 0 && r.RouteMap == ""
+}
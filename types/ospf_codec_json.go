@@ -0,0 +1,170 @@
+package types
+
+import "encoding/json"
+
+type ospfParameters struct {
+	RouterID string `json:"router-id,omitempty"`
+}
+
+func (o *OSPF) MarshalJSON() ([]byte, error) {
+	if o.opMode == OpModeDelete {
+		if o.IsEmpty() {
+			return []byte("null"), nil
+		}
+		return json.Marshal(o.deleteNodes())
+	}
+
+	var params *ospfParameters
+	if o.RouterID != "" {
+		params = &ospfParameters{RouterID: o.RouterID}
+	}
+
+	type Alias OSPF
+	return json.Marshal(&struct {
+		Parameters *ospfParameters `json:"parameters,omitempty"`
+		*Alias
+	}{
+		Parameters: params,
+		Alias:      (*Alias)(o),
+	})
+}
+
+func (o *OSPF) UnmarshalJSON(data []byte) error {
+	type Alias OSPF
+	aux := &struct {
+		Parameters *ospfParameters `json:"parameters"`
+		*Alias
+	}{
+		Alias: (*Alias)(o),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.Parameters != nil {
+		o.RouterID = aux.Parameters.RouterID
+	}
+
+	for id, a := range o.Areas {
+		if a == nil {
+			a = new(OSPFArea)
+			o.Areas[id] = a
+		}
+		a.ID = id
+	}
+	return nil
+}
+
+// deleteNodes returns the nodes of the instance that should be deleted.
+func (o *OSPF) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if o.RouterID != "" {
+		nodes["parameters"] = map[string]interface{}{
+			"router-id": nil,
+		}
+	}
+	if len(o.PassiveInterfaces) > 0 {
+		nodes["passive-interface"] = o.PassiveInterfaces
+	}
+	if o.Redistribute != nil && !o.Redistribute.IsEmpty() {
+		nodes["redistribute"] = o.Redistribute
+	}
+
+	return nodes
+}
+
+func (a *OSPFArea) MarshalJSON() ([]byte, error) {
+	if a.opMode == OpModeDelete && len(a.Networks) == 0 {
+		return []byte("null"), nil
+	}
+
+	type Alias OSPFArea
+	return json.Marshal((*Alias)(a))
+}
+
+func (r *OSPFRedistribute) UnmarshalJSON(data []byte) error {
+	aux := &struct {
+		Connected json.RawMessage `json:"connected"`
+		Static    json.RawMessage `json:"static"`
+		Kernel    json.RawMessage `json:"kernel"`
+		BGP       json.RawMessage `json:"bgp"`
+	}{}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	for _, source := range []struct {
+		raw json.RawMessage
+		r   **OSPFRedistribution
+	}{
+		{aux.Connected, &r.Connected},
+		{aux.Static, &r.Static},
+		{aux.Kernel, &r.Kernel},
+		{aux.BGP, &r.BGP},
+	} {
+		// A redistribution without any property is null.
+		if len(source.raw) == 0 {
+			continue
+		}
+		*source.r = new(OSPFRedistribution)
+		if string(source.raw) != "null" {
+			if err := json.Unmarshal(source.raw, *source.r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// A redistribution without any property is null in both op modes.
+func (r *OSPFRedistribution) MarshalJSON() ([]byte, error) {
+	if r.IsEmpty() {
+		return []byte("null"), nil
+	}
+
+	if r.opMode == OpModeDelete {
+		nodes := map[string]interface{}{}
+		if r.Metric != 0 {
+			nodes["metric"] = nil
+		}
+		if r.MetricType != 0 {
+			nodes["metric-type"] = nil
+		}
+		if r.RouteMap != "" {
+			nodes["route-map"] = nil
+		}
+		return json.Marshal(nodes)
+	}
+
+	type Alias OSPFRedistribution
+	return json.Marshal(&struct {
+		Metric     string `json:"metric,omitempty"`
+		MetricType string `json:"metric-type,omitempty"`
+		*Alias
+	}{
+		Metric:     itoa(r.Metric),
+		MetricType: itoa(r.MetricType),
+		Alias:      (*Alias)(r),
+	})
+}
+
+func (r *OSPFRedistribution) UnmarshalJSON(data []byte) (err error) {
+	type Alias OSPFRedistribution
+	aux := &struct {
+		Metric     string `json:"metric"`
+		MetricType string `json:"metric-type"`
+		*Alias
+	}{
+		Alias: (*Alias)(r),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if r.Metric, err = atoi("metric", aux.Metric); err != nil {
+		return err
+	}
+	r.MetricType, err = atoi("metric-type", aux.MetricType)
+	return err
+}
@@ -0,0 +1,42 @@
+package types
+
+// OSPFNeighborFull is the state of a neighbor whose link state database is
+// synchronized with the router.
+const OSPFNeighborFull = "Full"
+
+// OSPFNeighborStatus is an adjacency with an OSPF neighbor, as reported by show
+// ip ospf neighbor. State is the state of the adjacency, e.g. Full or
+// ExStart, and Role is the role of the neighbor on the network of the
+// interface, e.g. DR, Backup or DROther, which is empty on point to point
+// links. DeadTime is how long until the neighbor is declared down unless a
+// hello is received, e.g. 38.123s.
+type OSPFNeighborStatus struct {
+	RouterID  string `json:"router-id"`
+	Priority  int    `json:"priority"`
+	State     string `json:"state"`
+	Role      string `json:"role,omitempty"`
+	DeadTime  string `json:"dead-time"`
+	Address   string `json:"address"`
+	Interface string `json:"interface"`
+}
+
+// OSPFNeighborStatuses is the output of show ip ospf neighbor, ordered by
+// router ID and interface.
+type OSPFNeighborStatuses []*OSPFNeighborStatus
+
+// IsFull reports whether the adjacency with the neighbor is fully established.
+func (s *OSPFNeighborStatus) IsFull() bool {
+	return s.State == OSPFNeighborFull
+}
+
+// Lookup returns the adjacencies with the neighbor, which is empty if there
+// are none.
+func (statuses OSPFNeighborStatuses) Lookup(routerID string) []*OSPFNeighborStatus {
+	found := []*OSPFNeighborStatus{}
+	for _, s := range statuses {
+		if s.RouterID == routerID {
+			found = append(found, s)
+		}
+	}
+	return found
+}
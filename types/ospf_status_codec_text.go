@@ -0,0 +1,75 @@
+package types
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UnmarshalText decodes the output of show ip ospf neighbor. Every adjacency
+// is a row of the table following the header starting with Neighbor ID, e.g.
+//
+//	    Neighbor ID Pri State           Dead Time Address         Interface            RXmtL RqstL DBsmL
+//	10.0.0.2          1 Full/DR           38.123s 10.1.0.2        eth1:10.1.0.1            0     0     0
+//
+// where the interface is followed by the local address on its network.
+func (statuses *OSPFNeighborStatuses) UnmarshalText(text []byte) error {
+	out := OSPFNeighborStatuses{}
+
+	inTable := false
+	scanner := bufio.NewScanner(bytes.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !inTable {
+			inTable = strings.HasPrefix(line, "Neighbor ID")
+			continue
+		}
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 6 {
+			return fmt.Errorf("malformed OSPF neighbor: %s", line)
+		}
+
+		priority, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return fmt.Errorf("malformed OSPF neighbor: %s", line)
+		}
+
+		s := &OSPFNeighborStatus{
+			RouterID:  fields[0],
+			Priority:  priority,
+			State:     fields[2],
+			DeadTime:  fields[3],
+			Address:   fields[4],
+			Interface: fields[5],
+		}
+		if i := strings.Index(s.State, "/"); i >= 0 {
+			s.State, s.Role = s.State[:i], s.State[i+1:]
+			if s.Role == "-" {
+				s.Role = ""
+			}
+		}
+		if i := strings.Index(s.Interface, ":"); i >= 0 {
+			s.Interface = s.Interface[:i]
+		}
+		out = append(out, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].RouterID != out[j].RouterID {
+			return lessAddress(out[i].RouterID, out[j].RouterID)
+		}
+		return out[i].Interface < out[j].Interface
+	})
+	*statuses = out
+	return nil
+}
@@ -0,0 +1,83 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+const maxOSPFMetric = 16777214
+
+// Validate checks the router ID, passive interfaces and redistributions of the
+// instance as well as its areas.
+func (o *OSPF) Validate() error {
+	v := new(validator)
+
+	if o.RouterID != "" && !isIPv4(o.RouterID) {
+		v.add("parameters.router-id", "%q is not a valid IPv4 address", o.RouterID)
+	}
+	for i, iface := range o.PassiveInterfaces {
+		v.validateName(fmt.Sprintf("passive-interface[%d]", i), iface)
+	}
+
+	if r := o.Redistribute; r != nil {
+		for _, source := range []struct {
+			field string
+			r     *OSPFRedistribution
+		}{
+			{"redistribute.connected", r.Connected},
+			{"redistribute.static", r.Static},
+			{"redistribute.kernel", r.Kernel},
+			{"redistribute.bgp", r.BGP},
+		} {
+			if source.r != nil {
+				validateRedistribution(v, source.field, source.r)
+			}
+		}
+	}
+
+	for _, id := range sortedAreaIDs(o.Areas) {
+		if a := o.Areas[id]; a != nil {
+			validateArea(v, fmt.Sprintf("area[%s]", id), id, a)
+		}
+	}
+	return v.err()
+}
+
+// Validate checks the ID and networks of the area. The ID is either in dotted
+// decimal notation, e.g. 0.0.0.0, or a number.
+func (a *OSPFArea) Validate() error {
+	v := new(validator)
+	validateArea(v, "", a.ID, a)
+	return v.err()
+}
+
+func validateArea(v *validator, prefix, id string, a *OSPFArea) {
+	if _, err := strconv.ParseUint(id, 10, 32); err != nil && !isIPv4(id) {
+		v.add(join(prefix, "id"), "%q must be an IPv4 address or a number", id)
+	}
+	for i, n := range a.Networks {
+		validateNetwork(v, join(prefix, fmt.Sprintf("network[%d]", i)), n)
+	}
+}
+
+func validateRedistribution(v *validator, field string, r *OSPFRedistribution) {
+	if r.Metric < 0 || r.Metric > maxOSPFMetric {
+		v.add(join(field, "metric"), "%d must be between 0 and %d", r.Metric, maxOSPFMetric)
+	}
+	if r.MetricType != 0 && r.MetricType != 1 && r.MetricType != 2 {
+		v.add(join(field, "metric-type"), "%d must be 1 or 2", r.MetricType)
+	}
+	if r.RouteMap != "" {
+		v.validateName(join(field, "route-map"), r.RouteMap)
+	}
+}
+
+func sortedAreaIDs(areas map[string]*OSPFArea) []string {
+	ids := make([]string, 0, len(areas))
+	for id := range areas {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
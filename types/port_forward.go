@@ -0,0 +1,124 @@
+package types
+
+import "fmt"
+
+// PortForward is the simplified port forwarding of the router, e.g.
+// port-forward, which sits at the top of the configuration rather than below
+// service. Traffic arriving on the WAN interface for the original port of a
+// rule is forwarded to the address and port of the rule. With hairpin NAT
+// hosts on the LAN interfaces reach the forwards through the WAN address too.
+// With auto firewall EdgeOS accepts the forwarded traffic by itself, see
+// FirewallRules. Both are encoded as enable or disable when set and are left
+// to the default of the firmware when nil. Rules are ordered by number and
+// keyed by it in JSON.
+type PortForward struct {
+	WANInterface  string             `json:"wan-interface,omitempty" tfsdk:"wan_interface"`
+	LANInterfaces []string           `json:"lan-interface,omitempty" tfsdk:"lan_interfaces"`
+	HairpinNAT    *bool              `json:"-" tfsdk:"hairpin_nat"`
+	AutoFirewall  *bool              `json:"-" tfsdk:"auto_firewall"`
+	Rules         []*PortForwardRule `json:"-" tfsdk:"-"`
+	opMode        OpMode
+}
+
+// PortForwardRule forwards the original port, or range of ports, to the
+// address of a host on the LAN, e.g. service port-forward rule 1. Without a
+// forward-to port the original port is kept.
+type PortForwardRule struct {
+	Number       int                `json:"-" tfsdk:"id"`
+	Description  string             `json:"description,omitempty" tfsdk:"description"`
+	OriginalPort *PortRange         `json:"-" tfsdk:"original_port"`
+	Protocol     string             `json:"protocol,omitempty" tfsdk:"protocol"`
+	ForwardTo    *PortForwardTarget `json:"forward-to,omitempty" tfsdk:"forward_to"`
+}
+
+// PortForwardTarget is the host and port traffic is forwarded to.
+type PortForwardTarget struct {
+	Address string     `json:"address,omitempty" tfsdk:"address"`
+	Port    *PortRange `json:"-" tfsdk:"port"`
+}
+
+// SetOpMode controls how the port forwarding is encoded. When set to
+// OpModeDelete, every set property names a node to delete, LAN interfaces are
+// deleted by value and rules by number.
+func (p *PortForward) SetOpMode(m OpMode) {
+	(*p).opMode = m
+}
+
+// Properties returns a copy of the port forwarding without its rules.
+func (p *PortForward) Properties() *PortForward {
+	tmp := *p
+	tmp.Rules = nil
+	return &tmp
+}
+
+// IsEmpty reports whether none of the properties of the port forwarding are
+// set. Rules are not considered.
+func (p *PortForward) IsEmpty() bool {
+	return p.WANInterface == "" &&
+		len(p.LANInterfaces) == 0 &&
+		p.HairpinNAT == nil &&
+		p.AutoFirewall == nil
+}
+
+// IsConfigured reports whether port forwarding has the WAN and LAN interfaces
+// EdgeOS requires before any rule can be added.
+func (p *PortForward) IsConfigured() bool {
+	return p.WANInterface != "" && len(p.LANInterfaces) > 0
+}
+
+// Rule returns the rule with the number or nil if there is none.
+func (p *PortForward) Rule(number int) *PortForwardRule {
+	for _, rule := range p.Rules {
+		if rule != nil && rule.Number == number {
+			return rule
+		}
+	}
+	return nil
+}
+
+// FirewallRules returns the rules EdgeOS adds to the firewall on its own when
+// the auto firewall is enabled, one for each forward, numbered after it. Each
+// accepts traffic entering the WAN interface that is forwarded to the host and
+// port of the forward. Without the auto firewall no rule is generated and the
+// forwarded traffic must be accepted by a ruleset attached to the WAN
+// interface.
+func (p *PortForward) FirewallRules() []*Rule {
+	rules := []*Rule{}
+	if p.AutoFirewall == nil || !*p.AutoFirewall {
+		return rules
+	}
+
+	for _, fwd := range p.Rules {
+		if fwd == nil || fwd.ForwardTo == nil {
+			continue
+		}
+
+		address := fwd.ForwardTo.Address
+		port := fwd.ForwardTo.Port
+		if port == nil {
+			port = fwd.OriginalPort
+		}
+
+		protocol := fwd.Protocol
+		if protocol == "" {
+			protocol = "tcp_udp"
+		}
+
+		description := fmt.Sprintf("port-forward rule %d", fwd.Number)
+		if fwd.Description != "" {
+			description = fmt.Sprintf("%s: %s", description, fwd.Description)
+		}
+
+		rules = append(rules, &Rule{
+			Priority:    fwd.Number,
+			Description: &description,
+			Action:      "accept",
+			Protocol:    protocol,
+			Destination: &Destination{
+				Address: &address,
+				Port:    port,
+			},
+		})
+	}
+	return rules
+}
@@ -0,0 +1,172 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+func (p *PortForward) MarshalJSON() ([]byte, error) {
+	if p.opMode == OpModeDelete {
+		return json.Marshal(p.deleteNodes())
+	}
+
+	var rules map[string]*PortForwardRule
+	if len(p.Rules) > 0 {
+		rules = map[string]*PortForwardRule{}
+		for _, rule := range p.Rules {
+			if rule != nil {
+				rules[strconv.Itoa(rule.Number)] = rule
+			}
+		}
+	}
+
+	type Alias PortForward
+	return json.Marshal(&struct {
+		HairpinNAT   string                      `json:"hairpin-nat,omitempty"`
+		AutoFirewall string                      `json:"auto-firewall,omitempty"`
+		Rules        map[string]*PortForwardRule `json:"rule,omitempty"`
+		*Alias
+	}{
+		HairpinNAT:   enableDisable(p.HairpinNAT),
+		AutoFirewall: enableDisable(p.AutoFirewall),
+		Rules:        rules,
+		Alias:        (*Alias)(p),
+	})
+}
+
+func (p *PortForward) UnmarshalJSON(data []byte) error {
+	type Alias PortForward
+	aux := &struct {
+		HairpinNAT   string                      `json:"hairpin-nat"`
+		AutoFirewall string                      `json:"auto-firewall"`
+		Rules        map[string]*PortForwardRule `json:"rule"`
+		*Alias
+	}{
+		Alias: (*Alias)(p),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.HairpinNAT != "" {
+		p.HairpinNAT = toBoolPtr(aux.HairpinNAT)
+	}
+	if aux.AutoFirewall != "" {
+		p.AutoFirewall = toBoolPtr(aux.AutoFirewall)
+	}
+
+	p.Rules = nil
+	for k, rule := range aux.Rules {
+		if rule == nil {
+			continue
+		}
+		i, err := strconv.Atoi(k)
+		if err != nil {
+			return fmt.Errorf("malformed port forward rule number: %v", k)
+		}
+		rule.Number = i
+		p.Rules = append(p.Rules, rule)
+	}
+	sort.Slice(p.Rules, func(i, j int) bool {
+		return p.Rules[i].Number < p.Rules[j].Number
+	})
+	return nil
+}
+
+// deleteNodes returns the nodes of the port forwarding that should be deleted.
+func (p *PortForward) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if p.WANInterface != "" {
+		nodes["wan-interface"] = nil
+	}
+	if len(p.LANInterfaces) > 0 {
+		nodes["lan-interface"] = p.LANInterfaces
+	}
+	if p.HairpinNAT != nil {
+		nodes["hairpin-nat"] = nil
+	}
+	if p.AutoFirewall != nil {
+		nodes["auto-firewall"] = nil
+	}
+	if len(p.Rules) > 0 {
+		rules := map[string]interface{}{}
+		for _, rule := range p.Rules {
+			if rule != nil {
+				rules[strconv.Itoa(rule.Number)] = nil
+			}
+		}
+		nodes["rule"] = rules
+	}
+
+	return nodes
+}
+
+func (r *PortForwardRule) MarshalJSON() ([]byte, error) {
+	type Alias PortForwardRule
+	return json.Marshal(&struct {
+		OriginalPort string `json:"original-port,omitempty"`
+		*Alias
+	}{
+		OriginalPort: r.OriginalPort.toPort(),
+		Alias:        (*Alias)(r),
+	})
+}
+
+func (r *PortForwardRule) UnmarshalJSON(data []byte) (err error) {
+	type Alias PortForwardRule
+	aux := &struct {
+		OriginalPort string `json:"original-port"`
+		*Alias
+	}{
+		Alias: (*Alias)(r),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if r.OriginalPort, err = fromPort(aux.OriginalPort); err != nil {
+		return fmt.Errorf("Error setting original ports %s from json `%s`: %s", aux.OriginalPort, string(data), err.Error())
+	}
+	return nil
+}
+
+func (t *PortForwardTarget) MarshalJSON() ([]byte, error) {
+	type Alias PortForwardTarget
+	return json.Marshal(&struct {
+		Port string `json:"port,omitempty"`
+		*Alias
+	}{
+		Port:  t.Port.toPort(),
+		Alias: (*Alias)(t),
+	})
+}
+
+func (t *PortForwardTarget) UnmarshalJSON(data []byte) (err error) {
+	type Alias PortForwardTarget
+	aux := &struct {
+		Port string `json:"port"`
+		*Alias
+	}{
+		Alias: (*Alias)(t),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if t.Port, err = fromPort(aux.Port); err != nil {
+		return fmt.Errorf("Error setting forward-to ports %s from json `%s`: %s", aux.Port, string(data), err.Error())
+	}
+	return nil
+}
+
+// enableDisable encodes an optional enable or disable leaf, which is omitted
+// when nil.
+func enableDisable(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return toEnableDisable(b)
+}
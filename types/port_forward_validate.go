@@ -0,0 +1,88 @@
+package types
+
+import "fmt"
+
+// Validate checks the interfaces and every rule of the port forwarding. The
+// WAN and LAN interfaces are required once there is a rule.
+func (p *PortForward) Validate() error {
+	v := new(validator)
+
+	if p.WANInterface != "" {
+		v.validateName("wan-interface", p.WANInterface)
+	}
+
+	seen := map[string]bool{}
+	for i, iface := range p.LANInterfaces {
+		field := fmt.Sprintf("lan-interface[%d]", i)
+		v.validateName(field, iface)
+		if iface == p.WANInterface {
+			v.add(field, "%q is the WAN interface", iface)
+		}
+		if seen[iface] {
+			v.add(field, "%q is listed more than once", iface)
+		}
+		seen[iface] = true
+	}
+
+	if len(p.Rules) > 0 {
+		if p.WANInterface == "" {
+			v.add("wan-interface", "is required when rules are defined")
+		}
+		if len(p.LANInterfaces) == 0 {
+			v.add("lan-interface", "is required when rules are defined")
+		}
+	}
+
+	numbers := map[int]bool{}
+	for i, rule := range p.Rules {
+		if rule == nil {
+			v.add(fmt.Sprintf("rule[%d]", i), "must not be null")
+			continue
+		}
+
+		prefix := fmt.Sprintf("rule[%d]", rule.Number)
+		if numbers[rule.Number] {
+			v.add(prefix, "is defined more than once")
+		}
+		numbers[rule.Number] = true
+
+		rule.validate(v, prefix)
+	}
+	return v.err()
+}
+
+// Validate checks the rule on its own.
+func (r *PortForwardRule) Validate() error {
+	v := new(validator)
+	r.validate(v, "")
+	return v.err()
+}
+
+func (r *PortForwardRule) validate(v *validator, prefix string) {
+	if r.Number < MinRulePriority || r.Number > MaxRulePriority {
+		v.add(join(prefix, "rule"), "%d must be between %d and %d", r.Number, MinRulePriority, MaxRulePriority)
+	}
+
+	v.validateOneOf(join(prefix, "protocol"), r.Protocol, portProtocols)
+
+	if r.OriginalPort == nil {
+		v.add(join(prefix, "original-port"), "must be set")
+	} else {
+		r.OriginalPort.validate(v, join(prefix, "original-port"))
+	}
+
+	if r.ForwardTo == nil || r.ForwardTo.Address == "" {
+		v.add(join(prefix, "forward-to.address"), "must be set")
+		return
+	}
+	if !isIPv4(r.ForwardTo.Address) {
+		v.add(join(prefix, "forward-to.address"), "%q is not a valid IPv4 address", r.ForwardTo.Address)
+	}
+
+	if port := r.ForwardTo.Port; port != nil {
+		port.validate(v, join(prefix, "forward-to.port"))
+		if r.OriginalPort != nil && port.To-port.From != r.OriginalPort.To-r.OriginalPort.From {
+			v.add(join(prefix, "forward-to.port"), "range %s must be as long as the original port range %s", port.toPort(), r.OriginalPort.toPort())
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package types
+
+import "fmt"
+
+// PPPoE is a PPPoE client session on an ethernet interface or VLAN, e.g.
+// interfaces ethernet eth0 pppoe 0, which the kernel names pppoe0. Parent is
+// the path of the interface the session runs on and ID is the session number.
+type PPPoE struct {
+	ID           string              `json:"-" tfsdk:"id"`
+	Parent       InterfacePath       `json:"-" tfsdk:"-"`
+	Description  string              `json:"description,omitempty" tfsdk:"-"`
+	UserID       string              `json:"user-id,omitempty" tfsdk:"-"`
+	Password     string              `json:"password,omitempty" tfsdk:"-"`
+	MTU          int                 `json:"-" tfsdk:"-"`
+	DefaultRoute string              `json:"default-route,omitempty" tfsdk:"-"`
+	NameServer   string              `json:"name-server,omitempty" tfsdk:"-"`
+	Firewall     *FirewallAttachment `json:"firewall,omitempty" tfsdk:"-"`
+	opMode       OpMode
+}
+
+func (p *PPPoE) GetID() string {
+	return p.Path().String()
+}
+
+// Path returns the path of the session, e.g. ethernet eth0 pppoe 0.
+func (p *PPPoE) Path() InterfacePath {
+	return p.Parent.Child("pppoe", p.ID)
+}
+
+// SetOpMode controls how the session is encoded. When set to OpModeDelete,
+// every set property names a node to delete. A session without any property
+// removes the session entirely.
+func (p *PPPoE) SetOpMode(m OpMode) {
+	(*p).opMode = m
+}
+
+// Properties returns a copy of the session without its firewall attachment.
+func (p *PPPoE) Properties() *PPPoE {
+	tmp := *p
+	tmp.Firewall = nil
+	return &tmp
+}
+
+// IsEmpty reports whether none of the properties of the session are set. The
+// firewall attachment is not considered.
+func (p *PPPoE) IsEmpty() bool {
+	return p.Description == "" &&
+		p.UserID == "" &&
+		p.Password == "" &&
+		p.MTU == 0 &&
+		p.DefaultRoute == "" &&
+		p.NameServer == ""
+}
+
+// String masks the password so that sessions can be logged. It has a value
+// receiver so that both PPPoE and *PPPoE are masked.
+func (p PPPoE) String() string {
+	return fmt.Sprintf("PPPoE{Interface: %s, Description: %q, UserID: %q, Password: %q, MTU: %d, DefaultRoute: %q, NameServer: %q}",
+		p.Path(), p.Description, p.UserID, Secret(p.Password), p.MTU, p.DefaultRoute, p.NameServer)
+}
+
+// GoString masks the password when formatted with %#v.
+func (p PPPoE) GoString() string {
+	return p.String()
+}
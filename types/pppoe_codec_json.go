@@ -0,0 +1,63 @@
+package types
+
+import "encoding/json"
+
+func (p *PPPoE) MarshalJSON() ([]byte, error) {
+	if p.opMode == OpModeDelete {
+		if p.IsEmpty() {
+			return []byte("null"), nil
+		}
+		return json.Marshal(p.deleteNodes())
+	}
+
+	type Alias PPPoE
+	return json.Marshal(&struct {
+		MTU string `json:"mtu,omitempty"`
+		*Alias
+	}{
+		MTU:   itoa(p.MTU),
+		Alias: (*Alias)(p),
+	})
+}
+
+func (p *PPPoE) UnmarshalJSON(data []byte) (err error) {
+	type Alias PPPoE
+	aux := &struct {
+		MTU string `json:"mtu,omitempty"`
+		*Alias
+	}{
+		Alias: (*Alias)(p),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	p.MTU, err = atoi("mtu", aux.MTU)
+	return err
+}
+
+// deleteNodes returns the nodes of the session that should be deleted.
+func (p *PPPoE) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if p.Description != "" {
+		nodes["description"] = nil
+	}
+	if p.UserID != "" {
+		nodes["user-id"] = nil
+	}
+	if p.Password != "" {
+		nodes["password"] = nil
+	}
+	if p.MTU != 0 {
+		nodes["mtu"] = nil
+	}
+	if p.DefaultRoute != "" {
+		nodes["default-route"] = nil
+	}
+	if p.NameServer != "" {
+		nodes["name-server"] = nil
+	}
+
+	return nodes
+}
@@ -0,0 +1,30 @@
+package types
+
+const maxPPPoEMTU = 1492
+
+var (
+	pppoeDefaultRoutes = []string{"auto", "force", "none"}
+	pppoeNameServers   = []string{"auto", "none"}
+)
+
+// Validate checks the path, credentials and options of the session. The
+// firewall attachment is validated by its own client.
+func (p *PPPoE) Validate() error {
+	v := new(validator)
+
+	if err := p.Path().Validate(); err != nil {
+		v.add("interface", "%v", err)
+	}
+	if p.UserID == "" {
+		v.add("user-id", "must not be empty")
+	}
+	if p.Password == "" {
+		v.add("password", "must not be empty")
+	}
+	if p.MTU != 0 && (p.MTU < minMTU || p.MTU > maxPPPoEMTU) {
+		v.add("mtu", "%d must be between %d and %d", p.MTU, minMTU, maxPPPoEMTU)
+	}
+	v.validateOneOf("default-route", p.DefaultRoute, pppoeDefaultRoutes)
+	v.validateOneOf("name-server", p.NameServer, pppoeNameServers)
+	return v.err()
+}
@@ -0,0 +1,35 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Protocols is the protocols node of the configuration. Only the protocols the
+// SDK manages are modelled. BGP is keyed by the local AS number, of which
+// EdgeOS supports a single one.
+type Protocols struct {
+	Static *StaticRouting  `json:"static,omitempty"`
+	BGP    map[string]*BGP `json:"bgp,omitempty"`
+	OSPF   *OSPF           `json:"ospf,omitempty"`
+}
+
+func (p *Protocols) UnmarshalJSON(data []byte) error {
+	type Alias Protocols
+	if err := json.Unmarshal(data, (*Alias)(p)); err != nil {
+		return err
+	}
+
+	for k, b := range p.BGP {
+		if b == nil {
+			continue
+		}
+		asn, err := strconv.Atoi(k)
+		if err != nil {
+			return fmt.Errorf("malformed BGP AS number: %v", k)
+		}
+		b.ASN = asn
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package types
+
+// PseudoEthernet is a MAC VLAN interface on top of an ethernet interface, e.g.
+// interfaces pseudo-ethernet peth0. Link names the underlying interface.
+type PseudoEthernet struct {
+	ID          string              `json:"-" tfsdk:"id"`
+	Link        string              `json:"link,omitempty" tfsdk:"-"`
+	MAC         string              `json:"mac,omitempty" tfsdk:"-"`
+	Addresses   []string            `json:"address,omitempty" tfsdk:"-"`
+	Description string              `json:"description,omitempty" tfsdk:"-"`
+	DHCPOptions *DHCPOptions        `json:"dhcp-options,omitempty" tfsdk:"-"`
+	Disable     bool                `json:"-" tfsdk:"-"`
+	Firewall    *FirewallAttachment `json:"firewall,omitempty" tfsdk:"-"`
+	VIF         map[string]*VIF     `json:"vif,omitempty" tfsdk:"-"`
+	opMode      OpMode
+}
+
+func (p *PseudoEthernet) GetID() string {
+	return p.ID
+}
+
+// SetOpMode controls how the interface is encoded. When set to OpModeDelete,
+// every set property names a node to delete, and addresses are deleted by
+// value.
+func (p *PseudoEthernet) SetOpMode(m OpMode) {
+	(*p).opMode = m
+}
+
+// Properties returns a copy of the interface without its firewall attachment
+// and VLANs.
+func (p *PseudoEthernet) Properties() *PseudoEthernet {
+	tmp := *p
+	tmp.Firewall = nil
+	tmp.VIF = nil
+	return &tmp
+}
+
+// IsEmpty reports whether none of the properties of the interface are set.
+// The firewall attachment and VLANs are not considered.
+func (p *PseudoEthernet) IsEmpty() bool {
+	return p.Link == "" &&
+		p.MAC == "" &&
+		len(p.Addresses) == 0 &&
+		p.Description == "" &&
+		p.DHCPOptions == nil &&
+		!p.Disable
+}
@@ -0,0 +1,60 @@
+package types
+
+import "encoding/json"
+
+func (p *PseudoEthernet) MarshalJSON() ([]byte, error) {
+	if p.opMode == OpModeDelete {
+		return json.Marshal(p.deleteNodes())
+	}
+
+	type Alias PseudoEthernet
+	return json.Marshal(&struct {
+		Disable *null `json:"disable,omitempty"`
+		*Alias
+	}{
+		Disable: flag(p.Disable),
+		Alias:   (*Alias)(p),
+	})
+}
+
+func (p *PseudoEthernet) UnmarshalJSON(data []byte) (err error) {
+	type Alias PseudoEthernet
+	aux := &struct {
+		Disable null `json:"disable"`
+		*Alias
+	}{
+		Alias: (*Alias)(p),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	p.Disable = aux.Disable.val
+	return nil
+}
+
+// deleteNodes returns the nodes of the interface that should be deleted.
+func (p *PseudoEthernet) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if p.Link != "" {
+		nodes["link"] = nil
+	}
+	if p.MAC != "" {
+		nodes["mac"] = nil
+	}
+	if len(p.Addresses) > 0 {
+		nodes["address"] = p.Addresses
+	}
+	if p.Description != "" {
+		nodes["description"] = nil
+	}
+	if p.Disable {
+		nodes["disable"] = nil
+	}
+	if p.DHCPOptions != nil {
+		nodes["dhcp-options"] = p.DHCPOptions.deleteNodes()
+	}
+
+	return nodes
+}
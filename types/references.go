@@ -0,0 +1,221 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ReferenceKind is the kind of configuration node that refers to a firewall
+// group or ruleset.
+type ReferenceKind string
+
+const (
+	// ReferenceKindRule is a firewall rule that refers to a group.
+	ReferenceKindRule ReferenceKind = "rule"
+	// ReferenceKindAttachment is an interface that has a ruleset attached.
+	ReferenceKindAttachment ReferenceKind = "attachment"
+	// ReferenceKindNATRule is a NAT rule that refers to a group.
+	ReferenceKindNATRule ReferenceKind = "nat-rule"
+)
+
+// Reference describes a configuration node that refers to a firewall group or
+// ruleset. Priority and Field are set for firewall and NAT rules while
+// Interface, Direction and IPv6 are set for attachments. Ruleset is set for
+// firewall rules and attachments. Interface is the path of the interface, e.g.
+// "ethernet eth0 vif 20".
+type Reference struct {
+	Kind      ReferenceKind
+	Ruleset   string
+	Priority  int
+	Field     string
+	Interface string
+	Direction string
+	// IPv6 is whether the attachment is of an IPv6 ruleset (ipv6-name).
+	IPv6 bool
+}
+
+func (r Reference) String() string {
+	if r.Kind == ReferenceKindAttachment {
+		if r.IPv6 {
+			return fmt.Sprintf("interfaces %s firewall %s ipv6-name", r.Interface, r.Direction)
+		}
+		return fmt.Sprintf("interfaces %s firewall %s", r.Interface, r.Direction)
+	}
+	if r.Kind == ReferenceKindNATRule {
+		return fmt.Sprintf("service nat rule %d %s", r.Priority, r.Field)
+	}
+	return fmt.Sprintf("firewall name %s rule %d %s", r.Ruleset, r.Priority, r.Field)
+}
+
+// ReferenceIndex records who refers to each firewall group and ruleset.
+type ReferenceIndex struct {
+	addressGroups map[string][]Reference
+	portGroups    map[string][]Reference
+	rulesets      map[string][]Reference
+	ipv6Rulesets  map[string][]Reference
+}
+
+// NewReferenceIndex indexes the references found in the firewall rules, the
+// interface attachments and the NAT rules. Any argument may be nil.
+func NewReferenceIndex(fw *Firewall, ifaces *Interfaces, nat *NAT) *ReferenceIndex {
+	idx := &ReferenceIndex{
+		addressGroups: map[string][]Reference{},
+		portGroups:    map[string][]Reference{},
+		rulesets:      map[string][]Reference{},
+		ipv6Rulesets:  map[string][]Reference{},
+	}
+
+	if fw != nil {
+		for name, rs := range fw.Rulesets {
+			if rs == nil {
+				continue
+			}
+			for _, rule := range rs.Rules {
+				if rule == nil {
+					continue
+				}
+				if s := rule.Source; s != nil {
+					idx.addRule(idx.addressGroups, s.AddressGroup, name, rule.Priority, "source group address-group")
+					idx.addRule(idx.portGroups, s.PortGroup, name, rule.Priority, "source group port-group")
+				}
+				if d := rule.Destination; d != nil {
+					idx.addRule(idx.addressGroups, d.AddressGroup, name, rule.Priority, "destination group address-group")
+					idx.addRule(idx.portGroups, d.PortGroup, name, rule.Priority, "destination group port-group")
+				}
+			}
+		}
+	}
+
+	if nat != nil {
+		for _, rule := range nat.Rules {
+			if rule == nil {
+				continue
+			}
+			if s := rule.Source; s != nil {
+				idx.addNATRule(idx.addressGroups, s.AddressGroup, rule.Priority, "source group address-group")
+				idx.addNATRule(idx.portGroups, s.PortGroup, rule.Priority, "source group port-group")
+			}
+			if d := rule.Destination; d != nil {
+				idx.addNATRule(idx.addressGroups, d.AddressGroup, rule.Priority, "destination group address-group")
+				idx.addNATRule(idx.portGroups, d.PortGroup, rule.Priority, "destination group port-group")
+			}
+		}
+	}
+
+	if ifaces != nil {
+		for _, a := range ifaces.Attachments() {
+			idx.addAttachment(a.Path.String(), a)
+		}
+	}
+
+	for _, refs := range []map[string][]Reference{idx.addressGroups, idx.portGroups, idx.rulesets, idx.ipv6Rulesets} {
+		for _, r := range refs {
+			sortReferences(r)
+		}
+	}
+
+	return idx
+}
+
+func (idx *ReferenceIndex) addRule(m map[string][]Reference, group *string, ruleset string, priority int, field string) {
+	if group == nil || *group == "" {
+		return
+	}
+	m[*group] = append(m[*group], Reference{
+		Kind:     ReferenceKindRule,
+		Ruleset:  ruleset,
+		Priority: priority,
+		Field:    field,
+	})
+}
+
+func (idx *ReferenceIndex) addNATRule(m map[string][]Reference, group *string, priority int, field string) {
+	if group == nil || *group == "" {
+		return
+	}
+	m[*group] = append(m[*group], Reference{
+		Kind:     ReferenceKindNATRule,
+		Priority: priority,
+		Field:    field,
+	})
+}
+
+func (idx *ReferenceIndex) addAttachment(iface string, a *FirewallAttachment) {
+	if a == nil {
+		return
+	}
+	for _, dir := range []struct {
+		direction string
+		name      *string
+		ipv6      bool
+	}{
+		{"in", a.In, false},
+		{"out", a.Out, false},
+		{"local", a.Local, false},
+		{"in", a.IPv6In, true},
+		{"out", a.IPv6Out, true},
+		{"local", a.IPv6Local, true},
+	} {
+		if dir.name == nil || *dir.name == "" {
+			continue
+		}
+		m := idx.rulesets
+		if dir.ipv6 {
+			m = idx.ipv6Rulesets
+		}
+		m[*dir.name] = append(m[*dir.name], Reference{
+			Kind:      ReferenceKindAttachment,
+			Ruleset:   *dir.name,
+			Interface: iface,
+			Direction: dir.direction,
+			IPv6:      dir.ipv6,
+		})
+	}
+}
+
+// AddressGroup returns the firewall and NAT rules that refer to the address
+// group.
+func (idx *ReferenceIndex) AddressGroup(name string) []Reference {
+	return idx.addressGroups[name]
+}
+
+// PortGroup returns the firewall and NAT rules that refer to the port group.
+func (idx *ReferenceIndex) PortGroup(name string) []Reference {
+	return idx.portGroups[name]
+}
+
+// Ruleset returns the interfaces the IPv4 ruleset is attached to.
+func (idx *ReferenceIndex) Ruleset(name string) []Reference {
+	return idx.rulesets[name]
+}
+
+// IPv6Ruleset returns the interfaces the IPv6 ruleset is attached to.
+func (idx *ReferenceIndex) IPv6Ruleset(name string) []Reference {
+	return idx.ipv6Rulesets[name]
+}
+
+func sortReferences(refs []Reference) {
+	sort.SliceStable(refs, func(i, j int) bool {
+		a, b := refs[i], refs[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Ruleset != b.Ruleset {
+			return a.Ruleset < b.Ruleset
+		}
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		if a.Interface != b.Interface {
+			return lessPath(strings.Fields(a.Interface), strings.Fields(b.Interface))
+		}
+		if a.Direction != b.Direction {
+			return a.Direction < b.Direction
+		}
+		return !a.IPv6 && b.IPv6
+	})
+}
@@ -0,0 +1,21 @@
+package types
+
+// Redacted replaces passwords and keys whenever they are formatted.
+const Redacted = "********"
+
+// Secret is a password or key that is redacted whenever it is formatted, so
+// that values holding one can be logged. An empty secret formats as an empty
+// string so that it is still apparent whether one is set.
+type Secret string
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// GoString redacts the secret when formatted with %#v.
+func (s Secret) GoString() string {
+	return s.String()
+}
@@ -0,0 +1,9 @@
+package types
+
+// Service is the service node of the configuration. Only the services the SDK
+// manages are modelled.
+type Service struct {
+	DHCPServer *DHCPServer `json:"dhcp-server,omitempty"`
+	DNS        *DNS        `json:"dns,omitempty"`
+	NAT        *NAT        `json:"nat,omitempty"`
+}
@@ -0,0 +1,137 @@
+package types
+
+// StaticRouting holds the static routes of the main routing table and of the
+// additional routing tables used for policy based routing, e.g. protocols
+// static. Routes are keyed by destination and tables by their number.
+type StaticRouting struct {
+	Routes          map[string]*StaticRoute    `json:"route,omitempty"`
+	InterfaceRoutes map[string]*InterfaceRoute `json:"interface-route,omitempty"`
+	Tables          map[string]*RoutingTable   `json:"table,omitempty"`
+}
+
+// RoutingTable is an additional routing table, e.g. protocols static table
+// 10, which is selected by the modify rules of a firewall ruleset.
+type RoutingTable struct {
+	ID              int                        `json:"-" tfsdk:"id"`
+	Description     string                     `json:"description,omitempty" tfsdk:"description"`
+	Routes          map[string]*StaticRoute    `json:"route,omitempty" tfsdk:"-"`
+	InterfaceRoutes map[string]*InterfaceRoute `json:"interface-route,omitempty" tfsdk:"-"`
+	opMode          OpMode
+}
+
+// StaticRoute routes the destination through one or more gateways or drops
+// it, e.g. protocols static route 10.0.0.0/8. Table is the routing table the
+// route belongs to, where 0 is the main table. Next hops are keyed by the
+// address of the gateway.
+type StaticRoute struct {
+	Destination string              `json:"-" tfsdk:"destination"`
+	Table       int                 `json:"-" tfsdk:"table"`
+	Description string              `json:"description,omitempty" tfsdk:"description"`
+	NextHops    map[string]*NextHop `json:"next-hop,omitempty" tfsdk:"next_hops"`
+	Blackhole   *Blackhole          `json:"blackhole,omitempty" tfsdk:"blackhole"`
+	opMode      OpMode
+}
+
+// NextHop is a gateway of a static route. Among the usable next hops of the
+// same destination the one with the lowest distance wins.
+type NextHop struct {
+	Address     string `json:"-" tfsdk:"address"`
+	Description string `json:"description,omitempty" tfsdk:"description"`
+	Distance    int    `json:"-" tfsdk:"distance"`
+	Disable     bool   `json:"-" tfsdk:"disable"`
+	opMode      OpMode
+}
+
+// Blackhole silently drops the traffic of a static route.
+type Blackhole struct {
+	Description string `json:"description,omitempty" tfsdk:"description"`
+	Distance    int    `json:"-" tfsdk:"distance"`
+	opMode      OpMode
+}
+
+// InterfaceRoute routes the destination out of one or more interfaces without
+// a gateway, e.g. protocols static interface-route 10.10.0.0/16, which suits
+// point to point links such as tunnels. Next hops are keyed by interface.
+type InterfaceRoute struct {
+	Destination string                       `json:"-" tfsdk:"destination"`
+	Table       int                          `json:"-" tfsdk:"table"`
+	Description string                       `json:"description,omitempty" tfsdk:"description"`
+	NextHops    map[string]*NextHopInterface `json:"next-hop-interface,omitempty" tfsdk:"next_hops"`
+	opMode      OpMode
+}
+
+// NextHopInterface is an interface of an interface route.
+type NextHopInterface struct {
+	Interface   string `json:"-" tfsdk:"interface"`
+	Description string `json:"description,omitempty" tfsdk:"description"`
+	Distance    int    `json:"-" tfsdk:"distance"`
+	Disable     bool   `json:"-" tfsdk:"disable"`
+	opMode      OpMode
+}
+
+// SetOpMode controls how the table is encoded. When set to OpModeDelete, a set
+// description names a node to delete. Routes are never encoded in this mode.
+func (t *RoutingTable) SetOpMode(m OpMode) {
+	(*t).opMode = m
+}
+
+// Properties returns a copy of the table without its routes.
+func (t *RoutingTable) Properties() *RoutingTable {
+	tmp := *t
+	tmp.Routes = nil
+	tmp.InterfaceRoutes = nil
+	return &tmp
+}
+
+// SetOpMode controls how the route is encoded. When set to OpModeDelete, every
+// set property names a node to delete. Next hops and the blackhole are
+// encoded in the same mode, where one without any property is deleted as a
+// whole.
+func (r *StaticRoute) SetOpMode(m OpMode) {
+	(*r).opMode = m
+	for _, hop := range r.NextHops {
+		if hop != nil {
+			hop.opMode = m
+		}
+	}
+	if r.Blackhole != nil {
+		r.Blackhole.opMode = m
+	}
+}
+
+// IsEmpty reports whether none of the properties of the route are set.
+func (r *StaticRoute) IsEmpty() bool {
+	return r.Description == "" && len(r.NextHops) == 0 && r.Blackhole == nil
+}
+
+// SetOpMode controls how the route is encoded. When set to OpModeDelete, every
+// set property names a node to delete. Next hops are encoded in the same
+// mode, where one without any property is deleted as a whole.
+func (r *InterfaceRoute) SetOpMode(m OpMode) {
+	(*r).opMode = m
+	for _, hop := range r.NextHops {
+		if hop != nil {
+			hop.opMode = m
+		}
+	}
+}
+
+// IsEmpty reports whether none of the properties of the route are set.
+func (r *InterfaceRoute) IsEmpty() bool {
+	return r.Description == "" && len(r.NextHops) == 0
+}
+
+// IsEmpty reports whether none of the properties of the next hop are set.
+func (h *NextHop) IsEmpty() bool {
+	return h.Description == "" && h.Distance == 0 && !h.Disable
+}
+
+// IsEmpty reports whether none of the properties of the blackhole are set.
+func (b *Blackhole) IsEmpty() bool {
+	return b.Description == "" && b.Distance == 0
+}
+
+// IsEmpty reports whether none of the properties of the next hop are set.
+func (h *NextHopInterface) IsEmpty() bool {
+	return h.Description == "" && h.Distance == 0 && !h.Disable
+}
@@ -0,0 +1,213 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+func (s *StaticRouting) UnmarshalJSON(data []byte) error {
+	type Alias StaticRouting
+	if err := json.Unmarshal(data, (*Alias)(s)); err != nil {
+		return err
+	}
+
+	setRouteIDs(0, s.Routes, s.InterfaceRoutes)
+	for k, t := range s.Tables {
+		if t == nil {
+			continue
+		}
+		id, err := strconv.Atoi(k)
+		if err != nil {
+			return fmt.Errorf("malformed routing table: %v", k)
+		}
+		t.ID = id
+		setRouteIDs(id, t.Routes, t.InterfaceRoutes)
+	}
+	return nil
+}
+
+func setRouteIDs(table int, routes map[string]*StaticRoute, ifaceRoutes map[string]*InterfaceRoute) {
+	for dst, r := range routes {
+		if r != nil {
+			r.Destination = dst
+			r.Table = table
+		}
+	}
+	for dst, r := range ifaceRoutes {
+		if r != nil {
+			r.Destination = dst
+			r.Table = table
+		}
+	}
+}
+
+func (t *RoutingTable) MarshalJSON() ([]byte, error) {
+	if t.opMode == OpModeDelete {
+		nodes := map[string]interface{}{}
+		if t.Description != "" {
+			nodes["description"] = nil
+		}
+		return json.Marshal(nodes)
+	}
+
+	type Alias RoutingTable
+	return json.Marshal((*Alias)(t))
+}
+
+func (r *StaticRoute) MarshalJSON() ([]byte, error) {
+	if r.opMode == OpModeDelete {
+		nodes := map[string]interface{}{}
+		if r.Description != "" {
+			nodes["description"] = nil
+		}
+		if len(r.NextHops) > 0 {
+			nodes["next-hop"] = r.NextHops
+		}
+		if r.Blackhole != nil {
+			nodes["blackhole"] = r.Blackhole
+		}
+		return json.Marshal(nodes)
+	}
+
+	type Alias StaticRoute
+	return json.Marshal((*Alias)(r))
+}
+
+func (r *StaticRoute) UnmarshalJSON(data []byte) error {
+	type Alias StaticRoute
+	aux := &struct {
+		Blackhole json.RawMessage `json:"blackhole"`
+		*Alias
+	}{
+		Alias: (*Alias)(r),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	// A blackhole without any property is null.
+	if len(aux.Blackhole) > 0 {
+		r.Blackhole = new(Blackhole)
+		if string(aux.Blackhole) != "null" {
+			if err := json.Unmarshal(aux.Blackhole, r.Blackhole); err != nil {
+				return err
+			}
+		}
+	}
+
+	for addr, hop := range r.NextHops {
+		if hop == nil {
+			hop = new(NextHop)
+			r.NextHops[addr] = hop
+		}
+		hop.Address = addr
+	}
+	return nil
+}
+
+func (r *InterfaceRoute) MarshalJSON() ([]byte, error) {
+	if r.opMode == OpModeDelete {
+		nodes := map[string]interface{}{}
+		if r.Description != "" {
+			nodes["description"] = nil
+		}
+		if len(r.NextHops) > 0 {
+			nodes["next-hop-interface"] = r.NextHops
+		}
+		return json.Marshal(nodes)
+	}
+
+	type Alias InterfaceRoute
+	return json.Marshal((*Alias)(r))
+}
+
+func (r *InterfaceRoute) UnmarshalJSON(data []byte) error {
+	type Alias InterfaceRoute
+	if err := json.Unmarshal(data, (*Alias)(r)); err != nil {
+		return err
+	}
+
+	for iface, hop := range r.NextHops {
+		if hop == nil {
+			hop = new(NextHopInterface)
+			r.NextHops[iface] = hop
+		}
+		hop.Interface = iface
+	}
+	return nil
+}
+
+// A next hop or blackhole without any property is null in both op modes.
+func (h *NextHop) MarshalJSON() ([]byte, error) {
+	return marshalHop(h.opMode, h.Description, h.Distance, h.Disable)
+}
+
+func (h *NextHop) UnmarshalJSON(data []byte) (err error) {
+	h.Description, h.Distance, h.Disable, err = unmarshalHop(data)
+	return err
+}
+
+func (h *NextHopInterface) MarshalJSON() ([]byte, error) {
+	return marshalHop(h.opMode, h.Description, h.Distance, h.Disable)
+}
+
+func (h *NextHopInterface) UnmarshalJSON(data []byte) (err error) {
+	h.Description, h.Distance, h.Disable, err = unmarshalHop(data)
+	return err
+}
+
+func (b *Blackhole) MarshalJSON() ([]byte, error) {
+	return marshalHop(b.opMode, b.Description, b.Distance, false)
+}
+
+func (b *Blackhole) UnmarshalJSON(data []byte) (err error) {
+	b.Description, b.Distance, _, err = unmarshalHop(data)
+	return err
+}
+
+type apiHop struct {
+	Description string `json:"description,omitempty"`
+	Distance    string `json:"distance,omitempty"`
+	Disable     *null  `json:"disable,omitempty"`
+}
+
+func marshalHop(m OpMode, description string, distance int, disable bool) ([]byte, error) {
+	if description == "" && distance == 0 && !disable {
+		return []byte("null"), nil
+	}
+
+	if m == OpModeDelete {
+		nodes := map[string]interface{}{}
+		if description != "" {
+			nodes["description"] = nil
+		}
+		if distance != 0 {
+			nodes["distance"] = nil
+		}
+		if disable {
+			nodes["disable"] = nil
+		}
+		return json.Marshal(nodes)
+	}
+
+	return json.Marshal(&apiHop{
+		Description: description,
+		Distance:    itoa(distance),
+		Disable:     flag(disable),
+	})
+}
+
+func unmarshalHop(data []byte) (description string, distance int, disable bool, err error) {
+	aux := &struct {
+		Description string `json:"description"`
+		Distance    string `json:"distance"`
+		Disable     null   `json:"disable"`
+	}{}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return "", 0, false, err
+	}
+
+	distance, err = atoi("distance", aux.Distance)
+	return aux.Description, distance, aux.Disable.val, err
+}
@@ -0,0 +1,120 @@
+package types
+
+import (
+	"fmt"
+	"net"
+	"sort"
+)
+
+const (
+	minRoutingTable = 1
+	maxRoutingTable = 250
+)
+
+// Validate checks the number of the table.
+func (t *RoutingTable) Validate() error {
+	v := new(validator)
+	validateTable(v, "table", t.ID, false)
+	return v.err()
+}
+
+// Validate checks the destination, table and next hops of the route. A route
+// either has next hops or is a blackhole.
+func (r *StaticRoute) Validate() error {
+	v := new(validator)
+	validateDestination(v, r.Destination)
+	validateTable(v, "table", r.Table, true)
+
+	if len(r.NextHops) == 0 && r.Blackhole == nil {
+		v.add("next-hop", "must be set unless the route is a blackhole")
+	}
+	if len(r.NextHops) > 0 && r.Blackhole != nil {
+		v.add("blackhole", "must not be set together with next-hop")
+	}
+
+	for _, addr := range sortedKeys(r.NextHops) {
+		field := fmt.Sprintf("next-hop[%s]", addr)
+		if !isIPv4(addr) {
+			v.add(field, "%q is not a valid IPv4 address", addr)
+		}
+		if hop := r.NextHops[addr]; hop != nil {
+			validateDistance(v, join(field, "distance"), hop.Distance)
+		}
+	}
+	if r.Blackhole != nil {
+		validateDistance(v, "blackhole.distance", r.Blackhole.Distance)
+	}
+	return v.err()
+}
+
+// Validate checks the destination, table and interfaces of the route.
+func (r *InterfaceRoute) Validate() error {
+	v := new(validator)
+	validateDestination(v, r.Destination)
+	validateTable(v, "table", r.Table, true)
+
+	if len(r.NextHops) == 0 {
+		v.add("next-hop-interface", "must be set")
+	}
+
+	for _, iface := range sortedKeys(r.NextHops) {
+		field := fmt.Sprintf("next-hop-interface[%s]", iface)
+		v.validateName(field, iface)
+		if hop := r.NextHops[iface]; hop != nil {
+			validateDistance(v, join(field, "distance"), hop.Distance)
+		}
+	}
+	return v.err()
+}
+
+func validateDestination(v *validator, dst string) {
+	validateNetwork(v, "destination", dst)
+}
+
+// validateNetwork ensures val is an IPv4 network in CIDR notation without any
+// host bits set, as EdgeOS rejects it otherwise.
+func validateNetwork(v *validator, field, val string) {
+	ip, n, err := net.ParseCIDR(val)
+	if err != nil || ip.To4() == nil {
+		v.add(field, "%q must be an IPv4 network in CIDR notation", val)
+		return
+	}
+	if !ip.Equal(n.IP) {
+		v.add(field, "%q has host bits set, use %s", val, n.String())
+	}
+}
+
+// validateTable ensures the table is an additional routing table or, if
+// allowed, 0 for the main table.
+func validateTable(v *validator, field string, table int, allowMain bool) {
+	if allowMain && table == 0 {
+		return
+	}
+	if table < minRoutingTable || table > maxRoutingTable {
+		v.add(field, "%d must be between %d and %d", table, minRoutingTable, maxRoutingTable)
+	}
+}
+
+func validateDistance(v *validator, field string, distance int) {
+	if distance != 0 && (distance < minDistance || distance > maxDistance) {
+		v.add(field, "%d must be between %d and %d", distance, minDistance, maxDistance)
+	}
+}
+
+// sortedKeys returns the keys of the next hops in order so that validation
+// errors are reported in a stable order.
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch hops := m.(type) {
+	case map[string]*NextHop:
+		for k := range hops {
+			keys = append(keys, k)
+		}
+	case map[string]*NextHopInterface:
+		for k := range hops {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
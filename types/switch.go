@@ -0,0 +1,71 @@
+package types
+
+// Switch is the hardware switch of devices such as the EdgeRouter X, e.g.
+// interfaces switch switch0. Ports are the ethernet interfaces listed under
+// switch-port interface, keyed by name. Their VLANs only take effect when the
+// switch is VLAN aware.
+type Switch struct {
+	ID          string                 `json:"-" tfsdk:"id"`
+	Addresses   []string               `json:"address,omitempty" tfsdk:"-"`
+	Description string                 `json:"description,omitempty" tfsdk:"-"`
+	MTU         int                    `json:"-" tfsdk:"-"`
+	VLANAware   bool                   `json:"-" tfsdk:"-"`
+	Ports       map[string]*SwitchPort `json:"-" tfsdk:"-"`
+	Firewall    *FirewallAttachment    `json:"firewall,omitempty" tfsdk:"-"`
+	VIF         map[string]*VIF        `json:"vif,omitempty" tfsdk:"-"`
+	opMode      OpMode
+}
+
+// SwitchPort is the VLAN configuration of a port of a switch, e.g. interfaces
+// switch switch0 switch-port interface eth1. PVID is the VLAN of untagged
+// frames and VIDs are the VLANs of tagged frames. A port with only a PVID is an
+// access port and a port with VIDs is a trunk.
+type SwitchPort struct {
+	Switch    string `json:"-" tfsdk:"switch"`
+	Interface string `json:"-" tfsdk:"interface"`
+	PVID      int    `json:"-" tfsdk:"-"`
+	VIDs      []int  `json:"-" tfsdk:"-"`
+	opMode    OpMode
+}
+
+func (s *Switch) GetID() string {
+	return s.ID
+}
+
+// SetOpMode controls how the switch is encoded. When set to OpModeDelete,
+// every set property names a node to delete, and addresses are deleted by
+// value. Ports are never encoded in this mode.
+func (s *Switch) SetOpMode(m OpMode) {
+	(*s).opMode = m
+}
+
+// Properties returns a copy of the switch without its ports, firewall
+// attachment and VLANs.
+func (s *Switch) Properties() *Switch {
+	tmp := *s
+	tmp.Ports = nil
+	tmp.Firewall = nil
+	tmp.VIF = nil
+	return &tmp
+}
+
+// IsEmpty reports whether none of the properties of the switch are set.
+// Ports, the firewall attachment and VLANs are not considered.
+func (s *Switch) IsEmpty() bool {
+	return len(s.Addresses) == 0 &&
+		s.Description == "" &&
+		s.MTU == 0 &&
+		!s.VLANAware
+}
+
+// SetOpMode controls how the port is encoded. When set to OpModeDelete, a set
+// PVID names a node to delete and VIDs are deleted by value. A port with
+// neither deletes all of its VLANs.
+func (p *SwitchPort) SetOpMode(m OpMode) {
+	(*p).opMode = m
+}
+
+// IsEmpty reports whether the port has no VLANs.
+func (p *SwitchPort) IsEmpty() bool {
+	return p.PVID == 0 && len(p.VIDs) == 0
+}
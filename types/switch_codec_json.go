@@ -0,0 +1,163 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+const vlanAwareEnable = "enable"
+
+type switchPortNode struct {
+	Interfaces map[string]*SwitchPort `json:"interface,omitempty"`
+	VLANAware  string                 `json:"vlan-aware,omitempty"`
+}
+
+type switchPortVLAN struct {
+	PVID string   `json:"pvid,omitempty"`
+	VIDs []string `json:"vid,omitempty"`
+}
+
+func (s *Switch) MarshalJSON() ([]byte, error) {
+	if s.opMode == OpModeDelete {
+		return json.Marshal(s.deleteNodes())
+	}
+
+	var switchPort *switchPortNode
+	{
+		if s.VLANAware || len(s.Ports) > 0 {
+			switchPort = &switchPortNode{Interfaces: s.Ports}
+			if s.VLANAware {
+				switchPort.VLANAware = vlanAwareEnable
+			}
+		}
+	}
+
+	type Alias Switch
+	return json.Marshal(&struct {
+		MTU        string          `json:"mtu,omitempty"`
+		SwitchPort *switchPortNode `json:"switch-port,omitempty"`
+		*Alias
+	}{
+		MTU:        itoa(s.MTU),
+		SwitchPort: switchPort,
+		Alias:      (*Alias)(s),
+	})
+}
+
+func (s *Switch) UnmarshalJSON(data []byte) (err error) {
+	type Alias Switch
+	aux := &struct {
+		MTU        string          `json:"mtu,omitempty"`
+		SwitchPort *switchPortNode `json:"switch-port,omitempty"`
+		*Alias
+	}{
+		Alias: (*Alias)(s),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if s.MTU, err = atoi("mtu", aux.MTU); err != nil {
+		return err
+	}
+	if aux.SwitchPort != nil {
+		s.VLANAware = aux.SwitchPort.VLANAware == vlanAwareEnable
+		s.Ports = aux.SwitchPort.Interfaces
+		for name, p := range s.Ports {
+			if p == nil {
+				// A port without any VLANs is a valueless node.
+				p = new(SwitchPort)
+				s.Ports[name] = p
+			}
+			p.Interface = name
+		}
+	}
+	return nil
+}
+
+// deleteNodes returns the nodes of the switch that should be deleted.
+func (s *Switch) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if len(s.Addresses) > 0 {
+		nodes["address"] = s.Addresses
+	}
+	if s.Description != "" {
+		nodes["description"] = nil
+	}
+	if s.MTU != 0 {
+		nodes["mtu"] = nil
+	}
+	if s.VLANAware {
+		nodes["switch-port"] = map[string]interface{}{"vlan-aware": nil}
+	}
+
+	return nodes
+}
+
+func (p *SwitchPort) MarshalJSON() ([]byte, error) {
+	if p.opMode == OpModeDelete {
+		return json.Marshal(map[string]interface{}{"vlan": p.deleteNodes()})
+	}
+
+	if p.IsEmpty() {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]interface{}{"vlan": &switchPortVLAN{
+		PVID: itoa(p.PVID),
+		VIDs: vids(p.VIDs),
+	}})
+}
+
+func (p *SwitchPort) UnmarshalJSON(data []byte) (err error) {
+	aux := &struct {
+		VLAN *switchPortVLAN `json:"vlan,omitempty"`
+	}{}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.VLAN == nil {
+		return nil
+	}
+
+	if p.PVID, err = atoi("pvid", aux.VLAN.PVID); err != nil {
+		return err
+	}
+	for _, vid := range aux.VLAN.VIDs {
+		i, err := strconv.Atoi(vid)
+		if err != nil {
+			return fmt.Errorf("malformed vid: %v", vid)
+		}
+		p.VIDs = append(p.VIDs, i)
+	}
+	return nil
+}
+
+// deleteNodes returns the VLAN nodes of the port that should be deleted, or
+// nil, which deletes every VLAN of the port, if none are set.
+func (p *SwitchPort) deleteNodes() map[string]interface{} {
+	if p.IsEmpty() {
+		return nil
+	}
+
+	nodes := map[string]interface{}{}
+	if p.PVID != 0 {
+		nodes["pvid"] = nil
+	}
+	if len(p.VIDs) > 0 {
+		nodes["vid"] = vids(p.VIDs)
+	}
+	return nodes
+}
+
+func vids(ids []int) []string {
+	if len(ids) == 0 {
+		return nil
+	}
+	s := make([]string, 0, len(ids))
+	for _, id := range ids {
+		s = append(s, strconv.Itoa(id))
+	}
+	return s
+}
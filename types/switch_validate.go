@@ -0,0 +1,44 @@
+package types
+
+import "fmt"
+
+const minSwitchVLAN = 1
+
+// Validate checks the name, addresses and MTU of the switch. Ports are
+// validated by the port methods of the client.
+func (s *Switch) Validate() error {
+	v := new(validator)
+	v.validateInterfaceName("id", s.ID, "switch")
+
+	for i, addr := range s.Addresses {
+		v.validateInterfaceAddress(fmt.Sprintf("address[%d]", i), addr, true)
+	}
+	v.validateMTU("mtu", s.MTU)
+	return v.err()
+}
+
+// Validate checks that the VLANs of the port are valid and that the PVID is
+// not also tagged.
+func (p *SwitchPort) Validate() error {
+	v := new(validator)
+	v.validateInterfaceName("interface", p.Interface, "eth")
+
+	if p.PVID != 0 && (p.PVID < minSwitchVLAN || p.PVID > maxVLAN) {
+		v.add("pvid", "%d must be between %d and %d", p.PVID, minSwitchVLAN, maxVLAN)
+	}
+
+	seen := map[int]bool{}
+	for i, vid := range p.VIDs {
+		field := fmt.Sprintf("vid[%d]", i)
+		switch {
+		case vid < minSwitchVLAN || vid > maxVLAN:
+			v.add(field, "%d must be between %d and %d", vid, minSwitchVLAN, maxVLAN)
+		case vid == p.PVID:
+			v.add(field, "%d is already the pvid", vid)
+		case seen[vid]:
+			v.add(field, "%d is listed more than once", vid)
+		}
+		seen[vid] = true
+	}
+	return v.err()
+}
@@ -0,0 +1,131 @@
+package types
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// FieldError describes a single invalid field. Field is the path to the field
+// using EdgeOS node names, e.g. "rule[10].destination.port".
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError is returned by the Validate methods and contains every
+// invalid field that was found rather than just the first one.
+type ValidationError []*FieldError
+
+func (e ValidationError) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+type validator struct {
+	errs ValidationError
+}
+
+func (v *validator) add(field, format string, args ...interface{}) {
+	v.errs = append(v.errs, &FieldError{
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+func join(prefix, field string) string {
+	if prefix == "" {
+		return field
+	}
+	return prefix + "." + field
+}
+
+// validateName ensures a configuration node name is usable as an EdgeOS tag.
+func (v *validator) validateName(field, name string) {
+	if name == "" {
+		v.add(field, "must not be empty")
+		return
+	}
+	if strings.ContainsAny(name, " \t\n\"'") {
+		v.add(field, "%q must not contain whitespace or quotes", name)
+	}
+}
+
+// validateIPv4 ensures val is an IPv4 address, an IPv4 CIDR or an IPv4 range in
+// the form a.b.c.d-e.f.g.h.
+func (v *validator) validateIPv4(field, val string) {
+	if isIPv4(val) {
+		return
+	}
+
+	if _, n, err := net.ParseCIDR(val); err == nil && n.IP.To4() != nil {
+		return
+	}
+
+	if fromTo := strings.Split(val, "-"); len(fromTo) == 2 && isIPv4(fromTo[0]) && isIPv4(fromTo[1]) {
+		return
+	}
+
+	v.add(field, "%q is not a valid IPv4 address, CIDR or range", val)
+}
+
+func isIPv4(val string) bool {
+	ip := net.ParseIP(val)
+	return ip != nil && ip.To4() != nil && !strings.Contains(val, ":")
+}
+
+// validateInterfaceAddress ensures val is an IPv4 or IPv6 address in CIDR
+// notation or, if allowed, dhcp or dhcpv6.
+func (v *validator) validateInterfaceAddress(field, val string, allowDHCP bool) {
+	if allowDHCP && (val == "dhcp" || val == "dhcpv6") {
+		return
+	}
+	if _, _, err := net.ParseCIDR(val); err == nil {
+		return
+	}
+	if allowDHCP {
+		v.add(field, "%q must be dhcp, dhcpv6 or an address in CIDR notation", val)
+		return
+	}
+	v.add(field, "%q must be an address in CIDR notation", val)
+}
+
+// validateMTU ensures mtu, if set, is within the range EdgeOS accepts.
+func (v *validator) validateMTU(field string, mtu int) {
+	if mtu != 0 && (mtu < minMTU || mtu > maxMTU) {
+		v.add(field, "%d must be between %d and %d", mtu, minMTU, maxMTU)
+	}
+}
+
+// validateOneOf ensures val, if set, is one of the allowed values.
+func (v *validator) validateOneOf(field, val string, allowed []string) {
+	if val != "" && !contains(allowed, val) {
+		v.add(field, "%q must be one of %s", val, strings.Join(allowed, ", "))
+	}
+}
+
+// validateInterfaceName ensures name is prefix followed by a number, e.g. br0
+// for the prefix br.
+func (v *validator) validateInterfaceName(field, name, prefix string) {
+	if n, err := strconv.Atoi(strings.TrimPrefix(name, prefix)); !strings.HasPrefix(name, prefix) || err != nil || n < 0 {
+		v.add(field, "%q must be %s followed by a number", name, prefix)
+	}
+}
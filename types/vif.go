@@ -0,0 +1,52 @@
+package types
+
+// VIF is a VLAN sub-interface, e.g. interfaces ethernet eth1 vif 20, which the
+// kernel names eth1.20. Parent is the path of the interface the VLAN belongs
+// to and ID is the VLAN id.
+type VIF struct {
+	ID          string              `json:"-" tfsdk:"id"`
+	Parent      InterfacePath       `json:"-" tfsdk:"-"`
+	Addresses   []string            `json:"address,omitempty" tfsdk:"-"`
+	Description string              `json:"description,omitempty" tfsdk:"-"`
+	DHCPOptions *DHCPOptions        `json:"dhcp-options,omitempty" tfsdk:"-"`
+	Disable     bool                `json:"-" tfsdk:"-"`
+	MTU         int                 `json:"-" tfsdk:"-"`
+	Firewall    *FirewallAttachment `json:"firewall,omitempty" tfsdk:"-"`
+	PPPoE       map[string]*PPPoE   `json:"pppoe,omitempty" tfsdk:"-"`
+	opMode      OpMode
+}
+
+func (v *VIF) GetID() string {
+	return v.Path().String()
+}
+
+// Path returns the path of the VLAN, e.g. ethernet eth1 vif 20.
+func (v *VIF) Path() InterfacePath {
+	return v.Parent.Child("vif", v.ID)
+}
+
+// SetOpMode controls how the VLAN is encoded. When set to OpModeDelete, every
+// set property names a node to delete, and addresses are deleted by value. A
+// VLAN without any property removes the VLAN entirely.
+func (v *VIF) SetOpMode(m OpMode) {
+	(*v).opMode = m
+}
+
+// Properties returns a copy of the VLAN without its firewall attachment and
+// PPPoE sessions.
+func (v *VIF) Properties() *VIF {
+	tmp := *v
+	tmp.Firewall = nil
+	tmp.PPPoE = nil
+	return &tmp
+}
+
+// IsEmpty reports whether none of the properties of the VLAN are set. The
+// firewall attachment and PPPoE sessions are not considered.
+func (v *VIF) IsEmpty() bool {
+	return len(v.Addresses) == 0 &&
+		v.Description == "" &&
+		v.DHCPOptions == nil &&
+		!v.Disable &&
+		v.MTU == 0
+}
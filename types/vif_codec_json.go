@@ -0,0 +1,90 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+func (v *VIF) MarshalJSON() ([]byte, error) {
+	if v.opMode == OpModeDelete {
+		if v.IsEmpty() {
+			return []byte("null"), nil
+		}
+		return json.Marshal(v.deleteNodes())
+	}
+
+	var mtu string
+	{
+		if v.MTU != 0 {
+			mtu = strconv.Itoa(v.MTU)
+		}
+	}
+
+	var disable *null
+	{
+		if v.Disable {
+			disable = &null{true}
+		}
+	}
+
+	type Alias VIF
+	return json.Marshal(&struct {
+		Disable *null  `json:"disable,omitempty"`
+		MTU     string `json:"mtu,omitempty"`
+		*Alias
+	}{
+		Disable: disable,
+		MTU:     mtu,
+		Alias:   (*Alias)(v),
+	})
+}
+
+func (v *VIF) UnmarshalJSON(data []byte) (err error) {
+	type Alias VIF
+	aux := &struct {
+		Disable null   `json:"disable"`
+		MTU     string `json:"mtu,omitempty"`
+		*Alias
+	}{
+		Alias: (*Alias)(v),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	v.Disable = aux.Disable.val
+
+	if aux.MTU != "" {
+		i, err := strconv.Atoi(aux.MTU)
+		if err != nil {
+			return fmt.Errorf("malformed mtu: %v", aux.MTU)
+		}
+		v.MTU = i
+	}
+
+	return nil
+}
+
+// deleteNodes returns the nodes of the VLAN that should be deleted.
+func (v *VIF) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if len(v.Addresses) > 0 {
+		nodes["address"] = v.Addresses
+	}
+	if v.Description != "" {
+		nodes["description"] = nil
+	}
+	if v.Disable {
+		nodes["disable"] = nil
+	}
+	if v.MTU != 0 {
+		nodes["mtu"] = nil
+	}
+	if v.DHCPOptions != nil {
+		nodes["dhcp-options"] = v.DHCPOptions.deleteNodes()
+	}
+
+	return nodes
+}
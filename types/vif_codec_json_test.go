@@ -0,0 +1,35 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVIFJSON(t *testing.T) {
+	var v VIF
+	require.NoError(t, json.Unmarshal([]byte(`{"address":["10.0.20.1/24"],"description":"IoT","mtu":"1496","firewall":{"in":{"name":"IOT_IN"}}}`), &v))
+	require.Equal(t, VIF{
+		Addresses:   []string{"10.0.20.1/24"},
+		Description: "IoT",
+		MTU:         1496,
+		Firewall:    &FirewallAttachment{In: strptr("IOT_IN")},
+	}, v)
+
+	data, err := json.Marshal(v.Properties())
+	require.NoError(t, err)
+	require.Equal(t, `{"mtu":"1496","address":["10.0.20.1/24"],"description":"IoT"}`, string(data))
+
+	del := &VIF{Description: "IoT", DHCPOptions: &DHCPOptions{NameServer: "update"}}
+	del.SetOpMode(OpModeDelete)
+	data, err = json.Marshal(del)
+	require.NoError(t, err)
+	require.Equal(t, `{"description":null,"dhcp-options":{"name-server":null}}`, string(data))
+
+	del = &VIF{}
+	del.SetOpMode(OpModeDelete)
+	data, err = json.Marshal(del)
+	require.NoError(t, err)
+	require.Equal(t, `null`, string(data))
+}
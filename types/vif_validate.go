@@ -0,0 +1,33 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	minVLAN = 0
+	maxVLAN = 4094
+)
+
+// Validate checks the path, addresses, MTU and DHCP client options of the
+// VLAN. The firewall attachment is validated by its own client.
+func (v *VIF) Validate() error {
+	val := new(validator)
+
+	if err := v.Path().Validate(); err != nil {
+		val.add("interface", "%v", err)
+	} else if id, _ := strconv.Atoi(v.ID); id < minVLAN || id > maxVLAN {
+		val.add("id", "%d must be between %d and %d", id, minVLAN, maxVLAN)
+	}
+
+	for i, addr := range v.Addresses {
+		val.validateInterfaceAddress(fmt.Sprintf("address[%d]", i), addr, true)
+	}
+	val.validateMTU("mtu", v.MTU)
+
+	if o := v.DHCPOptions; o != nil {
+		o.validate(val, "dhcp-options")
+	}
+	return val.err()
+}
@@ -0,0 +1,40 @@
+package types
+
+// VTI is a virtual tunnel interface for route based IPsec, e.g. interfaces vti
+// vti0. The IPsec peer that binds to it is configured separately.
+type VTI struct {
+	ID          string              `json:"-" tfsdk:"id"`
+	Addresses   []string            `json:"address,omitempty" tfsdk:"-"`
+	Description string              `json:"description,omitempty" tfsdk:"-"`
+	Disable     bool                `json:"-" tfsdk:"-"`
+	MTU         int                 `json:"-" tfsdk:"-"`
+	Firewall    *FirewallAttachment `json:"firewall,omitempty" tfsdk:"-"`
+	opMode      OpMode
+}
+
+func (v *VTI) GetID() string {
+	return v.ID
+}
+
+// SetOpMode controls how the interface is encoded. When set to OpModeDelete,
+// every set property names a node to delete, and addresses are deleted by
+// value.
+func (v *VTI) SetOpMode(m OpMode) {
+	(*v).opMode = m
+}
+
+// Properties returns a copy of the interface without its firewall attachment.
+func (v *VTI) Properties() *VTI {
+	tmp := *v
+	tmp.Firewall = nil
+	return &tmp
+}
+
+// IsEmpty reports whether none of the properties of the interface are set.
+// The firewall attachment is not considered.
+func (v *VTI) IsEmpty() bool {
+	return len(v.Addresses) == 0 &&
+		v.Description == "" &&
+		!v.Disable &&
+		v.MTU == 0
+}
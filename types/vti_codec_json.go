@@ -0,0 +1,58 @@
+package types
+
+import "encoding/json"
+
+func (v *VTI) MarshalJSON() ([]byte, error) {
+	if v.opMode == OpModeDelete {
+		return json.Marshal(v.deleteNodes())
+	}
+
+	type Alias VTI
+	return json.Marshal(&struct {
+		Disable *null  `json:"disable,omitempty"`
+		MTU     string `json:"mtu,omitempty"`
+		*Alias
+	}{
+		Disable: flag(v.Disable),
+		MTU:     itoa(v.MTU),
+		Alias:   (*Alias)(v),
+	})
+}
+
+func (v *VTI) UnmarshalJSON(data []byte) (err error) {
+	type Alias VTI
+	aux := &struct {
+		Disable null   `json:"disable"`
+		MTU     string `json:"mtu,omitempty"`
+		*Alias
+	}{
+		Alias: (*Alias)(v),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	v.Disable = aux.Disable.val
+	v.MTU, err = atoi("mtu", aux.MTU)
+	return err
+}
+
+// deleteNodes returns the nodes of the interface that should be deleted.
+func (v *VTI) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if len(v.Addresses) > 0 {
+		nodes["address"] = v.Addresses
+	}
+	if v.Description != "" {
+		nodes["description"] = nil
+	}
+	if v.Disable {
+		nodes["disable"] = nil
+	}
+	if v.MTU != 0 {
+		nodes["mtu"] = nil
+	}
+
+	return nodes
+}
@@ -0,0 +1,118 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+)
+
+// WireGuard is a WireGuard tunnel interface, e.g. interfaces wireguard wg0.
+// PrivateKey holds either the key itself or the path of a file on the router
+// that holds it. Peers are keyed by their public key.
+type WireGuard struct {
+	ID              string                    `json:"-" tfsdk:"id"`
+	Addresses       []string                  `json:"address,omitempty" tfsdk:"-"`
+	Description     string                    `json:"description,omitempty" tfsdk:"-"`
+	ListenPort      int                       `json:"-" tfsdk:"-"`
+	MTU             int                       `json:"-" tfsdk:"-"`
+	PrivateKey      string                    `json:"private-key,omitempty" tfsdk:"-"`
+	RouteAllowedIPs *bool                     `json:"-" tfsdk:"-"`
+	Peers           map[string]*WireGuardPeer `json:"peer,omitempty" tfsdk:"-"`
+	Firewall        *FirewallAttachment       `json:"firewall,omitempty" tfsdk:"-"`
+	opMode          OpMode
+}
+
+// WireGuardPeer is a peer of a WireGuard interface, identified by its public
+// key. PresharedKey holds either the key itself or the path of a file on the
+// router that holds it.
+type WireGuardPeer struct {
+	PublicKey           string   `json:"-" tfsdk:"id"`
+	Interface           string   `json:"-" tfsdk:"interface"`
+	AllowedIPs          []string `json:"allowed-ips,omitempty" tfsdk:"-"`
+	Description         string   `json:"description,omitempty" tfsdk:"-"`
+	Endpoint            string   `json:"endpoint,omitempty" tfsdk:"-"`
+	PersistentKeepalive int      `json:"-" tfsdk:"-"`
+	PresharedKey        string   `json:"preshared-key,omitempty" tfsdk:"-"`
+	opMode              OpMode
+}
+
+func (w *WireGuard) GetID() string {
+	return w.ID
+}
+
+// SetOpMode controls how the interface is encoded. When set to OpModeDelete,
+// every set property names a node to delete, and addresses are deleted by
+// value. Peers and the firewall attachment are never encoded in this mode.
+func (w *WireGuard) SetOpMode(m OpMode) {
+	(*w).opMode = m
+}
+
+// Properties returns a copy of the interface without its peers and firewall
+// attachment.
+func (w *WireGuard) Properties() *WireGuard {
+	tmp := *w
+	tmp.Peers = nil
+	tmp.Firewall = nil
+	return &tmp
+}
+
+// IsEmpty reports whether none of the properties of the interface are set.
+// Peers and the firewall attachment are not considered.
+func (w *WireGuard) IsEmpty() bool {
+	return len(w.Addresses) == 0 &&
+		w.Description == "" &&
+		w.ListenPort == 0 &&
+		w.MTU == 0 &&
+		w.PrivateKey == "" &&
+		w.RouteAllowedIPs == nil
+}
+
+// String masks the private key so that interfaces can be logged. It has a
+// value receiver so that both WireGuard and *WireGuard are masked.
+func (w WireGuard) String() string {
+	peers := make([]string, 0, len(w.Peers))
+	for _, p := range w.Peers {
+		if p != nil {
+			peers = append(peers, p.PublicKey)
+		}
+	}
+	sort.Strings(peers)
+	return fmt.Sprintf("WireGuard{ID: %s, Addresses: %v, Description: %q, ListenPort: %d, MTU: %d, PrivateKey: %q, Peers: %v}",
+		w.ID, w.Addresses, w.Description, w.ListenPort, w.MTU, Secret(w.PrivateKey), peers)
+}
+
+// GoString masks the private key when formatted with %#v.
+func (w WireGuard) GoString() string {
+	return w.String()
+}
+
+func (p *WireGuardPeer) GetID() string {
+	return p.PublicKey
+}
+
+// SetOpMode controls how the peer is encoded. When set to OpModeDelete, every
+// set property names a node to delete, and allowed IPs are deleted by value. A
+// peer without any property removes the peer entirely.
+func (p *WireGuardPeer) SetOpMode(m OpMode) {
+	(*p).opMode = m
+}
+
+// IsEmpty reports whether none of the properties of the peer are set.
+func (p *WireGuardPeer) IsEmpty() bool {
+	return len(p.AllowedIPs) == 0 &&
+		p.Description == "" &&
+		p.Endpoint == "" &&
+		p.PersistentKeepalive == 0 &&
+		p.PresharedKey == ""
+}
+
+// String masks the preshared key so that peers can be logged. It has a value
+// receiver so that both WireGuardPeer and *WireGuardPeer are masked.
+func (p WireGuardPeer) String() string {
+	return fmt.Sprintf("WireGuardPeer{Interface: %s, PublicKey: %s, AllowedIPs: %v, Description: %q, Endpoint: %q, PersistentKeepalive: %d, PresharedKey: %q}",
+		p.Interface, p.PublicKey, p.AllowedIPs, p.Description, p.Endpoint, p.PersistentKeepalive, Secret(p.PresharedKey))
+}
+
+// GoString masks the preshared key when formatted with %#v.
+func (p WireGuardPeer) GoString() string {
+	return p.String()
+}
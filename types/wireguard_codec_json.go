@@ -0,0 +1,147 @@
+package types
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+func (w *WireGuard) MarshalJSON() ([]byte, error) {
+	if w.opMode == OpModeDelete {
+		return json.Marshal(w.deleteNodes())
+	}
+
+	var routeAllowedIPs string
+	{
+		if w.RouteAllowedIPs != nil {
+			routeAllowedIPs = strconv.FormatBool(*w.RouteAllowedIPs)
+		}
+	}
+
+	type Alias WireGuard
+	return json.Marshal(&struct {
+		ListenPort      string `json:"listen-port,omitempty"`
+		MTU             string `json:"mtu,omitempty"`
+		RouteAllowedIPs string `json:"route-allowed-ips,omitempty"`
+		*Alias
+	}{
+		ListenPort:      itoa(w.ListenPort),
+		MTU:             itoa(w.MTU),
+		RouteAllowedIPs: routeAllowedIPs,
+		Alias:           (*Alias)(w),
+	})
+}
+
+func (w *WireGuard) UnmarshalJSON(data []byte) (err error) {
+	type Alias WireGuard
+	aux := &struct {
+		ListenPort      string `json:"listen-port,omitempty"`
+		MTU             string `json:"mtu,omitempty"`
+		RouteAllowedIPs string `json:"route-allowed-ips,omitempty"`
+		*Alias
+	}{
+		Alias: (*Alias)(w),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.RouteAllowedIPs != "" {
+		route := aux.RouteAllowedIPs == "true"
+		w.RouteAllowedIPs = &route
+	}
+	if w.ListenPort, err = atoi("listen-port", aux.ListenPort); err != nil {
+		return err
+	}
+	if w.MTU, err = atoi("mtu", aux.MTU); err != nil {
+		return err
+	}
+	for key, p := range w.Peers {
+		if p != nil {
+			p.PublicKey = key
+		}
+	}
+	return nil
+}
+
+// deleteNodes returns the nodes of the interface that should be deleted.
+func (w *WireGuard) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if len(w.Addresses) > 0 {
+		nodes["address"] = w.Addresses
+	}
+	if w.Description != "" {
+		nodes["description"] = nil
+	}
+	if w.ListenPort != 0 {
+		nodes["listen-port"] = nil
+	}
+	if w.MTU != 0 {
+		nodes["mtu"] = nil
+	}
+	if w.PrivateKey != "" {
+		nodes["private-key"] = nil
+	}
+	if w.RouteAllowedIPs != nil {
+		nodes["route-allowed-ips"] = nil
+	}
+
+	return nodes
+}
+
+func (p *WireGuardPeer) MarshalJSON() ([]byte, error) {
+	if p.opMode == OpModeDelete {
+		if p.IsEmpty() {
+			return []byte("null"), nil
+		}
+		return json.Marshal(p.deleteNodes())
+	}
+
+	type Alias WireGuardPeer
+	return json.Marshal(&struct {
+		PersistentKeepalive string `json:"persistent-keepalive,omitempty"`
+		*Alias
+	}{
+		PersistentKeepalive: itoa(p.PersistentKeepalive),
+		Alias:               (*Alias)(p),
+	})
+}
+
+func (p *WireGuardPeer) UnmarshalJSON(data []byte) (err error) {
+	type Alias WireGuardPeer
+	aux := &struct {
+		PersistentKeepalive string `json:"persistent-keepalive,omitempty"`
+		*Alias
+	}{
+		Alias: (*Alias)(p),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	p.PersistentKeepalive, err = atoi("persistent-keepalive", aux.PersistentKeepalive)
+	return err
+}
+
+// deleteNodes returns the nodes of the peer that should be deleted.
+func (p *WireGuardPeer) deleteNodes() map[string]interface{} {
+	nodes := map[string]interface{}{}
+
+	if len(p.AllowedIPs) > 0 {
+		nodes["allowed-ips"] = p.AllowedIPs
+	}
+	if p.Description != "" {
+		nodes["description"] = nil
+	}
+	if p.Endpoint != "" {
+		nodes["endpoint"] = nil
+	}
+	if p.PersistentKeepalive != 0 {
+		nodes["persistent-keepalive"] = nil
+	}
+	if p.PresharedKey != "" {
+		nodes["preshared-key"] = nil
+	}
+
+	return nodes
+}
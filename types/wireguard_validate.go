@@ -0,0 +1,91 @@
+package types
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	wireGuardKeyLen = 32
+
+	maxKeepalive = 65535
+)
+
+// Validate checks the name, addresses, listen port, MTU, private key and every
+// peer of the interface. Keys are never included in the errors.
+func (w *WireGuard) Validate() error {
+	v := new(validator)
+	v.validateInterfaceName("id", w.ID, "wg")
+
+	for i, addr := range w.Addresses {
+		v.validateInterfaceAddress(fmt.Sprintf("address[%d]", i), addr, false)
+	}
+	if w.ListenPort != 0 {
+		validatePort(v, "listen-port", w.ListenPort)
+	}
+	v.validateMTU("mtu", w.MTU)
+	if w.PrivateKey != "" {
+		v.validateWireGuardKey("private-key", w.PrivateKey, true)
+	}
+
+	for key, p := range w.Peers {
+		if p == nil {
+			continue
+		}
+		tmp := *p
+		tmp.PublicKey = key
+		tmp.validate(v, fmt.Sprintf("peer[%s]", key))
+	}
+	return v.err()
+}
+
+// Validate checks the public key, allowed IPs, endpoint, keepalive interval and
+// preshared key of the peer. The preshared key is never included in the
+// errors.
+func (p *WireGuardPeer) Validate() error {
+	v := new(validator)
+	p.validate(v, "")
+	return v.err()
+}
+
+func (p *WireGuardPeer) validate(v *validator, prefix string) {
+	v.validateWireGuardKey(join(prefix, "public-key"), p.PublicKey, false)
+
+	for i, ip := range p.AllowedIPs {
+		if _, _, err := net.ParseCIDR(ip); err != nil {
+			v.add(join(prefix, fmt.Sprintf("allowed-ips[%d]", i)), "%q must be an address in CIDR notation", ip)
+		}
+	}
+	if p.Endpoint != "" {
+		host, port, err := net.SplitHostPort(p.Endpoint)
+		if n, portErr := strconv.Atoi(port); err != nil || host == "" || portErr != nil || n < minPort || n > maxPort {
+			v.add(join(prefix, "endpoint"), "%q must be a host and port, e.g. vpn.example.com:51820", p.Endpoint)
+		}
+	}
+	if p.PersistentKeepalive < 0 || p.PersistentKeepalive > maxKeepalive {
+		v.add(join(prefix, "persistent-keepalive"), "%d must be between 0 and %d", p.PersistentKeepalive, maxKeepalive)
+	}
+	if p.PresharedKey != "" {
+		v.validateWireGuardKey(join(prefix, "preshared-key"), p.PresharedKey, true)
+	}
+}
+
+// validateWireGuardKey ensures key is a base64 encoded 32 byte key or, if
+// secret, the absolute path of a file holding one. Secret keys are never
+// included in the error.
+func (v *validator) validateWireGuardKey(field, key string, secret bool) {
+	if secret && strings.HasPrefix(key, "/") {
+		return
+	}
+	if b, err := base64.StdEncoding.DecodeString(key); err == nil && len(b) == wireGuardKeyLen {
+		return
+	}
+	if secret {
+		v.add(field, "must be a base64 encoded %d byte key or the path of a file holding one", wireGuardKeyLen)
+		return
+	}
+	v.add(field, "%q must be a base64 encoded %d byte key", key, wireGuardKeyLen)
+}